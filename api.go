@@ -1,7 +1,6 @@
 package main
 
 import (
-	"fmt"
 	"net/http"
 
 	"github.com/go-chi/chi"
@@ -10,15 +9,22 @@ import (
 )
 
 type APIError struct {
-	Error   string `json:"error,omitempty"`
-	Details string `json:"details,omitempty"`
+	Code    string          `json:"code,omitempty"` //The code of the error in the error catalog, see GET /errors/{code}
+	Error   string          `json:"error,omitempty"`
+	Details string          `json:"details,omitempty"`
+	Fields  []*SettingError `json:"fields,omitempty"` //Why each rejected change to a setting was rejected
 }
 
-func errAPI(err ...interface{}) *APIError {
-	if len(err) > 1 {
-		return &APIError{Error: err[0].(string), Details: fmt.Sprintf("%v", err[1].(error))}
+// errAPI logs the given error at the level of its severity and returns an API error for it
+func errAPI(err error) *APIError {
+	clinetErr := asClinetError(err)
+	clinetErr.Severity().logger().With("code", clinetErr.Code).Println(clinetErr.Error())
+
+	apiErr := &APIError{Code: clinetErr.Code, Error: clinetErr.Message("en")}
+	if clinetErr.Cause != nil {
+		apiErr.Details = clinetErr.Cause.Error()
 	}
-	return &APIError{Error: err[0].(string)}
+	return apiErr
 }
 
 func StartAPI(host string) {
@@ -32,6 +38,17 @@ func StartAPI(host string) {
 		ErrorAPI.Printf("Error walking routes: %v", err)
 		return
 	}
+	undocumented, _, err := undocumentedAPIRoutes(router)
+	if err != nil {
+		ErrorAPI.Printf("Error walking routes: %v", err)
+		return
+	}
+	for _, route := range undocumented {
+		WarningAPI.Printf("Route %s is missing from the OpenAPI document", route)
+	}
+
+	apiAuthLoad()
+	apiAuditOpen()
 
 	if err := http.ListenAndServe(host, router); err != nil {
 		ErrorAPI.Printf("Error running HTTP server: %v", err)
@@ -42,13 +59,34 @@ func APIRouter() *chi.Mux {
 	router := chi.NewRouter()
 	router.Use(
 		render.SetContentType(render.ContentTypeJSON), //Set Content-Type to application/json
-		middleware.Logger,
+		apiRequestID,
+		middleware.RequestLogger(&middleware.DefaultLogFormatter{Logger: InfoAPI, NoColor: true}),
+		apiCORS,
 		middleware.RedirectSlashes,
 		middleware.Recoverer,
+		apiRateLimitIP, //Before authenticating, so guessing tokens is limited too
+		apiAuthenticate,
+		apiAudit,
+		apiRateLimitToken, //After auditing, so requests from abusive tokens show up in the audit log
 	)
 
+	router.Get("/healthz", apiGetHealthz)     //Whether or not the process is alive and responsive
+	router.Get("/readyz", apiGetReadyz)       //Whether or not every shard is ready to serve commands and API requests
+	router.Get("/metrics", apiGetMetrics)     //Prometheus metrics for every shard
+	router.Get("/errors", apiGetErrors)       //Every error code in the error catalog
+	router.Get("/errors/{code}", apiGetError) //A single error code, for looking up codes users report
+
+	router.With(apiRequireAuth).Get("/ws", apiGetWS) //Streams events from guilds as they happen over a WebSocket
+
+	router.Get("/", apiGetRoot)             //Redirects to the dashboard
+	router.Mount("/dashboard", Dashboard()) //Serves the web dashboard
+
+	router.Mount("/auth", APIAuth())
+
 	router.Route("/api", func(r chi.Router) {
+		r.Get("/openapi.json", apiGetOpenAPI(router)) //The OpenAPI document of every route
 		r.Mount("/v0", APIv0())
+		r.Mount("/v1", APIv1()) //Wraps responses in an envelope and pages lists, see apiv1.go
 	})
 
 	return router
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+)
+
+/*
+	Every API request that changes something, and every invite generated through the API, is recorded in the audit log along with who made it.
+	The audit log is a file of JSON records, one per line, rotated like the bot's own logs, and the latest records are kept in memory for GET /auth/audit.
+	When sharded, only the first shard records requests, as it's the one that receives them.
+*/
+
+const (
+	//How many of the latest audit records are kept in memory
+	apiAuditMemory = 1000
+
+	//How many audit records are returned when no limit is given
+	apiAuditDefaultLimit = 100
+)
+
+var (
+	//Where API requests are recorded, next to the API tokens
+	apiAuditFile = filepath.Join("state", "api", "audit.log")
+
+	apiAuditLog     *RotatingFile
+	apiAuditRecords = make([]*APIAuditRecord, 0) //The latest audit records, oldest first
+	apiAuditLock    sync.RWMutex
+
+	//Routes that are recorded even though they don't change anything, as they hand out access to something
+	apiAuditedRoutes = map[string]bool{
+		"GET /api/v0/guild/{guildID}/invite/{key}": true,
+	}
+)
+
+// APIAuditRecord holds who made an API request and what came of it
+type APIAuditRecord struct {
+	Time      time.Time `json:"time"`
+	RequestID string    `json:"requestID"`
+	UserID    string    `json:"userID,omitempty"`  //Empty if the request wasn't authenticated with an API token or session
+	TokenID   string    `json:"tokenID,omitempty"` //The API token or session the request was authenticated with
+	IP        string    `json:"ip"`
+	Method    string    `json:"method"`
+	Route     string    `json:"route"` //The pattern of the route rather than the path, so keys in paths aren't recorded
+	GuildID   string    `json:"guildID,omitempty"`
+	Status    int       `json:"status"`
+}
+
+// apiAuditOpen opens the audit log and loads its latest records
+func apiAuditOpen() {
+	if err := os.MkdirAll(filepath.Dir(apiAuditFile), 0700); err != nil {
+		ErrorAPI.Printf("Error creating the audit log directory: %v", err)
+		return
+	}
+
+	apiAuditLock.Lock()
+	defer apiAuditLock.Unlock()
+
+	if file, err := os.Open(apiAuditFile); err == nil {
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			record := &APIAuditRecord{}
+			if err := json.Unmarshal(scanner.Bytes(), record); err != nil {
+				continue
+			}
+			apiAuditRecords = append(apiAuditRecords, record)
+			if len(apiAuditRecords) > apiAuditMemory {
+				apiAuditRecords = apiAuditRecords[1:]
+			}
+		}
+		file.Close()
+	}
+
+	file, err := openRotatingFile(apiAuditFile)
+	if err != nil {
+		ErrorAPI.Printf("Error opening the audit log: %v", err)
+		return
+	}
+	file.Configure(botData().BotOptions.Logging)
+	apiAuditLog = file
+}
+
+// apiAuditAdd adds a record to the audit log
+func apiAuditAdd(record *APIAuditRecord) {
+	apiAuditLock.Lock()
+	defer apiAuditLock.Unlock()
+
+	apiAuditRecords = append(apiAuditRecords, record)
+	if len(apiAuditRecords) > apiAuditMemory {
+		apiAuditRecords = apiAuditRecords[1:]
+	}
+
+	if apiAuditLog == nil {
+		return
+	}
+	recordJSON, err := json.Marshal(record)
+	if err != nil {
+		ErrorAPI.Printf("Error encoding an audit record: %v", err)
+		return
+	}
+	if _, err := apiAuditLog.Write(append(recordJSON, '\n')); err != nil {
+		ErrorAPI.Printf("Error writing to the audit log: %v", err)
+	}
+}
+
+// apiAudit records every API request that changes something, or that's made to a route in apiAuditedRoutes
+func apiAudit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isIPCRequest(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		started := time.Now()
+		wrapped := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(wrapped, r)
+
+		routeContext := chi.RouteContext(r.Context())
+		route := routeContext.RoutePattern()
+		if r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+			if !apiAuditedRoutes[apiRouteKey(r.Method, route)] {
+				return
+			}
+		}
+
+		record := &APIAuditRecord{
+			Time:      started,
+			RequestID: apiRequestIDOf(r),
+			IP:        apiClientIP(r),
+			Method:    r.Method,
+			Route:     route,
+			GuildID:   routeContext.URLParam("guildID"),
+			Status:    wrapped.Status(),
+		}
+		if record.Status == 0 {
+			record.Status = http.StatusOK
+		}
+		if principal := apiPrincipal(r); principal != nil {
+			record.UserID = principal.UserID
+			record.TokenID = principal.TokenID
+		}
+		apiAuditAdd(record)
+	})
+}
+
+func authGetAudit(w http.ResponseWriter, r *http.Request) {
+	principal := apiPrincipal(r)
+	query := r.URL.Query()
+
+	userID := principal.UserID
+	if principal.IsAdmin() {
+		userID = query.Get("user") //Admins see every user's records unless they ask for one
+	} else if query.Get("user") != "" && query.Get("user") != principal.UserID {
+		render.Status(r, http.StatusForbidden)
+		render.JSON(w, r, errAPI(newError(errCodeAPIForbidden, "user "+query.Get("user"))))
+		return
+	}
+	guildID := query.Get("guild")
+	tokenID := query.Get("token")
+
+	limit := apiAuditDefaultLimit
+	if query.Get("limit") != "" {
+		parsed, err := strconv.Atoi(query.Get("limit"))
+		if err != nil || parsed <= 0 {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, errAPI(newError(errCodeAPIParamInvalid, "limit")))
+			return
+		}
+		limit = parsed
+	}
+
+	apiAuditLock.RLock()
+	defer apiAuditLock.RUnlock()
+
+	records := make([]*APIAuditRecord, 0)
+	for i := len(apiAuditRecords) - 1; i >= 0 && len(records) < limit; i-- {
+		record := apiAuditRecords[i]
+		if (userID != "" && record.UserID != userID) || (guildID != "" && record.GuildID != guildID) || (tokenID != "" && record.TokenID != tokenID) {
+			continue
+		}
+		records = append(records, record)
+	}
+	render.JSON(w, r, records)
+}
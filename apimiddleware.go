@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+)
+
+/*
+	Every API request gets an ID, sent back in the X-Request-ID header and logged with the request, so a response can be matched to the logs and audit records.
+	Requests are limited per IP before they're authenticated, so guessing tokens is limited too, and per API token or session after.
+	When sharded, only the first shard limits requests, as it's the one that receives them; requests it proxies to the other shards carry the ID it gave them.
+*/
+
+const (
+	apiRequestIDHeader = "X-Request-ID"
+
+	//The headers a page on another origin may send and read
+	apiCORSAllowHeaders  = "Authorization, Content-Type, " + apiRequestIDHeader + ", " + webhookKeyHeader
+	apiCORSExposeHeaders = "Retry-After, X-RateLimit-Limit, X-RateLimit-Remaining, " + apiRequestIDHeader
+	apiCORSAllowMethods  = "GET, POST, PUT, PATCH, DELETE"
+)
+
+var (
+	//How often each IP and each API token makes requests, where key = IP or token ID
+	apiIPLimiter    = newRateLimiter(time.Minute)
+	apiTokenLimiter = newRateLimiter(time.Minute)
+
+	//How often invites are generated for each guild, where key = guild ID
+	apiInviteLimiter = newRateLimiter(time.Hour)
+)
+
+// apiRequestID gives an API request an ID, or keeps the one given by the first shard if it was proxied from it
+func apiRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := ""
+		if isIPCRequest(r) {
+			requestID = r.Header.Get(apiRequestIDHeader)
+		} else {
+			requestID, _ = randomHex(8)
+			r.Header.Set(apiRequestIDHeader, requestID) //Passed on to the shard that owns the guild when proxied
+			w.Header().Set(apiRequestIDHeader, requestID)
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), middleware.RequestIDKey, requestID)))
+	})
+}
+
+// apiRequestIDOf returns the ID of an API request
+func apiRequestIDOf(r *http.Request) string {
+	return middleware.GetReqID(r.Context())
+}
+
+// apiClientIP returns the IP of whoever made an API request
+// Behind a reverse proxy, that's the last address in X-Forwarded-For, as that's the one the proxy added itself
+func apiClientIP(r *http.Request) string {
+	if botData().BotOptions.API.TrustProxy {
+		if forwardedFor := r.Header.Get("X-Forwarded-For"); forwardedFor != "" {
+			addrs := strings.Split(forwardedFor, ",")
+			return strings.TrimSpace(addrs[len(addrs)-1])
+		}
+		if realIP := r.Header.Get("X-Real-IP"); realIP != "" {
+			return realIP
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// apiCORSOrigin returns whether or not pages on the given origin may call the API, and whether they may do so with the user's session cookie
+func apiCORSOrigin(origin string) (allowed bool, credentials bool) {
+	for _, allowedOrigin := range botData().BotOptions.API.CORSOrigins {
+		if allowedOrigin == "*" {
+			allowed = true
+		} else if strings.EqualFold(strings.TrimSuffix(allowedOrigin, "/"), origin) {
+			return true, true
+		}
+	}
+	return allowed, false
+}
+
+// apiCORS lets pages on the configured origins call the API from a browser, answering their preflight requests
+func apiCORS(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" || isIPCRequest(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Origin")
+		allowed, credentials := apiCORSOrigin(origin)
+		if !allowed {
+			next.ServeHTTP(w, r) //Browsers won't let the page read the response
+			return
+		}
+		if credentials {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+		} else {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		}
+		w.Header().Set("Access-Control-Expose-Headers", apiCORSExposeHeaders)
+
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Set("Access-Control-Allow-Methods", apiCORSAllowMethods)
+			w.Header().Set("Access-Control-Allow-Headers", apiCORSAllowHeaders)
+			w.Header().Set("Access-Control-Max-Age", "600")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// apiAllow records a request by the key if it's within the limit per minute, otherwise responding with an error and returning false
+func apiAllow(w http.ResponseWriter, r *http.Request, limiter *RateLimiter, key string, limit int) bool {
+	remaining, retryAfter, allowed := limiter.Allow(key, limit)
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(limit))
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+	if !allowed {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		render.Status(r, http.StatusTooManyRequests)
+		render.JSON(w, r, errAPI(newError(errCodeAPIRateLimited, limit)))
+		return false
+	}
+	return true
+}
+
+// apiRateLimitIP rejects API requests from IPs that made too many of them within the last minute
+func apiRateLimitIP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit := botData().BotOptions.API.RateLimit.PerIP
+		if limit <= 0 || isIPCRequest(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if apiAllow(w, r, apiIPLimiter, apiClientIP(r), limit) {
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// apiRateLimitToken rejects API requests made with an API token or session that made too many of them within the last minute
+func apiRateLimitToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit := botData().BotOptions.API.RateLimit.PerToken
+		principal := apiPrincipal(r)
+		if limit <= 0 || principal == nil || isIPCRequest(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if apiAllow(w, r, apiTokenLimiter, principal.TokenID, limit) {
+			next.ServeHTTP(w, r)
+		}
+	})
+}
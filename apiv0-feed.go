@@ -0,0 +1,213 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+)
+
+/*
+	Feeds can be managed through the API with the same rules as the feed command.
+	Each feed is addressed by its index in the guild's feeds, starting at 0.
+*/
+
+// APIFeed holds a feed that posts to a channel
+type APIFeed struct {
+	ID        string `json:"id"` //Derived from the URL, which is unique to each of a guild's feeds
+	URL       string `json:"url"`
+	Title     string `json:"title"`
+	Link      string `json:"link,omitempty"` //The website the feed belongs to
+	ChannelID string `json:"channelID"`      //The channel new posts are sent to
+	Frequency int    `json:"frequency"`      //How often the feed is checked for new posts, in seconds
+}
+
+// APIFeedRequest holds a request to add a feed or change one
+type APIFeedRequest struct {
+	URL       string  `json:"url"`       //Only used when adding a feed
+	ChannelID *string `json:"channelID"` //Required when adding a feed
+	Frequency *int    `json:"frequency"` //Defaults to the configured feed frequency when adding a feed
+}
+
+// feedID returns the ID of a feed as the API shows it
+func feedID(feedURL string) string {
+	hash := sha256.Sum256([]byte(feedURL))
+	return hex.EncodeToString(hash[:8])
+}
+
+// newAPIFeed returns a feed as the API shows it
+func newAPIFeed(feed *Feed) *APIFeed {
+	apiFeed := &APIFeed{ID: feedID(feed.FeedURL), URL: feed.FeedURL, ChannelID: feed.ChannelID, Frequency: feed.Frequency}
+	if feed.Feed != nil {
+		apiFeed.Title = feed.Title
+		apiFeed.Link = feed.Link
+	}
+	return apiFeed
+}
+
+// v0FeedIndex returns the feed index given in the URL, responding with an error and returning false if the guild has no such feed
+func v0FeedIndex(w http.ResponseWriter, r *http.Request, guildID string) (int, bool) {
+	index, err := strconv.Atoi(chi.URLParam(r, "index"))
+	if err != nil || index < 0 || index >= len(guildSettings[guildID].Feeds) {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, errAPI(newError(errCodeAPIParamInvalid, "index")))
+		return 0, false
+	}
+	return index, true
+}
+
+// validateFeedRequest checks the channel and frequency of a feed request, responding with an error and returning false if either is invalid
+func validateFeedRequest(w http.ResponseWriter, r *http.Request, guildID string, request *APIFeedRequest) bool {
+	if request.ChannelID != nil {
+		if err := validateSettingChannel(guildID, *request.ChannelID); err != nil || *request.ChannelID == "" {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, errAPI(newError(errCodeAPIParamInvalid, "channelID")))
+			return false
+		}
+	}
+	if request.Frequency != nil && *request.Frequency < botData().BotOptions.FeedFrequency {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, errAPI(newError(errCodeFeedFrequency, botData().BotOptions.FeedFrequency)))
+		return false
+	}
+	return true
+}
+
+func v0GetGuildFeeds(w http.ResponseWriter, r *http.Request) {
+	guildID := chi.URLParam(r, "guildID")
+	unlock := v0LockGuild(guildID)
+	defer unlock()
+
+	feeds := make([]*APIFeed, 0)
+	if settings, exists := guildSettings[guildID]; exists {
+		for _, feed := range settings.Feeds {
+			feeds = append(feeds, newAPIFeed(feed))
+		}
+	}
+	render.JSON(w, r, feeds)
+}
+
+func v0PostGuildFeed(w http.ResponseWriter, r *http.Request) {
+	if feed, ok := v0AddFeed(w, r, chi.URLParam(r, "guildID")); ok {
+		render.Status(r, http.StatusCreated)
+		render.JSON(w, r, feed)
+	}
+}
+
+// v0AddFeed adds the feed in the request body to a guild, responding with an error and returning false if it can't be added
+func v0AddFeed(w http.ResponseWriter, r *http.Request, guildID string) (*APIFeed, bool) {
+	request := &APIFeedRequest{}
+	if !v0DecodeBody(w, r, request) {
+		return nil, false
+	}
+	if request.URL == "" {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, errAPI(newError(errCodeAPIParamMissing, "url")))
+		return nil, false
+	}
+	if _, err := url.ParseRequestURI(request.URL); err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, errAPI(newError(errCodeAPIParamInvalid, "url")))
+		return nil, false
+	}
+	if request.ChannelID == nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, errAPI(newError(errCodeAPIParamMissing, "channelID")))
+		return nil, false
+	}
+	if !validateFeedRequest(w, r, guildID, request) {
+		return nil, false
+	}
+	frequency := botData().BotOptions.FeedFrequency
+	if request.Frequency != nil {
+		frequency = *request.Frequency
+	}
+
+	unlock := v0LockGuild(guildID)
+	defer unlock()
+
+	if _, exists := guildSettings[guildID]; !exists {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, errAPI(newError(errCodeAPINotFound, "guildID", "settings")))
+		return nil, false
+	}
+	for _, feed := range guildSettings[guildID].Feeds {
+		if feed.FeedURL == request.URL {
+			render.Status(r, http.StatusConflict)
+			render.JSON(w, r, errAPI(newError(errCodeFeedExists, request.URL)))
+			return nil, false
+		}
+	}
+
+	if err := addFeed(guildID, *request.ChannelID, request.URL, frequency); err != nil {
+		render.Status(r, http.StatusBadGateway)
+		render.JSON(w, r, errAPI(wrapError(errCodeFeedFetchFailed, err, request.URL)))
+		return nil, false
+	}
+	stateSaveAll()
+
+	InfoAPI.With("guild", guildID, "user", apiPrincipal(r).UserID).Printf("Added feed %s", request.URL)
+	feeds := guildSettings[guildID].Feeds
+	return newAPIFeed(feeds[len(feeds)-1]), true
+}
+
+func v0PatchGuildFeed(w http.ResponseWriter, r *http.Request) {
+	guildID := chi.URLParam(r, "guildID")
+	request := &APIFeedRequest{}
+	if !v0DecodeBody(w, r, request) || !validateFeedRequest(w, r, guildID, request) {
+		return
+	}
+
+	unlock := v0LockGuild(guildID)
+	defer unlock()
+
+	if _, exists := guildSettings[guildID]; !exists {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, errAPI(newError(errCodeAPINotFound, "guildID", "settings")))
+		return
+	}
+	index, ok := v0FeedIndex(w, r, guildID)
+	if !ok {
+		return
+	}
+
+	feed := guildSettings[guildID].Feeds[index]
+	if request.ChannelID != nil {
+		feed.ChannelID = *request.ChannelID
+	}
+	if request.Frequency != nil {
+		feed.Frequency = *request.Frequency //Takes effect after the next check
+	}
+	stateSaveAll()
+
+	InfoAPI.With("guild", guildID, "user", apiPrincipal(r).UserID).Printf("Changed feed %s", feed.FeedURL)
+	render.JSON(w, r, newAPIFeed(feed))
+}
+
+func v0DeleteGuildFeed(w http.ResponseWriter, r *http.Request) {
+	guildID := chi.URLParam(r, "guildID")
+	unlock := v0LockGuild(guildID)
+	defer unlock()
+
+	if _, exists := guildSettings[guildID]; !exists {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, errAPI(newError(errCodeAPINotFound, "guildID", "settings")))
+		return
+	}
+	index, ok := v0FeedIndex(w, r, guildID)
+	if !ok {
+		return
+	}
+
+	feeds := guildSettings[guildID].Feeds
+	removed := feeds[index]
+	guildSettings[guildID].Feeds = append(feeds[:index], feeds[index+1:]...)
+	stateSaveAll()
+
+	InfoAPI.With("guild", guildID, "user", apiPrincipal(r).UserID).Printf("Removed feed %s", removed.FeedURL)
+	render.NoContent(w, r)
+}
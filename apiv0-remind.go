@@ -0,0 +1,68 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+)
+
+/*
+	Reminders are kept by the shard that owns the guild they were set in, so they're listed per guild.
+	Only the user that set a reminder can see or delete it, so each user sees their own reminders in the order they'll be sent.
+*/
+
+// v0UserReminders returns a user's reminders in a guild in the order they'll be sent
+func v0UserReminders(guildID, userID string) []RemindEntry {
+	remindEntriesLock.RLock()
+	defer remindEntriesLock.RUnlock()
+
+	reminders := make([]RemindEntry, 0)
+	for _, entry := range remindEntries {
+		if entry.GuildID == guildID && entry.UserID == userID {
+			reminders = append(reminders, entry)
+		}
+	}
+	sort.SliceStable(reminders, func(i, j int) bool { return reminders[i].When.Before(reminders[j].When) })
+	return reminders
+}
+
+func v0GetGuildReminders(w http.ResponseWriter, r *http.Request) {
+	guildID := chi.URLParam(r, "guildID")
+	unlock := v0LockGuild(guildID)
+	defer unlock()
+
+	render.JSON(w, r, v0UserReminders(guildID, apiPrincipal(r).UserID))
+}
+
+func v0DeleteGuildReminder(w http.ResponseWriter, r *http.Request) {
+	guildID := chi.URLParam(r, "guildID")
+	userID := apiPrincipal(r).UserID
+	unlock := v0LockGuild(guildID)
+	defer unlock()
+
+	reminders := v0UserReminders(guildID, userID)
+	index, err := strconv.Atoi(chi.URLParam(r, "index"))
+	if err != nil || index < 0 || index >= len(reminders) {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, errAPI(newError(errCodeAPIParamInvalid, "index")))
+		return
+	}
+
+	//The reminder's timer checks that it still exists before sending it, so removing it is enough
+	removed := reminders[index]
+	remindEntriesLock.Lock()
+	for i, entry := range remindEntries {
+		if entry.GuildID == guildID && entry.UserID == userID && entry.Message == removed.Message && entry.When.Equal(removed.When) {
+			remindEntries = append(remindEntries[:i], remindEntries[i+1:]...)
+			break
+		}
+	}
+	remindEntriesLock.Unlock()
+	stateSaveAll()
+
+	InfoAPI.With("guild", guildID, "user", userID).Println("Deleted a reminder")
+	render.NoContent(w, r)
+}
@@ -0,0 +1,457 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+)
+
+/*
+	The voice endpoints follow the same rules as the voice commands.
+	Any member of the guild can see what's playing and manage the queue, while playing, skipping, pausing, resuming and stopping requires being in the bot's voice channel.
+*/
+
+// APIVoiceStatus holds the state of a guild's voice session
+type APIVoiceStatus struct {
+	Connected     bool                `json:"connected"`               //Whether or not the bot is in a voice channel
+	ChannelID     string              `json:"channelID,omitempty"`     //The voice channel the bot is in
+	TextChannelID string              `json:"textChannelID,omitempty"` //The channel voice messages are sent to
+	Repeat        string              `json:"repeat"`                  //One of none, queue or nowplaying
+	Shuffle       bool                `json:"shuffle"`                 //Whether or not the queue plays in a random order
+	NowPlaying    *APIVoiceNowPlaying `json:"nowPlaying"`              //What's playing, if anything
+	QueueLength   int                 `json:"queueLength"`             //How many entries are waiting in the queue
+}
+
+// APIVoiceNowPlaying holds what's playing in a guild's voice session
+type APIVoiceNowPlaying struct {
+	Entry    *APIVoiceEntry `json:"entry"`
+	Position float64        `json:"position"` //How far into the entry playback is, in seconds
+	Paused   bool           `json:"paused"`
+}
+
+// APIVoiceEntry holds a queue entry
+type APIVoiceEntry struct {
+	Title        string            `json:"title"`
+	Artists      []*APIVoiceArtist `json:"artists"`
+	URL          string            `json:"url"`
+	ArtworkURL   string            `json:"artworkURL,omitempty"`
+	ThumbnailURL string            `json:"thumbnailURL,omitempty"`
+	Duration     float64           `json:"duration"` //In seconds
+	Service      string            `json:"service"`
+	ServiceColor int               `json:"serviceColor"`
+	RequesterID  string            `json:"requesterID,omitempty"`
+}
+
+// APIVoiceArtist holds an artist of a queue entry
+type APIVoiceArtist struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// APIVoiceQueueRequest holds a request to add an entry to the queue
+type APIVoiceQueueRequest struct {
+	URL           string `json:"url"`
+	TextChannelID string `json:"textChannelID,omitempty"` //The channel voice messages are sent to, defaulting to the channel already in use or the guild's system channel
+}
+
+// APIVoiceMoveRequest holds a request to move a queue entry
+type APIVoiceMoveRequest struct {
+	To *int `json:"to"` //The index to move the entry to
+}
+
+// APIVoiceRepeatRequest holds a request to change the repeat level
+type APIVoiceRepeatRequest struct {
+	Repeat string `json:"repeat"`
+}
+
+// APIVoiceShuffleRequest holds a request to change whether the queue is shuffled
+type APIVoiceShuffleRequest struct {
+	Shuffle *bool `json:"shuffle"`
+}
+
+var (
+	//The HTTP status to respond with for each voice error, where any other error is a 400 if it's the user's fault or a 500 otherwise
+	v0VoiceErrorStatus = map[string]int{
+		errCodeVoiceUserNotInChannel:   http.StatusForbidden,
+		errCodeVoiceUserWrongChannel:   http.StatusForbidden,
+		errCodeVoiceServiceDisabled:    http.StatusForbidden,
+		errCodeVoiceBotNotInChannel:    http.StatusConflict,
+		errVoiceNotStreaming.Code:      http.StatusConflict,
+		errVoicePausedAlready.Code:     http.StatusConflict,
+		errVoicePlayingAlready.Code:    http.StatusConflict,
+		errVoicePlayMuted.Code:         http.StatusConflict,
+		errVoiceJoinChannel.Code:       http.StatusBadGateway,
+		errVoiceJoinChangeChannel.Code: http.StatusBadGateway,
+	}
+)
+
+// newAPIVoiceEntry returns a queue entry as the API shows it
+func newAPIVoiceEntry(entry *QueueEntry) *APIVoiceEntry {
+	apiEntry := &APIVoiceEntry{
+		Artists:      make([]*APIVoiceArtist, 0),
+		Service:      entry.ServiceName,
+		ServiceColor: entry.ServiceColor,
+	}
+	if entry.Metadata != nil {
+		apiEntry.Title = entry.Metadata.Title
+		apiEntry.URL = entry.Metadata.DisplayURL
+		apiEntry.ArtworkURL = entry.Metadata.ArtworkURL
+		apiEntry.ThumbnailURL = entry.Metadata.ThumbnailURL
+		apiEntry.Duration = entry.Metadata.Duration
+		for _, artist := range entry.Metadata.Artists {
+			apiEntry.Artists = append(apiEntry.Artists, &APIVoiceArtist{Name: artist.Name, URL: artist.URL})
+		}
+	}
+	if entry.Requester != nil {
+		apiEntry.RequesterID = entry.Requester.ID
+	}
+	return apiEntry
+}
+
+// v0VoiceNowPlaying returns what's playing in a voice session, or nil if nothing is
+func v0VoiceNowPlaying(voice *Voice) *APIVoiceNowPlaying {
+	if !voice.IsStreaming() {
+		return nil
+	}
+
+	voice.Lock()
+	defer voice.Unlock()
+
+	if voice.NowPlaying == nil || voice.NowPlaying.Entry == nil {
+		return nil
+	}
+	nowPlaying := &APIVoiceNowPlaying{
+		Entry:    newAPIVoiceEntry(voice.NowPlaying.Entry),
+		Position: voice.NowPlaying.Position.Seconds(),
+	}
+	if voice.StreamingSession != nil {
+		nowPlaying.Paused = voice.StreamingSession.Paused()
+	}
+	return nowPlaying
+}
+
+// v0VoiceStatus returns the state of a voice session
+func v0VoiceStatus(voice *Voice) *APIVoiceStatus {
+	status := &APIVoiceStatus{
+		Connected:     voice.IsConnected(),
+		TextChannelID: voice.TextChannelID,
+		Repeat:        voice.RepeatLevel.String(),
+		Shuffle:       voice.Shuffle,
+		NowPlaying:    v0VoiceNowPlaying(voice),
+		QueueLength:   len(voice.Entries),
+	}
+	if status.Connected {
+		status.ChannelID = voice.VoiceConnection.ChannelID
+	}
+	return status
+}
+
+// v0LockGuild locks a guild's data the same way a command does while it runs, returning the function to unlock it
+func v0LockGuild(guildID string) func() {
+	if data, exists := guildData[guildID]; exists {
+		data.Lock()
+		return data.Unlock
+	}
+	return func() {}
+}
+
+// v0Voice locks a guild and returns its voice session, which must be followed by a call to the returned unlock function
+func v0Voice(guildID string) (*Voice, func()) {
+	unlock := v0LockGuild(guildID)
+	VoiceInit(guildID)
+	return voiceData[guildID], unlock
+}
+
+// v0VoiceTextChannel returns the text channel voice messages should be sent to, responding with an error and returning false if the requested channel isn't in the guild
+func v0VoiceTextChannel(w http.ResponseWriter, r *http.Request, guildID string, voice *Voice, channelID string) (string, bool) {
+	if channelID == "" {
+		if voice.TextChannelID != "" {
+			return voice.TextChannelID, true
+		}
+		guild, err := botData().DiscordSession.State.Guild(guildID)
+		if err != nil {
+			return "", true
+		}
+		return guild.SystemChannelID, true
+	}
+
+	channel, err := botData().DiscordSession.State.Channel(channelID)
+	if err != nil || channel.GuildID != guildID || channel.Type != discordgo.ChannelTypeGuildText {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, errAPI(newError(errCodeAPIParamInvalid, "textChannelID")))
+		return "", false
+	}
+	return channelID, true
+}
+
+// renderVoiceError responds with a voice error
+func renderVoiceError(w http.ResponseWriter, r *http.Request, err error) {
+	clinetErr := asClinetError(err)
+	status, exists := v0VoiceErrorStatus[clinetErr.Code]
+	if !exists {
+		status = http.StatusInternalServerError
+		if clinetErr.Severity() == SeverityUser {
+			status = http.StatusBadRequest
+		}
+	}
+	render.Status(r, status)
+	render.JSON(w, r, errAPI(clinetErr))
+}
+
+// v0VoiceQueueIndex returns the queue entry index given in the URL, responding with an error and returning false if it isn't in the queue
+func v0VoiceQueueIndex(w http.ResponseWriter, r *http.Request, voice *Voice) (int, bool) {
+	index, err := strconv.Atoi(chi.URLParam(r, "index"))
+	if err != nil || index < 0 || index >= len(voice.Entries) {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, errAPI(newError(errCodeAPIParamInvalid, "index")))
+		return 0, false
+	}
+	return index, true
+}
+
+// v0DecodeBody decodes a JSON request body, responding with an error and returning false if it's invalid
+func v0DecodeBody(w http.ResponseWriter, r *http.Request, body interface{}) bool {
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, v0MaxBodySize)).Decode(body); err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, errAPI(wrapError(errCodeAPIParamInvalid, err, "body")))
+		return false
+	}
+	return true
+}
+
+func v0GetVoice(w http.ResponseWriter, r *http.Request) {
+	voice, unlock := v0Voice(chi.URLParam(r, "guildID"))
+	defer unlock()
+
+	render.JSON(w, r, v0VoiceStatus(voice))
+}
+
+func v0GetVoiceNowPlaying(w http.ResponseWriter, r *http.Request) {
+	voice, unlock := v0Voice(chi.URLParam(r, "guildID"))
+	defer unlock()
+
+	nowPlaying := v0VoiceNowPlaying(voice)
+	if nowPlaying == nil {
+		renderVoiceError(w, r, errVoiceNotStreaming)
+		return
+	}
+	render.JSON(w, r, nowPlaying)
+}
+
+func v0GetVoiceQueue(w http.ResponseWriter, r *http.Request) {
+	voice, unlock := v0Voice(chi.URLParam(r, "guildID"))
+	defer unlock()
+
+	entries := make([]*APIVoiceEntry, 0, len(voice.Entries))
+	for _, entry := range voice.Entries {
+		entries = append(entries, newAPIVoiceEntry(entry))
+	}
+	render.JSON(w, r, entries)
+}
+
+// v0PostVoiceQueue plays a URL, or adds it to the queue if something is already playing, joining the user's voice channel if needed
+func v0PostVoiceQueue(w http.ResponseWriter, r *http.Request) {
+	guildID := chi.URLParam(r, "guildID")
+	userID := apiPrincipal(r).UserID
+
+	request := &APIVoiceQueueRequest{}
+	if !v0DecodeBody(w, r, request) {
+		return
+	}
+	if request.URL == "" {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, errAPI(newError(errCodeAPIParamMissing, "url")))
+		return
+	}
+	if _, err := url.ParseRequestURI(request.URL); err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, errAPI(newError(errCodeAPIParamInvalid, "url")))
+		return
+	}
+
+	voice, unlock := v0Voice(guildID)
+	defer unlock()
+
+	userChannel := userVoiceChannel(guildID, userID)
+	if userChannel == "" {
+		renderVoiceError(w, r, newError(errCodeVoiceUserNotInChannel, "play"))
+		return
+	}
+	if voice.IsConnected() && userChannel != voice.VoiceConnection.ChannelID {
+		renderVoiceError(w, r, newError(errCodeVoiceUserWrongChannel, botData().BotName, "play"))
+		return
+	}
+
+	queueEntry, err := createQueueEntry(request.URL)
+	if err != nil {
+		renderVoiceError(w, r, wrapError(errCodeVoiceNoService, err))
+		return
+	}
+	if !serviceEnabled(guildID, queueEntry.ServiceName) {
+		renderVoiceError(w, r, newError(errCodeVoiceServiceDisabled, queueEntry.ServiceName))
+		return
+	}
+	member, err := botData().DiscordSession.State.Member(guildID, userID)
+	if err != nil {
+		if member, err = botData().DiscordSession.GuildMember(guildID, userID); err != nil {
+			renderVoiceError(w, r, wrapError(errCodeVoiceNoRequester, err))
+			return
+		}
+	}
+	queueEntry.Requester = member.User
+
+	textChannelID, ok := v0VoiceTextChannel(w, r, guildID, voice, request.TextChannelID)
+	if !ok {
+		return
+	}
+
+	if !voice.IsConnected() {
+		if err := voice.Connect(guildID, userChannel); err != nil {
+			renderVoiceError(w, r, err)
+			return
+		}
+	}
+
+	voice.SetTextChannel(textChannelID)
+
+	InfoAPI.With("guild", guildID, "user", userID).Printf("Playing [%s] from %s", queueEntry.Metadata.Title, queueEntry.Metadata.DisplayURL)
+	go voice.Play(queueEntry, true)
+
+	render.Status(r, http.StatusAccepted)
+	render.JSON(w, r, newAPIVoiceEntry(queueEntry))
+}
+
+func v0DeleteVoiceQueueEntry(w http.ResponseWriter, r *http.Request) {
+	voice, unlock := v0Voice(chi.URLParam(r, "guildID"))
+	defer unlock()
+
+	index, ok := v0VoiceQueueIndex(w, r, voice)
+	if !ok {
+		return
+	}
+	voice.QueueRemove(index)
+
+	render.JSON(w, r, v0VoiceStatus(voice))
+}
+
+func v0PostVoiceQueueMove(w http.ResponseWriter, r *http.Request) {
+	request := &APIVoiceMoveRequest{}
+	if !v0DecodeBody(w, r, request) {
+		return
+	}
+
+	voice, unlock := v0Voice(chi.URLParam(r, "guildID"))
+	defer unlock()
+
+	index, ok := v0VoiceQueueIndex(w, r, voice)
+	if !ok {
+		return
+	}
+	if request.To == nil || *request.To < 0 || *request.To >= len(voice.Entries) {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, errAPI(newError(errCodeAPIParamInvalid, "to")))
+		return
+	}
+
+	entry := voice.Entries[index]
+	voice.Entries = append(voice.Entries[:index], voice.Entries[index+1:]...)
+	voice.Entries = append(voice.Entries[:*request.To], append([]*QueueEntry{entry}, voice.Entries[*request.To:]...)...)
+	voice.queueChanged()
+
+	render.JSON(w, r, v0VoiceStatus(voice))
+}
+
+// v0VoiceControl runs a playback control for the user making the request, as long as they're in the bot's voice channel
+func v0VoiceControl(action string, control func(voice *Voice) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		guildID := chi.URLParam(r, "guildID")
+		userID := apiPrincipal(r).UserID
+
+		voice, unlock := v0Voice(guildID)
+		defer unlock()
+
+		if err := voice.CanControl(guildID, userID, action); err != nil {
+			renderVoiceError(w, r, err)
+			return
+		}
+		if err := control(voice); err != nil {
+			renderVoiceError(w, r, err)
+			return
+		}
+
+		InfoAPI.With("guild", guildID, "user", userID).Printf("Ran %s on the voice session", action)
+		render.JSON(w, r, v0VoiceStatus(voice))
+	}
+}
+
+func v0VoiceSkip(voice *Voice) error {
+	if !voice.IsStreaming() {
+		return errVoiceNotStreaming
+	}
+	if err := voice.Skip(); err != nil {
+		return wrapError(errCodeVoiceSkipFailed, err)
+	}
+	return nil
+}
+
+func v0VoicePause(voice *Voice) error {
+	_, err := voice.Pause()
+	return err
+}
+
+func v0VoiceResume(voice *Voice) error {
+	_, err := voice.Resume()
+	return err
+}
+
+func v0VoiceStop(voice *Voice) error {
+	if !voice.IsStreaming() {
+		return errVoiceNotStreaming
+	}
+	if err := voice.Stop(); err != nil {
+		return wrapError(errCodeVoiceStopFailed, err)
+	}
+	return nil
+}
+
+func v0PutVoiceRepeat(w http.ResponseWriter, r *http.Request) {
+	request := &APIVoiceRepeatRequest{}
+	if !v0DecodeBody(w, r, request) {
+		return
+	}
+	level, ok := parseRepeatLevel(request.Repeat)
+	if !ok {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, errAPI(newError(errCodeAPIParamInvalid, "repeat")))
+		return
+	}
+
+	voice, unlock := v0Voice(chi.URLParam(r, "guildID"))
+	defer unlock()
+
+	voice.RepeatLevel = level
+	render.JSON(w, r, v0VoiceStatus(voice))
+}
+
+func v0PutVoiceShuffle(w http.ResponseWriter, r *http.Request) {
+	request := &APIVoiceShuffleRequest{}
+	if !v0DecodeBody(w, r, request) {
+		return
+	}
+	if request.Shuffle == nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, errAPI(newError(errCodeAPIParamMissing, "shuffle")))
+		return
+	}
+
+	voice, unlock := v0Voice(chi.URLParam(r, "guildID"))
+	defer unlock()
+
+	voice.Shuffle = *request.Shuffle
+	render.JSON(w, r, v0VoiceStatus(voice))
+}
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+)
+
+/*
+	Webhook posts are authenticated by a webhook key of the guild, sent in the X-Clinet-Webhook-Key header, instead of an API token.
+	Managing the channels and keys requires being able to manage the guild, like every other guild setting.
+*/
+
+const (
+	webhookKeyHeader = "X-Clinet-Webhook-Key"
+
+	//The longest message Discord accepts
+	webhookMaxContentLength = 2000
+)
+
+// APIWebhookPost holds a message to post through a webhook
+type APIWebhookPost struct {
+	Channel string                  `json:"channel"` //The alias of the channel to post to
+	Content string                  `json:"content"`
+	Embed   *discordgo.MessageEmbed `json:"embed"` //The same as the responseEmbed of custom responses
+}
+
+// APIWebhookPosted holds a message posted through a webhook
+type APIWebhookPosted struct {
+	ChannelID string `json:"channelID"`
+	MessageID string `json:"messageID"`
+}
+
+// APIWebhooks holds the webhook channels and keys of a guild
+type APIWebhooks struct {
+	Channels map[string]string `json:"channels"` //Where key = alias and value = channel ID
+	Keys     []*WebhookKey     `json:"keys"`
+}
+
+// APIWebhookKeyRequest holds a request to create a webhook key
+type APIWebhookKeyRequest struct {
+	Name      string `json:"name"`
+	RateLimit int    `json:"rateLimit"` //How many messages the key may post per minute, 0 uses the configured limit
+}
+
+// APINewWebhookKey holds a newly created webhook key, which is only ever shown once
+type APINewWebhookKey struct {
+	Key  string      `json:"key"`
+	Info *WebhookKey `json:"info"`
+}
+
+// APIWebhookChannelRequest holds a request to point a channel alias at a channel
+type APIWebhookChannelRequest struct {
+	ChannelID string `json:"channelID"`
+}
+
+// v0Webhooks returns the webhook channels and keys of a guild as the API shows them
+func v0Webhooks(guildID string) *APIWebhooks {
+	webhooks := &APIWebhooks{Channels: make(map[string]string), Keys: make([]*WebhookKey, 0)}
+	if settings, exists := guildSettings[guildID]; exists {
+		for alias, channelID := range settings.WebhookChannels {
+			webhooks.Channels[alias] = channelID
+		}
+		for _, key := range settings.WebhookKeys {
+			webhooks.Keys = append(webhooks.Keys, key.redacted())
+		}
+	}
+	return webhooks
+}
+
+// v0WebhookAlias returns the channel alias given in the URL, responding with an error and returning false if it isn't valid
+func v0WebhookAlias(w http.ResponseWriter, r *http.Request) (string, bool) {
+	alias := chi.URLParam(r, "alias")
+	if !webhookAliasRegexp.MatchString(alias) {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, errAPI(newError(errCodeWebhookAliasInvalid)))
+		return "", false
+	}
+	return alias, true
+}
+
+// v0RequireGuildSettings responds with an error and returns false if the guild has no settings
+func v0RequireGuildSettings(w http.ResponseWriter, r *http.Request, guildID string) bool {
+	if _, exists := guildSettings[guildID]; !exists {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, errAPI(newError(errCodeAPINotFound, "guildID", "settings")))
+		return false
+	}
+	return true
+}
+
+func v0PostGuildWebhook(w http.ResponseWriter, r *http.Request) {
+	guildID := chi.URLParam(r, "guildID")
+	post := &APIWebhookPost{}
+	if !v0DecodeBody(w, r, post) {
+		return
+	}
+	if post.Content == "" && post.Embed == nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, errAPI(newError(errCodeWebhookEmpty)))
+		return
+	}
+	if utf8.RuneCountInString(post.Content) > webhookMaxContentLength {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, errAPI(newError(errCodeWebhookTooLong, webhookMaxContentLength)))
+		return
+	}
+
+	unlock := v0LockGuild(guildID)
+	key := lookupWebhookKey(guildID, r.Header.Get(webhookKeyHeader))
+	if key == nil {
+		unlock()
+		render.Status(r, http.StatusUnauthorized)
+		render.JSON(w, r, errAPI(newError(errCodeWebhookKeyInvalid)))
+		return
+	}
+	channelID, exists := guildSettings[guildID].WebhookChannels[post.Channel]
+	if !exists {
+		unlock()
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, errAPI(newError(errCodeWebhookChannelUnknown, post.Channel)))
+		return
+	}
+	if retryAfter, allowed := allowWebhookPost(key); !allowed {
+		limit := key.Limit()
+		unlock()
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		render.Status(r, http.StatusTooManyRequests)
+		render.JSON(w, r, errAPI(newError(errCodeWebhookRateLimited, limit)))
+		return
+	}
+	key.LastUsed = time.Now()
+	unlock() //Don't hold up commands in the guild while Discord responds
+
+	message, err := botData().DiscordSession.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Content: post.Content,
+		Embed:   post.Embed,
+		AllowedMentions: &discordgo.MessageAllowedMentions{ //Keys may mention users and roles, but never everyone
+			Parse: []discordgo.AllowedMentionType{discordgo.AllowedMentionTypeUsers, discordgo.AllowedMentionTypeRoles},
+		},
+	})
+	if err != nil {
+		render.Status(r, http.StatusBadGateway)
+		render.JSON(w, r, errAPI(wrapError(errCodeWebhookPostFailed, err)))
+		return
+	}
+
+	InfoAPI.With("guild", guildID, "key", key.ID).Printf("Webhook posted to %s", post.Channel)
+	logWebhookPost(guildID, key, message)
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, &APIWebhookPosted{ChannelID: message.ChannelID, MessageID: message.ID})
+}
+
+func v0GetGuildWebhooks(w http.ResponseWriter, r *http.Request) {
+	guildID := chi.URLParam(r, "guildID")
+	unlock := v0LockGuild(guildID)
+	defer unlock()
+
+	render.JSON(w, r, v0Webhooks(guildID))
+}
+
+func v0PostGuildWebhookKey(w http.ResponseWriter, r *http.Request) {
+	guildID := chi.URLParam(r, "guildID")
+	request := &APIWebhookKeyRequest{}
+	if !v0DecodeBody(w, r, request) {
+		return
+	}
+	request.Name = strings.TrimSpace(request.Name)
+	if request.Name == "" {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, errAPI(newError(errCodeAPIParamMissing, "name")))
+		return
+	}
+	if request.RateLimit < 0 {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, errAPI(newError(errCodeAPIParamInvalid, "rateLimit")))
+		return
+	}
+
+	unlock := v0LockGuild(guildID)
+	defer unlock()
+
+	if !v0RequireGuildSettings(w, r, guildID) {
+		return
+	}
+	key, info, err := issueWebhookKey(guildID, request.Name, request.RateLimit)
+	if err != nil {
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, errAPI(err))
+		return
+	}
+	stateSaveAll() //The key is only shown once, so it mustn't be lost before the next save
+
+	InfoAPI.With("guild", guildID, "user", apiPrincipal(r).UserID, "key", info.ID).Println("Created a webhook key")
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, &APINewWebhookKey{Key: key, Info: info.redacted()})
+}
+
+func v0DeleteGuildWebhookKey(w http.ResponseWriter, r *http.Request) {
+	guildID := chi.URLParam(r, "guildID")
+	keyID := chi.URLParam(r, "keyID")
+	unlock := v0LockGuild(guildID)
+	defer unlock()
+
+	if !v0RequireGuildSettings(w, r, guildID) {
+		return
+	}
+	if !revokeWebhookKey(guildID, keyID) {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, errAPI(newError(errCodeAPIParamInvalid, "keyID")))
+		return
+	}
+	stateSaveAll()
+
+	InfoAPI.With("guild", guildID, "user", apiPrincipal(r).UserID, "key", keyID).Println("Revoked a webhook key")
+	render.NoContent(w, r)
+}
+
+func v0PutGuildWebhookChannel(w http.ResponseWriter, r *http.Request) {
+	guildID := chi.URLParam(r, "guildID")
+	alias, ok := v0WebhookAlias(w, r)
+	if !ok {
+		return
+	}
+	request := &APIWebhookChannelRequest{}
+	if !v0DecodeBody(w, r, request) {
+		return
+	}
+	if err := validateSettingChannel(guildID, request.ChannelID); err != nil || request.ChannelID == "" {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, errAPI(newError(errCodeAPIParamInvalid, "channelID")))
+		return
+	}
+
+	unlock := v0LockGuild(guildID)
+	defer unlock()
+
+	if !v0RequireGuildSettings(w, r, guildID) {
+		return
+	}
+	if guildSettings[guildID].WebhookChannels == nil {
+		guildSettings[guildID].WebhookChannels = make(map[string]string)
+	}
+	guildSettings[guildID].WebhookChannels[alias] = request.ChannelID
+	stateSaveAll()
+
+	InfoAPI.With("guild", guildID, "user", apiPrincipal(r).UserID).Printf("Pointed webhook channel %s to %s", alias, request.ChannelID)
+	render.JSON(w, r, v0Webhooks(guildID))
+}
+
+func v0DeleteGuildWebhookChannel(w http.ResponseWriter, r *http.Request) {
+	guildID := chi.URLParam(r, "guildID")
+	alias, ok := v0WebhookAlias(w, r)
+	if !ok {
+		return
+	}
+	unlock := v0LockGuild(guildID)
+	defer unlock()
+
+	if !v0RequireGuildSettings(w, r, guildID) {
+		return
+	}
+	if _, exists := guildSettings[guildID].WebhookChannels[alias]; !exists {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, errAPI(newError(errCodeWebhookChannelUnknown, alias)))
+		return
+	}
+	delete(guildSettings[guildID].WebhookChannels, alias)
+	stateSaveAll()
+
+	InfoAPI.With("guild", guildID, "user", apiPrincipal(r).UserID).Printf("Removed webhook channel %s", alias)
+	render.NoContent(w, r)
+}
@@ -1,101 +1,289 @@
 package main
 
 import (
+	"crypto/subtle"
+	"encoding/json"
+	"io/ioutil"
+	"math"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/go-chi/chi"
 	"github.com/go-chi/render"
 )
 
-func APIv0() *chi.Mux {
-	router := chi.NewRouter()
+const (
+	//The largest request body accepted
+	v0MaxBodySize = 1 << 20
+)
 
-	//Layout endpoint
-	router.Get("/layout/main", v0GetLayoutMain)            //Retrieves the main layout
-	router.Get("/layout/guild", v0GetLayoutGuild)          //Retrieves the guild layout
-	router.Get("/layout/guild/role", v0GetLayoutGuildRole) //Retrieves the guild roles layout
-	router.Get("/layout/user", v0GetLayoutUser)            //Retrieves the user layout
+// APIUserGuild holds a guild a user shares with the bot
+type APIUserGuild struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	IconURL string `json:"iconURL,omitempty"`
+	Manager bool   `json:"manager"` //Whether or not the user can manage the guild, and so see and change its settings
+}
 
-	//Guild endpoint
-	router.Get("/guild/{guildID}", v0GetGuild)                           //Retrieves info about a particular guild
-	router.Get("/guild/{guildID}/settings", v0GetGuildSettings)          //Retrieves all settings and their values for a particular guild
-	router.Put("/guild/{guildID}/settings/{setting}", v0PutGuildSetting) //Sets a new value to a particular guild setting
+// APIGuildChannel holds a channel in a guild
+type APIGuildChannel struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Type     string `json:"type"` //One of text, voice, category, news or other
+	Position int    `json:"position"`
+	ParentID string `json:"parentID,omitempty"` //The category the channel is in, if any
+}
 
-	//Guild starboard endpoint
-	router.Get("/guild/{guildID}/starboard", v0GetGuildStarboard) //Retrieves all starboard settings and entries
+var (
+	//The name of each type of channel shown through the API, where any other type is shown as other
+	apiChannelTypes = map[discordgo.ChannelType]string{
+		discordgo.ChannelTypeGuildText:     "text",
+		discordgo.ChannelTypeGuildVoice:    "voice",
+		discordgo.ChannelTypeGuildCategory: "category",
+		discordgo.ChannelTypeGuildNews:     "news",
+	}
+)
+
+func APIv0() *chi.Mux {
+	router := chi.NewRouter()
 
-	//Guild invite link generation endpoint
-	router.Get("/guild/{guildID}/invite/{key}", v0GetGuildInvite) //Retrieves a new one-user invite link for the specified guild
+	//Layout endpoint
+	router.Get("/layout/main", v0GetLayoutMain)                      //Retrieves every layout
+	router.Get("/layout/guild", v0GetLayoutGuild)                    //Retrieves the guild layout
+	router.Get("/layout/guild/role", v0GetLayoutGuildRole)           //Retrieves the guild roles layout
+	router.Get("/layout/guild/starboard", v0GetLayoutGuildStarboard) //Retrieves the guild starboard layout
+	router.Get("/layout/user", v0GetLayoutUser)                      //Retrieves the user layout
+
+	router.Route("/guild/{guildID}", func(r chi.Router) {
+		r.Use(shardProxy) //Guild state only lives on the shard that owns the guild
+
+		//Guild invite link generation endpoint, authenticated by the guild's own invite key instead
+		r.Get("/invite/{key}", v0GetGuildInvite) //Retrieves a new one-user invite link for the specified guild
+
+		//Guild webhook endpoint, authenticated by one of the guild's webhook keys instead
+		r.Post("/webhook", v0PostGuildWebhook) //Posts a message to one of the webhook channels
+
+		//Guild voice endpoint, usable by any member of the guild like the voice commands
+		r.Route("/voice", func(r chi.Router) {
+			r.Use(apiRequireGuildMember)
+
+			r.Get("/", v0GetVoice)                                     //Retrieves the state of the voice session
+			r.Get("/nowplaying", v0GetVoiceNowPlaying)                 //Retrieves what's playing and how far into it playback is
+			r.Get("/queue", v0GetVoiceQueue)                           //Retrieves every entry in the queue
+			r.Post("/queue", v0PostVoiceQueue)                         //Plays a URL or adds it to the queue
+			r.Delete("/queue/{index}", v0DeleteVoiceQueueEntry)        //Removes an entry from the queue
+			r.Post("/queue/{index}/move", v0PostVoiceQueueMove)        //Moves an entry to another place in the queue
+			r.Post("/skip", v0VoiceControl("skip", v0VoiceSkip))       //Skips to the next entry in the queue
+			r.Post("/pause", v0VoiceControl("pause", v0VoicePause))    //Pauses playback
+			r.Post("/resume", v0VoiceControl("resume", v0VoiceResume)) //Resumes playback
+			r.Post("/stop", v0VoiceControl("stop", v0VoiceStop))       //Stops playback
+			r.Put("/repeat", v0PutVoiceRepeat)                         //Sets the repeat level
+			r.Put("/shuffle", v0PutVoiceShuffle)                       //Sets whether the queue is played in a random order
+		})
+
+		//Guild reminder endpoint, where each member only sees their own reminders
+		r.Route("/reminders", func(r chi.Router) {
+			r.Use(apiRequireGuildMember)
+
+			r.Get("/", v0GetGuildReminders)             //Retrieves the user's reminders in the guild
+			r.Delete("/{index}", v0DeleteGuildReminder) //Deletes one of the user's reminders
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(apiRequireGuildManager) //Only those who can manage the guild may see or change its data
+
+			//Guild endpoint
+			r.Get("/", v0GetGuild)                          //Retrieves info about a particular guild
+			r.Get("/settings", v0GetGuildSettings)          //Retrieves all settings and their values for a particular guild
+			r.Patch("/settings", v0PatchGuildSettings)      //Sets new values to any of the guild settings
+			r.Put("/settings/{setting}", v0PutGuildSetting) //Sets a new value to a particular guild setting
+			r.Get("/channels", v0GetGuildChannels)          //Retrieves every channel in the guild, for picking channels in settings
+
+			//Guild starboard endpoint
+			r.Get("/starboard", v0GetGuildStarboard) //Retrieves all starboard settings and entries
+
+			//Guild feed endpoint
+			r.Get("/feeds", v0GetGuildFeeds)              //Retrieves every feed
+			r.Post("/feeds", v0PostGuildFeed)             //Adds a feed
+			r.Patch("/feeds/{index}", v0PatchGuildFeed)   //Changes the channel or frequency of a feed
+			r.Delete("/feeds/{index}", v0DeleteGuildFeed) //Removes a feed
+
+			//Guild webhook management endpoint
+			r.Get("/webhooks", v0GetGuildWebhooks)                              //Retrieves every webhook channel and key
+			r.Post("/webhooks/keys", v0PostGuildWebhookKey)                     //Creates a webhook key
+			r.Delete("/webhooks/keys/{keyID}", v0DeleteGuildWebhookKey)         //Revokes a webhook key
+			r.Put("/webhooks/channels/{alias}", v0PutGuildWebhookChannel)       //Points a channel alias at a channel
+			r.Delete("/webhooks/channels/{alias}", v0DeleteGuildWebhookChannel) //Removes a channel alias
+		})
+	})
 
 	//User endpoint
-	router.Get("/user/{userID}", v0GetUser)                           //Retrieves info about a particular user
-	router.Get("/user/{userID}/settings", v0GetUserSettings)          //Retrieves all settings and their values for a particular user
-	router.Put("/user/{userID}/settings/{setting}", v0PutUserSetting) //Sets a new value to a particular user setting
+	router.Route("/user/{userID}", func(r chi.Router) {
+		r.Use(apiRequireUser) //Only the user may see or change their own data
+
+		r.Get("/", v0GetUser)                          //Retrieves info about a particular user
+		r.Get("/guilds", v0GetUserGuilds)              //Retrieves every guild the user shares with the bot
+		r.Get("/settings", v0GetUserSettings)          //Retrieves all settings and their values for a particular user
+		r.Patch("/settings", v0PatchUserSettings)      //Sets new values to any of the user settings
+		r.Put("/settings/{setting}", v0PutUserSetting) //Sets a new value to a particular user setting
+	})
 
 	return router
 }
 
 func v0GetLayoutMain(w http.ResponseWriter, r *http.Request) {
-	render.PlainText(w, r, "stub")
+	render.JSON(w, r, settingLayouts())
 }
 
 func v0GetLayoutGuild(w http.ResponseWriter, r *http.Request) {
-	render.PlainText(w, r, "stub")
+	render.JSON(w, r, guildSettingsLayout())
 }
 
 func v0GetLayoutGuildRole(w http.ResponseWriter, r *http.Request) {
-	render.PlainText(w, r, "stub")
+	render.JSON(w, r, guildRoleLayout())
+}
+
+func v0GetLayoutGuildStarboard(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, guildStarboardLayout())
 }
 
 func v0GetLayoutUser(w http.ResponseWriter, r *http.Request) {
-	render.PlainText(w, r, "stub")
+	render.JSON(w, r, userSettingsLayout())
 }
 
 func v0GetGuild(w http.ResponseWriter, r *http.Request) {
 	guildID := chi.URLParam(r, "guildID")
 	if guildID == "" {
-		render.JSON(w, r, errAPI("guildID must not be empty"))
+		render.JSON(w, r, errAPI(newError(errCodeAPIParamMissing, "guildID")))
 		return
 	}
 
-	guild, err := botData.DiscordSession.Guild(guildID)
+	guild, err := botData().DiscordSession.Guild(guildID)
 	if err != nil {
-		render.JSON(w, r, errAPI("guildID invalid"))
+		render.JSON(w, r, errAPI(newError(errCodeAPIParamInvalid, "guildID")))
 		return
 	}
 
 	render.JSON(w, r, guild)
 }
 
+func v0GetGuildChannels(w http.ResponseWriter, r *http.Request) {
+	guild, err := botData().DiscordSession.State.Guild(chi.URLParam(r, "guildID"))
+	if err != nil {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, errAPI(newError(errCodeAPINotFound, "guildID", "channels")))
+		return
+	}
+
+	botData().DiscordSession.State.RLock()
+	channels := make([]*APIGuildChannel, 0, len(guild.Channels))
+	for _, channel := range guild.Channels {
+		channelType, exists := apiChannelTypes[channel.Type]
+		if !exists {
+			channelType = "other"
+		}
+		channels = append(channels, &APIGuildChannel{ID: channel.ID, Name: channel.Name, Type: channelType, Position: channel.Position, ParentID: channel.ParentID})
+	}
+	botData().DiscordSession.State.RUnlock()
+
+	sort.Slice(channels, func(i, j int) bool { return channels[i].Position < channels[j].Position })
+	render.JSON(w, r, channels)
+}
+
 func v0GetGuildSettings(w http.ResponseWriter, r *http.Request) {
 	guildID := chi.URLParam(r, "guildID")
 	if guildID == "" {
-		render.JSON(w, r, errAPI("guildID must not be empty"))
+		render.JSON(w, r, errAPI(newError(errCodeAPIParamMissing, "guildID")))
 		return
 	}
 
 	if _, ok := guildSettings[guildID]; !ok {
-		render.JSON(w, r, errAPI("specified guildID has no settings"))
+		render.JSON(w, r, errAPI(newError(errCodeAPINotFound, "guildID", "settings")))
 		return
 	}
 
-	render.JSON(w, r, guildSettings[guildID])
+	render.JSON(w, r, redactGuildSettings(*guildSettings[guildID]))
+}
+
+// redactGuildSettings returns a copy of guild settings without any of their secrets, for responses
+func redactGuildSettings(settings GuildSettings) GuildSettings {
+	if settings.APIInviteKey != "" {
+		settings.APIInviteKey = redactedSecret
+	}
+	if settings.WebhookKeys != nil {
+		keys := make([]*WebhookKey, 0, len(settings.WebhookKeys))
+		for _, key := range settings.WebhookKeys {
+			keys = append(keys, key.redacted())
+		}
+		settings.WebhookKeys = keys
+	}
+	return settings
 }
 
 func v0PutGuildSetting(w http.ResponseWriter, r *http.Request) {
-	render.PlainText(w, r, "stub")
+	guildID := chi.URLParam(r, "guildID")
+	v0ChangeGuildSettings(w, r, guildID, func(settings *GuildSettings, rawValue json.RawMessage) []*SettingError {
+		return applySetting(settings, guildSettingRules, guildID, chi.URLParam(r, "setting"), rawValue)
+	})
+}
+
+func v0PatchGuildSettings(w http.ResponseWriter, r *http.Request) {
+	guildID := chi.URLParam(r, "guildID")
+	v0ChangeGuildSettings(w, r, guildID, func(settings *GuildSettings, rawChanges json.RawMessage) []*SettingError {
+		return applySettings(settings, guildSettingRules, guildID, rawChanges)
+	})
+}
+
+// v0ChangeGuildSettings applies the request body to a copy of a guild's settings, which only replaces them if every change was valid
+func v0ChangeGuildSettings(w http.ResponseWriter, r *http.Request, guildID string, change func(*GuildSettings, json.RawMessage) []*SettingError) {
+	if _, ok := guildSettings[guildID]; !ok {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, errAPI(newError(errCodeAPINotFound, "guildID", "settings")))
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, v0MaxBodySize))
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, errAPI(wrapError(errCodeAPIParamInvalid, err, "body")))
+		return
+	}
+
+	unlock := v0LockGuild(guildID) //Commands change guild settings while holding this lock
+	defer unlock()
+
+	settings := *guildSettings[guildID]
+	if errs := change(&settings, body); len(errs) > 0 {
+		renderSettingErrors(w, r, errs)
+		return
+	}
+	*guildSettings[guildID] = settings
+	stateSaveAll()
+
+	InfoAPI.With("guild", guildID, "user", apiPrincipal(r).UserID).Println("Changed guild settings")
+	render.JSON(w, r, redactGuildSettings(settings))
+}
+
+// renderSettingErrors responds with every rejected change to a setting
+func renderSettingErrors(w http.ResponseWriter, r *http.Request, errs []*SettingError) {
+	apiErr := errAPI(newError(errCodeAPISettingsInvalid))
+	apiErr.Fields = errs
+	render.Status(r, http.StatusUnprocessableEntity)
+	render.JSON(w, r, apiErr)
 }
 
 func v0GetGuildStarboard(w http.ResponseWriter, r *http.Request) {
 	guildID := chi.URLParam(r, "guildID")
 	if guildID == "" {
-		render.JSON(w, r, errAPI("guildID must not be empty"))
+		render.JSON(w, r, errAPI(newError(errCodeAPIParamMissing, "guildID")))
 		return
 	}
 
 	if _, ok := starboards[guildID]; !ok {
-		render.JSON(w, r, errAPI("specified guildID has no starboard data"))
+		render.JSON(w, r, errAPI(newError(errCodeAPINotFound, "guildID", "starboard data")))
 		return
 	}
 
@@ -105,23 +293,40 @@ func v0GetGuildStarboard(w http.ResponseWriter, r *http.Request) {
 func v0GetGuildInvite(w http.ResponseWriter, r *http.Request) {
 	guildID := chi.URLParam(r, "guildID")
 	if guildID == "" {
-		render.JSON(w, r, errAPI("guildID must not be empty"))
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, errAPI(newError(errCodeAPIParamMissing, "guildID")))
 		return
 	}
 
 	key := chi.URLParam(r, "key")
 	if key == "" {
-		render.JSON(w, r, errAPI("key must not be empty"))
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, errAPI(newError(errCodeAPIParamMissing, "key")))
 		return
 	}
 
-	if _, ok := guildSettings[guildID]; !ok {
-		render.JSON(w, r, errAPI("specified guildID has no settings"))
+	unlock := v0LockGuild(guildID)
+	settings, ok := guildSettings[guildID]
+	if !ok {
+		unlock()
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, errAPI(newError(errCodeAPINotFound, "guildID", "settings")))
 		return
 	}
+	inviteKey, inviteChannel := settings.APIInviteKey, settings.APIInviteChannel
+	unlock()
 
-	if key != guildSettings[guildID].APIInviteKey {
-		render.JSON(w, r, errAPI("specified key is invalid"))
+	if inviteKey == "" || subtle.ConstantTimeCompare([]byte(key), []byte(inviteKey)) != 1 {
+		render.Status(r, http.StatusUnauthorized)
+		render.JSON(w, r, errAPI(newError(errCodeAPIParamInvalid, "key")))
+		return
+	}
+
+	limit := botData().BotOptions.API.RateLimit.Invites
+	if _, retryAfter, allowed := apiInviteLimiter.Allow(guildID, limit); !allowed {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		render.Status(r, http.StatusTooManyRequests)
+		render.JSON(w, r, errAPI(newError(errCodeAPIInviteLimited, limit)))
 		return
 	}
 
@@ -130,46 +335,148 @@ func v0GetGuildInvite(w http.ResponseWriter, r *http.Request) {
 		MaxUses: 1,    //Only one use
 	}
 
-	invite, err := botData.DiscordSession.ChannelInviteCreate(guildSettings[guildID].APIInviteChannel, inviteSettings)
+	invite, err := botData().DiscordSession.ChannelInviteCreate(inviteChannel, inviteSettings)
 	if err != nil {
-		render.JSON(w, r, errAPI("error generating invite", err))
+		render.Status(r, http.StatusBadGateway)
+		render.JSON(w, r, errAPI(wrapError(errCodeAPIInviteFailed, err)))
 		return
 	}
 
+	InfoAPI.With("guild", guildID).Printf("Generated invite %s", invite.Code)
 	render.JSON(w, r, invite)
 }
 
 func v0GetUser(w http.ResponseWriter, r *http.Request) {
 	userID := chi.URLParam(r, "userID")
 	if userID == "" {
-		render.JSON(w, r, errAPI("userID must not be empty"))
+		render.JSON(w, r, errAPI(newError(errCodeAPIParamMissing, "userID")))
 		return
 	}
 
-	user, err := botData.DiscordSession.User(userID)
+	user, err := botData().DiscordSession.User(userID)
 	if err != nil {
-		render.JSON(w, r, errAPI("userID invalid"))
+		render.JSON(w, r, errAPI(newError(errCodeAPIParamInvalid, "userID")))
 		return
 	}
 
 	render.JSON(w, r, user)
 }
 
+func v0GetUserGuilds(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+	render.JSON(w, r, userGuilds(userID, apiPrincipal(r).IsAdmin() && apiPrincipal(r).UserID == userID))
+}
+
+// localUserGuilds returns every guild this shard is in that the user is a member of, or every guild at all if all is set
+// Membership is only checked against the state, as asking Discord about every guild would take too long
+func localUserGuilds(userID string, all bool) []*APIUserGuild {
+	botData().DiscordSession.State.RLock()
+	stateGuilds := append([]*discordgo.Guild{}, botData().DiscordSession.State.Guilds...)
+	botData().DiscordSession.State.RUnlock()
+
+	guilds := make([]*APIUserGuild, 0)
+	for _, guild := range stateGuilds {
+		userGuild := &APIUserGuild{ID: guild.ID, Name: guild.Name, Manager: all}
+		if guild.Icon != "" {
+			userGuild.IconURL = guild.IconURL()
+		}
+		if !all {
+			if _, err := botData().DiscordSession.State.Member(guild.ID, userID); err != nil {
+				continue
+			}
+			manages, err := MemberManagesGuild(botData().DiscordSession, guild.ID, userID)
+			userGuild.Manager = err == nil && manages
+		}
+		guilds = append(guilds, userGuild)
+	}
+	return guilds
+}
+
+// userGuilds returns every guild the user shares with the bot across every shard, sorted by name
+func userGuilds(userID string, all bool) []*APIUserGuild {
+	guilds := localUserGuilds(userID, all)
+	if shardCount > 1 {
+		shardAddrs, err := ipcShardAddrs()
+		if err != nil {
+			Error.Printf("Error finding the other shards: %v", err)
+		}
+		for id, addr := range shardAddrs {
+			if id == shardID {
+				continue
+			}
+			shardGuilds := make([]*APIUserGuild, 0)
+			if err := ipcRequest("GET", "http://"+addr+"/guilds/"+userID+"?all="+strconv.FormatBool(all), nil, &shardGuilds); err != nil {
+				Error.Printf("Error fetching the guilds of shard %d: %v", id, err)
+				continue
+			}
+			guilds = append(guilds, shardGuilds...)
+		}
+	}
+
+	sort.Slice(guilds, func(i, j int) bool { return strings.ToLower(guilds[i].Name) < strings.ToLower(guilds[j].Name) })
+	return guilds
+}
+
 func v0GetUserSettings(w http.ResponseWriter, r *http.Request) {
 	userID := chi.URLParam(r, "userID")
 	if userID == "" {
-		render.JSON(w, r, errAPI("userID must not be empty"))
+		render.JSON(w, r, errAPI(newError(errCodeAPIParamMissing, "userID")))
 		return
 	}
 
-	if _, ok := userSettings[userID]; !ok {
-		render.JSON(w, r, errAPI("specified userID has no settings"))
+	settings, ok := copyUserSettings(userID)
+	if !ok {
+		render.JSON(w, r, errAPI(newError(errCodeAPINotFound, "userID", "settings")))
 		return
 	}
 
-	render.JSON(w, r, userSettings[userID])
+	render.JSON(w, r, settings)
 }
 
 func v0PutUserSetting(w http.ResponseWriter, r *http.Request) {
-	render.PlainText(w, r, "stub")
+	userID := chi.URLParam(r, "userID")
+	v0ChangeUserSettings(w, r, userID, func(settings *UserSettings, rawValue json.RawMessage) []*SettingError {
+		return applySetting(settings, userSettingRules, userID, chi.URLParam(r, "setting"), rawValue)
+	})
+}
+
+func v0PatchUserSettings(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+	v0ChangeUserSettings(w, r, userID, func(settings *UserSettings, rawChanges json.RawMessage) []*SettingError {
+		return applySettings(settings, userSettingRules, userID, rawChanges)
+	})
+}
+
+// v0ChangeUserSettings applies the request body to a copy of a user's settings, which only replaces them if every change was valid
+func v0ChangeUserSettings(w http.ResponseWriter, r *http.Request, userID string, change func(*UserSettings, json.RawMessage) []*SettingError) {
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, v0MaxBodySize))
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, errAPI(wrapError(errCodeAPIParamInvalid, err, "body")))
+		return
+	}
+
+	initializeUserSettings(userID)
+
+	userSettingsLock.Lock()
+	existing, ok := userSettings[userID]
+	if !ok {
+		//The user's data was deleted since their settings were initialized
+		userSettingsLock.Unlock()
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, errAPI(newError(errCodeAPINotFound, "userID", "settings")))
+		return
+	}
+	settings := *existing
+	if errs := change(&settings, body); len(errs) > 0 {
+		userSettingsLock.Unlock()
+		renderSettingErrors(w, r, errs)
+		return
+	}
+	*existing = settings
+	userSettingsLock.Unlock()
+	stateSaveAll() //Also syncs the change to the other shards
+
+	InfoAPI.With("user", userID).Println("Changed user settings")
+	render.JSON(w, r, settings)
 }
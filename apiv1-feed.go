@@ -0,0 +1,119 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+)
+
+/*
+	Feeds follow the same rules as in v0, but are addressed by their ID rather than their index, and listed in the order of their URLs.
+*/
+
+// v1FeedIndex returns the index of the feed with the ID given in the URL, responding with an error and returning false if the guild has no such feed
+// The caller must hold the guild's lock
+func v1FeedIndex(w http.ResponseWriter, r *http.Request, guildID string) (int, bool) {
+	if settings, exists := guildSettings[guildID]; exists {
+		id := chi.URLParam(r, "feedID")
+		for index, feed := range settings.Feeds {
+			if feedID(feed.FeedURL) == id {
+				return index, true
+			}
+		}
+	}
+	render.Status(r, http.StatusNotFound)
+	render.JSON(w, r, errAPI(newError(errCodeAPINotFound, "feedID", "feed")))
+	return 0, false
+}
+
+func v1GetFeeds(w http.ResponseWriter, r *http.Request) {
+	guildID := chi.URLParam(r, "guildID")
+	channelID := r.URL.Query().Get("channel")
+	unlock := v0LockGuild(guildID)
+	defer unlock()
+
+	feeds := make([]*APIFeed, 0)
+	if settings, exists := guildSettings[guildID]; exists {
+		for _, feed := range settings.Feeds {
+			if channelID == "" || feed.ChannelID == channelID {
+				feeds = append(feeds, newAPIFeed(feed))
+			}
+		}
+	}
+
+	key := func(i int) string { return feeds[i].URL }
+	sort.Slice(feeds, func(i, j int) bool { return key(i) < key(j) })
+	start, end, next, ok := v1Page(w, r, len(feeds), key)
+	if !ok {
+		return
+	}
+	v1RenderList(w, r, feeds[start:end], next)
+}
+
+func v1PostFeed(w http.ResponseWriter, r *http.Request) {
+	if feed, ok := v0AddFeed(w, r, chi.URLParam(r, "guildID")); ok {
+		render.Status(r, http.StatusCreated)
+		v1Render(w, r, feed)
+	}
+}
+
+func v1GetFeed(w http.ResponseWriter, r *http.Request) {
+	guildID := chi.URLParam(r, "guildID")
+	unlock := v0LockGuild(guildID)
+	defer unlock()
+
+	index, ok := v1FeedIndex(w, r, guildID)
+	if !ok {
+		return
+	}
+	v1Render(w, r, newAPIFeed(guildSettings[guildID].Feeds[index]))
+}
+
+func v1PatchFeed(w http.ResponseWriter, r *http.Request) {
+	guildID := chi.URLParam(r, "guildID")
+	request := &APIFeedRequest{}
+	if !v0DecodeBody(w, r, request) || !validateFeedRequest(w, r, guildID, request) {
+		return
+	}
+
+	unlock := v0LockGuild(guildID)
+	defer unlock()
+
+	index, ok := v1FeedIndex(w, r, guildID)
+	if !ok {
+		return
+	}
+
+	feed := guildSettings[guildID].Feeds[index]
+	if request.ChannelID != nil {
+		feed.ChannelID = *request.ChannelID
+	}
+	if request.Frequency != nil {
+		feed.Frequency = *request.Frequency //Takes effect after the next check
+	}
+	stateSaveAll()
+
+	InfoAPI.With("guild", guildID, "user", apiPrincipal(r).UserID).Printf("Changed feed %s", feed.FeedURL)
+	v1Render(w, r, newAPIFeed(feed))
+}
+
+func v1DeleteFeed(w http.ResponseWriter, r *http.Request) {
+	guildID := chi.URLParam(r, "guildID")
+	unlock := v0LockGuild(guildID)
+	defer unlock()
+
+	index, ok := v1FeedIndex(w, r, guildID)
+	if !ok {
+		return
+	}
+
+	feeds := guildSettings[guildID].Feeds
+	removed := feeds[index]
+	guildSettings[guildID].Feeds = append(feeds[:index], feeds[index+1:]...)
+	stateSaveAll()
+
+	InfoAPI.With("guild", guildID, "user", apiPrincipal(r).UserID).Printf("Removed feed %s", removed.FeedURL)
+	render.NoContent(w, r)
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+)
+
+/*
+	Starboards, balances, rolemes and custom responses as v1 shows them.
+	Rolemes and custom responses have no IDs of their own, so they're ordered by their position in the guild's settings. Like those settings, they're read-only here.
+*/
+
+// APIv1Starboard holds the starboard settings of a guild, without its entries
+type APIv1Starboard struct {
+	Active            bool     `json:"active"`
+	AllowSelfStar     bool     `json:"allowSelfStar"`     //Whether or not a user may star their own message
+	BlacklistChannels []string `json:"blacklistChannels"` //The channels excluded from the starboard
+	BlacklistUsers    []string `json:"blacklistUsers"`    //The users excluded from the starboard
+	ChannelID         string   `json:"channelID"`
+	Emoji             string   `json:"emoji"`
+	NSFWChannelID     string   `json:"nsfwChannelID"` //The starboard for messages from NSFW channels
+	NSFWEmoji         string   `json:"nsfwEmoji"`
+	MinimumStars      int      `json:"minimumStars"` //How many stars a message needs to be added to the starboard
+	Entries           int      `json:"entries"`      //How many entries the starboard has, listed at /starboard/entries
+}
+
+// APIv1StarboardEntry holds a message on the starboard
+type APIv1StarboardEntry struct {
+	SourceChannelID    string `json:"sourceChannelID"`
+	SourceMessageID    string `json:"sourceMessageID"`
+	StarboardChannelID string `json:"starboardChannelID"`
+	StarboardMessageID string `json:"starboardMessageID"`
+	AuthorID           string `json:"authorID,omitempty"` //Empty for entries made before authors were tracked
+	Stars              int    `json:"stars"`
+}
+
+// APIv1Balance holds the balance of a user
+type APIv1Balance struct {
+	UserID    string     `json:"userID"`
+	Balance   int        `json:"balance"`
+	DailyNext *time.Time `json:"dailyNext,omitempty"` //When the user may next receive their daily credits, only shown to the user themselves
+}
+
+// APIv1RoleMe holds a roleme of a guild
+type APIv1RoleMe struct {
+	Position      int      `json:"position"` //Where the roleme is in the guild's roleMeList setting
+	Triggers      []string `json:"triggers"`
+	AddRoles      []string `json:"addRoles"`
+	RemoveRoles   []string `json:"removeRoles"`
+	CaseSensitive bool     `json:"caseSensitive"`
+	ChannelIDs    []string `json:"channelIDs"` //The channels the roleme works in, every channel if empty
+}
+
+// APIv1CustomResponse holds a custom response of a guild
+type APIv1CustomResponse struct {
+	Position     int                      `json:"position"` //Where the custom response is in the guild's customResponses setting
+	Expression   string                   `json:"expression"`
+	Responses    []CustomResponseReply    `json:"responses"`
+	CmdResponses []CustomResponseReplyCmd `json:"cmdResponses"`
+}
+
+// v1Strings returns the given strings, or an empty list instead of nil
+func v1Strings(values []string) []string {
+	if values == nil {
+		return make([]string, 0)
+	}
+	return values
+}
+
+// v1Contains returns whether or not the value is one of the values
+func v1Contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func v1GetStarboard(w http.ResponseWriter, r *http.Request) {
+	guildID := chi.URLParam(r, "guildID")
+	unlock := v0LockGuild(guildID)
+	defer unlock()
+
+	starboard, exists := starboards[guildID]
+	if !exists {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, errAPI(newError(errCodeAPINotFound, "guildID", "starboard data")))
+		return
+	}
+	v1Render(w, r, &APIv1Starboard{
+		Active:            starboard.Active,
+		AllowSelfStar:     starboard.AllowSelfStar,
+		BlacklistChannels: v1Strings(starboard.BlacklistChannels),
+		BlacklistUsers:    v1Strings(starboard.BlacklistUsers),
+		ChannelID:         starboard.ChannelID,
+		Emoji:             starboard.Emoji,
+		NSFWChannelID:     starboard.NSFWChannelID,
+		NSFWEmoji:         starboard.NSFWEmoji,
+		MinimumStars:      starboard.MinimumStars,
+		Entries:           len(starboard.StarboardEntries),
+	})
+}
+
+func v1GetStarboardEntries(w http.ResponseWriter, r *http.Request) {
+	guildID := chi.URLParam(r, "guildID")
+	query := r.URL.Query()
+	minStars, ok := v1QueryInt(w, r, "minStars", 0)
+	if !ok {
+		return
+	}
+
+	unlock := v0LockGuild(guildID)
+	defer unlock()
+
+	entries := make([]*APIv1StarboardEntry, 0)
+	if starboard, exists := starboards[guildID]; exists {
+		for _, entry := range starboard.StarboardEntries {
+			if (query.Get("author") != "" && entry.SourceAuthorID != query.Get("author")) || (query.Get("channel") != "" && entry.SourceChannelID != query.Get("channel")) || entry.Stars < minStars {
+				continue
+			}
+			entries = append(entries, &APIv1StarboardEntry{
+				SourceChannelID:    entry.SourceChannelID,
+				SourceMessageID:    entry.SourceMessageID,
+				StarboardChannelID: entry.StarboardChannelID,
+				StarboardMessageID: entry.StarboardMessageID,
+				AuthorID:           entry.SourceAuthorID,
+				Stars:              entry.Stars,
+			})
+		}
+	}
+
+	key := func(i int) string { return v1SnowflakeKey(entries[i].SourceMessageID) + entries[i].StarboardMessageID }
+	sort.Slice(entries, func(i, j int) bool { return key(i) < key(j) })
+	start, end, next, ok := v1Page(w, r, len(entries), key)
+	if !ok {
+		return
+	}
+	v1RenderList(w, r, entries[start:end], next)
+}
+
+func v1GetBalances(w http.ResponseWriter, r *http.Request) {
+	guildID := chi.URLParam(r, "guildID")
+	minBalance, ok := v1QueryInt(w, r, "minBalance", 1)
+	if !ok {
+		return
+	}
+
+	//Only members the bot knows about are listed, as asking Discord about every user would take too long
+	balances := make([]*APIv1Balance, 0)
+	userSettingsLock.RLock()
+	for userID, settings := range userSettings {
+		if settings.Balance < minBalance {
+			continue
+		}
+		if _, err := botData().DiscordSession.State.Member(guildID, userID); err != nil {
+			continue
+		}
+		balances = append(balances, &APIv1Balance{UserID: userID, Balance: settings.Balance}) //Other members' dailies are their own business
+	}
+	userSettingsLock.RUnlock()
+
+	key := func(i int) string { return v1ReverseKey(int64(balances[i].Balance)) + balances[i].UserID }
+	sort.Slice(balances, func(i, j int) bool { return key(i) < key(j) })
+	start, end, next, ok := v1Page(w, r, len(balances), key)
+	if !ok {
+		return
+	}
+	v1RenderList(w, r, balances[start:end], next)
+}
+
+func v1GetBalance(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+	settings, _ := copyUserSettings(userID)
+	v1Render(w, r, &APIv1Balance{UserID: userID, Balance: settings.Balance, DailyNext: &settings.DailyNext})
+}
+
+func v1GetRoleMes(w http.ResponseWriter, r *http.Request) {
+	guildID := chi.URLParam(r, "guildID")
+	query := r.URL.Query()
+	unlock := v0LockGuild(guildID)
+	defer unlock()
+
+	roleMes := make([]*APIv1RoleMe, 0)
+	if settings, exists := guildSettings[guildID]; exists {
+		for position, roleMe := range settings.RoleMeList {
+			if channelID := query.Get("channel"); channelID != "" && len(roleMe.ChannelIDs) > 0 && !v1Contains(roleMe.ChannelIDs, channelID) {
+				continue
+			}
+			if roleID := query.Get("role"); roleID != "" && !v1Contains(roleMe.AddRoles, roleID) && !v1Contains(roleMe.RemoveRoles, roleID) {
+				continue
+			}
+			roleMes = append(roleMes, &APIv1RoleMe{
+				Position:      position,
+				Triggers:      v1Strings(roleMe.Triggers),
+				AddRoles:      v1Strings(roleMe.AddRoles),
+				RemoveRoles:   v1Strings(roleMe.RemoveRoles),
+				CaseSensitive: roleMe.CaseSensitive,
+				ChannelIDs:    v1Strings(roleMe.ChannelIDs),
+			})
+		}
+	}
+
+	start, end, next, ok := v1Page(w, r, len(roleMes), func(i int) string { return v1Key(int64(roleMes[i].Position)) })
+	if !ok {
+		return
+	}
+	v1RenderList(w, r, roleMes[start:end], next)
+}
+
+func v1GetCustomResponses(w http.ResponseWriter, r *http.Request) {
+	guildID := chi.URLParam(r, "guildID")
+	unlock := v0LockGuild(guildID)
+	defer unlock()
+
+	customResponses := make([]*APIv1CustomResponse, 0)
+	if settings, exists := guildSettings[guildID]; exists {
+		for position, customResponse := range settings.CustomResponses {
+			if command := r.URL.Query().Get("command"); command != "" && !customResponseRunsCommand(customResponse, command) {
+				continue
+			}
+			apiCustomResponse := &APIv1CustomResponse{
+				Position:     position,
+				Expression:   customResponse.Expression,
+				Responses:    customResponse.Responses,
+				CmdResponses: customResponse.CmdResponses,
+			}
+			if apiCustomResponse.Responses == nil {
+				apiCustomResponse.Responses = make([]CustomResponseReply, 0)
+			}
+			if apiCustomResponse.CmdResponses == nil {
+				apiCustomResponse.CmdResponses = make([]CustomResponseReplyCmd, 0)
+			}
+			customResponses = append(customResponses, apiCustomResponse)
+		}
+	}
+
+	start, end, next, ok := v1Page(w, r, len(customResponses), func(i int) string { return v1Key(int64(customResponses[i].Position)) })
+	if !ok {
+		return
+	}
+	v1RenderList(w, r, customResponses[start:end], next)
+}
+
+// customResponseRunsCommand returns whether or not a custom response runs the given command
+func customResponseRunsCommand(customResponse CustomResponseQuery, command string) bool {
+	for _, cmdResponse := range customResponse.CmdResponses {
+		if cmdResponse.CommandName == command {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+)
+
+/*
+	Each reminder is identified by when it was set, which is unique to each of a user's reminders, so deleting one doesn't change the IDs of the others like v0's indexes.
+*/
+
+const (
+	//The longest reminder accepted, so it still fits in the embed it's sent in
+	v1MaxReminderLength = 1024
+)
+
+// APIv1Reminder holds a reminder of a user
+type APIv1Reminder struct {
+	ID        string    `json:"id"`
+	ChannelID string    `json:"channelID"` //The channel the reminder is sent to
+	Message   string    `json:"message"`
+	Added     time.Time `json:"added"`
+	When      time.Time `json:"when"`
+}
+
+// APIv1ReminderRequest holds a request to set a reminder
+type APIv1ReminderRequest struct {
+	ChannelID string    `json:"channelID"` //The channel to send the reminder to
+	Message   string    `json:"message"`
+	When      time.Time `json:"when"` //When to send the reminder, in RFC 3339 format
+}
+
+// reminderID returns the ID of a reminder as v1 shows it
+func reminderID(entry RemindEntry) string {
+	return strconv.FormatInt(entry.Added.UnixNano(), 10)
+}
+
+// newAPIv1Reminder returns a reminder as v1 shows it
+func newAPIv1Reminder(entry RemindEntry) *APIv1Reminder {
+	return &APIv1Reminder{ID: reminderID(entry), ChannelID: entry.ChannelID, Message: entry.Message, Added: entry.Added, When: entry.When}
+}
+
+func v1GetReminders(w http.ResponseWriter, r *http.Request) {
+	guildID := chi.URLParam(r, "guildID")
+	channelID := r.URL.Query().Get("channel")
+
+	reminders := make([]*APIv1Reminder, 0)
+	for _, entry := range v0UserReminders(guildID, apiPrincipal(r).UserID) {
+		if channelID == "" || entry.ChannelID == channelID {
+			reminders = append(reminders, newAPIv1Reminder(entry))
+		}
+	}
+
+	//Ordered by when they'll be sent, like v0, with the ID breaking ties
+	key := func(i int) string { return v1Key(reminders[i].When.UnixNano()) + reminders[i].ID }
+	sort.Slice(reminders, func(i, j int) bool { return key(i) < key(j) })
+	start, end, next, ok := v1Page(w, r, len(reminders), key)
+	if !ok {
+		return
+	}
+	v1RenderList(w, r, reminders[start:end], next)
+}
+
+func v1PostReminder(w http.ResponseWriter, r *http.Request) {
+	guildID := chi.URLParam(r, "guildID")
+	userID := apiPrincipal(r).UserID
+	request := &APIv1ReminderRequest{}
+	if !v0DecodeBody(w, r, request) {
+		return
+	}
+	request.Message = strings.TrimSpace(request.Message)
+	if request.Message == "" {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, errAPI(newError(errCodeAPIParamMissing, "message")))
+		return
+	}
+	if utf8.RuneCountInString(request.Message) > v1MaxReminderLength {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, errAPI(newError(errCodeAPIParamInvalid, "message")))
+		return
+	}
+	if err := validateSettingChannel(guildID, request.ChannelID); err != nil || request.ChannelID == "" {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, errAPI(newError(errCodeAPIParamInvalid, "channelID")))
+		return
+	}
+	if !apiPrincipal(r).IsAdmin() {
+		//The remind command only sends reminders where the user is talking, so only allow channels the user could send them to themselves
+		permissions, err := botData().DiscordSession.State.UserChannelPermissions(userID, request.ChannelID)
+		if err != nil || permissions&discordgo.PermissionSendMessages == 0 {
+			render.Status(r, http.StatusForbidden)
+			render.JSON(w, r, errAPI(newError(errCodeAPIForbidden, "channel "+request.ChannelID)))
+			return
+		}
+	}
+	now := time.Now()
+	if !request.When.After(now) {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, errAPI(newError(errCodeAPIParamInvalid, "when")))
+		return
+	}
+
+	remindWhen(userID, guildID, request.ChannelID, request.Message, now, request.When, now)
+	stateSaveAll()
+
+	InfoAPI.With("guild", guildID, "user", userID).Println("Set a reminder")
+	render.Status(r, http.StatusCreated)
+	v1Render(w, r, newAPIv1Reminder(RemindEntry{UserID: userID, ChannelID: request.ChannelID, GuildID: guildID, Message: request.Message, Added: now, When: request.When}))
+}
+
+func v1DeleteReminder(w http.ResponseWriter, r *http.Request) {
+	guildID := chi.URLParam(r, "guildID")
+	userID := apiPrincipal(r).UserID
+	id := chi.URLParam(r, "reminderID")
+	remindEntriesLock.Lock()
+
+	//The reminder's timer checks that it still exists before sending it, so removing it is enough
+	for i, entry := range remindEntries {
+		if entry.GuildID == guildID && entry.UserID == userID && reminderID(entry) == id {
+			remindEntries = append(remindEntries[:i], remindEntries[i+1:]...)
+			remindEntriesLock.Unlock()
+			stateSaveAll()
+
+			InfoAPI.With("guild", guildID, "user", userID).Println("Deleted a reminder")
+			render.NoContent(w, r)
+			return
+		}
+	}
+	remindEntriesLock.Unlock()
+	render.Status(r, http.StatusNotFound)
+	render.JSON(w, r, errAPI(newError(errCodeAPINotFound, "reminderID", "reminder")))
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"encoding/base64"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+)
+
+/*
+	API v1 wraps every successful response in the same envelope, where the result is in data.
+	Lists are paged with cursors: a page has at most limit items, and next holds the cursor to pass back for the page after it.
+	Each list is ordered by a key that never changes for an item, so items added or removed between pages don't shift the next page.
+	Errors are the same APIError as everywhere else in the API, and v0 stays in place for existing clients.
+*/
+
+const (
+	apiV1Prefix = "/api/v1"
+
+	//How many items a page has unless the request asks for fewer or more
+	v1DefaultLimit = 50
+
+	//The most items a page may have
+	v1MaxLimit = 200
+)
+
+// APIv1Envelope holds the body of every successful v1 response
+type APIv1Envelope struct {
+	Data interface{} `json:"data"`
+	Next string      `json:"next,omitempty"` //The cursor of the next page of a list, empty on the last page
+}
+
+func APIv1() *chi.Mux {
+	router := chi.NewRouter()
+
+	router.Route("/guilds/{guildID}", func(r chi.Router) {
+		r.Use(shardProxy) //Guild state only lives on the shard that owns the guild
+
+		r.Group(func(r chi.Router) {
+			r.Use(apiRequireGuildMember)
+
+			//Reminder endpoint, where each member only sees their own reminders
+			r.Get("/reminders", v1GetReminders)                   //Lists the user's reminders in the guild
+			r.Post("/reminders", v1PostReminder)                  //Sets a reminder for the user
+			r.Delete("/reminders/{reminderID}", v1DeleteReminder) //Deletes one of the user's reminders
+
+			//Balance endpoint
+			r.Get("/balances", v1GetBalances) //Lists the balances of the guild's members, highest first
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(apiRequireGuildManager)
+
+			//Starboard endpoint
+			r.Get("/starboard", v1GetStarboard)                //Retrieves the starboard settings
+			r.Get("/starboard/entries", v1GetStarboardEntries) //Lists the starboard entries, newest first
+
+			//Feed endpoint
+			r.Get("/feeds", v1GetFeeds)               //Lists the feeds
+			r.Post("/feeds", v1PostFeed)              //Adds a feed
+			r.Get("/feeds/{feedID}", v1GetFeed)       //Retrieves a feed
+			r.Patch("/feeds/{feedID}", v1PatchFeed)   //Changes the channel or frequency of a feed
+			r.Delete("/feeds/{feedID}", v1DeleteFeed) //Removes a feed
+
+			//Roleme and custom response endpoints, which are read-only like their settings
+			r.Get("/rolemes", v1GetRoleMes)                 //Lists the rolemes
+			r.Get("/customresponses", v1GetCustomResponses) //Lists the custom responses
+		})
+	})
+
+	router.Route("/users/{userID}", func(r chi.Router) {
+		r.Use(apiRequireUser) //Only the user may see their own data
+
+		r.Get("/balance", v1GetBalance) //Retrieves the user's balance
+	})
+
+	return router
+}
+
+// v1Render responds with the given data in the envelope
+func v1Render(w http.ResponseWriter, r *http.Request, data interface{}) {
+	render.JSON(w, r, &APIv1Envelope{Data: data})
+}
+
+// v1RenderList responds with a page of a list in the envelope
+func v1RenderList(w http.ResponseWriter, r *http.Request, page interface{}, next string) {
+	render.JSON(w, r, &APIv1Envelope{Data: page, Next: next})
+}
+
+// v1Key returns a key that orders numbers from lowest to highest
+func v1Key(n int64) string {
+	key := strconv.FormatInt(n, 10)
+	for len(key) < 19 {
+		key = "0" + key
+	}
+	return key
+}
+
+// v1ReverseKey returns a key that orders numbers from highest to lowest
+func v1ReverseKey(n int64) string {
+	return v1Key(math.MaxInt64 - n)
+}
+
+// v1SnowflakeKey returns a key that orders Discord IDs from newest to oldest
+func v1SnowflakeKey(id string) string {
+	n, _ := strconv.ParseInt(id, 10, 64)
+	return v1ReverseKey(n)
+}
+
+// v1Page returns the range of a list to respond with, given the key of each item in the order they're listed, and the cursor of the page after it
+// Keys must be unique and sorted from lowest to highest. On an invalid cursor or limit, it responds with an error and returns false
+func v1Page(w http.ResponseWriter, r *http.Request, count int, key func(i int) string) (start, end int, next string, ok bool) {
+	query := r.URL.Query()
+
+	limit := v1DefaultLimit
+	if query.Get("limit") != "" {
+		parsed, err := strconv.Atoi(query.Get("limit"))
+		if err != nil || parsed <= 0 || parsed > v1MaxLimit {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, errAPI(newError(errCodeAPIParamInvalid, "limit")))
+			return 0, 0, "", false
+		}
+		limit = parsed
+	}
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		after, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err != nil || len(after) == 0 {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, errAPI(newError(errCodeAPIParamInvalid, "cursor")))
+			return 0, 0, "", false
+		}
+		start = sort.Search(count, func(i int) bool { return key(i) > string(after) })
+	}
+
+	end = start + limit
+	if end >= count {
+		return start, count, "", true
+	}
+	return start, end, base64.RawURLEncoding.EncodeToString([]byte(key(end - 1))), true
+}
+
+// v1QueryInt returns the integer query parameter with the given name, or the fallback if it isn't given
+// If it isn't an integer, it responds with an error and returns false
+func v1QueryInt(w http.ResponseWriter, r *http.Request, name string, fallback int) (int, bool) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return fallback, true
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, errAPI(newError(errCodeAPIParamInvalid, name)))
+		return 0, false
+	}
+	return parsed, true
+}
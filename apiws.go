@@ -0,0 +1,266 @@
+package main
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/render"
+	"github.com/gorilla/websocket"
+)
+
+/*
+	GET /ws streams events as they happen to dashboards and overlays, one JSON object per message, so they don't have to poll.
+	Clients pick the guilds they want events from with ?guild= and the types of events with ?type=, either of which can be repeated or comma-separated.
+	Access to each guild is checked when connecting and every few minutes after, and the stream is closed as soon as it's lost.
+*/
+
+const (
+	wsWriteTimeout  = 10 * time.Second //How long writing a message can take before the client is considered gone
+	wsPongTimeout   = 60 * time.Second //How long the client can go without answering a ping
+	wsPingInterval  = 30 * time.Second //How often the client is pinged, which must be shorter than wsPongTimeout
+	wsCheckInterval = 5 * time.Minute  //How often the client's token and access to each guild is checked again
+
+	wsHello = "hello" //The type of the first message sent to a client, holding what it'll receive
+)
+
+var wsUpgrader = websocket.Upgrader{CheckOrigin: wsCheckOrigin}
+
+// WSHello holds the guilds and types of events a client will receive
+type WSHello struct {
+	Guilds []string `json:"guilds"` //Empty if the client receives events from every guild
+	Types  []string `json:"types"`
+}
+
+// wsStream holds what a client receives events for
+type wsStream struct {
+	sync.RWMutex
+
+	principal *APIPrincipal
+	guilds    map[string]GuildAccess //The access the client has to each guild it asked for, nil if it receives events from every guild
+	types     map[string]bool        //The types of events the client asked for
+}
+
+// wsCheckOrigin allows connecting from other sites only with a bearer token, as browsers send session cookies along with WebSocket connections from any site
+func wsCheckOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" || strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+		return true
+	}
+	originURL, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	if strings.EqualFold(originURL.Host, r.Host) {
+		return true
+	}
+	publicURL, err := url.Parse(botData().BotOptions.API.PublicURL)
+	return err == nil && publicURL.Host != "" && strings.EqualFold(originURL.Host, publicURL.Host)
+}
+
+// wsQueryValues returns every value of a query parameter, splitting comma-separated values
+func wsQueryValues(query url.Values, key string) []string {
+	values := make([]string, 0)
+	for _, value := range query[key] {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				values = append(values, part)
+			}
+		}
+	}
+	return values
+}
+
+// authorize checks the client's access to each guild it asked for, returning an error if it has none to any of them
+// Access is checked without holding the lock, as asking other shards can be slow and events are filtered while holding it
+func (stream *wsStream) authorize() error {
+	stream.RLock()
+	guilds := make(map[string]GuildAccess, len(stream.guilds))
+	for guildID := range stream.guilds {
+		guilds[guildID] = GuildAccessNone
+	}
+	stream.RUnlock()
+	if len(guilds) == 0 {
+		return nil
+	}
+
+	for guildID := range guilds {
+		if stream.principal.IsAdmin() {
+			guilds[guildID] = GuildAccessManager
+			continue
+		}
+		access, err := guildAccess(guildID, stream.principal.UserID)
+		if err != nil {
+			return err
+		}
+		if access == GuildAccessNone {
+			return newError(errCodeAPIForbidden, "guild "+guildID)
+		}
+		guilds[guildID] = access
+	}
+
+	stream.Lock()
+	stream.guilds = guilds
+	stream.Unlock()
+	return nil
+}
+
+// allows returns whether or not the client should receive an event
+func (stream *wsStream) allows(event *Event) bool {
+	if !stream.types[event.Type] {
+		return false
+	}
+	if event.UserID != "" && event.UserID != stream.principal.UserID && !stream.principal.IsAdmin() {
+		return false
+	}
+
+	stream.RLock()
+	defer stream.RUnlock()
+
+	if stream.guilds == nil {
+		return true
+	}
+	access, exists := stream.guilds[event.GuildID]
+	return exists && access >= eventAccess[event.Type]
+}
+
+// hello returns the first message sent to the client
+func (stream *wsStream) hello() *Event {
+	hello := &WSHello{Guilds: make([]string, 0), Types: make([]string, 0)}
+	for guildID := range stream.guilds {
+		hello.Guilds = append(hello.Guilds, guildID)
+	}
+	sort.Strings(hello.Guilds)
+	for _, eventType := range eventTypes() {
+		if stream.types[eventType] {
+			hello.Types = append(hello.Types, eventType)
+		}
+	}
+	return &Event{Type: wsHello, Time: time.Now(), Data: hello}
+}
+
+func apiGetWS(w http.ResponseWriter, r *http.Request) {
+	principal := apiPrincipal(r)
+	query := r.URL.Query()
+	stream := &wsStream{principal: principal, types: make(map[string]bool)}
+
+	for _, eventType := range wsQueryValues(query, "type") {
+		if _, exists := eventAccess[eventType]; !exists {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, errAPI(newError(errCodeAPIParamInvalid, "type")))
+			return
+		}
+		stream.types[eventType] = true
+	}
+	if len(stream.types) == 0 {
+		for eventType := range eventAccess {
+			stream.types[eventType] = true
+		}
+	}
+
+	guildIDs := wsQueryValues(query, "guild")
+	if len(guildIDs) == 0 && !principal.IsAdmin() {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, errAPI(newError(errCodeAPIParamMissing, "guild")))
+		return
+	}
+	if len(guildIDs) > 0 {
+		stream.guilds = make(map[string]GuildAccess)
+		for _, guildID := range guildIDs {
+			if !isSnowflake(guildID) {
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, errAPI(newError(errCodeAPIParamInvalid, "guild")))
+				return
+			}
+			stream.guilds[guildID] = GuildAccessNone
+		}
+	}
+
+	if err := stream.authorize(); err != nil {
+		if asClinetError(err).Code == errCodeAPIForbidden {
+			render.Status(r, http.StatusForbidden)
+		} else {
+			render.Status(r, http.StatusServiceUnavailable)
+		}
+		render.JSON(w, r, errAPI(err))
+		return
+	}
+
+	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return //The upgrader already responded with the error
+	}
+	defer conn.Close()
+
+	subscriber := subscribeEvents(stream.allows)
+	defer subscriber.Unsubscribe()
+
+	logger := InfoAPI.With("user", principal.UserID)
+	logger.Printf("Streaming events from %d guilds", len(stream.guilds))
+	defer logger.Println("Stopped streaming events")
+
+	//Read from the client to handle pongs and notice when it disconnects, ignoring anything it sends
+	disconnected := make(chan struct{})
+	go func() {
+		defer close(disconnected)
+		conn.SetReadLimit(512)
+		conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+		})
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	closeStream := func(code int, reason string) {
+		conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(wsWriteTimeout))
+	}
+
+	conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	if err := conn.WriteJSON(stream.hello()); err != nil {
+		return
+	}
+
+	pingTicker := time.NewTicker(wsPingInterval)
+	defer pingTicker.Stop()
+	checkTicker := time.NewTicker(wsCheckInterval)
+	defer checkTicker.Stop()
+
+	for {
+		select {
+		case event := <-subscriber.Events:
+			conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			if err := conn.WriteJSON(event); err != nil {
+				return
+			}
+		case <-pingTicker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout)); err != nil {
+				return
+			}
+		case <-checkTicker.C:
+			if principal.TokenID != "" && !apiTokenActive(principal.TokenID) {
+				closeStream(websocket.ClosePolicyViolation, "token revoked")
+				return
+			}
+			if err := stream.authorize(); err != nil {
+				if asClinetError(err).Code != errCodeAPIForbidden {
+					WarningAPI.With("user", principal.UserID).Printf("Error checking access to the guilds events are streamed from: %v", err)
+					continue //Keep the access the client had until it can be checked again
+				}
+				closeStream(websocket.ClosePolicyViolation, asClinetError(err).Message("en"))
+				return
+			}
+		case <-subscriber.Lagged:
+			closeStream(websocket.ClosePolicyViolation, "fell too far behind on events")
+			return
+		case <-disconnected:
+			return
+		}
+	}
+}
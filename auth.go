@@ -0,0 +1,675 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+)
+
+/*
+	API requests are authenticated with either a session from logging in with Discord, or an API token created by a logged in user.
+	Both are random tokens that are only ever stored as SHA-256 hashes, and are sent as a bearer token or in the session cookie.
+	When sharded, the first shard authenticates every request and forwards who made it to the shard that owns the guild over IPC.
+*/
+
+const (
+	apiSessionCookie   = "clinet_session"
+	apiStateCookie     = "clinet_oauth_state"
+	apiRedirectCookie  = "clinet_oauth_redirect"
+	apiPrincipalHeader = "X-Clinet-API-Principal"
+	apiTokenPrefix     = "clinet_"
+
+	//The scope that allows access to every guild and user, only ever granted to the bot owner
+	apiScopeAdmin = "admin"
+
+	//What secrets are replaced with in responses
+	redactedSecret = "[redacted]"
+)
+
+var (
+	//Where API tokens and sessions are saved, outside of the shard state directories so they survive changing the shard count
+	apiAuthFile = filepath.Join("state", "api", "auth.json")
+
+	//Every API token and session, where key = token ID
+	apiTokens     = make(map[string]*APIToken)
+	apiTokensLock sync.RWMutex
+	apiAuthLoaded bool //Whether this process serves the API and loaded the tokens, so it's the only one that saves them
+
+	//The Discord endpoints used by the OAuth2 login flow
+	discordOAuth2URL = "https://discord.com/api/oauth2"
+	discordAPIURL    = "https://discord.com/api/v8"
+)
+
+type apiContextKey string
+
+const apiPrincipalKey apiContextKey = "principal"
+
+// APIToken holds an API token or session, without the token itself
+type APIToken struct {
+	ID       string    `json:"id"`
+	Name     string    `json:"name"`
+	Hash     string    `json:"hash,omitempty"` //The SHA-256 hash of the token, never included in responses
+	UserID   string    `json:"userID"`
+	Scopes   []string  `json:"scopes"`
+	Session  bool      `json:"session"` //Whether the token was issued by logging in with Discord rather than created by its user
+	Created  time.Time `json:"created"`
+	Expires  time.Time `json:"expires"` //The zero time if the token never expires
+	LastUsed time.Time `json:"lastUsed"`
+}
+
+// Expired returns whether or not the token has expired
+func (token *APIToken) Expired() bool {
+	return !token.Expires.IsZero() && time.Now().After(token.Expires)
+}
+
+// APIPrincipal holds who made an API request
+type APIPrincipal struct {
+	UserID  string   `json:"userID"`
+	Scopes  []string `json:"scopes"`
+	TokenID string   `json:"tokenID"`
+}
+
+// HasScope returns whether or not the principal was granted the given scope
+func (principal *APIPrincipal) HasScope(scope string) bool {
+	for _, grantedScope := range principal.Scopes {
+		if grantedScope == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAdmin returns whether or not the principal may access every guild and user
+func (principal *APIPrincipal) IsAdmin() bool {
+	return principal.HasScope(apiScopeAdmin)
+}
+
+// APITokenRequest holds a request to create an API token
+type APITokenRequest struct {
+	Name      string   `json:"name"`
+	Scopes    []string `json:"scopes"`
+	ExpiresIn int      `json:"expiresIn"` //How many hours until the token expires, 0 if it never does
+}
+
+// APINewToken holds a newly issued API token or session, the only time the token itself is ever shown
+type APINewToken struct {
+	Token string    `json:"token"`
+	Info  *APIToken `json:"info"`
+}
+
+// apiAuthLoad loads every API token and session saved by apiAuthSave
+func apiAuthLoad() {
+	apiTokensLock.Lock()
+	defer apiTokensLock.Unlock()
+
+	if err := stateRestoreRaw(apiAuthFile, &apiTokens); err != nil && !os.IsNotExist(err) {
+		ErrorAPI.Printf("Error loading API tokens: %v", err)
+	}
+	for id, token := range apiTokens {
+		if token.Expired() {
+			delete(apiTokens, id)
+		}
+	}
+	apiAuthLoaded = true
+}
+
+// apiAuthSave saves every API token and session that hasn't expired
+func apiAuthSave() {
+	apiTokensLock.Lock()
+	defer apiTokensLock.Unlock()
+
+	for id, token := range apiTokens {
+		if token.Expired() {
+			delete(apiTokens, id)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(apiAuthFile), 0700); err != nil {
+		ErrorAPI.Printf("Error creating the API token directory: %v", err)
+		return
+	}
+	if err := stateSaveRaw(apiTokens, apiAuthFile); err != nil {
+		ErrorAPI.Printf("Error saving API tokens: %v", err)
+	}
+}
+
+// hashAPIToken returns the SHA-256 hash of a token as it's stored
+func hashAPIToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// randomHex returns the given amount of random bytes as hex
+func randomHex(length int) (string, error) {
+	data := make([]byte, length)
+	if _, err := rand.Read(data); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(data), nil
+}
+
+// issueAPIToken creates and saves a new API token or session for a user, returning the token itself along with its info
+func issueAPIToken(userID, name string, scopes []string, session bool, lifetime time.Duration) (*APINewToken, error) {
+	id, err := randomHex(8)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, err
+	}
+	token := apiTokenPrefix + id + "_" + secret
+	if scopes == nil {
+		scopes = make([]string, 0)
+	}
+
+	info := &APIToken{
+		ID:      id,
+		Name:    name,
+		Hash:    hashAPIToken(token),
+		UserID:  userID,
+		Scopes:  scopes,
+		Session: session,
+		Created: time.Now(),
+	}
+	if lifetime > 0 {
+		info.Expires = info.Created.Add(lifetime)
+	}
+
+	apiTokensLock.Lock()
+	apiTokens[id] = info
+	apiTokensLock.Unlock()
+	apiAuthSave()
+
+	return &APINewToken{Token: token, Info: info.redacted()}, nil
+}
+
+// redacted returns a copy of the token info without its hash, for responses
+func (token *APIToken) redacted() *APIToken {
+	redacted := *token
+	redacted.Hash = ""
+	return &redacted
+}
+
+// lookupAPIToken returns the principal of the given token, or nil if it's invalid or expired
+func lookupAPIToken(token string) *APIPrincipal {
+	parts := strings.SplitN(strings.TrimPrefix(token, apiTokenPrefix), "_", 2)
+	if !strings.HasPrefix(token, apiTokenPrefix) || len(parts) != 2 {
+		return nil
+	}
+
+	apiTokensLock.Lock()
+	defer apiTokensLock.Unlock()
+
+	info, exists := apiTokens[parts[0]]
+	if !exists || info.Expired() || subtle.ConstantTimeCompare([]byte(info.Hash), []byte(hashAPIToken(token))) != 1 {
+		return nil
+	}
+	info.LastUsed = time.Now()
+
+	principal := &APIPrincipal{UserID: info.UserID, Scopes: make([]string, 0), TokenID: info.ID}
+	for _, scope := range info.Scopes {
+		if scope == apiScopeAdmin && info.UserID != botData().BotOwnerID {
+			continue //The bot owner may have changed since the token was issued
+		}
+		principal.Scopes = append(principal.Scopes, scope)
+	}
+	return principal
+}
+
+// apiTokenActive returns whether or not the token with the given ID still exists and hasn't expired, for checking on requests that outlive the token
+func apiTokenActive(tokenID string) bool {
+	apiTokensLock.RLock()
+	defer apiTokensLock.RUnlock()
+
+	info, exists := apiTokens[tokenID]
+	return exists && !info.Expired()
+}
+
+// apiPrincipal returns who made an API request, or nil if it wasn't authenticated
+func apiPrincipal(r *http.Request) *APIPrincipal {
+	principal, _ := r.Context().Value(apiPrincipalKey).(*APIPrincipal)
+	return principal
+}
+
+// apiAuthenticate finds who made an API request from its bearer token or session cookie, rejecting requests with invalid credentials
+// Requests proxied from the first shard over IPC carry the principal it authenticated instead
+func apiAuthenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isIPCRequest(r) {
+			principal := &APIPrincipal{}
+			if err := json.Unmarshal([]byte(r.Header.Get(apiPrincipalHeader)), principal); err == nil {
+				r = r.WithContext(context.WithValue(r.Context(), apiPrincipalKey, principal))
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token := ""
+		if authorization := r.Header.Get("Authorization"); strings.HasPrefix(authorization, "Bearer ") {
+			token = strings.TrimPrefix(authorization, "Bearer ")
+		} else if cookie, err := r.Cookie(apiSessionCookie); err == nil {
+			token = cookie.Value
+		}
+		if token == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		principal := lookupAPIToken(token)
+		if principal == nil {
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, errAPI(newError(errCodeAPITokenInvalid)))
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), apiPrincipalKey, principal)))
+	})
+}
+
+// apiRequireAuth rejects API requests that weren't authenticated
+func apiRequireAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if apiPrincipal(r) == nil {
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, errAPI(newError(errCodeAPIUnauthorized)))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// apiRequireAdmin rejects API requests that weren't made with the admin scope
+func apiRequireAdmin(next http.Handler) http.Handler {
+	return apiRequireAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !apiPrincipal(r).IsAdmin() {
+			render.Status(r, http.StatusForbidden)
+			render.JSON(w, r, errAPI(newError(errCodeAPIForbidden, "this endpoint")))
+			return
+		}
+		next.ServeHTTP(w, r)
+	}))
+}
+
+// GuildAccess is how much of a guild a user can access through the API
+type GuildAccess int
+
+const (
+	GuildAccessNone    GuildAccess = iota //Not a member of the guild
+	GuildAccessMember                     //A member of the guild
+	GuildAccessManager                    //A member that can manage the guild
+)
+
+// localGuildAccess returns how much of a guild a user can access
+// This has to run on the shard that owns the guild, as that's the only shard that knows its members and roles
+func localGuildAccess(guildID, userID string) GuildAccess {
+	if manages, err := MemberManagesGuild(botData().DiscordSession, guildID, userID); err == nil && manages {
+		return GuildAccessManager
+	}
+	if _, err := botData().DiscordSession.State.Member(guildID, userID); err == nil {
+		return GuildAccessMember
+	}
+	if _, err := botData().DiscordSession.GuildMember(guildID, userID); err == nil {
+		return GuildAccessMember
+	}
+	return GuildAccessNone
+}
+
+// guildAccess returns how much of a guild a user can access, asking the shard that owns the guild if it isn't this one
+func guildAccess(guildID, userID string) (GuildAccess, error) {
+	if shardCount <= 1 || ownsGuild(guildID) {
+		return localGuildAccess(guildID, userID), nil
+	}
+
+	owner := shardForGuild(guildID)
+	shardAddrs, err := ipcShardAddrs()
+	if err != nil || shardAddrs[owner] == "" {
+		return GuildAccessNone, wrapError(errCodeIPCShardUnavailable, err, owner)
+	}
+	access := GuildAccessNone
+	err = ipcRequest("GET", "http://"+shardAddrs[owner]+"/access/"+guildID+"/"+userID, nil, &access)
+	return access, err
+}
+
+// apiRequireGuildAccess rejects API requests for a guild from anyone without the given access to it
+// This has to run on the shard that owns the guild, as that's the only shard that knows its members and roles
+func apiRequireGuildAccess(access GuildAccess) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return apiRequireAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal := apiPrincipal(r)
+			if guildID := chi.URLParam(r, "guildID"); !principal.IsAdmin() && localGuildAccess(guildID, principal.UserID) < access {
+				render.Status(r, http.StatusForbidden)
+				render.JSON(w, r, errAPI(newError(errCodeAPIForbidden, "guild "+guildID)))
+				return
+			}
+			next.ServeHTTP(w, r)
+		}))
+	}
+}
+
+// apiRequireGuildManager rejects API requests for a guild from anyone that can't manage it
+func apiRequireGuildManager(next http.Handler) http.Handler {
+	return apiRequireGuildAccess(GuildAccessManager)(next)
+}
+
+// apiRequireGuildMember rejects API requests for a guild from anyone that isn't a member of it
+func apiRequireGuildMember(next http.Handler) http.Handler {
+	return apiRequireGuildAccess(GuildAccessMember)(next)
+}
+
+// apiRequireUser rejects API requests for a user from anyone other than that user
+func apiRequireUser(next http.Handler) http.Handler {
+	return apiRequireAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal := apiPrincipal(r)
+		if userID := chi.URLParam(r, "userID"); !principal.IsAdmin() && principal.UserID != userID {
+			render.Status(r, http.StatusForbidden)
+			render.JSON(w, r, errAPI(newError(errCodeAPIForbidden, "user "+userID)))
+			return
+		}
+		next.ServeHTTP(w, r)
+	}))
+}
+
+// APIAuth returns the router for logging in with Discord and managing API tokens
+func APIAuth() *chi.Mux {
+	router := chi.NewRouter()
+
+	router.Get("/login", authGetLogin)       //Redirects to Discord to log in
+	router.Get("/callback", authGetCallback) //Finishes logging in with Discord and issues a session
+
+	router.Group(func(r chi.Router) {
+		r.Use(apiRequireAuth)
+
+		r.Get("/me", authGetMe)                        //Retrieves who the request was authenticated as
+		r.Post("/logout", authPostLogout)              //Revokes the session or token the request was authenticated with
+		r.Get("/tokens", authGetTokens)                //Retrieves every API token and session of the user
+		r.Post("/tokens", authPostToken)               //Creates a new API token for the user
+		r.Delete("/tokens/{tokenID}", authDeleteToken) //Revokes an API token or session of the user
+		r.Get("/audit", authGetAudit)                  //Retrieves the latest audit records of the user, or of every user for admins
+	})
+
+	return router
+}
+
+// oauth2RedirectURL returns where Discord should send users back to after logging in
+func oauth2RedirectURL() string {
+	return strings.TrimSuffix(botData().BotOptions.API.PublicURL, "/") + "/auth/callback"
+}
+
+// oauth2ClientID returns the client ID of the bot's application, which is the bot's user ID unless configured otherwise
+func oauth2ClientID() string {
+	if botData().BotKeys.DiscordClientID != "" {
+		return botData().BotKeys.DiscordClientID
+	}
+	if botData().DiscordSession != nil && botData().DiscordSession.State.User != nil {
+		return botData().DiscordSession.State.User.ID
+	}
+	return ""
+}
+
+func authGetLogin(w http.ResponseWriter, r *http.Request) {
+	if botData().BotKeys.DiscordClientSecret == "" || botData().BotOptions.API.PublicURL == "" {
+		render.Status(r, http.StatusNotImplemented)
+		render.JSON(w, r, errAPI(newError(errCodeAPIOAuth2Disabled)))
+		return
+	}
+
+	state, err := randomHex(16)
+	if err != nil {
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, errAPI(wrapError(errCodeAPIOAuth2Failed, err)))
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     apiStateCookie,
+		Value:    state,
+		Path:     "/auth",
+		MaxAge:   600,
+		HttpOnly: true,
+		Secure:   strings.HasPrefix(botData().BotOptions.API.PublicURL, "https://"),
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	if redirect := r.URL.Query().Get("redirect"); isLocalRedirect(redirect) {
+		http.SetCookie(w, &http.Cookie{
+			Name:     apiRedirectCookie,
+			Value:    url.QueryEscape(redirect),
+			Path:     "/auth",
+			MaxAge:   600,
+			HttpOnly: true,
+			Secure:   strings.HasPrefix(botData().BotOptions.API.PublicURL, "https://"),
+			SameSite: http.SameSiteLaxMode,
+		})
+	}
+
+	query := url.Values{
+		"client_id":     {oauth2ClientID()},
+		"redirect_uri":  {oauth2RedirectURL()},
+		"response_type": {"code"},
+		"scope":         {"identify"},
+		"state":         {state},
+		"prompt":        {"none"},
+	}
+	http.Redirect(w, r, discordOAuth2URL+"/authorize?"+query.Encode(), http.StatusFound)
+}
+
+func authGetCallback(w http.ResponseWriter, r *http.Request) {
+	stateCookie, err := r.Cookie(apiStateCookie)
+	if err != nil || r.URL.Query().Get("state") == "" || subtle.ConstantTimeCompare([]byte(stateCookie.Value), []byte(r.URL.Query().Get("state"))) != 1 {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, errAPI(newError(errCodeAPIOAuth2Failed)))
+		return
+	}
+	http.SetCookie(w, &http.Cookie{Name: apiStateCookie, Path: "/auth", MaxAge: -1})
+
+	userID, err := oauth2Identify(r.URL.Query().Get("code"))
+	if err != nil {
+		render.Status(r, http.StatusBadGateway)
+		render.JSON(w, r, errAPI(wrapError(errCodeAPIOAuth2Failed, err)))
+		return
+	}
+
+	scopes := make([]string, 0)
+	if userID == botData().BotOwnerID {
+		scopes = append(scopes, apiScopeAdmin)
+	}
+	lifetime := time.Duration(botData().BotOptions.API.SessionLifetime) * time.Hour
+	session, err := issueAPIToken(userID, "Discord login", scopes, true, lifetime)
+	if err != nil {
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, errAPI(wrapError(errCodeAPIOAuth2Failed, err)))
+		return
+	}
+	InfoAPI.With("user", userID, "token", session.Info.ID).Println("Logged in with Discord")
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     apiSessionCookie,
+		Value:    session.Token,
+		Path:     "/",
+		Expires:  session.Info.Expires,
+		HttpOnly: true,
+		Secure:   strings.HasPrefix(botData().BotOptions.API.PublicURL, "https://"),
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	//Send users that logged in from a page, such as the dashboard, back to it
+	if redirectCookie, err := r.Cookie(apiRedirectCookie); err == nil {
+		http.SetCookie(w, &http.Cookie{Name: apiRedirectCookie, Path: "/auth", MaxAge: -1})
+		if redirect, err := url.QueryUnescape(redirectCookie.Value); err == nil && isLocalRedirect(redirect) {
+			http.Redirect(w, r, redirect, http.StatusFound)
+			return
+		}
+	}
+	render.JSON(w, r, session)
+}
+
+// isLocalRedirect returns whether or not a redirect stays on the API host, so logging in can't send users elsewhere
+func isLocalRedirect(redirect string) bool {
+	return strings.HasPrefix(redirect, "/") && !strings.HasPrefix(redirect, "//") && !strings.HasPrefix(redirect, "/\\")
+}
+
+// oauth2Identify exchanges an OAuth2 authorization code for an access token, and returns the ID of the user it belongs to
+func oauth2Identify(code string) (string, error) {
+	if code == "" {
+		return "", fmt.Errorf("missing authorization code")
+	}
+
+	form := url.Values{
+		"client_id":     {oauth2ClientID()},
+		"client_secret": {botData().BotKeys.DiscordClientSecret},
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {oauth2RedirectURL()},
+	}
+	tokenResp, err := botHTTPClient.PostForm(discordOAuth2URL+"/token", form)
+	if err != nil {
+		return "", err
+	}
+	defer tokenResp.Body.Close()
+	if tokenResp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token exchange failed: %s", tokenResp.Status)
+	}
+	accessToken := struct {
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.NewDecoder(tokenResp.Body).Decode(&accessToken); err != nil {
+		return "", err
+	}
+
+	userReq, err := http.NewRequest("GET", discordAPIURL+"/users/@me", nil)
+	if err != nil {
+		return "", err
+	}
+	userReq.Header.Set("Authorization", "Bearer "+accessToken.AccessToken)
+	userResp, err := botHTTPClient.Do(userReq)
+	if err != nil {
+		return "", err
+	}
+	defer userResp.Body.Close()
+	if userResp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("fetching user failed: %s", userResp.Status)
+	}
+	user := struct {
+		ID string `json:"id"`
+	}{}
+	if err := json.NewDecoder(userResp.Body).Decode(&user); err != nil {
+		return "", err
+	}
+	if user.ID == "" {
+		return "", fmt.Errorf("Discord didn't return a user ID")
+	}
+	return user.ID, nil
+}
+
+func authGetMe(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, apiPrincipal(r))
+}
+
+func authPostLogout(w http.ResponseWriter, r *http.Request) {
+	apiTokensLock.Lock()
+	delete(apiTokens, apiPrincipal(r).TokenID)
+	apiTokensLock.Unlock()
+	apiAuthSave()
+
+	http.SetCookie(w, &http.Cookie{Name: apiSessionCookie, Path: "/", MaxAge: -1})
+	render.NoContent(w, r)
+}
+
+func authGetTokens(w http.ResponseWriter, r *http.Request) {
+	principal := apiPrincipal(r)
+	userID := principal.UserID
+	if user := r.URL.Query().Get("user"); user != "" && user != userID {
+		if !principal.IsAdmin() {
+			render.Status(r, http.StatusForbidden)
+			render.JSON(w, r, errAPI(newError(errCodeAPIForbidden, "user "+user)))
+			return
+		}
+		userID = user //Admins may look up anyone's tokens to revoke abusive ones
+	}
+
+	apiTokensLock.RLock()
+	defer apiTokensLock.RUnlock()
+
+	tokens := make([]*APIToken, 0)
+	for _, token := range apiTokens {
+		if token.UserID == userID && !token.Expired() {
+			tokens = append(tokens, token.redacted())
+		}
+	}
+	render.JSON(w, r, tokens)
+}
+
+func authPostToken(w http.ResponseWriter, r *http.Request) {
+	principal := apiPrincipal(r)
+
+	tokenRequest := &APITokenRequest{}
+	if err := json.NewDecoder(r.Body).Decode(tokenRequest); err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, errAPI(wrapError(errCodeAPIParamInvalid, err, "token request")))
+		return
+	}
+	if tokenRequest.Name == "" {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, errAPI(newError(errCodeAPIParamMissing, "name")))
+		return
+	}
+	if tokenRequest.ExpiresIn < 0 {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, errAPI(newError(errCodeAPIParamInvalid, "expiresIn")))
+		return
+	}
+	if tokenRequest.Scopes == nil {
+		tokenRequest.Scopes = make([]string, 0)
+	}
+	for _, scope := range tokenRequest.Scopes {
+		if !principal.HasScope(scope) {
+			render.Status(r, http.StatusForbidden)
+			render.JSON(w, r, errAPI(newError(errCodeAPIForbidden, "scope "+scope)))
+			return
+		}
+	}
+
+	token, err := issueAPIToken(principal.UserID, tokenRequest.Name, tokenRequest.Scopes, false, time.Duration(tokenRequest.ExpiresIn)*time.Hour)
+	if err != nil {
+		render.Status(r, http.StatusInternalServerError)
+		render.JSON(w, r, errAPI(err))
+		return
+	}
+	InfoAPI.With("user", principal.UserID, "token", token.Info.ID).Println("Created an API token")
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, token)
+}
+
+func authDeleteToken(w http.ResponseWriter, r *http.Request) {
+	principal := apiPrincipal(r)
+	tokenID := chi.URLParam(r, "tokenID")
+
+	apiTokensLock.Lock()
+	token, exists := apiTokens[tokenID]
+	if !exists || (token.UserID != principal.UserID && !principal.IsAdmin()) {
+		apiTokensLock.Unlock()
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, errAPI(newError(errCodeAPINotFound, "tokenID", "token")))
+		return
+	}
+	delete(apiTokens, tokenID)
+	apiTokensLock.Unlock()
+	apiAuthSave()
+	apiTokenLimiter.Forget(tokenID)
+
+	InfoAPI.With("user", principal.UserID, "token", tokenID).Println("Revoked an API token")
+	render.NoContent(w, r)
+}
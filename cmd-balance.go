@@ -26,27 +26,28 @@ func commandBalance(args []string, env *CommandEnvironment) *discordgo.MessageEm
 		}
 		var balances []string
 		for _, mention := range mentions {
-			if _, exists := userSettings[mention.ID]; !exists {
-				balances = append(balances, "<@!"+mention.ID+">: $0")
-			} else {
-				balances = append(balances, "<@!"+mention.ID+">: $"+strconv.Itoa(userSettings[mention.ID].Balance))
-			}
+			settings, _ := copyUserSettings(mention.ID)
+			balances = append(balances, "<@!"+mention.ID+">: $"+strconv.Itoa(settings.Balance))
 		}
 		return NewGenericEmbedAdvanced("Balance", "The balances of the mentioned users are available below:\n\n"+strings.Join(balances, "\n"), 0x85BB65)
 	}
 
-	if userSettings[env.User.ID].DailyNext.IsZero() {
-		return NewGenericEmbedAdvanced("Balance", "Your current balance is __$"+strconv.Itoa(userSettings[env.User.ID].Balance)+"__!\n\nYou may run "+env.BotPrefix+"daily to receive your first __$200__ daily credits.", 0x85BB65)
+	settings, _ := copyUserSettings(env.User.ID)
+	if settings.DailyNext.IsZero() {
+		return NewGenericEmbedAdvanced("Balance", "Your current balance is __$"+strconv.Itoa(settings.Balance)+"__!\n\nYou may run "+env.BotPrefix+"daily to receive your first __$200__ daily credits.", 0x85BB65)
 	}
 
-	if time.Now().After(userSettings[env.User.ID].DailyNext) {
-		return NewGenericEmbedAdvanced("Balance", "Your current balance is __$"+strconv.Itoa(userSettings[env.User.ID].Balance)+"__!\n\nYou may run "+env.BotPrefix+"daily to receive your next __$200__ daily credits.", 0x85BB65)
+	if time.Now().After(settings.DailyNext) {
+		return NewGenericEmbedAdvanced("Balance", "Your current balance is __$"+strconv.Itoa(settings.Balance)+"__!\n\nYou may run "+env.BotPrefix+"daily to receive your next __$200__ daily credits.", 0x85BB65)
 	}
 
-	return NewGenericEmbedAdvanced("Balance", "Your current balance is __$"+strconv.Itoa(userSettings[env.User.ID].Balance)+"__!\n\nYou may receive your next __$200__ daily credits approximately "+humanize.Time(userSettings[env.User.ID].DailyNext)+".", 0x85BB65)
+	return NewGenericEmbedAdvanced("Balance", "Your current balance is __$"+strconv.Itoa(settings.Balance)+"__!\n\nYou may receive your next __$200__ daily credits approximately "+humanize.Time(settings.DailyNext)+".", 0x85BB65)
 }
 
 func commandDaily(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
+	userSettingsLock.Lock()
+	defer userSettingsLock.Unlock()
+
 	if userSettings[env.User.ID].DailyNext.IsZero() {
 		userSettings[env.User.ID].Balance += 5000
 		userSettings[env.User.ID].DailyNext = time.Now().Add(time.Hour * 24)
@@ -64,31 +65,34 @@ func commandDaily(args []string, env *CommandEnvironment) *discordgo.MessageEmbe
 
 func commandTransfer(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	if len(env.Message.Mentions) <= 0 {
-		return NewErrorEmbed("Transfer Error", "You must specify a user to transfer credits to.")
+		return env.errorEmbed(newError(errCodeTransferUserMissing))
 	}
 	if len(env.Message.Mentions) > 1 {
-		return NewErrorEmbed("Transfer Error", "You cannot specify more than one user to transfer credits to.")
+		return env.errorEmbed(newError(errCodeTransferUserMultiple))
 	}
 
 	credits, err := strconv.Atoi(args[0])
 	if err != nil {
-		return NewErrorEmbed("Transfer Error", "``"+args[0]+"`` is not a valid number.")
+		return env.errorEmbed(wrapError(errCodeCommandInvalidNumber, err, args[0]))
 	}
 
 	if credits <= 0 {
-		return NewErrorEmbed("Transfer Error", "You cannot transfer less than __$1__ in credits.")
-	}
-
-	if credits > userSettings[env.User.ID].Balance {
-		return NewErrorEmbed("Transfer Error", "You have insufficient credits to perform this transfer.")
+		return env.errorEmbed(newError(errCodeTransferTooLow))
 	}
 
 	target := env.Message.Mentions[0]
 	if target.Bot {
-		return NewErrorEmbed("Transfer Error", "You cannot transfer credits to a bot!")
+		return env.errorEmbed(newError(errCodeTransferToBot))
 	}
 	initializeUserSettings(target.ID)
 
+	userSettingsLock.Lock()
+	defer userSettingsLock.Unlock()
+
+	if credits > userSettings[env.User.ID].Balance {
+		return env.errorEmbed(newError(errCodeTransferInsufficient))
+	}
+
 	userSettings[env.User.ID].Balance -= credits
 	userSettings[target.ID].Balance += credits
 
@@ -1,10 +1,8 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"net/http"
 	"os"
 	"os/exec"
 	"sort"
@@ -12,60 +10,18 @@ import (
 	"strings"
 	"time"
 
-	duckduckgo "github.com/JoshuaDoes/duckduckgolang"
-	soundcloud "github.com/JoshuaDoes/go-soundcloud"
-	wolfram "github.com/JoshuaDoes/go-wolfram"
 	"github.com/bwmarrin/discordgo"
-	"github.com/google/go-github/github"
-	klogger "github.com/koffeinsource/go-klogger"
-	xkcd "github.com/nishanths/go-xkcd"
-	"google.golang.org/api/googleapi/transport"
-	"google.golang.org/api/youtube/v3"
 	git "gopkg.in/src-d/go-git.v4"
 )
 
 func commandReload(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
-	configFileHandle, err := os.Open(configFile)
-	defer configFileHandle.Close()
-	if err != nil {
-		return NewErrorEmbed("Reload Error", "There was an error loading the bot configuration file.")
-	}
-
-	configParser := json.NewDecoder(configFileHandle)
-	if err = configParser.Decode(&botData); err != nil {
-		return NewErrorEmbed("Reload Error", "There was an error applying the bot configuration to memory. State and/or configuration may be corrupted, consider checking the configuration and restarting the bot process.")
-	}
-
-	err = botData.PrepConfig()
-	if err != nil {
-		return NewErrorEmbed("Reload Error", "There were some inconsistencies with the bot configuration. State and/or configuration may be corrupted, consider checking the configuration and restarting the bot process.")
-	}
-
-	if botData.BotOptions.UseDuckDuckGo {
-		botData.BotClients.DuckDuckGo = &duckduckgo.Client{AppName: botData.BotKeys.DuckDuckGoAppName}
-	}
-	if botData.BotOptions.UseImgur {
-		botData.BotClients.Imgur.HTTPClient = &http.Client{}
-		botData.BotClients.Imgur.Log = &klogger.CLILogger{}
-		botData.BotClients.Imgur.ImgurClientID = botData.BotKeys.ImgurClientID
-	}
-	if botData.BotOptions.UseSoundCloud {
-		botData.BotClients.SoundCloud = &soundcloud.Client{ClientID: botData.BotKeys.SoundCloudClientID}
-	}
-	if botData.BotOptions.UseWolframAlpha {
-		botData.BotClients.Wolfram = &wolfram.Client{AppID: botData.BotKeys.WolframAppID}
-	}
-	if botData.BotOptions.UseXKCD {
-		botData.BotClients.XKCD = xkcd.NewClient()
-	}
-	if botData.BotOptions.UseYouTube {
-		httpClient := &http.Client{
-			Transport: &transport.APIKey{Key: botData.BotKeys.YouTubeAPIKey},
+	errs := reloadBotData()
+	if len(errs) > 0 {
+		errList := ""
+		for _, err := range errs {
+			errList += "\n- " + err.Error()
 		}
-		botData.BotClients.YouTube, _ = youtube.New(httpClient)
-	}
-	if botData.BotOptions.UseGitHub {
-		botData.BotClients.GitHub = github.NewClient(nil)
+		return env.errorEmbed(newError(errCodeReloadRejected, errList))
 	}
 
 	return NewGenericEmbed("Reload", "Successfully reloaded the bot configuration.")
@@ -73,13 +29,13 @@ func commandReload(args []string, env *CommandEnvironment) *discordgo.MessageEmb
 
 func commandRestart(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	//Tell the user we're restarting
-	botData.DiscordSession.ChannelMessageSendEmbed(env.Channel.ID, NewGenericEmbed("Restart", "Restarting "+botData.BotName+"..."))
+	botData().DiscordSession.ChannelMessageSendEmbed(env.Channel.ID, NewGenericEmbed("Restart", "Restarting "+botData().BotName+"..."))
 
 	//Write the current channel ID to a restart file for the bot to read after the restart
 	ioutil.WriteFile(".restart", []byte(env.Channel.ID), 0644)
 
-	//Save the state so it's not lost
-	stateSaveAll()
+	//Save the state and leave all voice channels so playback can resume after the restart
+	shutdownBot("restart")
 
 	//Close the bot process, as the MASTER process will open it again
 	os.Exit(0)
@@ -93,7 +49,7 @@ func commandUpdate(args []string, env *CommandEnvironment) *discordgo.MessageEmb
 
 	output, err := golangver.CombinedOutput()
 	if len(output) <= 0 || err != nil {
-		return NewErrorEmbed("Update Error", "Unable to execute ``go version``. Make sure Go ["+GolangVersion+"] is installed on the host machine.\n\n"+fmt.Sprintf("%s\n```%v```", output, err))
+		return env.errorEmbed(wrapError(errCodeUpdateGoMissing, err, GolangVersion, output, err))
 	}
 
 	//Check if the govvv wrapper is installed
@@ -101,13 +57,13 @@ func commandUpdate(args []string, env *CommandEnvironment) *discordgo.MessageEmb
 
 	output, _ = govvv.CombinedOutput()
 	if len(output) <= 0 {
-		return NewErrorEmbed("Update Error", "Unable to execute ``govvv``. Make sure govvv is installed on the host machine."+fmt.Sprintf("```%v```", output))
+		return env.errorEmbed(newError(errCodeUpdateGovvvMissing, output))
 	}
 
 	//Create a temporary directory to store the git repository in
 	repodir, err := ioutil.TempDir("", "clinetupdate")
 	if err != nil {
-		return NewErrorEmbed("Update Error", "Error creating a temporary directory to store the Clinet git repository in.")
+		return env.errorEmbed(wrapError(errCodeUpdateTempDirFailed, err))
 	}
 	defer os.RemoveAll(repodir)
 
@@ -117,84 +73,94 @@ func commandUpdate(args []string, env *CommandEnvironment) *discordgo.MessageEmb
 		Depth: 1,
 	})
 	if err != nil {
-		return NewErrorEmbed("Update Error", "Error cloning the git repo.")
+		return env.errorEmbed(wrapError(errCodeUpdateCloneFailed, err))
 	}
 	ref, err := repo.Head()
 	if err != nil {
-		return NewErrorEmbed("Update Error", "Error finding the HEAD of the git repo.")
+		return env.errorEmbed(wrapError(errCodeUpdateHeadFailed, err))
 	}
 	commit, err := repo.CommitObject(ref.Hash())
 	if err != nil {
-		return NewErrorEmbed("Update Error", "Error fetching the HEAD commit of the git repo.")
+		return env.errorEmbed(wrapError(errCodeUpdateCommitFailed, err))
 	}
 	commitHash := commit.Hash.String()
 	if commitHash == GitCommitFull {
 		if len(args) <= 0 || len(args) >= 1 && args[0] != "force" {
-			return NewGenericEmbed("Update", botData.BotName+" is already up to date!")
+			return NewGenericEmbed("Update", botData().BotName+" is already up to date!")
 		}
 	}
 
 	//Tell the user we're updating
-	botData.DiscordSession.ChannelMessageSendEmbed(env.Channel.ID, NewGenericEmbed("Update", "Updating "+botData.BotName+" to commit ``"+commitHash+"`` from commit ``"+GitCommitFull+"``..."))
+	botData().DiscordSession.ChannelMessageSendEmbed(env.Channel.ID, NewGenericEmbed("Update", "Updating "+botData().BotName+" to commit ``"+commitHash+"`` from commit ``"+GitCommitFull+"``..."))
 
 	//Build the update
 	outputFile := repodir + "/" + os.Args[0]
 
 	govvvbuild := exec.Command("govvv", "build", "-o", outputFile)
 	govvvbuild.Dir = repodir
-	if !botData.DebugMode {
+	if !botData().DebugMode {
 		govvvbuild.Args = append(govvvbuild.Args, "-ldflags=-s -w")
 	}
 
 	output, err = govvvbuild.CombinedOutput()
 	if err != nil {
-		return NewErrorEmbed("Update Error", "Unable to build "+botData.BotName+" ``"+commitHash+"``.\n\n"+fmt.Sprintf("```%s```", output))
+		return env.errorEmbed(wrapError(errCodeUpdateBuildFailed, err, botData().BotName, commitHash, output))
 	}
 
 	if _, err = os.Stat(outputFile); os.IsNotExist(err) {
-		return NewErrorEmbed("Update Error", "Unable to find the updated build of "+botData.BotName+" ``"+commitHash+"``.\n\n"+fmt.Sprintf("```%v```", err))
+		return env.errorEmbed(wrapError(errCodeUpdateBuildMissing, err, botData().BotName, commitHash, err))
+	}
+
+	//Smoke test the new build against the current configuration and state before trusting it
+	if err = verifyBinary(outputFile); err != nil {
+		return env.errorEmbed(wrapError(errCodeUpdateVerifyFailed, err, botData().BotName, commitHash, err))
 	}
 
-	os.Rename(os.Args[0], os.Args[0]+".old")
-	os.Rename(outputFile, os.Args[0])
+	if err = swapBinary(outputFile); err != nil {
+		return env.errorEmbed(wrapError(errCodeUpdateInstallFailed, err, botData().BotName, commitHash, err))
+	}
+
+	//Record the update so the master process can roll it back if it crash loops
+	writeUpdateRecord(&UpdateRecord{Started: time.Now(), FromBuild: BuildID, ToCommit: commitHash, ChannelID: env.Channel.ID})
 
 	//Write the current channel ID to an update file for the bot to read after restarting
 	ioutil.WriteFile(".update", []byte(env.Channel.ID), 0644)
 
-	//Save the state so it's not lost
-	stateSaveAll()
-
 	//Mark updating flag as true so interrupted events (such as voice playback) will notify users that an update interrupted the event
-	botData.Updating = true
+	botData().Updating = true
 
-	//Leave all voice channels
-	for _, voiceIDRow := range voiceData {
-		if voiceIDRow.IsConnected() {
-			if voiceIDRow.IsStreaming() {
-				//Notify users that an update is occuring
-				botData.DiscordSession.ChannelMessageSendEmbed(voiceIDRow.TextChannelID, NewEmbed().SetTitle("Update").SetDescription("Your audio playback has been interrupted for a "+botData.BotName+" update event. You may resume playback in a few seconds.").SetColor(0x1C1C1C).MessageEmbed)
+	//Save the state and leave all voice channels so playback can resume after the update
+	shutdownBot("update")
 
-				debugLog("> Stopping stream in voice channel "+voiceIDRow.VoiceConnection.ChannelID+"...", false)
-				voiceIDRow.Stop()
-			}
-			debugLog("> Closing connection to voice channel "+voiceIDRow.VoiceConnection.ChannelID+"...", false)
-			voiceIDRow.VoiceConnection.Close()
-		}
+	//Spawn a new master process that will kill this one
+	if err = spawnMaster(); err != nil {
+		return env.errorEmbed(newError(errCodeUpdateSpawnFailed))
 	}
 
-	debugLog("> Disconnecting from Discord...", true)
-	botData.DiscordSession.Close()
+	return NewGenericEmbed("Update", "Waiting for update to finish...")
+}
 
-	//Spawn a new bot process that will kill this one
-	botProcess := exec.Command(os.Args[0], "-killold", "true")
-	botProcess.Stdout = os.Stdout
-	botProcess.Stderr = os.Stderr
-	err = botProcess.Start()
-	if err != nil {
-		return NewErrorEmbed("Update Error", "Unable to spawn the updated bot process.")
+func commandRollback(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
+	if err := rollbackBinary(); err != nil {
+		return env.errorEmbed(wrapError(errCodeRollbackFailed, err, botData().BotName, err))
 	}
+	os.Remove(updatePendingFile)
 
-	return NewGenericEmbed("Update", "Waiting for update to finish...")
+	//Tell the user we're rolling back
+	botData().DiscordSession.ChannelMessageSendEmbed(env.Channel.ID, NewGenericEmbed("Rollback", "Rolling back "+botData().BotName+" to the previous build..."))
+
+	//Write the current channel ID to a restart file for the bot to read after restarting
+	ioutil.WriteFile(".restart", []byte(env.Channel.ID), 0644)
+
+	//Save the state and leave all voice channels so playback can resume after the rollback
+	shutdownBot("rollback")
+
+	//Spawn a new master process that will kill this one
+	if err := spawnMaster(); err != nil {
+		return env.errorEmbed(newError(errCodeRollbackSpawnFailed))
+	}
+
+	return nil
 }
 
 func commandSudo(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
@@ -202,14 +168,14 @@ func commandSudo(args []string, env *CommandEnvironment) *discordgo.MessageEmbed
 	userID = strings.TrimLeft(userID, "<@!")
 	userID = strings.TrimRight(userID, ">")
 
-	user, err := botData.DiscordSession.User(userID)
+	user, err := botData().DiscordSession.User(userID)
 	if err != nil {
-		return NewErrorEmbed("Sudo Error", "Invalid user ``"+args[0]+"``.")
+		return env.errorEmbed(wrapError(errCodeCommandInvalidUser, err, args[0]))
 	}
 
-	member, err := botData.DiscordSession.GuildMember(env.Guild.ID, userID)
+	member, err := botData().DiscordSession.GuildMember(env.Guild.ID, userID)
 	if err != nil {
-		return NewErrorEmbed("Sudo Error", "Specified user does not exist in current guild.")
+		return env.errorEmbed(wrapError(errCodeSudoNotMember, err))
 	}
 
 	env.User = user
@@ -222,6 +188,28 @@ func commandSudo(args []string, env *CommandEnvironment) *discordgo.MessageEmbed
 	return callCommand(args[1], make([]string, 0), env)
 }
 
+func commandUserData(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
+	userID := args[1]
+	userID = strings.TrimLeft(userID, "<@!")
+	userID = strings.TrimRight(userID, ">")
+
+	switch args[0] {
+	case "export":
+		err := sendUserDataExport(env.User.ID, exportUserData(userID, env.Guild.ID))
+		if err != nil {
+			return env.errorEmbed(wrapError(errCodeUserDataExportFailed, err))
+		}
+		return NewGenericEmbed("User Data", "Sent a copy of the data for <@!"+userID+"> to your DMs.")
+	case "delete", "purge":
+		if len(args) < 3 || args[2] != "confirm" {
+			return env.errorEmbed(newError(errCodeUserDataPurgeUnconfirmed, userID, env.BotPrefix, args[0], args[1]))
+		}
+		result := purgeUserData(userID, env.Guild.ID)
+		return NewGenericEmbed("User Data", "Deleted the data for <@!"+userID+">.\n\n"+result.Summary())
+	}
+	return env.errorEmbed(newError(errCodeCommandUnknownAction, args[0]))
+}
+
 func commandStatus(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	gameType := discordgo.ActivityTypeGame
 	name := args[1:]
@@ -232,7 +220,7 @@ func commandStatus(args []string, env *CommandEnvironment) *discordgo.MessageEmb
 		break
 	case "1", "streaming", "stream", "live", "livestream", "livestreaming":
 		if len(args) < 2 {
-			return NewErrorEmbed("Status Error", "You must specify the URL of the stream before the status message!")
+			return env.errorEmbed(newError(errCodeStatusURLMissing))
 		}
 		gameType = discordgo.ActivityTypeStreaming
 		url = args[1]
@@ -242,10 +230,10 @@ func commandStatus(args []string, env *CommandEnvironment) *discordgo.MessageEmb
 	case "3", "watching", "watch", "view":
 		gameType = 3
 	default:
-		return NewErrorEmbed("Status Error", "Unknown status type: ", args[0])
+		return env.errorEmbed(newError(errCodeStatusTypeUnknown, args[0]))
 	}
 
-	err := botData.DiscordSession.UpdateStatusComplex(discordgo.UpdateStatusData{
+	err := botData().DiscordSession.UpdateStatusComplex(discordgo.UpdateStatusData{
 		Activities: []*discordgo.Activity{
 			&discordgo.Activity{
 				Name: strings.Join(name, " "),
@@ -255,7 +243,7 @@ func commandStatus(args []string, env *CommandEnvironment) *discordgo.MessageEmb
 		},
 	})
 	if err != nil {
-		return NewErrorEmbed("Status Error", "There was an error setting the new status!")
+		return env.errorEmbed(wrapError(errCodeStatusFailed, err))
 	}
 
 	return NewGenericEmbed("Status", "Set the new status successfully!")
@@ -263,46 +251,46 @@ func commandStatus(args []string, env *CommandEnvironment) *discordgo.MessageEmb
 
 func commandAbout(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	return NewEmbed().
-		SetTitle(botData.BotName+" - About").
-		SetDescription(botData.BotName+" is a Discord bot written in Google's Go programming language, intended for conversation and fact-based queries.").
-		AddField("How can I use "+botData.BotName+" in my server?", "Simply open the Invite Link at the end of this message and follow the on-screen instructions.").
-		AddField("How can I help keep "+botData.BotName+" running?", "The best ways to help keep "+botData.BotName+" running are to either donate using the Donation Link or contribute to the source code using the Source Code Link, both at the end of this message.").
-		AddField("How can I use "+botData.BotName+"?", "There are many ways to make use of "+botData.BotName+".\n1) Type ``"+env.BotPrefix+"help`` and try using some of the available commands.\n2) Ask "+botData.BotName+" a question, ex: ``@"+botData.DiscordSession.State.User.String()+", what time is it?`` or ``@"+botData.DiscordSession.State.User.String()+", what is DiscordApp?``.").
-		AddField("Where can I join the "+botData.BotName+" Discord server?", "If you would like to get help and support with "+botData.BotName+" or experiment with the latest and greatest of "+botData.BotName+", use the Discord Server Invite Link at the end of this message.").
-		AddField("Bot Invite Link", botData.BotInviteURL).
-		AddField("Discord Server Invite Link", botData.BotDiscordURL).
-		AddField("Donation Link", botData.BotDonationURL).
-		AddField("Source Code Link", botData.BotSourceURL).
+		SetTitle(botData().BotName+" - About").
+		SetDescription(botData().BotName+" is a Discord bot written in Google's Go programming language, intended for conversation and fact-based queries.").
+		AddField("How can I use "+botData().BotName+" in my server?", "Simply open the Invite Link at the end of this message and follow the on-screen instructions.").
+		AddField("How can I help keep "+botData().BotName+" running?", "The best ways to help keep "+botData().BotName+" running are to either donate using the Donation Link or contribute to the source code using the Source Code Link, both at the end of this message.").
+		AddField("How can I use "+botData().BotName+"?", "There are many ways to make use of "+botData().BotName+".\n1) Type ``"+env.BotPrefix+"help`` and try using some of the available commands.\n2) Ask "+botData().BotName+" a question, ex: ``@"+botData().DiscordSession.State.User.String()+", what time is it?`` or ``@"+botData().DiscordSession.State.User.String()+", what is DiscordApp?``.").
+		AddField("Where can I join the "+botData().BotName+" Discord server?", "If you would like to get help and support with "+botData().BotName+" or experiment with the latest and greatest of "+botData().BotName+", use the Discord Server Invite Link at the end of this message.").
+		AddField("Bot Invite Link", botData().BotInviteURL).
+		AddField("Discord Server Invite Link", botData().BotDiscordURL).
+		AddField("Donation Link", botData().BotDonationURL).
+		AddField("Source Code Link", botData().BotSourceURL).
 		SetColor(0x1C1C1C).MessageEmbed
 }
 func commandInvite(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	return NewEmbed().
-		SetTitle(botData.BotName+" - Invite").
-		SetDescription("Below are the available invite links for "+botData.BotName+".").
-		AddField("Bot Invite", botData.BotInviteURL).
-		AddField("Discord Server (Support/Development/Testing)", botData.BotDiscordURL).
+		SetTitle(botData().BotName+" - Invite").
+		SetDescription("Below are the available invite links for "+botData().BotName+".").
+		AddField("Bot Invite", botData().BotInviteURL).
+		AddField("Discord Server (Support/Development/Testing)", botData().BotDiscordURL).
 		SetColor(0x1C1C1C).MessageEmbed
 }
 func commandDonate(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	return NewEmbed().
-		SetTitle(botData.BotName+" - Donate").
-		SetDescription("Below are the available donation links for "+botData.BotName+".").
-		AddField("PayPal", botData.BotDonationURL).
+		SetTitle(botData().BotName+" - Donate").
+		SetDescription("Below are the available donation links for "+botData().BotName+".").
+		AddField("PayPal", botData().BotDonationURL).
 		SetColor(0x1C1C1C).MessageEmbed
 }
 func commandSource(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	return NewEmbed().
-		SetTitle(botData.BotName+" - Source Code").
-		SetDescription("Below are the available source code links for "+botData.BotName+".").
-		AddField("GitHub", botData.BotSourceURL).
+		SetTitle(botData().BotName+" - Source Code").
+		SetDescription("Below are the available source code links for "+botData().BotName+".").
+		AddField("GitHub", botData().BotSourceURL).
 		SetColor(0x1C1C1C).MessageEmbed
 }
 func commandHelp(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	//First see if help text is being requested for a particular command
 	if len(args) > 0 {
-		if command, exists := botData.Commands[args[0]]; exists {
+		if command, exists := botData().Commands[args[0]]; exists {
 			if command.IsAlternateOf != "" {
-				if commandAlternate, exists := botData.Commands[command.IsAlternateOf]; exists {
+				if commandAlternate, exists := botData().Commands[command.IsAlternateOf]; exists {
 					command = commandAlternate
 				} else {
 					return nil
@@ -314,7 +302,7 @@ func commandHelp(args []string, env *CommandEnvironment) *discordgo.MessageEmbed
 
 	//Before we fetch help text data, we need to have an alphabetical listing of commands
 	var commandMapKeys []string
-	for commandMapKey := range botData.Commands {
+	for commandMapKey := range botData().Commands {
 		commandMapKeys = append(commandMapKeys, commandMapKey)
 	}
 	sort.Strings(commandMapKeys)
@@ -324,13 +312,13 @@ func commandHelp(args []string, env *CommandEnvironment) *discordgo.MessageEmbed
 
 	//Iterate over the alphabetically sorted command list and add each listed command to the help embed field list
 	for _, commandName := range commandMapKeys {
-		command := botData.Commands[commandName]
+		command := botData().Commands[commandName]
 		if command.IsAlternateOf == "" {
-			if command.IsAdministrative && env.User.ID != botData.BotOwnerID {
+			if command.IsAdministrative && env.User.ID != botData().BotOwnerID {
 				continue
 			}
 			if command.RequiredPermissions != 0 {
-				if permissionsAllowed, _ := MemberHasPermission(botData.DiscordSession, env.Guild.ID, env.User.ID, env.Channel.ID, command.RequiredPermissions); permissionsAllowed == false {
+				if permissionsAllowed, _ := MemberHasPermission(botData().DiscordSession, env.Guild.ID, env.User.ID, env.Channel.ID, command.RequiredPermissions); permissionsAllowed == false {
 					continue
 				}
 			}
@@ -343,20 +331,20 @@ func commandHelp(args []string, env *CommandEnvironment) *discordgo.MessageEmbed
 	if len(args) > 0 {
 		newPageNumber, err := strconv.Atoi(args[0])
 		if err != nil {
-			return NewErrorEmbed("Help Error", "Invalid command or page number.")
+			return env.errorEmbed(wrapError(errCodeHelpInvalid, err))
 		}
 		pageNumber = newPageNumber
 	}
 
 	//Create the help page and give it the command list
-	helpEmbed, totalPages, err := page(commandFields, pageNumber, botData.BotOptions.HelpMaxResults)
+	helpEmbed, totalPages, err := page(commandFields, pageNumber, botData().BotOptions.HelpMaxResults)
 	if err != nil {
-		return NewErrorEmbed("Help Error", fmt.Sprintf("%v", err))
+		return env.errorEmbed(wrapError(errCodeCommandPageNotFound, err, pageNumber))
 	}
 
 	//Prepare the help page to look nice
 	helpEmbed.
-		SetTitle(botData.BotName + " - Help").
+		SetTitle(botData().BotName + " - Help").
 		SetDescription("A list of commands you have permission to use.").
 		SetFooter("Page " + strconv.Itoa(pageNumber) + " of " + strconv.Itoa(totalPages) + " | " + env.BotPrefix + env.Command + " {page}").
 		SetColor(0xFAFAFA)
@@ -366,7 +354,7 @@ func commandHelp(args []string, env *CommandEnvironment) *discordgo.MessageEmbed
 }
 func commandVersion(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	return NewEmbed().
-		SetTitle(botData.BotName+" - Version").
+		SetTitle(botData().BotName+" - Version").
 		AddField("Build ID", BuildID).
 		AddField("Build Date", BuildDate).
 		AddField("Latest Development", GitCommitMsg).
@@ -376,7 +364,7 @@ func commandVersion(args []string, env *CommandEnvironment) *discordgo.MessageEm
 }
 func commandCredits(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	return NewEmbed().
-		SetTitle(botData.BotName+" - Credits").
+		SetTitle(botData().BotName+" - Credits").
 		AddField("Bot Development", "- JoshuaDoes (2018)").
 		AddField("Programming Language", "- Golang").
 		AddField("Golang Libraries", "[duckduckgolang](https://github.com/JoshuaDoes/duckduckgolang), "+
@@ -405,8 +393,8 @@ func commandCredits(args []string, env *CommandEnvironment) *discordgo.MessageEm
 
 func commandPing(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	//Create a list of ping test results
-	pingResults := make([]int, botData.BotOptions.MaxPingCount)
-	pingResultsStr := make([]string, botData.BotOptions.MaxPingCount)
+	pingResults := make([]int, botData().BotOptions.MaxPingCount)
+	pingResultsStr := make([]string, botData().BotOptions.MaxPingCount)
 
 	//Create ping embed
 	pingEmbed := NewGenericEmbed("Ping!", "Waiting for ping...")
@@ -417,7 +405,7 @@ func commandPing(args []string, env *CommandEnvironment) *discordgo.MessageEmbed
 		timeCurrent := int(time.Now().UnixNano() / 1000000)
 
 		//Send ping embed
-		pingMessage, err := botData.DiscordSession.ChannelMessageSendEmbed(env.Channel.ID, pingEmbed)
+		pingMessage, err := botData().DiscordSession.ChannelMessageSendEmbed(env.Channel.ID, pingEmbed)
 		if err != nil {
 			pingResults[i] = -1
 			continue
@@ -427,7 +415,7 @@ func commandPing(args []string, env *CommandEnvironment) *discordgo.MessageEmbed
 		timeNew := int(time.Now().UnixNano() / 1000000)
 
 		//Delete pingMessage to prevent spam
-		botData.DiscordSession.ChannelMessageDelete(env.Channel.ID, pingMessage.ID)
+		botData().DiscordSession.ChannelMessageDelete(env.Channel.ID, pingMessage.ID)
 
 		//Subtract new time from old time to get the ping
 		timeDiff := timeNew - timeCurrent
@@ -470,8 +458,8 @@ func commandPing(args []string, env *CommandEnvironment) *discordgo.MessageEmbed
 	//Return ping results
 	return NewEmbed().
 		SetTitle("Pong!").
-		SetDescription(fmt.Sprintf("Average ping is ``%dms``. A total of ``%d/%d`` ping tests failed.\n*%s*", pingAverage, jitterCount, botData.BotOptions.MaxPingCount, addonMessage)).
+		SetDescription(fmt.Sprintf("Average ping is ``%dms``. A total of ``%d/%d`` ping tests failed.\n*%s*", pingAverage, jitterCount, botData().BotOptions.MaxPingCount, addonMessage)).
 		AddField("Ping Results", strings.Join(pingResultsStr, ", ")).
-		SetFooter(fmt.Sprintf("Ping results are determined by sending %d messages and determining how long it takes for each message to send successfully and return a success code. The average ping is determined by taking the sum of all of the ping results and dividing it by %d.", botData.BotOptions.MaxPingCount, botData.BotOptions.MaxPingCount)).
+		SetFooter(fmt.Sprintf("Ping results are determined by sending %d messages and determining how long it takes for each message to send successfully and return a success code. The average ping is determined by taking the sum of all of the ping results and dividing it by %d.", botData().BotOptions.MaxPingCount, botData().BotOptions.MaxPingCount)).
 		SetColor(0x1C1C1C).MessageEmbed
 }
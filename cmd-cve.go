@@ -8,7 +8,7 @@ import (
 func commandCVE(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	cveData, err := cve.GetCVE(args[0])
 	if err != nil {
-		return NewErrorEmbed("CVE Error", "There was an error fetching information about CVE ``"+args[0]+"``.")
+		return env.errorEmbed(wrapError(errCodeCVEFailed, err, args[0]))
 	}
 	return NewEmbed().
 		SetTitle(args[0]).
@@ -2,13 +2,59 @@ package main
 
 import (
 	"strconv"
+	"strings"
 
 	"github.com/bwmarrin/discordgo"
 )
 
 //Debug commands
 func commandDebug(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
-	botData.DebugMode = !botData.DebugMode
+	if len(args) > 0 && args[0] == "level" {
+		return commandDebugLevel(args[1:], env)
+	}
 
-	return NewGenericEmbed("Debug Mode", "Debug mode has been set to "+strconv.FormatBool(botData.DebugMode)+".")
+	botData().DebugMode = !botData().DebugMode
+
+	//Debug mode logs everything, so turning it off goes back to the configured log level
+	if botData().DebugMode {
+		setLogLevel("", LogDebug)
+	} else {
+		level, _ := parseLogLevel(botData().BotOptions.Logging.Level)
+		setLogLevel("", level)
+	}
+
+	return NewGenericEmbed("Debug Mode", "Debug mode has been set to "+strconv.FormatBool(botData().DebugMode)+".")
+}
+
+// commandDebugLevel lists the log levels, or sets the log level of everything or of a subsystem until the configuration is reloaded
+func commandDebugLevel(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
+	if len(args) == 0 {
+		levels := logLevels()
+		levelsEmbed := NewEmbed().
+			SetTitle("Log Levels").
+			AddField("default", levels[""].String())
+		for _, subsystem := range logSubsystems {
+			levelsEmbed.AddField(subsystem, levels[subsystem].String())
+		}
+		return levelsEmbed.InlineAllFields().SetColor(0x1C1C1C).MessageEmbed
+	}
+
+	level, err := parseLogLevel(args[0])
+	if err != nil {
+		return env.errorEmbed(wrapError(errCodeDebugLogLevelUnknown, err, args[0]))
+	}
+
+	if len(args) > 1 {
+		subsystem := strings.ToLower(args[1])
+		if !isLogSubsystem(subsystem) {
+			return env.errorEmbed(newError(errCodeDebugSubsystemUnknown, args[1], strings.Join(logSubsystems, ", ")))
+		}
+		setLogLevel(subsystem, level)
+		Info.With("user", env.User.ID).Printf("Log level of the %s subsystem set to %s", subsystem, level)
+		return NewGenericEmbed("Log Levels", "The %s log level has been set to %s.", subsystem, level)
+	}
+
+	setLogLevel("", level)
+	Info.With("user", env.User.ID).Printf("Log level set to %s", level)
+	return NewGenericEmbed("Log Levels", "The log level has been set to %s.", level)
 }
@@ -40,24 +40,24 @@ func commandFeed(args []CommandArgument, env *CommandEnvironment) *discordgo.Mes
 
 	//isAll := false
 	isSettingChannel := false
-	frequency := botData.BotOptions.FeedFrequency
+	frequency := botData().BotOptions.FeedFrequency
 
 	for _, arg := range args {
 		switch arg.Name {
 		case "add":
 			if len(feedsToEdit) > 0 || len(feedsToRemove) > 0 || isListing {
-				return NewErrorEmbed("Feed Error", "You cannot mix arguments dictating what to do!")
+				return env.errorEmbed(newError(errCodeCommandMixedArguments))
 			}
 			if arg.Value == "" {
-				return NewErrorEmbed("Feed Error", "You must specify a feed to add when using the ``-add`` argument.")
+				return env.errorEmbed(newError(errCodeFeedAddMissing))
 			}
 			if _, err := url.ParseRequestURI(arg.Value); err != nil {
-				return NewErrorEmbed("Feed Error", "``"+arg.Value+"`` is not a valid URL.")
+				return env.errorEmbed(newError(errCodeFeedURLInvalid, arg.Value))
 			}
 
 			for _, feed := range guildSettings[env.Guild.ID].Feeds {
 				if arg.Value == feed.FeedLink {
-					return NewErrorEmbed("Feed Error", "Feed ``"+arg.Value+"`` already exists.")
+					return env.errorEmbed(newError(errCodeFeedExists, arg.Value))
 				}
 			}
 
@@ -65,55 +65,55 @@ func commandFeed(args []CommandArgument, env *CommandEnvironment) *discordgo.Mes
 			feedsToAdd = append(feedsToAdd, arg.Value)
 		case "frequency", "f":
 			if arg.Value == "" {
-				return NewErrorEmbed("Feed Error", "You must specify a post check frequency to use when using the ``-"+arg.Name+"`` argument.")
+				return env.errorEmbed(newError(errCodeFeedFrequencyMissing, arg.Name))
 			}
 			freq, err := strconv.Atoi(arg.Value)
 			if err != nil {
-				return NewErrorEmbed("Feed Error", "``"+arg.Value+"`` is not a valid number.")
+				return env.errorEmbed(wrapError(errCodeCommandInvalidNumber, err, arg.Value))
 			}
-			if freq < botData.BotOptions.FeedFrequency {
-				return NewErrorEmbed("Feed Error", "Frequency must not be lower than "+strconv.Itoa(botData.BotOptions.FeedFrequency)+" seconds.")
+			if freq < botData().BotOptions.FeedFrequency {
+				return env.errorEmbed(newError(errCodeFeedFrequency, botData().BotOptions.FeedFrequency))
 			}
 			frequency = freq
 			//		case "all":
 			//			isAll = true
 		case "list":
 			if len(feedsToAdd) > 0 || len(feedsToEdit) > 0 || len(feedsToRemove) > 0 {
-				return NewErrorEmbed("Feed Error", "You cannot mix arguments dictating what to do!")
+				return env.errorEmbed(newError(errCodeCommandMixedArguments))
 			}
 			isListing = true
 		case "setchannel":
 			isSettingChannel = true
 		case "edit":
 			if len(feedsToAdd) > 0 || len(feedsToRemove) > 0 || isListing {
-				return NewErrorEmbed("Feed Error", "You cannot mix arguments dictating what to do!")
+				return env.errorEmbed(newError(errCodeCommandMixedArguments))
 			}
 			if arg.Value == "" {
-				return NewErrorEmbed("Feed Error", "You must specify a feed entry to edit when using the ``-edit`` argument.")
+				return env.errorEmbed(newError(errCodeFeedEditMissing))
 			}
 			entry, err := strconv.Atoi(arg.Value)
 			if err != nil {
-				return NewErrorEmbed("Feed Error", "``"+arg.Value+"`` is not a valid number.")
+				return env.errorEmbed(wrapError(errCodeCommandInvalidNumber, err, arg.Value))
 			}
 			if entry > len(guildSettings[env.Guild.ID].Feeds) || entry <= 0 {
-				return NewErrorEmbed("Feed Error", "``"+arg.Value+"`` is not a valid feed entry.")
+				return env.errorEmbed(newError(errCodeFeedEntryInvalid, arg.Value))
 			}
 
 			isEditing = true
 			feedsToEdit = append(feedsToEdit, entry)
 		case "remove":
 			if len(feedsToAdd) > 0 || len(feedsToEdit) > 0 || isListing {
-				return NewErrorEmbed("Feed Error", "You cannot mix arguments dictating what to do!")
+				return env.errorEmbed(newError(errCodeCommandMixedArguments))
 			}
 			if arg.Value == "" {
-				return NewErrorEmbed("Feed Error", "You must specify a feed entry to remove when using the ``-remove`` argument.")
+				return env.errorEmbed(newError(errCodeFeedRemoveMissing))
 			}
 			entry, err := strconv.Atoi(arg.Value)
 			if err != nil {
-				return NewErrorEmbed("Feed Error", "``"+arg.Value+"`` is not a valid number.")
+				return env.errorEmbed(wrapError(errCodeCommandInvalidNumber, err, arg.Value))
 			}
 			if entry > len(guildSettings[env.Guild.ID].Feeds) || entry <= 0 {
-				return NewErrorEmbed("Feed Error", "``"+arg.Value+"`` is not a valid feed entry.")
+				return env.errorEmbed(newError(errCodeFeedEntryInvalid, arg.Value))
 			}
 
 			isRemoving = true
@@ -190,7 +190,7 @@ func commandFeed(args []CommandArgument, env *CommandEnvironment) *discordgo.Mes
 }
 
 func addFeed(guildID, channelID, feedURL string, frequency int) error {
-	feed, err := botData.BotClients.FeedParser.ParseURL(feedURL)
+	feed, err := botData().BotClients.FeedParser.ParseURL(feedURL)
 	if err != nil {
 		return err
 	}
@@ -204,7 +204,7 @@ func addFeed(guildID, channelID, feedURL string, frequency int) error {
 
 	waitDuration := time.Duration(frequency) * time.Second
 	time.AfterFunc(waitDuration, func() {
-		postFeed(guildID, len(guildSettings[guildID].Feeds)-1, wrapFeed.Title, frequency)
+		postFeed(guildID, len(guildSettings[guildID].Feeds)-1, wrapFeed.Title)
 	})
 
 	return nil
@@ -217,14 +217,16 @@ func addFeed(guildID, channelID, feedURL string, frequency int) error {
 //
 // If the comparison fails, it means that the given feedPointer no longer points to its original feed as the original feed was removed.
 // In this case, the postFeed function will not be re-registered for a later call.
-func postFeed(guildID string, feedPointer int, feedTitle string, frequency int) {
+func postFeed(guildID string, feedPointer int, feedTitle string) {
+	defer recoverHandler("postFeed", guildID)
+
 	if len(guildSettings[guildID].Feeds) == 0 {
 		return
 	}
 	if feedPointer < 0 {
 		return
 	}
-	if feedPointer >= len(guildSettings) {
+	if feedPointer >= len(guildSettings[guildID].Feeds) {
 		return
 	}
 
@@ -233,15 +235,19 @@ func postFeed(guildID string, feedPointer int, feedTitle string, frequency int)
 		return
 	}
 
-	waitDuration := time.Duration(frequency) * time.Second
+	//Check again at the feed's current frequency, which may have been changed since the last check
+	waitDuration := time.Duration(feed.Frequency) * time.Second
 	time.AfterFunc(waitDuration, func() {
-		postFeed(guildID, feedPointer, feed.Title, frequency)
+		postFeed(guildID, feedPointer, feed.Title)
 	})
 
-	newFeed, err := botData.BotClients.FeedParser.ParseURL(feed.FeedURL)
+	newFeed, err := botData().BotClients.FeedParser.ParseURL(feed.FeedURL)
 	if err != nil {
+		metricFeedPolls.Inc("failure")
+		WarningFeed.With("guild", guildID, "channel", feed.ChannelID).Printf("Error checking feed [%s] for new posts: %v", feed.FeedURL, err)
 		return
 	}
+	metricFeedPolls.Inc("success")
 
 	newPostCount := 0
 	for _, newPost := range newFeed.Items {
@@ -259,6 +265,7 @@ func postFeed(guildID string, feedPointer int, feedTitle string, frequency int)
 	}
 
 	if newPostCount > 0 {
+		DebugFeed.With("guild", guildID, "channel", feed.ChannelID).Printf("Posting %d new posts from feed [%s]", newPostCount, feed.FeedURL)
 		newPosts := newFeed.Items[0:newPostCount]
 
 		for _, post := range newPosts {
@@ -273,7 +280,11 @@ func postFeed(guildID string, feedPointer int, feedTitle string, frequency int)
 				AddField(post.Title, content).
 				SetFooter("Updated " + post.Updated).
 				SetColor(0x1C1C1C).MessageEmbed
-			botData.DiscordSession.ChannelMessageSendEmbed(feed.ChannelID, postEmbed)
+			if _, err := botData().DiscordSession.ChannelMessageSendEmbed(feed.ChannelID, postEmbed); err != nil {
+				ErrorFeed.With("guild", guildID, "channel", feed.ChannelID).Printf("Error posting to feed channel: %v", err)
+				continue
+			}
+			publishEvent(guildID, EventFeedPosted, &EventFeedPost{FeedURL: feed.FeedURL, FeedTitle: newFeed.Title, ChannelID: feed.ChannelID, Title: post.Title, URL: post.Link})
 		}
 
 		wrapFeed := &Feed{Feed: newFeed}
@@ -10,10 +10,10 @@ import (
 func commandGeoIP(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	data, err := goeip.Lookup(args[0])
 	if err != nil {
-		return NewErrorEmbed("GeoIP Error", "There was an error with the GeoIP utility.")
+		return env.errorEmbed(wrapError(errCodeGeoIPFailed, err))
 	}
 	if data.Error > 0 {
-		return NewErrorEmbed("GeoIP Error", data.Details)
+		return env.errorEmbed(newError(errCodeGeoIPLookupFailed, data.Details))
 	}
 
 	geoipEmbed := NewEmbed().
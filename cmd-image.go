@@ -6,7 +6,6 @@ import (
 	"image"
 	"image/color"
 	"image/png"
-	"net/http"
 	"strconv"
 	"strings"
 
@@ -21,18 +20,18 @@ func commandImageAdv(args []CommandArgument, env *CommandEnvironment) *discordgo
 	if len(env.Message.Attachments) > 0 {
 		for i, attachment := range env.Message.Attachments {
 			srcImageURL := attachment.URL
-			srcImageHTTP, err := http.Get(srcImageURL)
+			srcImageHTTP, err := botHTTPClient.Get(srcImageURL)
 			if err != nil {
-				return NewErrorEmbed("Image Error", "Unable to fetch attachment %d.", i+1)
+				return env.errorEmbed(wrapError(errCodeImageFetchFailed, err, i+1))
 			}
 			srcImage, _, err := image.Decode(srcImageHTTP.Body)
 			if err != nil {
-				return NewErrorEmbed("Image Error", "Unable to decode attachment %d as an image.", i+1)
+				return env.errorEmbed(wrapError(errCodeImageDecodeFailed, err, i+1))
 			}
 			images = append(images, srcImage)
 		}
 	} else {
-		channelMessages, err := botData.DiscordSession.ChannelMessages(env.Channel.ID, 100, "", "", "")
+		channelMessages, err := botData().DiscordSession.ChannelMessages(env.Channel.ID, 100, "", "", "")
 		if err == nil {
 			for i := 0; i < len(channelMessages); i++ {
 				if len(channelMessages[i].Embeds) > 0 {
@@ -40,7 +39,7 @@ func commandImageAdv(args []CommandArgument, env *CommandEnvironment) *discordgo
 						if embed.Image != nil {
 							if embed.Image.URL != "" {
 								srcImageURL := embed.Image.URL
-								srcImageHTTP, err := http.Get(srcImageURL)
+								srcImageHTTP, err := botHTTPClient.Get(srcImageURL)
 								if err != nil {
 									continue
 								}
@@ -56,7 +55,7 @@ func commandImageAdv(args []CommandArgument, env *CommandEnvironment) *discordgo
 				if len(channelMessages[i].Attachments) > 0 {
 					for _, attachment := range channelMessages[i].Attachments {
 						srcImageURL := attachment.URL
-						srcImageHTTP, err := http.Get(srcImageURL)
+						srcImageHTTP, err := botHTTPClient.Get(srcImageURL)
 						if err != nil {
 							continue
 						}
@@ -91,7 +90,7 @@ func commandImageAdv(args []CommandArgument, env *CommandEnvironment) *discordgo
 				case "bg", "bgcolor", "bgcolour", "backgroundcolor", "backgroundcolour":
 					newBackgroundColor, err := colors.Parse(effect.Value)
 					if err != nil {
-						return NewErrorEmbed("Image Error", "Invalid background color ``"+effect.Value+"``.")
+						return env.errorEmbed(wrapError(errCodeImageEffectInvalid, err, effect.Value, effect.Name))
 					}
 					newBackgroundColorRGBA := newBackgroundColor.ToRGBA()
 					alpha := uint8(newBackgroundColorRGBA.A * 0xFF)
@@ -104,14 +103,14 @@ func commandImageAdv(args []CommandArgument, env *CommandEnvironment) *discordgo
 				case "brightness":
 					brightness, err := strconv.ParseFloat(strings.TrimSuffix(effect.Value, "%"), 32)
 					if err != nil {
-						return NewErrorEmbed("Image Error", "Invalid brightness percentage ``"+effect.Value+"``.")
+						return env.errorEmbed(wrapError(errCodeImageEffectInvalid, err, effect.Value, effect.Name))
 					}
 					brightness -= 100
 					g.Add(gift.Brightness(float32(brightness)))
 				case "contrast":
 					contrast, err := strconv.ParseFloat(strings.TrimSuffix(effect.Value, "%"), 32)
 					if err != nil {
-						return NewErrorEmbed("Image Error", "Invalid contrast percentage ``"+effect.Value+"``.")
+						return env.errorEmbed(wrapError(errCodeImageEffectInvalid, err, effect.Value, effect.Name))
 					}
 					contrast -= 100
 					g.Add(gift.Contrast(float32(contrast)))
@@ -122,19 +121,19 @@ func commandImageAdv(args []CommandArgument, env *CommandEnvironment) *discordgo
 					case "v", "vertical", "up", "down":
 						g.Add(gift.FlipVertical())
 					default:
-						return NewErrorEmbed("Image Error", "Invalid flip direction ``"+effect.Value+"``.")
+						return env.errorEmbed(newError(errCodeImageEffectInvalid, effect.Value, effect.Name))
 					}
 				case "gamma":
 					gamma, err := strconv.ParseFloat(strings.TrimSuffix(effect.Value, "%"), 32)
 					if err != nil {
-						return NewErrorEmbed("Image Error", "Invalid gamma percentage ``"+effect.Value+"``.")
+						return env.errorEmbed(wrapError(errCodeImageEffectInvalid, err, effect.Value, effect.Name))
 					}
 					gamma /= 100
 					g.Add(gift.Gamma(float32(gamma)))
 				case "gaussian", "gaussianblur":
 					gaussian, err := strconv.ParseFloat(strings.TrimSuffix(effect.Value, "%"), 32)
 					if err != nil {
-						return NewErrorEmbed("Image Error", "Invalid gaussian blur percentage ``"+effect.Value+"``.")
+						return env.errorEmbed(wrapError(errCodeImageEffectInvalid, err, effect.Value, effect.Name))
 					}
 					gaussian /= 100
 					g.Add(gift.GaussianBlur(float32(gaussian)))
@@ -143,7 +142,7 @@ func commandImageAdv(args []CommandArgument, env *CommandEnvironment) *discordgo
 				case "height":
 					newHeight, err := strconv.Atoi(effect.Value)
 					if err != nil {
-						return NewErrorEmbed("Image Error", "Invalid height integer ``"+effect.Value+"``.")
+						return env.errorEmbed(wrapError(errCodeImageEffectInvalid, err, effect.Value, effect.Name))
 					}
 					height = newHeight
 				case "interpolation":
@@ -155,14 +154,14 @@ func commandImageAdv(args []CommandArgument, env *CommandEnvironment) *discordgo
 					case "nn", "nearestneighbor", "nearestneighbour", "nearest":
 						interpolation = gift.NearestNeighborInterpolation
 					default:
-						return NewErrorEmbed("Image Error", "Invalid interpolation ``"+effect.Value+"``.")
+						return env.errorEmbed(newError(errCodeImageEffectInvalid, effect.Value, effect.Name))
 					}
 				case "invert":
 					g.Add(gift.Invert())
 				case "pixelate":
 					pixelate, err := strconv.Atoi(effect.Value)
 					if err != nil {
-						return NewErrorEmbed("Image Error", "Invalid pixelation integer ``"+effect.Value+"``.")
+						return env.errorEmbed(wrapError(errCodeImageEffectInvalid, err, effect.Value, effect.Name))
 					}
 					g.Add(gift.Pixelate(pixelate))
 				case "resampling":
@@ -178,25 +177,25 @@ func commandImageAdv(args []CommandArgument, env *CommandEnvironment) *discordgo
 					case "nn", "nearestneighbor", "nearestneighbour", "nearest":
 						resampling = gift.NearestNeighborResampling
 					default:
-						return NewErrorEmbed("Image Error", "Invalid resampling ``"+effect.Value+"``.")
+						return env.errorEmbed(newError(errCodeImageEffectInvalid, effect.Value, effect.Name))
 					}
 				case "rotate":
 					angle, err := strconv.ParseFloat(effect.Value, 32)
 					if err != nil {
-						return NewErrorEmbed("Image Error", "Invalid rotation angle ``"+effect.Value+"``.")
+						return env.errorEmbed(wrapError(errCodeImageEffectInvalid, err, effect.Value, effect.Name))
 					}
 					g.Add(gift.Rotate(float32(angle), backgroundColor, interpolation))
 				case "saturation":
 					saturation, err := strconv.ParseFloat(strings.TrimSuffix(effect.Value, "%"), 32)
 					if err != nil {
-						return NewErrorEmbed("Image Error", "Invalid saturation percentage ``"+effect.Value+"``.")
+						return env.errorEmbed(wrapError(errCodeImageEffectInvalid, err, effect.Value, effect.Name))
 					}
 					saturation -= 100
 					g.Add(gift.Saturation(float32(saturation)))
 				case "sepia":
 					sepia, err := strconv.ParseFloat(strings.TrimSuffix(effect.Value, "%"), 32)
 					if err != nil {
-						return NewErrorEmbed("Image Error", "Invalid sepia percentage ``"+effect.Value+"``.")
+						return env.errorEmbed(wrapError(errCodeImageEffectInvalid, err, effect.Value, effect.Name))
 					}
 					g.Add(gift.Sepia(float32(sepia)))
 				case "sobel":
@@ -204,7 +203,7 @@ func commandImageAdv(args []CommandArgument, env *CommandEnvironment) *discordgo
 				case "threshold":
 					threshold, err := strconv.ParseFloat(strings.TrimSuffix(effect.Value, "%"), 32)
 					if err != nil {
-						return NewErrorEmbed("Image Error", "Invalid threshold percentage ``"+effect.Value+"``.")
+						return env.errorEmbed(wrapError(errCodeImageEffectInvalid, err, effect.Value, effect.Name))
 					}
 					g.Add(gift.Threshold(float32(threshold)))
 				case "transpose":
@@ -214,11 +213,11 @@ func commandImageAdv(args []CommandArgument, env *CommandEnvironment) *discordgo
 				case "width":
 					newWidth, err := strconv.Atoi(effect.Value)
 					if err != nil {
-						return NewErrorEmbed("Image Error", "Invalid width integer ``"+effect.Value+"``.")
+						return env.errorEmbed(wrapError(errCodeImageEffectInvalid, err, effect.Value, effect.Name))
 					}
 					width = newWidth
 				default:
-					return NewErrorEmbed("Image Error", "Unknown effect ``"+effect.Name+"``.")
+					return env.errorEmbed(newError(errCodeImageEffectUnknown, effect.Name))
 				}
 			}
 
@@ -229,9 +228,9 @@ func commandImageAdv(args []CommandArgument, env *CommandEnvironment) *discordgo
 
 			err := png.Encode(&outImage, dstImage)
 			if err != nil {
-				return NewErrorEmbed("Image Error", "Unable to encode processed image.")
+				return env.errorEmbed(wrapError(errCodeImageEncodeFailed, err))
 			}
-			_, err = botData.DiscordSession.ChannelMessageSendComplex(env.Channel.ID, &discordgo.MessageSend{
+			_, err = botData().DiscordSession.ChannelMessageSendComplex(env.Channel.ID, &discordgo.MessageSend{
 				File: &discordgo.File{
 					Name:   "clinet-processed.png",
 					Reader: &outImage,
@@ -244,11 +243,11 @@ func commandImageAdv(args []CommandArgument, env *CommandEnvironment) *discordgo
 				},
 			})
 			if err != nil {
-				return NewErrorEmbed("Image Error", "Unable to upload processed image %d.", i)
+				return env.errorEmbed(wrapError(errCodeImageUploadFailed, err, i))
 			}
 		}
 		return nil
 	}
 
-	return NewErrorEmbed("Image Error", "Unable to find any attached images or any images in the past 100 messages.")
+	return env.errorEmbed(newError(errCodeImageNotFound))
 }
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 
@@ -10,57 +11,76 @@ import (
 )
 
 func commandBotInfo(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
-	guildCount := len(botData.DiscordSession.State.Guilds)
+	shardStats := allShardStats()
+	guildCount := 0
+	for _, stats := range shardStats {
+		guildCount += stats.Guilds
+	}
 	commandCount := 0
-	for _, command := range botData.Commands {
+	for _, command := range botData().Commands {
 		if command.IsAlternateOf == "" {
 			commandCount++
 		}
 	}
 
 	botEmbed := NewEmbed().
-		SetAuthor(botData.BotName, botData.DiscordSession.State.User.AvatarURL("2048")).
-		AddField("Bot Owner", "<@!"+botData.BotOwnerID+">").
+		SetAuthor(botData().BotName, botData().DiscordSession.State.User.AvatarURL("2048")).
+		AddField("Bot Owner", "<@!"+botData().BotOwnerID+">").
 		AddField("Guild Count", strconv.Itoa(guildCount)).
-		AddField("Default Prefix", botData.CommandPrefix).
+		AddField("Default Prefix", botData().CommandPrefix).
 		AddField("Command Count", strconv.Itoa(commandCount)).
 		AddField("Uptime", humanize.Time(uptime)).
-		AddField("Debug Mode", strconv.FormatBool(botData.DebugMode)).
+		AddField("Restarts", strconv.Itoa(restarts)).
+		AddField("Debug Mode", strconv.FormatBool(botData().DebugMode)).
 		InlineAllFields().
 		SetColor(0x1C1C1C)
 
 	enabledFeatures := make([]string, 0)
-	if botData.BotOptions.UseCustomResponses {
+	if botData().BotOptions.UseCustomResponses {
 		enabledFeatures = append(enabledFeatures, "Custom Responses")
 	}
-	if botData.BotOptions.UseDuckDuckGo {
+	if botData().BotOptions.UseDuckDuckGo {
 		enabledFeatures = append(enabledFeatures, "DuckDuckGo")
 	}
-	if botData.BotOptions.UseGitHub {
+	if botData().BotOptions.UseGitHub {
 		enabledFeatures = append(enabledFeatures, "GitHub")
 	}
-	if botData.BotOptions.UseImgur {
+	if botData().BotOptions.UseImgur {
 		enabledFeatures = append(enabledFeatures, "Imgur")
 	}
-	if botData.BotOptions.UseSoundCloud {
+	if botData().BotOptions.UseSoundCloud {
 		enabledFeatures = append(enabledFeatures, "SoundCloud")
 	}
-	if botData.BotOptions.UseSpotify {
+	if botData().BotOptions.UseSpotify {
 		enabledFeatures = append(enabledFeatures, "Spotify")
 	}
-	if botData.BotOptions.UseWolframAlpha {
+	if botData().BotOptions.UseWolframAlpha {
 		enabledFeatures = append(enabledFeatures, "Wolfram|Alpha")
 	}
-	if botData.BotOptions.UseXKCD {
+	if botData().BotOptions.UseXKCD {
 		enabledFeatures = append(enabledFeatures, "xkcd")
 	}
-	if botData.BotOptions.UseYouTube {
+	if botData().BotOptions.UseYouTube {
 		enabledFeatures = append(enabledFeatures, "YouTube")
 	}
 	if len(enabledFeatures) > 0 {
 		botEmbed.AddField("Enabled Features", strings.Join(enabledFeatures, ", "))
 	}
 
+	if shardCount > 1 {
+		botEmbed.AddField("Shard", strconv.Itoa(shardID)+" of "+strconv.Itoa(shardCount))
+
+		shardList := make([]string, 0)
+		for _, stats := range shardStats {
+			if stats.Unreachable {
+				shardList = append(shardList, "Shard "+strconv.Itoa(stats.ShardID)+": Unreachable")
+				continue
+			}
+			shardList = append(shardList, fmt.Sprintf("Shard %d: %d guilds, %d voice sessions, %dms latency, started %s, %d restarts", stats.ShardID, stats.Guilds, stats.VoiceSessions, stats.Latency.Milliseconds(), humanize.Time(stats.Started), stats.Restarts))
+		}
+		botEmbed.AddField("Shards", strings.Join(shardList, "\n"))
+	}
+
 	botEmbed.AddField("Reason for Downtime", DowntimeReason)
 
 	return botEmbed.MessageEmbed
@@ -79,7 +99,7 @@ func commandServerInfo(args []string, env *CommandEnvironment) *discordgo.Messag
 
 	afkChannel := "None"
 	if env.Guild.AfkChannelID != "" {
-		channel, err := botData.DiscordSession.Channel(env.Guild.AfkChannelID)
+		channel, err := botData().DiscordSession.Channel(env.Guild.AfkChannelID)
 		if err == nil && channel.Type == discordgo.ChannelTypeGuildVoice {
 			afkChannel = ":speaker: " + channel.Name
 		}
@@ -125,7 +145,7 @@ func commandUserInfo(args []string, env *CommandEnvironment) *discordgo.MessageE
 	if len(env.Message.Mentions) > 0 {
 		user = env.Message.Mentions[0]
 
-		memberMention, err := botData.DiscordSession.GuildMember(env.Guild.ID, user.ID)
+		memberMention, err := botData().DiscordSession.GuildMember(env.Guild.ID, user.ID)
 		if err != nil {
 			memberFound = false
 		}
@@ -133,26 +153,27 @@ func commandUserInfo(args []string, env *CommandEnvironment) *discordgo.MessageE
 	} else if len(args) > 0 {
 		mention := args[0]
 
-		userMention, err := botData.DiscordSession.User(mention)
+		userMention, err := botData().DiscordSession.User(mention)
 		if err != nil {
-			return NewErrorEmbed("User Info Error", "Invalid user ``"+mention+"``.")
+			return env.errorEmbed(wrapError(errCodeCommandInvalidUser, err, mention))
 		}
 		user = userMention
 
-		memberMention, err := botData.DiscordSession.GuildMember(env.Guild.ID, user.ID)
+		memberMention, err := botData().DiscordSession.GuildMember(env.Guild.ID, user.ID)
 		if err != nil {
 			memberFound = false
 		}
 		member = memberMention
 	}
 
-	timezone := userSettings[env.User.ID].Timezone
+	settings, _ := copyUserSettings(env.User.ID)
+	timezone := settings.Timezone
 	if timezone == "" {
-		return NewErrorEmbed("User Info Error", "Please set a timezone first!\n\nEx: ``"+env.BotPrefix+"user timezone America/New_York``")
+		return env.errorEmbed(newError(errCodeTimezoneUnset, env.BotPrefix))
 	}
 	location, err := tz.LoadLocation(timezone)
 	if err != nil {
-		return NewErrorEmbed("User Info Error", "You have an invalid timezone set, please set a new one first!\n\nEx: ``"+env.BotPrefix+"user timezone America/New_York``")
+		return env.errorEmbed(wrapError(errCodeTimezoneInvalid, err, env.BotPrefix))
 	}
 
 	creationDate := ""
@@ -191,7 +212,7 @@ func commandUserInfo(args []string, env *CommandEnvironment) *discordgo.MessageE
 		if len(member.Roles) > 0 {
 			roles := make([]string, 0)
 			for _, roleID := range member.Roles {
-				role, err := botData.DiscordSession.State.Role(env.Guild.ID, roleID)
+				role, err := botData().DiscordSession.State.Role(env.Guild.ID, roleID)
 				if err == nil {
 					roles = append(roles, role.Name)
 				}
@@ -201,7 +222,7 @@ func commandUserInfo(args []string, env *CommandEnvironment) *discordgo.MessageE
 	}
 
 	if memberFound {
-		presence, err := botData.DiscordSession.State.Presence(env.Guild.ID, user.ID)
+		presence, err := botData().DiscordSession.State.Presence(env.Guild.ID, user.ID)
 		if err == nil {
 			status := ""
 			switch presence.Status {
@@ -227,7 +248,7 @@ func commandUserInfo(args []string, env *CommandEnvironment) *discordgo.MessageE
 		}
 	}
 
-	if userSettings, found := userSettings[user.ID]; found {
+	if userSettings, found := copyUserSettings(user.ID); found {
 		if userSettings.AboutMe != "" {
 			userInfoEmbed.AddField("About Me", userSettings.AboutMe)
 		}
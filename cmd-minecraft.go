@@ -93,24 +93,26 @@ func GetAPIUsernameHistory(mc *minecraft.Minecraft, uuid string) ([]MCUsernameHi
 }
 
 func commandMinecraft(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
-	timezone := userSettings[env.User.ID].Timezone
+	settings, _ := copyUserSettings(env.User.ID)
+	timezone := settings.Timezone
 	if timezone == "" {
-		return NewErrorEmbed("Minecraft Error", "Please set a timezone first!\n\nEx: ``"+env.BotPrefix+"user timezone America/New_York``")
+		return env.errorEmbed(newError(errCodeTimezoneUnset, env.BotPrefix))
 	}
 	location, err := tz.LoadLocation(timezone)
 	if err != nil {
-		return NewErrorEmbed("Minecraft Error", "You have an invalid timezone set, please set a new one first!\n\nEx: ``"+env.BotPrefix+"user timezone America/New_York``")
+		return env.errorEmbed(wrapError(errCodeTimezoneInvalid, err, env.BotPrefix))
 	}
 
 	switch args[0] {
 	case "user", "player", "avatar", "skin", "uuid":
 		minecraftAPI := minecraft.NewMinecraft()
+		instrumentClient(minecraftAPI.Client)
 
 		profileAPI, err := minecraftAPI.GetAPIProfile(args[1])
 		if err != nil {
 			oldProfileAPI, err := GetAPIOldProfile(minecraftAPI, args[1])
 			if err != nil {
-				return NewErrorEmbed("Minecraft Error", "Invalid or unknown username ``"+args[1]+"``.")
+				return env.errorEmbed(wrapError(errCodeMinecraftUserUnknown, err, args[1]))
 			}
 			profileAPI = *oldProfileAPI
 		}
@@ -163,7 +165,7 @@ func commandMinecraft(args []string, env *CommandEnvironment) *discordgo.Message
 
 		server, err := minepong.Ping(host)
 		if err != nil {
-			return NewErrorEmbed("Minecraft Error", "Invalid or unknown server ``"+args[1]+"``.")
+			return env.errorEmbed(wrapError(errCodeMinecraftServerUnknown, err, args[1]))
 		}
 
 		title := "Minecraft - " + args[1]
@@ -191,7 +193,7 @@ func commandMinecraft(args []string, env *CommandEnvironment) *discordgo.Message
 		return minecraftEmbed.MessageEmbed
 	}
 
-	return NewErrorEmbed("Minecraft Error", "Unknown command ``"+args[1]+"``.")
+	return env.errorEmbed(newError(errCodeCommandUnknownAction, args[1]))
 }
 
 func mcFormat(desc interface{}) string {
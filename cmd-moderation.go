@@ -12,15 +12,15 @@ import (
 func commandPurge(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	amount, err := strconv.Atoi(args[0])
 	if err != nil {
-		return NewErrorEmbed("Purge Error", "``"+args[0]+"`` is not a valid number.")
+		return env.errorEmbed(wrapError(errCodeCommandInvalidNumber, err, args[0]))
 	}
 	if amount <= 0 || amount > 100 {
-		return NewErrorEmbed("Purge Error", "Amount of messages to purge must be between 1 and 100.")
+		return env.errorEmbed(newError(errCodePurgeAmountRange))
 	}
 
-	messages, err := botData.DiscordSession.ChannelMessages(env.Channel.ID, amount, env.Message.ID, "", "")
+	messages, err := botData().DiscordSession.ChannelMessages(env.Channel.ID, amount, env.Message.ID, "", "")
 	if err != nil {
-		return NewErrorEmbed("Purge Error", "An error occurred fetching the last "+args[0]+" messages.")
+		return env.errorEmbed(wrapError(errCodePurgeFetchFailed, err, args[0]))
 	}
 
 	messageIDs := make([]string, 0)
@@ -35,9 +35,9 @@ func commandPurge(args []string, env *CommandEnvironment) *discordgo.MessageEmbe
 			}
 		}
 
-		err = botData.DiscordSession.ChannelMessagesBulkDelete(env.Channel.ID, messageIDs)
+		err = botData().DiscordSession.ChannelMessagesBulkDelete(env.Channel.ID, messageIDs)
 		if err != nil {
-			return NewErrorEmbed("Purge Error", "An error occurred deleting the last "+args[0]+" messages from the specified user(s).")
+			return env.errorEmbed(wrapError(errCodePurgeUserDeleteFailed, err, args[0]))
 		}
 
 		return NewGenericEmbed("Purge", "Successfully purged the last "+args[0]+" messages from the specified user(s).")
@@ -47,16 +47,16 @@ func commandPurge(args []string, env *CommandEnvironment) *discordgo.MessageEmbe
 		messageIDs = append(messageIDs, messages[i].ID)
 	}
 
-	err = botData.DiscordSession.ChannelMessagesBulkDelete(env.Channel.ID, messageIDs)
+	err = botData().DiscordSession.ChannelMessagesBulkDelete(env.Channel.ID, messageIDs)
 	if err != nil {
-		return NewErrorEmbed("Purge Error", "An error occurred deleting the last "+args[0]+" messages.")
+		return env.errorEmbed(wrapError(errCodePurgeDeleteFailed, err, args[0]))
 	}
 
 	return NewGenericEmbed("Purge", "Successfully purged the last "+args[0]+" messages.")
 }
 func commandKick(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	if len(env.Message.Mentions) == 0 {
-		NewErrorEmbed("Kick Error", "You must specify which user(s) to kick from the server.")
+		return env.errorEmbed(newError(errCodeKickUsersMissing))
 	}
 
 	reasonMessage := ""
@@ -64,7 +64,7 @@ func commandKick(args []string, env *CommandEnvironment) *discordgo.MessageEmbed
 	for i, part := range args {
 		if strings.HasPrefix(part, "<@") && strings.HasSuffix(part, ">") {
 			if strings.TrimRight(strings.TrimLeft(strings.TrimLeft(part, "<@"), "!"), ">") == env.User.ID {
-				return NewErrorEmbed("Kick Error", "You can't kick yourself!")
+				return env.errorEmbed(newError(errCodeKickSelf))
 			}
 			usersToKick = append(usersToKick, strings.TrimRight(strings.TrimLeft(strings.TrimLeft(part, "<@"), "!"), ">"))
 			continue
@@ -73,29 +73,29 @@ func commandKick(args []string, env *CommandEnvironment) *discordgo.MessageEmbed
 		break
 	}
 	if len(usersToKick) == 0 {
-		return NewErrorEmbed("Kick Error", "You must specify which user(s) to kick.")
+		return env.errorEmbed(newError(errCodeKickUsersMissing))
 	}
 
 	if reasonMessage == "" {
 		for i := range usersToKick {
-			err := botData.DiscordSession.GuildMemberDelete(env.Guild.ID, usersToKick[i])
+			err := botData().DiscordSession.GuildMemberDelete(env.Guild.ID, usersToKick[i])
 			if err != nil {
-				return NewErrorEmbed("Kick Error", "An error occurred kicking <@"+usersToKick[i]+">. Please consider manually kicking and report this issue to a developer.")
+				return env.errorEmbed(wrapError(errCodeKickFailed, err, usersToKick[i]))
 			}
 		}
 		return NewGenericEmbed("Kick", "Successfully kicked the selected user(s).")
 	}
 	for i := range usersToKick {
-		err := botData.DiscordSession.GuildMemberDeleteWithReason(env.Guild.ID, usersToKick[i], reasonMessage)
+		err := botData().DiscordSession.GuildMemberDeleteWithReason(env.Guild.ID, usersToKick[i], reasonMessage)
 		if err != nil {
-			return NewErrorEmbed("Kick Error", "An error occurred kicking <@"+usersToKick[i]+">. Please consider manually kicking and report this issue to a developer.")
+			return env.errorEmbed(wrapError(errCodeKickFailed, err, usersToKick[i]))
 		}
 	}
 	return NewGenericEmbed("Kick", "Successfully kicked the selected user(s) for the following reason:\n**"+reasonMessage+"**")
 }
 func commandBan(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	if len(env.Message.Mentions) == 0 {
-		return NewErrorEmbed("Ban Error", "You must specify which user(s) to ban from the server.")
+		return env.errorEmbed(newError(errCodeBanUsersMissing))
 	}
 
 	reasonMessage := ""
@@ -108,12 +108,12 @@ func commandBan(args []string, env *CommandEnvironment) *discordgo.MessageEmbed
 				messagesDaysToDelete = days
 				continue
 			} else {
-				return NewErrorEmbed("Ban Error", "``"+part+"`` is not a valid number.")
+				return env.errorEmbed(newError(errCodeCommandInvalidNumber, part))
 			}
 		}
 		if strings.HasPrefix(part, "<@") && strings.HasSuffix(part, ">") {
 			if strings.TrimRight(strings.TrimLeft(strings.TrimLeft(part, "<@"), "!"), ">") == env.User.ID {
-				return NewErrorEmbed("Kick Error", "You can't kick yourself!")
+				return env.errorEmbed(newError(errCodeBanSelf))
 			}
 			usersToBan = append(usersToBan, strings.TrimRight(strings.TrimLeft(strings.TrimLeft(part, "<@"), "!"), ">"))
 			continue
@@ -122,25 +122,25 @@ func commandBan(args []string, env *CommandEnvironment) *discordgo.MessageEmbed
 		break
 	}
 	if len(usersToBan) == 0 {
-		return NewErrorEmbed("Ban Error", "You must specify which user(s) to ban.")
+		return env.errorEmbed(newError(errCodeBanUsersMissing))
 	}
 	if messagesDaysToDelete > 7 {
-		return NewErrorEmbed("Ban Error", "You may only delete up to and including 7 days worth of messages from these users.")
+		return env.errorEmbed(newError(errCodeBanDaysRange))
 	}
 
 	if reasonMessage == "" {
 		for i := range usersToBan {
-			err := botData.DiscordSession.GuildBanCreate(env.Guild.ID, usersToBan[i], messagesDaysToDelete)
+			err := botData().DiscordSession.GuildBanCreate(env.Guild.ID, usersToBan[i], messagesDaysToDelete)
 			if err != nil {
-				return NewErrorEmbed("Ban Error", "An error occurred banning <@"+usersToBan[i]+">. Please consider manually banning and report this issue to a developer.")
+				return env.errorEmbed(wrapError(errCodeBanFailed, err, usersToBan[i]))
 			}
 		}
 		return NewGenericEmbed("Ban", "Successfully banned the selected user(s).")
 	}
 	for i := range usersToBan {
-		err := botData.DiscordSession.GuildBanCreateWithReason(env.Guild.ID, usersToBan[i], reasonMessage, messagesDaysToDelete)
+		err := botData().DiscordSession.GuildBanCreateWithReason(env.Guild.ID, usersToBan[i], reasonMessage, messagesDaysToDelete)
 		if err != nil {
-			return NewErrorEmbed("Ban Error", "An error occurred banning <@"+usersToBan[i]+">. Please consider manually banning and report this issue to a developer.")
+			return env.errorEmbed(wrapError(errCodeBanFailed, err, usersToBan[i]))
 		}
 	}
 	return NewGenericEmbed("Ban", "Successfully banned the selected user(s) for the following reason:\n**"+reasonMessage+"**")
@@ -154,28 +154,28 @@ func commandHackBan(args []CommandArgument, env *CommandEnvironment) *discordgo.
 		switch args[i].Name {
 		case "days":
 			if args[i].Value == "" {
-				return NewErrorEmbed("HackBan Error", "You must specify how many days of messages to delete if you use the ``-days`` argument.")
+				return env.errorEmbed(newError(errCodeHackBanDaysMissing))
 			}
 			days, err := strconv.Atoi(args[i].Value)
 			if err != nil {
-				return NewErrorEmbed("HackBan Error", "Invalid days ``"+args[i].Value+"``.")
+				return env.errorEmbed(wrapError(errCodeHackBanDaysInvalid, err, args[i].Value))
 			}
 			messagesDaysToDelete = days
 		case "id":
 			if args[i].Value == "" {
-				return NewErrorEmbed("HackBan Error", "You must specify the ID of the user to hackban if you use the ``-id`` argument.")
+				return env.errorEmbed(newError(errCodeHackBanIDMissing))
 			}
 			usersToBan = append(usersToBan, args[i].Value)
 		case "reason":
 			if args[i].Value == "" {
-				return NewErrorEmbed("HackBan Error", "You must specify the reason for hackbanning if you use the ``-reason`` argument.")
+				return env.errorEmbed(newError(errCodeHackBanReasonMissing))
 			}
 			reasonMessage = args[i].Value
 		}
 	}
 
 	if len(usersToBan) == 0 {
-		return NewErrorEmbed("HackBan Error", "You must specify which user IDs to hackban.")
+		return env.errorEmbed(newError(errCodeHackBanUsersMissing))
 	}
 
 	if reasonMessage == "" {
@@ -185,7 +185,7 @@ func commandHackBan(args []CommandArgument, env *CommandEnvironment) *discordgo.
 	failedBans := make([]string, 0)
 	failedErrors := make([]error, 0)
 	for i := range usersToBan {
-		err := botData.DiscordSession.GuildBanCreateWithReason(env.Guild.ID, usersToBan[i], reasonMessage, messagesDaysToDelete)
+		err := botData().DiscordSession.GuildBanCreateWithReason(env.Guild.ID, usersToBan[i], reasonMessage, messagesDaysToDelete)
 		if err != nil {
 			failedBans = append(failedBans, usersToBan[i])
 			failedErrors = append(failedErrors, err)
@@ -198,11 +198,11 @@ func commandHackBan(args []CommandArgument, env *CommandEnvironment) *discordgo.
 	}
 
 	if len(failedBans) > 0 {
-		resp = "There was an error hackbanning the following users:\n"
+		failedList := ""
 		for i := 0; i < len(failedBans); i++ {
-			resp += fmt.Sprintf("\n- <!%s>: %v", failedBans[i], failedErrors[i])
+			failedList += fmt.Sprintf("\n- <@%s>: %v", failedBans[i], failedErrors[i])
 		}
-		return NewErrorEmbed("HackBan Error", resp)
+		return env.errorEmbed(newError(errCodeHackBanFailed, failedList))
 	}
 	return NewGenericEmbed("HackBan", resp)
 }
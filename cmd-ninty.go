@@ -7,32 +7,32 @@ import (
 )
 
 func commandNNID(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
-	exists, exml, err := botData.BotClients.Ninty.DoesUserExist(args[0])
+	exists, exml, err := botData().BotClients.Ninty.DoesUserExist(args[0])
 	if err != nil {
-		return NewErrorEmbed("NNID Error", "Error checking for user ``"+args[0]+"``.")
+		return env.errorEmbed(wrapError(errCodeNNIDCheckFailed, err, args[0]))
 	}
 
 	if len(exml.Errors) != 0 {
-		return NewErrorEmbed("NNID Error", "Error checking for user ``"+args[0]+"``.\n```"+exml.Errors[0].Error()+"```")
+		return env.errorEmbed(wrapError(errCodeNNIDCheckFailed, exml.Errors[0], args[0]))
 	}
 
 	if exists {
-		pids, exml, err := botData.BotClients.Ninty.GetPIDs(args)
+		pids, exml, err := botData().BotClients.Ninty.GetPIDs(args)
 		if err != nil {
-			return NewErrorEmbed("NNID Error", "Error getting pid for user ``"+args[0]+"``.")
+			return env.errorEmbed(wrapError(errCodeNNIDPIDFailed, err, args[0]))
 		}
 
 		if len(exml.Errors) != 0 {
-			return NewErrorEmbed("NNID Error", "Error getting pid for user ``"+args[0]+"``.\n```"+exml.Errors[0].Error()+"```")
+			return env.errorEmbed(wrapError(errCodeNNIDPIDFailed, exml.Errors[0], args[0]))
 		}
 
-		miis, exml, err := botData.BotClients.Ninty.GetMiis(pids)
+		miis, exml, err := botData().BotClients.Ninty.GetMiis(pids)
 		if err != nil {
-			return NewErrorEmbed("NNID Error", "Error getting mii for user ``"+args[0]+"``.")
+			return env.errorEmbed(wrapError(errCodeNNIDMiiFailed, err, args[0]))
 		}
 
 		if len(exml.Errors) != 0 {
-			return NewErrorEmbed("NNID Error", "Error getting mii for user ``"+args[0]+"``.\n```"+exml.Errors[0].Error()+"```")
+			return env.errorEmbed(wrapError(errCodeNNIDMiiFailed, exml.Errors[0], args[0]))
 		}
 
 		e := NewEmbed().
@@ -77,22 +77,22 @@ func commandTranslate(args []string, env *CommandEnvironment) *discordgo.Message
 			if len(args) > 2 {
 				translation, err := translateFrom(args[0], args[1], strings.Join(args[2:], " "))
 				if err != nil {
-					return NewErrorEmbed("Translate Error", fmt.Sprintf("Failed to translate: %v", err))
+					return env.errorEmbed(wrapError(errCodeTranslateFailed, err, err))
 				}
 				return NewGenericEmbed("Translation from "+getLanguageName(args[0])+" to "+getLanguageName(args[1]), translation)
 			}
 
-			return NewErrorEmbed("Translate Error", "You must specify the message to translate!")
+			return env.errorEmbed(newError(errCodeTranslateMessageMissing))
 		}
 
 		translation, err := translate(args[0], strings.Join(args[1:], " "))
 		if err != nil {
-			return NewErrorEmbed("Translate Error", fmt.Sprintf("Failed to translate: %v", err))
+			return env.errorEmbed(wrapError(errCodeTranslateFailed, err, err))
 		}
 		return NewGenericEmbed("Translation to "+getLanguageName(args[0]), translation)
 	}
 
-	return NewErrorEmbed("Translate Error", "Unknown target language: "+args[0])
+	return env.errorEmbed(newError(errCodeTranslateLanguageUnknown, args[0]))
 }
 
 func commandScreenshot(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
@@ -101,13 +101,13 @@ func commandScreenshot(args []string, env *CommandEnvironment) *discordgo.Messag
 	}
 
 	timeout := time.Duration(10 * time.Second)
-	client := http.Client{
+	client := instrumentClient(&http.Client{
 		Timeout: timeout,
-	}
+	})
 
 	siteURL, err := url.Parse(args[0])
 	if err != nil {
-		return NewErrorEmbed("Screenshot Error", "Unknown address format ``"+args[0]+"``.")
+		return env.errorEmbed(wrapError(errCodeScreenshotAddressInvalid, err, args[0]))
 	}
 	if siteURL.Scheme == "" {
 		siteURL.Scheme = "http" //By standard, SSL-enabled sites should automatically redirect to https if needed
@@ -116,13 +116,13 @@ func commandScreenshot(args []string, env *CommandEnvironment) *discordgo.Messag
 
 	req, err := http.NewRequest("GET", fmt.Sprintf("https://image.thum.io/get/maxAge/0/width/2000/noanimate/fullpage/%s", website), nil)
 	if err != nil {
-		return NewErrorEmbed("Screenshot Error", "The website ``"+args[0]+"`` does not exist or is currently unreachable.")
+		return env.errorEmbed(wrapError(errCodeScreenshotUnreachable, err, args[0]))
 	}
 	req.Header.Set("User-Agent", "Clinet/"+GitCommitFull)
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return NewErrorEmbed("Screenshot Error", "The website ``"+args[0]+"`` does not exist or is currently unreachable.")
+		return env.errorEmbed(wrapError(errCodeScreenshotUnreachable, err, args[0]))
 	}
 
 	var screenshotImage image.Image
@@ -131,23 +131,23 @@ func commandScreenshot(args []string, env *CommandEnvironment) *discordgo.Messag
 	case "image/gif":
 		gifAnim, err := gif.DecodeAll(resp.Body)
 		if err != nil {
-			return NewErrorEmbed("Screenshot Error", "The API failed to respond with a valid screenshot.")
+			return env.errorEmbed(wrapError(errCodeScreenshotInvalid, err))
 		}
 		screenshotImage = gifAnim.Image[len(gifAnim.Image)-1]
 	case "image/png", "image/jpeg":
 		srcImage, _, err := image.Decode(resp.Body)
 		if err != nil {
-			return NewErrorEmbed("Screenshot Error", "The API failed to respond with a valid screenshot.")
+			return env.errorEmbed(wrapError(errCodeScreenshotInvalid, err))
 		}
 		screenshotImage = srcImage
 	default:
-		return NewErrorEmbed("Screenshot Error", "The API failed to respond in an expected way.")
+		return env.errorEmbed(newError(errCodeScreenshotUnexpected))
 	}
 
 	var outImage bytes.Buffer
 	err = png.Encode(&outImage, screenshotImage)
 	if err != nil {
-		return NewErrorEmbed("Screenshot Error", "Unexpected error processing screenshot.")
+		return env.errorEmbed(wrapError(errCodeScreenshotProcessFailed, err))
 	}
 
 	imageName := website
@@ -155,7 +155,7 @@ func commandScreenshot(args []string, env *CommandEnvironment) *discordgo.Messag
 	imageName = strings.Replace(imageName, "/", "_", -1)
 	imageName += fmt.Sprintf("-%d", time.Now().Unix())
 	imageName = "clinet-screenshot_" + imageName + ".png"
-	_, err = botData.DiscordSession.ChannelMessageSendComplex(env.Channel.ID, &discordgo.MessageSend{
+	_, err = botData().DiscordSession.ChannelMessageSendComplex(env.Channel.ID, &discordgo.MessageSend{
 		File: &discordgo.File{
 			Name:   imageName,
 			Reader: &outImage,
@@ -169,7 +169,7 @@ func commandScreenshot(args []string, env *CommandEnvironment) *discordgo.Messag
 		},
 	})
 	if err != nil {
-		return NewErrorEmbed("Screenshot Error", "Unexpected error uploading screenshot.")
+		return env.errorEmbed(wrapError(errCodeScreenshotUploadFailed, err))
 	}
 	return nil
 }
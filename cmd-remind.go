@@ -23,13 +23,14 @@ type RemindEntry struct {
 }
 
 func commandRemind(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
-	timezone := userSettings[env.User.ID].Timezone
+	settings, _ := copyUserSettings(env.User.ID)
+	timezone := settings.Timezone
 	if timezone == "" {
-		return NewErrorEmbed("Remind Error", "Please set a timezone first!\n\nEx: ``"+env.BotPrefix+"user timezone America/New_York``")
+		return env.errorEmbed(newError(errCodeTimezoneUnset, env.BotPrefix))
 	}
 	location, err := tz.LoadLocation(timezone)
 	if err != nil {
-		return NewErrorEmbed("Remind Error", "You have an invalid timezone set, please set a new one first!\n\nEx: ``"+env.BotPrefix+"user timezone America/New_York``")
+		return env.errorEmbed(wrapError(errCodeTimezoneInvalid, err, env.BotPrefix))
 	}
 
 	switch args[0] {
@@ -38,11 +39,12 @@ func commandRemind(args []string, env *CommandEnvironment) *discordgo.MessageEmb
 		if len(args) == 2 {
 			page, err := strconv.Atoi(args[1])
 			if err != nil {
-				return NewErrorEmbed("Remind Error", "Invalid page number ``"+args[0]+"``.")
+				return env.errorEmbed(wrapError(errCodeCommandInvalidPage, err, args[0]))
 			}
 			pageNumber = page
 		}
 
+		remindEntriesLock.RLock()
 		remindList := make([]*discordgo.MessageEmbedField, 0)
 		for _, entry := range remindEntries {
 			if entry.UserID == env.User.ID {
@@ -52,17 +54,21 @@ func commandRemind(args []string, env *CommandEnvironment) *discordgo.MessageEmb
 				})
 			}
 		}
+		remindEntriesLock.RUnlock()
 
 		remindListEmbed, totalPages, err := page(remindList, pageNumber, 10)
 		if totalPages == 0 {
 			return NewGenericEmbed("Remind", "No remind entries were found.")
 		}
 		if err != nil {
-			return NewErrorEmbed("Remind Error", "Invalid page number ``"+strconv.Itoa(pageNumber)+"``.")
+			return env.errorEmbed(wrapError(errCodeCommandPageNotFound, err, pageNumber))
 		}
 
 		return remindListEmbed.SetTitle("Remind List - Page " + strconv.Itoa(pageNumber) + "/" + strconv.Itoa(totalPages)).MessageEmbed
 	case "delete", "remove":
+		remindEntriesLock.Lock()
+		defer remindEntriesLock.Unlock()
+
 		remindList := make([]RemindEntry, 0)
 		for _, entry := range remindEntries {
 			if entry.UserID == env.User.ID {
@@ -75,12 +81,12 @@ func commandRemind(args []string, env *CommandEnvironment) *discordgo.MessageEmb
 		for _, remindEntry := range args[1:] {
 			remindEntryNumber, err := strconv.Atoi(remindEntry)
 			if err != nil {
-				return NewErrorEmbed("Remind Error", "``"+remindEntry+"`` is not a valid number.")
+				return env.errorEmbed(wrapError(errCodeCommandInvalidNumber, err, remindEntry))
 			}
 			remindEntryNumber--
 
 			if remindEntryNumber >= len(remindList) || remindEntryNumber < 0 {
-				return NewErrorEmbed("Remind Error", "``"+remindEntry+"`` is not a valid remind entry.")
+				return env.errorEmbed(newError(errCodeRemindEntryInvalid, remindEntry))
 			}
 		}
 
@@ -136,12 +142,12 @@ func commandRemind(args []string, env *CommandEnvironment) *discordgo.MessageEmb
 
 	r, err := w.Parse(text, now)
 	if err != nil || r == nil {
-		return NewErrorEmbed("Remind Error", "There was an error figuring out what time to remind you with this message at.")
+		return env.errorEmbed(newError(errCodeRemindTimeUnknown))
 	}
 
 	waitDuration := r.Time.In(location).Sub(now)
 	if waitDuration < 0 {
-		return NewErrorEmbed("Remind Error", "That time was "+humanize.Time(r.Time.In(location))+"!")
+		return env.errorEmbed(newError(errCodeRemindTimePassed, humanize.Time(r.Time.In(location))))
 	}
 
 	defer remindWhen(env.User.ID, env.Guild.ID, env.Channel.ID, text, now.In(location), r.Time.In(location), now.In(location))
@@ -154,11 +160,28 @@ func commandRemind(args []string, env *CommandEnvironment) *discordgo.MessageEmb
 }
 
 func remindWhen(userID, guildID, channelID, message string, added, when, now time.Time) {
-	remindEntries = append(remindEntries, RemindEntry{UserID: userID, ChannelID: channelID, Message: message, Added: added, When: when})
+	remindEntriesLock.Lock()
+	remindEntries = append(remindEntries, RemindEntry{UserID: userID, ChannelID: channelID, GuildID: guildID, Message: message, Added: added, When: when})
+	remindEntriesLock.Unlock()
 
 	waitDuration := when.Sub(now)
 	time.AfterFunc(waitDuration, func() {
-		botData.DiscordSession.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		//The remind entry may have been removed since it was scheduled, in which case there's nothing to remind
+		//Each of a user's reminders was added at a different time, which is also its ID in API v1
+		remindEntriesLock.RLock()
+		stillExists := false
+		for _, entry := range remindEntries {
+			if entry.UserID == userID && entry.Added.Equal(added) {
+				stillExists = true
+				break
+			}
+		}
+		remindEntriesLock.RUnlock()
+		if !stillExists {
+			return
+		}
+
+		botData().DiscordSession.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
 			Content: "<@!" + userID + "> :alarm_clock:",
 			Embed: NewEmbed().
 				SetTitle("Remind").
@@ -166,12 +189,15 @@ func remindWhen(userID, guildID, channelID, message string, added, when, now tim
 				AddField("Reminder", message).
 				SetColor(0x1C1C1C).MessageEmbed,
 		})
+		publishUserEvent(guildID, userID, EventReminderFired, &RemindEntry{UserID: userID, ChannelID: channelID, GuildID: guildID, Message: message, Added: added, When: when})
 
+		remindEntriesLock.Lock()
 		for i := len(remindEntries) - 1; i >= 0; i-- {
-			if remindEntries[i].UserID == userID && remindEntries[i].Message == message {
+			if remindEntries[i].UserID == userID && remindEntries[i].Added.Equal(added) {
 				remindEntries = append(remindEntries[:i], remindEntries[i+1:]...)
 				break
 			}
 		}
+		remindEntriesLock.Unlock()
 	})
 }
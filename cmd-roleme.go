@@ -48,32 +48,32 @@ func commandRoleMe(args []CommandArgument, env *CommandEnvironment) *discordgo.M
 		switch strings.ToLower(arg.Name) {
 		case "addrole", "roleadd":
 			if arg.Value == "" {
-				return NewErrorEmbed("RoleMe Error", "You must supply a value to the addrole argument.")
+				return env.errorEmbed(newError(errCodeRoleMeValueMissing, "addrole"))
 			}
 			role, err := getRole(env.Guild.ID, arg.Value)
 			if err != nil {
-				return NewErrorEmbed("RoleMe Error", "Error finding role %s.", arg.Value)
+				return env.errorEmbed(wrapError(errCodeRoleMeRoleUnknown, err, arg.Value))
 			}
 			if isStrInSlice(rolesToAdd, role.ID) {
-				return NewErrorEmbed("RoleMe Error", "You cannot specify the same role to add twice.")
+				return env.errorEmbed(newError(errCodeRoleMeAddDuplicate))
 			}
 			if isStrInSlice(rolesToRemove, role.ID) {
-				return NewErrorEmbed("RoleMe Error", "You cannot specify a role to add if the role is already specified to be removed.")
+				return env.errorEmbed(newError(errCodeRoleMeAddConflict))
 			}
 			rolesToAdd = append(rolesToAdd, role.ID)
 		case "removerole", "roleremove", "deleterole", "roledelete":
 			if arg.Value == "" {
-				return NewErrorEmbed("RoleMe Error", "You must supply a value to the removerole argument.")
+				return env.errorEmbed(newError(errCodeRoleMeValueMissing, "removerole"))
 			}
 			role, err := getRole(env.Guild.ID, arg.Value)
 			if err != nil {
-				return NewErrorEmbed("RoleMe Error", "Error finding role %s.", arg.Value)
+				return env.errorEmbed(wrapError(errCodeRoleMeRoleUnknown, err, arg.Value))
 			}
 			if isStrInSlice(rolesToRemove, role.ID) {
-				return NewErrorEmbed("RoleMe Error", "You cannot specify the same role to remove twice.")
+				return env.errorEmbed(newError(errCodeRoleMeRemoveDuplicate))
 			}
 			if isStrInSlice(rolesToAdd, role.ID) {
-				return NewErrorEmbed("RoleMe Error", "You cannot specify a role to remove if the role is already specified to be added.")
+				return env.errorEmbed(newError(errCodeRoleMeRemoveConflict))
 			}
 			rolesToRemove = append(rolesToRemove, role.ID)
 		case "casesensitive":
@@ -85,41 +85,41 @@ func commandRoleMe(args []CommandArgument, env *CommandEnvironment) *discordgo.M
 			}
 		case "channel":
 			if arg.Value == "" {
-				return NewErrorEmbed("RoleMe Error", "You must supply a value to the channel argument.")
+				return env.errorEmbed(newError(errCodeRoleMeValueMissing, "channel"))
 			}
 			channel, err := getChannel(env.Guild.ID, arg.Value)
 			if err != nil {
-				return NewErrorEmbed("RoleMe Error", "Error finding channel %s.", arg.Value)
+				return env.errorEmbed(wrapError(errCodeRoleMeChannelUnknown, err, arg.Value))
 			}
 			if isStrInSlice(channelIDs, channel.ID) {
-				return NewErrorEmbed("RoleMe Error", "You cannot specify the same channel twice.")
+				return env.errorEmbed(newError(errCodeRoleMeChannelDuplicate))
 			}
 			channelIDs = append(channelIDs, channel.ID)
 		case "trigger", "message", "msg":
 			if arg.Value == "" {
-				return NewErrorEmbed("RoleMe Error", "You must supply a value to the trigger argument.")
+				return env.errorEmbed(newError(errCodeRoleMeValueMissing, "trigger"))
 			}
 			if isStrInSlice(triggers, arg.Value) {
-				return NewErrorEmbed("RoleMe Error", "You cannot specify the same trigger twice.")
+				return env.errorEmbed(newError(errCodeRoleMeTriggerDuplicate))
 			}
 			triggers = append(triggers, arg.Value)
 		case "delete", "remove":
 			if arg.Value == "" {
-				return NewErrorEmbed("RoleMe Error", "You must supply a value to the delete argument.")
+				return env.errorEmbed(newError(errCodeRoleMeValueMissing, "delete"))
 			}
 			entryToDelete, err := strconv.Atoi(arg.Value)
 			if err != nil {
-				return NewErrorEmbed("RoleMe Error", "Invalid entry number ``%s``.", arg.Value)
+				return env.errorEmbed(wrapError(errCodeRoleMeEntryInvalid, err, arg.Value))
 			}
 			if isIntInSlice(entriesToDelete, entryToDelete) {
-				return NewErrorEmbed("RoleMe Error", "You cannot specify the same event to delete twice.")
+				return env.errorEmbed(newError(errCodeRoleMeDeleteDuplicate))
 			}
 			if entryToDelete <= 0 || entryToDelete > len(guildSettings[env.Guild.ID].RoleMeList) {
-				return NewErrorEmbed("RoleMe Error", "Unknown entry number ``%s``.", arg.Value)
+				return env.errorEmbed(newError(errCodeRoleMeEntryUnknown, arg.Value))
 			}
 			entriesToDelete = append(entriesToDelete, entryToDelete-1)
 		default:
-			return NewErrorEmbed("RoleMe Error", "Unknown argument ``%s``.", arg.Name)
+			return env.errorEmbed(newError(errCodeCommandUnknownArgument, arg.Name))
 		}
 	}
 
@@ -141,10 +141,10 @@ func commandRoleMe(args []CommandArgument, env *CommandEnvironment) *discordgo.M
 		return NewGenericEmbed("RoleMe", "Deleted the specified roleme entries successfully!")
 	}
 	if len(rolesToAdd) == 0 && len(rolesToRemove) == 0 {
-		return NewErrorEmbed("RoleMe Error", "You must specify either one or more roles to add or one or more roles to remove.")
+		return env.errorEmbed(newError(errCodeRoleMeRolesMissing))
 	}
 	if len(triggers) == 0 {
-		return NewErrorEmbed("RoleMe Error", "You must specify one or more triggers to trigger this roleme event.")
+		return env.errorEmbed(newError(errCodeRoleMeTriggersMissing))
 	}
 
 	newRoleMe := &RoleMe{
@@ -159,7 +159,7 @@ func commandRoleMe(args []CommandArgument, env *CommandEnvironment) *discordgo.M
 		for _, trigger := range roleMe.Triggers {
 			for _, newTrigger := range newRoleMe.Triggers {
 				if trigger == newTrigger {
-					return NewErrorEmbed("RoleMe Error", "The trigger ``%s`` already exists!", trigger)
+					return env.errorEmbed(newError(errCodeRoleMeTriggerExists, trigger))
 				}
 			}
 		}
@@ -186,7 +186,7 @@ func handleRoleMe(roleMe *RoleMe, guildID, channelID, userID string) {
 	errCount := 0
 	successCount := 0
 	for _, roleToAdd := range roleMe.AddRoles {
-		err := botData.DiscordSession.GuildMemberRoleAdd(guildID, userID, roleToAdd)
+		err := botData().DiscordSession.GuildMemberRoleAdd(guildID, userID, roleToAdd)
 		if err != nil {
 			errCount++
 		} else {
@@ -194,7 +194,7 @@ func handleRoleMe(roleMe *RoleMe, guildID, channelID, userID string) {
 		}
 	}
 	for _, roleToRemove := range roleMe.RemoveRoles {
-		err := botData.DiscordSession.GuildMemberRoleRemove(guildID, userID, roleToRemove)
+		err := botData().DiscordSession.GuildMemberRoleRemove(guildID, userID, roleToRemove)
 		if err != nil {
 			errCount++
 		} else {
@@ -203,16 +203,16 @@ func handleRoleMe(roleMe *RoleMe, guildID, channelID, userID string) {
 	}
 
 	if errCount == 0 {
-		botData.DiscordSession.ChannelMessageSendEmbed(channelID, NewGenericEmbed("RoleMe", "Edited your roles successfully!"))
+		botData().DiscordSession.ChannelMessageSendEmbed(channelID, NewGenericEmbed("RoleMe", "Edited your roles successfully!"))
 	} else if errCount < successCount {
-		botData.DiscordSession.ChannelMessageSendEmbed(channelID, NewGenericEmbed("RoleMe", "There were some errors editing your roles, but there were more successes!"))
+		botData().DiscordSession.ChannelMessageSendEmbed(channelID, NewGenericEmbed("RoleMe", "There were some errors editing your roles, but there were more successes!"))
 	} else {
-		botData.DiscordSession.ChannelMessageSendEmbed(channelID, NewErrorEmbed("RoleMe Error", "There were some errors editing your roles. :c"))
+		botData().DiscordSession.ChannelMessageSendEmbed(channelID, getErrorEmbed(newError(errCodeRoleMeEditFailed), guildLocale(guildID)))
 	}
 }
 
 func getRole(guildID, role string) (*discordgo.Role, error) {
-	guildRoles, err := botData.DiscordSession.GuildRoles(guildID)
+	guildRoles, err := botData().DiscordSession.GuildRoles(guildID)
 	if err != nil {
 		return nil, err
 	}
@@ -253,7 +253,7 @@ func getRole(guildID, role string) (*discordgo.Role, error) {
 }
 
 func getChannel(guildID, channel string) (*discordgo.Channel, error) {
-	guildChannels, err := botData.DiscordSession.GuildChannels(guildID)
+	guildChannels, err := botData().DiscordSession.GuildChannels(guildID)
 	if err != nil {
 		return nil, err
 	}
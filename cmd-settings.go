@@ -1,12 +1,13 @@
 package main
 
 import (
+	"encoding/json"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
-	"4d63.com/tz"
 	"github.com/bwmarrin/discordgo"
 	"github.com/fatih/structs"
 )
@@ -31,6 +32,7 @@ var (
 		GuildUpdate:       true,
 		SwearDetect:       true,
 		UserModlog:        true,
+		WebhookPost:       true,
 		VoiceStateUpdate:  true,
 	}
 )
@@ -40,8 +42,8 @@ type GuildSettings struct { //By default this will only be configurable for user
 	AllowVoice              bool                  `json:"allowVoice,omitempty"`              //Whether voice commands should be usable in this guild
 	BotAdminRoles           []string              `json:"adminRoles,omitempty"`              //An array of role IDs that can admin the bot without the guild administrator permission
 	BotAdminUsers           []string              `json:"adminUsers,omitempty"`              //An array of user IDs that can admin the bot without a guild administrator role
-	BotOptions              BotOptions            `json:"botOptions,omitempty"`              //The bot options to use in this guild (true gets overridden if global bot config is false)
-	BotPrefix               string                `json:"botPrefix",omitempty`               //The bot prefix to use in this guild
+	BotOptions              GuildBotOptions       `json:"botFeatures,omitempty"`             //The bot options to use in this guild (true gets overridden if global bot config is false)
+	BotPrefix               string                `json:"botPrefix,omitempty"`               //The bot prefix to use in this guild
 	CustomResponses         []CustomResponseQuery `json:"customResponses,omitempty"`         //An array of custom responses specific to the guild
 	LogSettings             LogSettings           `json:"logSettings,omitempty"`             //Logging settings
 	SwearFilter             SwearFilter           `json:"swearFilter,omitempty"`             //The swear filter settings specific to this guild
@@ -55,6 +57,8 @@ type GuildSettings struct { //By default this will only be configurable for user
 	APIInviteChannel        string                `json:"apiInviteChannel,omitempty"`        //The channel to use for server-side invite link generation
 	APIInviteKey            string                `json:"apiInviteKey,omitempty"`            //The key to use for server-side invite link generation
 	Feeds                   []*Feed               `json:"feeds,omitempty"`                   //A list of feeds for the current guild
+	WebhookChannels         map[string]string     `json:"webhookChannels,omitempty"`         //The channels webhooks can post to, where key = alias and value = channel ID
+	WebhookKeys             []*WebhookKey         `json:"webhookKeys,omitempty"`             //The keys that can post to the webhook channels
 }
 
 // UserSettings holds settings specific to a user
@@ -88,31 +92,48 @@ type LogSettings struct {
 // LogEvents holds logging events and whether or not they're enabled
 type LogEvents struct {
 	//Events received from Discord
-	ChannelCreate     bool `json:"channelCreate"`
-	ChannelDelete     bool `json:"channelDelete"`
-	ChannelUpdate     bool `json:"channelUpdate"`
-	GuildBanAdd       bool `json:"guildBanAdd"`
-	GuildBanRemove    bool `json:"guildBanRemove"`
-	GuildEmojisUpdate bool `json:"guildEmojisUpdate"`
-	GuildMemberAdd    bool `json:"guildMemberAdd"`
-	GuildMemberRemove bool `json:"guildMemberRemove"`
-	GuildRoleCreate   bool `json:"guildRoleCreate"`
-	GuildRoleDelete   bool `json:"guildRoleDelete"`
-	GuildRoleUpdate   bool `json:"guildRoleUpdate"`
-	GuildUpdate       bool `json:"guildUpdate"`
-	UserUpdate        bool `json:"userUpdate"`
-	VoiceStateUpdate  bool `json:"voiceStateUpdate"`
+	ChannelCreate     bool `json:"channelCreate"`     //Triggered when a channel is created
+	ChannelDelete     bool `json:"channelDelete"`     //Triggered when a channel is deleted
+	ChannelUpdate     bool `json:"channelUpdate"`     //Triggered when a channel is changed
+	GuildBanAdd       bool `json:"guildBanAdd"`       //Triggered when a user is banned
+	GuildBanRemove    bool `json:"guildBanRemove"`    //Triggered when a user is unbanned
+	GuildEmojisUpdate bool `json:"guildEmojisUpdate"` //Triggered when the emojis are changed
+	GuildMemberAdd    bool `json:"guildMemberAdd"`    //Triggered when a user joins
+	GuildMemberRemove bool `json:"guildMemberRemove"` //Triggered when a user leaves
+	GuildRoleCreate   bool `json:"guildRoleCreate"`   //Triggered when a role is created
+	GuildRoleDelete   bool `json:"guildRoleDelete"`   //Triggered when a role is deleted
+	GuildRoleUpdate   bool `json:"guildRoleUpdate"`   //Triggered when a role is changed
+	GuildUpdate       bool `json:"guildUpdate"`       //Triggered when the guild is changed
+	UserUpdate        bool `json:"userUpdate"`        //Triggered when a user changes their profile
+	VoiceStateUpdate  bool `json:"voiceStateUpdate"`  //Triggered when a user joins, leaves or moves between voice channels
 
 	//Custom events
 	SwearDetect bool `json:"swearDetect"` //Triggered if a user uses a blacklisted (swear) word
 	UserModlog  bool `json:"userModlog"`  //Triggered if a user's modlog is updated globally
+	WebhookPost bool `json:"webhookPost"` //Triggered when a webhook key posts a message
+}
+
+// logEventsJSON decodes logging events without calling LogEvents.UnmarshalJSON again
+type logEventsJSON LogEvents
+
+// UnmarshalJSON decodes logging events, enabling webhook posts for guilds saved before the event existed as it's recommended
+func (events *LogEvents) UnmarshalJSON(data []byte) error {
+	legacy := &struct {
+		*logEventsJSON
+		WebhookPost *bool `json:"webhookPost"`
+	}{logEventsJSON: (*logEventsJSON)(events)}
+	if err := json.Unmarshal(data, legacy); err != nil {
+		return err
+	}
+	events.WebhookPost = legacy.WebhookPost == nil || *legacy.WebhookPost
+	return nil
 }
 
 func commandSettingsBot(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	switch args[0] {
 	case "prefix":
 		if len(args) > 1 {
-			if args[1] == botData.CommandPrefix {
+			if args[1] == botData().CommandPrefix {
 				guildSettings[env.Guild.ID].BotPrefix = ""
 			} else {
 				guildSettings[env.Guild.ID].BotPrefix = args[1]
@@ -122,9 +143,68 @@ func commandSettingsBot(args []string, env *CommandEnvironment) *discordgo.Messa
 		if guildSettings[env.Guild.ID].BotPrefix != "" {
 			return NewGenericEmbed("Bot Settings - Command Prefix", "Current command prefix:\n\n"+guildSettings[env.Guild.ID].BotPrefix)
 		}
-		return NewGenericEmbed("Bot Settings - Command Prefix", "Current command prefix:\n\n"+botData.CommandPrefix)
+		return NewGenericEmbed("Bot Settings - Command Prefix", "Current command prefix:\n\n"+botData().CommandPrefix)
+	case "feature", "features":
+		featureCommand := &Command{
+			HelpText: "Manages which features are enabled in this server.",
+			RequiredArguments: []string{
+				"action (feature)",
+			},
+			Arguments: []CommandArgument{
+				{Name: "list", Description: "Lists every feature and whether it's enabled", ArgType: "this"},
+				{Name: "enable", Description: "Enables a feature", ArgType: "feature"},
+				{Name: "disable", Description: "Disables a feature", ArgType: "feature"},
+				{Name: "reset", Description: "Resets a feature to the bot's default", ArgType: "feature"},
+			},
+		}
+		cmdUsage := getCustomCommandUsage(featureCommand, "bot "+args[0], "Bot Settings - Features Help", env)
+
+		if len(args) < 2 {
+			return cmdUsage
+		}
+
+		switch args[1] {
+		case "list":
+			featureList := ""
+			for _, feature := range features {
+				state := "Enabled"
+				if !*feature.Global(&botData().BotOptions) {
+					state = "Disabled by the bot"
+				} else if !featureEnabled(env.Guild.ID, feature.Name) {
+					state = "Disabled"
+				}
+				featureList += "``" + feature.Name + "`` - " + feature.Description + ": **" + state + "**\n"
+			}
+			return NewGenericEmbed("Bot Settings - Features", featureList)
+		case "enable", "disable", "reset":
+			if len(args) < 3 {
+				return cmdUsage
+			}
+			feature := getFeature(args[2])
+			if feature == nil {
+				return env.errorEmbed(newError(errCodeSettingFeatureUnknown, args[2], env.BotPrefix))
+			}
+
+			override := feature.Guild(&guildSettings[env.Guild.ID].BotOptions)
+			switch args[1] {
+			case "enable":
+				if !*feature.Global(&botData().BotOptions) {
+					return env.errorEmbed(newError(errCodeSettingFeatureDisabled, feature.Name))
+				}
+				enabled := true
+				*override = &enabled
+				return NewGenericEmbed("Bot Settings - Features", "Enabled the feature ``"+feature.Name+"`` in this server.")
+			case "disable":
+				enabled := false
+				*override = &enabled
+				return NewGenericEmbed("Bot Settings - Features", "Disabled the feature ``"+feature.Name+"`` in this server.")
+			}
+			*override = nil
+			return NewGenericEmbed("Bot Settings - Features", "Reset the feature ``"+feature.Name+"`` to the bot's default.")
+		}
+		return env.errorEmbed(newError(errCodeCommandUnknownAction, args[1]))
 	}
-	return NewErrorEmbed("Bot Settings Error", "Error finding the setting ``"+args[0]+"``.")
+	return env.errorEmbed(newError(errCodeCommandUnknownSetting, args[0]))
 }
 
 func commandSettingsUser(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
@@ -133,30 +213,35 @@ func commandSettingsUser(args []string, env *CommandEnvironment) *discordgo.Mess
 	switch args[0] {
 	case "about", "aboutme", "description", "desc", "info":
 		if len(args) <= 1 {
-			if userSettings[env.User.ID].AboutMe == "" {
-				return NewErrorEmbed("User Settings - About Me Error", "You must specify an aboutme to view it.")
+			if settings, _ := copyUserSettings(env.User.ID); settings.AboutMe == "" {
+				return env.errorEmbed(newError(errCodeSettingAboutMeMissing))
 			}
-			return aboutMe(env.User.ID)
+			return aboutMe(env.User.ID, env)
 		}
 		if len(args) == 2 && len(env.Message.Mentions) > 0 {
-			return aboutMe(env.Message.Mentions[0].ID)
+			return aboutMe(env.Message.Mentions[0].ID, env)
 		}
+		userSettingsLock.Lock()
 		userSettings[env.User.ID].AboutMe = strings.Join(args[1:], " ")
+		userSettingsLock.Unlock()
 		return NewGenericEmbed("User Settings - About Me", "Successfully set your about me!")
 	case "timezone", "tz":
+		userSettingsLock.Lock()
+		defer userSettingsLock.Unlock()
+
 		if len(args) <= 1 {
 			if userSettings[env.User.ID].Timezone == "" {
-				return NewErrorEmbed("User Settings - Timezone Error", "You must specify a timezone to view it.")
+				return env.errorEmbed(newError(errCodeSettingTimezoneMissing))
 			}
-			location, err := tz.LoadLocation(userSettings[env.User.ID].Timezone)
+			location, err := loadTimezone(userSettings[env.User.ID].Timezone)
 			if err != nil {
-				return NewErrorEmbed("User Settings - Timezone Error", "You have an invalid timezone set, please set a new one first.\n\nEx: ``"+env.BotPrefix+"user timezone America/New_York``")
+				return env.errorEmbed(wrapError(errCodeTimezoneInvalid, err, env.BotPrefix))
 			}
 			return NewGenericEmbed("User Settings - Timezone", "Your current timezone is set to ``"+userSettings[env.User.ID].Timezone+"``.\nYour current time is ``"+time.Now().In(location).String()+"``.")
 		}
-		location, err := tz.LoadLocation(args[1])
+		location, err := loadTimezone(args[1])
 		if err != nil {
-			return NewErrorEmbed("User Settings - Timezone Error", "Invalid timezone.")
+			return env.errorEmbed(err)
 		}
 		userSettings[env.User.ID].Timezone = args[1]
 		return NewGenericEmbed("User Settings - Timezone", "Successfully set your timezone to ``"+args[1]+"``.\nYour current time is ``"+time.Now().In(location).String()+"``.")
@@ -167,6 +252,8 @@ func commandSettingsUser(args []string, env *CommandEnvironment) *discordgo.Mess
 		* cli$user social clear switchfc
 		* cli$user social available
 		 */
+		userSettingsLock.Lock()
+		defer userSettingsLock.Unlock()
 
 		socialCommand := &Command{
 			HelpText: "Manages your socials.",
@@ -189,45 +276,41 @@ func commandSettingsUser(args []string, env *CommandEnvironment) *discordgo.Mess
 		switch args[1] {
 		case "set", "add":
 			if len(args) < 4 {
-				return NewErrorEmbed("User Settings - Socials", "You must specify a social identifier to set it.")
+				return env.errorEmbed(newError(errCodeSettingSocialMissing))
 			}
 			switch args[2] {
 			case "switchfc":
-				if !regexpSwitchFC.MatchString(args[3]) {
-					return NewErrorEmbed("User Settings - Socials", "Invalid Switch friend code.")
+				if err := validateSwitchFC(args[3]); err != nil {
+					return env.errorEmbed(err)
 				}
 				if userSettings[env.User.ID].Socials.SwitchFC == args[3] {
-					return NewErrorEmbed("User Settings - Socials", "You have already set that Switch friend code.")
+					return env.errorEmbed(newError(errCodeSettingSocialAlreadySet, "Switch friend code"))
 				}
 				userSettings[env.User.ID].Socials.SwitchFC = args[3]
 				return NewGenericEmbed("User Settings - Socials", "Successfully set your Switch friend code to ``"+args[3]+"``.")
 			case "nintendoid", "nintyid", "nnid":
 				if userSettings[env.User.ID].Socials.NNID == args[3] {
-					return NewErrorEmbed("User Settings - Socials", "You have already set that NNID.")
+					return env.errorEmbed(newError(errCodeSettingSocialAlreadySet, "NNID"))
 				}
-				exists, _, err := botData.BotClients.Ninty.DoesUserExist(args[3])
-				if err != nil {
-					return NewErrorEmbed("User Settings - Social Error", "There was an error checking if that NNID exists.")
-				}
-				if !exists {
-					return NewErrorEmbed("User Settings - Social Error", "That NNID doesn't exist!")
+				if err := validateNNID(args[3]); err != nil {
+					return env.errorEmbed(err)
 				}
 				userSettings[env.User.ID].Socials.NNID = args[3]
 				return NewGenericEmbed("User Settings - Socials", "Successfully set your NNID to ``"+args[3]+"``.")
 			case "psn":
 				if userSettings[env.User.ID].Socials.PSN == args[3] {
-					return NewErrorEmbed("User Settings - Socials", "You have already set that PSN.")
+					return env.errorEmbed(newError(errCodeSettingSocialAlreadySet, "PSN"))
 				}
 				userSettings[env.User.ID].Socials.PSN = args[3]
 				return NewGenericEmbed("User Settings - Socials", "Successfully set your PSN to ``"+args[3]+"``.")
 			case "xbox", "gamertag":
 				if userSettings[env.User.ID].Socials.Xbox == args[3] {
-					return NewErrorEmbed("User Settings - Socials", "You have already set that Xbox Live gamertag.")
+					return env.errorEmbed(newError(errCodeSettingSocialAlreadySet, "Xbox Live gamertag"))
 				}
 				userSettings[env.User.ID].Socials.Xbox = args[3]
 				return NewGenericEmbed("User Settings - Socials", "Successfully set your Xbox Live gamertag to ``"+args[3]+"``.")
 			}
-			return NewErrorEmbed("User Settings - Socials Error", "Unknown social "+args[2]+"``.")
+			return env.errorEmbed(newError(errCodeSettingSocialUnknown, args[2]))
 		case "list":
 			socialsEmbed := NewEmbed().
 				SetTitle("Socials").
@@ -275,30 +358,30 @@ func commandSettingsUser(args []string, env *CommandEnvironment) *discordgo.Mess
 			switch args[2] {
 			case "switchfc":
 				if userSettings[env.User.ID].Socials.SwitchFC == "" {
-					return NewErrorEmbed("User Settings - Socials", "You don't have a Switch friend code set.")
+					return env.errorEmbed(newError(errCodeSettingSocialNotSet, "Switch friend code"))
 				}
 				userSettings[env.User.ID].Socials.SwitchFC = ""
 				return NewGenericEmbed("User Settings - Socials", "Cleared your Switch friend code.")
 			case "nintendoid", "nintyid", "nnid":
 				if userSettings[env.User.ID].Socials.NNID == "" {
-					return NewErrorEmbed("User Settings - Socials", "You don't have an NNID set.")
+					return env.errorEmbed(newError(errCodeSettingSocialNotSet, "NNID"))
 				}
 				userSettings[env.User.ID].Socials.NNID = ""
 				return NewGenericEmbed("User Settings - Socials", "Cleared your NNID.")
 			case "psn":
 				if userSettings[env.User.ID].Socials.PSN == "" {
-					return NewErrorEmbed("User Settings - Socials", "You don't have a PSN set.")
+					return env.errorEmbed(newError(errCodeSettingSocialNotSet, "PSN"))
 				}
 				userSettings[env.User.ID].Socials.PSN = ""
 				return NewGenericEmbed("User Settings - Socials", "Cleared your PSN.")
 			case "xbox":
 				if userSettings[env.User.ID].Socials.Xbox == "" {
-					return NewErrorEmbed("User Settings - Socials", "You don't have an Xbox Live gamertag set.")
+					return env.errorEmbed(newError(errCodeSettingSocialNotSet, "Xbox Live gamertag"))
 				}
 				userSettings[env.User.ID].Socials.Xbox = ""
 				return NewGenericEmbed("User Settings - Socials", "Cleared your Xbox Live gamertag.")
 			}
-			return NewErrorEmbed("User Settings - Socials Error", "Unknown social ``"+args[2]+"``.")
+			return env.errorEmbed(newError(errCodeSettingSocialUnknown, args[2]))
 		case "available", "types":
 			return NewGenericEmbed("User Settings - Socials - Types", "These are the available socials you can use:\n\n"+
 				"``switchfc`` - Nintendo Switch friend code\n"+
@@ -307,20 +390,52 @@ func commandSettingsUser(args []string, env *CommandEnvironment) *discordgo.Mess
 				"``xbox`` - Xbox Live Gamertag",
 			)
 		}
-		return NewErrorEmbed("User Settings - Socials Error", "Unknown socials command ``"+args[1]+"``.")
+		return env.errorEmbed(newError(errCodeCommandUnknownAction, args[1]))
+	case "data", "mydata":
+		dataCommand := &Command{
+			HelpText: "Manages the data " + botData().BotName + " stores about you.",
+			RequiredArguments: []string{
+				"action",
+			},
+			Arguments: []CommandArgument{
+				{Name: "export", Description: "Sends you a copy of your data in your DMs", ArgType: "this"},
+				{Name: "delete", Description: "Deletes all of your data", ArgType: "this"},
+			},
+		}
+		cmdUsage := getCustomCommandUsage(dataCommand, "user "+args[0], "User Settings - Data Help", env)
+
+		if len(args) < 2 {
+			return cmdUsage
+		}
+
+		switch args[1] {
+		case "export", "download":
+			err := sendUserDataExport(env.User.ID, exportUserData(env.User.ID, env.Guild.ID))
+			if err != nil {
+				return env.errorEmbed(wrapError(errCodeUserDataExportFailed, err))
+			}
+			return NewGenericEmbed("User Settings - Data", "Sent a copy of your data to your DMs!")
+		case "delete", "purge", "forget":
+			if len(args) < 3 || args[2] != "confirm" {
+				return env.errorEmbed(newError(errCodeUserDataDeleteUnconfirmed, env.BotPrefix))
+			}
+			result := purgeUserData(env.User.ID, env.Guild.ID)
+			return NewGenericEmbed("User Settings - Data", "Deleted your data.\n\n"+result.Summary())
+		}
+		return env.errorEmbed(newError(errCodeCommandUnknownAction, args[1]))
 	}
-	return NewErrorEmbed("User Settings Error", "Error finding the setting ``"+args[0]+"``.")
+	return env.errorEmbed(newError(errCodeCommandUnknownSetting, args[0]))
 }
 
-func aboutMe(userID string) *discordgo.MessageEmbed {
-	settings, found := userSettings[userID]
+func aboutMe(userID string, env *CommandEnvironment) *discordgo.MessageEmbed {
+	settings, found := copyUserSettings(userID)
 	if !found {
-		return NewErrorEmbed("About Me - Error", "Error finding the aboutme for <@!"+userID+">.")
+		return env.errorEmbed(newError(errCodeSettingAboutMeNotFound, userID))
 	}
 
-	user, err := botData.DiscordSession.User(userID)
+	user, err := botData().DiscordSession.User(userID)
 	if err != nil {
-		return NewErrorEmbed("About Me - Error", "Error finding the user <@!"+userID+">.")
+		return env.errorEmbed(wrapError(errCodeSettingAboutMeUserNotFound, err, userID))
 	}
 
 	return NewEmbed().
@@ -354,7 +469,7 @@ func commandSettingsServer(args []string, env *CommandEnvironment) *discordgo.Me
 			guildSettings[env.Guild.ID].TipsChannel = ""
 			return NewGenericEmbed("Server Settings - Tips", "Successfully disabled hourly tips for this channel.")
 		}
-		return NewErrorEmbed("Server Settings - Tips Error", "Unknown tips command ``"+args[1]+"``.")
+		return env.errorEmbed(newError(errCodeCommandUnknownAction, args[1]))
 	case "autosendnowplaying":
 		switch args[1] {
 		case "enable":
@@ -364,7 +479,7 @@ func commandSettingsServer(args []string, env *CommandEnvironment) *discordgo.Me
 			guildSettings[env.Guild.ID].AutoSendNowPlaying = false
 			return NewGenericEmbed("Server Settings - Auto Send Now Playing", "Successfully disabled sending now playing messages each time a new track is started without user interaction.")
 		}
-		return NewErrorEmbed("Server Settings - Auto Send Now Playing Error", "Unknown ASNP command ``"+args[1]+"``.")
+		return env.errorEmbed(newError(errCodeCommandUnknownAction, args[1]))
 	case "invitegen":
 		if len(args) < 2 {
 			invitegenHelpCmd := &Command{
@@ -394,7 +509,79 @@ func commandSettingsServer(args []string, env *CommandEnvironment) *discordgo.Me
 			}
 			return NewGenericEmbed("Server Settings - API Invite Generation", "The current key for generating invite links is ``"+guildSettings[env.Guild.ID].APIInviteKey+"``.")
 		}
-		return NewErrorEmbed("Server Settings - API Invite Generation Error", "Unknown invitegen command ``"+args[1]+"``.")
+		return env.errorEmbed(newError(errCodeCommandUnknownAction, args[1]))
+	case "webhook", "webhooks":
+		if len(args) < 2 {
+			webhookHelpCmd := &Command{
+				HelpText: "Manages the channels webhooks can post to. Webhook keys are created through the API.",
+				RequiredArguments: []string{
+					"setting (value(s))",
+				},
+				Arguments: []CommandArgument{
+					{Name: "list", Description: "Lists the webhook channels and keys", ArgType: "this"},
+					{Name: "channel", Description: "Lets webhooks post to the current channel by the given alias", ArgType: "alias"},
+					{Name: "remove", Description: "Stops webhooks from posting to the channel with the given alias", ArgType: "alias"},
+					{Name: "revoke", Description: "Revokes the webhook key with the given ID", ArgType: "key ID"},
+				},
+			}
+			return getCustomCommandUsage(webhookHelpCmd, "server webhook", "Server Settings - Webhooks Help", env)
+		}
+
+		settings := guildSettings[env.Guild.ID]
+		switch args[1] {
+		case "list":
+			if len(settings.WebhookChannels) == 0 && len(settings.WebhookKeys) == 0 {
+				return NewGenericEmbed("Server Settings - Webhooks", "No webhook channels or keys are set up for this server.")
+			}
+			aliases := make([]string, 0, len(settings.WebhookChannels))
+			for alias := range settings.WebhookChannels {
+				aliases = append(aliases, alias)
+			}
+			sort.Strings(aliases)
+			channels := make([]string, 0, len(aliases))
+			for _, alias := range aliases {
+				channels = append(channels, "``"+alias+"``: <#"+settings.WebhookChannels[alias]+">")
+			}
+			keys := make([]string, 0, len(settings.WebhookKeys))
+			for _, key := range settings.WebhookKeys {
+				keys = append(keys, key.Name+" (``"+key.ID+"``)")
+			}
+			if len(channels) == 0 {
+				channels = append(channels, "None")
+			}
+			if len(keys) == 0 {
+				keys = append(keys, "None")
+			}
+			return NewEmbed().
+				SetTitle("Server Settings - Webhooks").
+				AddField("Channels", strings.Join(channels, "\n")).
+				AddField("Keys", strings.Join(keys, "\n")).
+				SetColor(0x1C1C1C).MessageEmbed
+		case "channel":
+			if len(args) < 3 || !webhookAliasRegexp.MatchString(args[2]) {
+				return env.errorEmbed(newError(errCodeWebhookAliasInvalid))
+			}
+			if settings.WebhookChannels == nil {
+				settings.WebhookChannels = make(map[string]string)
+			}
+			settings.WebhookChannels[args[2]] = env.Channel.ID
+			return NewGenericEmbed("Server Settings - Webhooks", "Successfully let webhooks post to this channel as ``"+args[2]+"``.")
+		case "remove":
+			if len(args) < 3 {
+				return env.errorEmbed(newError(errCodeWebhookAliasMissing))
+			}
+			if _, exists := settings.WebhookChannels[args[2]]; !exists {
+				return env.errorEmbed(newError(errCodeWebhookChannelUnknown, args[2]))
+			}
+			delete(settings.WebhookChannels, args[2])
+			return NewGenericEmbed("Server Settings - Webhooks", "Successfully removed the webhook channel ``"+args[2]+"``.")
+		case "revoke":
+			if len(args) < 3 || !revokeWebhookKey(env.Guild.ID, args[2]) {
+				return env.errorEmbed(newError(errCodeWebhookKeyMissing))
+			}
+			return NewGenericEmbed("Server Settings - Webhooks", "Successfully revoked the webhook key ``"+args[2]+"``.")
+		}
+		return env.errorEmbed(newError(errCodeCommandUnknownAction, args[1]))
 	case "filter":
 		if len(args) < 2 {
 			filterHelpCmd := &Command{
@@ -434,13 +621,17 @@ func commandSettingsServer(args []string, env *CommandEnvironment) *discordgo.Me
 			switch args[2] {
 			case "add":
 				if len(args) < 4 {
-					return NewErrorEmbed("Server Settings - Swear Filter Error", "You must specify one or more words to add to the filter.")
+					return env.errorEmbed(newError(errCodeSettingFilterAddMissing))
+				}
+				words := append(append([]string{}, guildSettings[env.Guild.ID].SwearFilter.BlacklistedWords...), args[3:]...)
+				if err := validateFilterWords(words); err != nil {
+					return env.errorEmbed(err)
 				}
-				guildSettings[env.Guild.ID].SwearFilter.BlacklistedWords = append(guildSettings[env.Guild.ID].SwearFilter.BlacklistedWords, args[3:]...)
+				guildSettings[env.Guild.ID].SwearFilter.BlacklistedWords = words
 				return NewGenericEmbed("Server Settings - Swear Filter", "Successfully added the provided words to the filter.")
 			case "remove":
 				if len(args) < 4 {
-					return NewErrorEmbed("Server Settings - Swear Filter Error", "You must specify one or more words to remove from the filter.")
+					return env.errorEmbed(newError(errCodeSettingFilterRemoveMissing))
 				}
 				for _, word := range guildSettings[env.Guild.ID].SwearFilter.BlacklistedWords {
 					guildSettings[env.Guild.ID].SwearFilter.BlacklistedWords = remove(guildSettings[env.Guild.ID].SwearFilter.BlacklistedWords, word)
@@ -460,12 +651,15 @@ func commandSettingsServer(args []string, env *CommandEnvironment) *discordgo.Me
 			}
 			timeout, err := strconv.Atoi(args[2])
 			if err != nil {
-				return NewErrorEmbed("Server Settings - Swear Filter Error", "``"+args[2]+"`` is not a valid number.")
+				return env.errorEmbed(wrapError(errCodeCommandInvalidNumber, err, args[2]))
+			}
+			if err := validateSettingNotNegative(env.Guild.ID, timeout); err != nil {
+				return env.errorEmbed(err)
 			}
 			guildSettings[env.Guild.ID].SwearFilter.WarningDeleteTimeout = time.Duration(timeout)
 			return NewGenericEmbed("Server Settings - Swear Filter", "Successfully set he timeout for deleting warning messages to "+args[2]+" seconds.")
 		}
-		return NewErrorEmbed("Server Settings - Swear Filter Error", "Unknown filter command ``"+args[1]+"``.")
+		return env.errorEmbed(newError(errCodeCommandUnknownAction, args[1]))
 	case "log":
 		if len(args) < 2 {
 			logHelpCmd := &Command{
@@ -502,7 +696,7 @@ func commandSettingsServer(args []string, env *CommandEnvironment) *discordgo.Me
 					for _, event := range fields {
 						err := event.Set(true)
 						if err != nil {
-							return NewErrorEmbed("Server Settings - Log", "Unable to enable all logging events.")
+							return env.errorEmbed(wrapError(errCodeSettingLogEnableFailed, err))
 						}
 					}
 
@@ -619,10 +813,10 @@ func commandSettingsServer(args []string, env *CommandEnvironment) *discordgo.Me
 
 			return NewGenericEmbed("Server Settings - Log", responseMessage)
 		}
-		return NewErrorEmbed("Server Settings - Log Error", "Unknown log command ``"+args[1]+"``.")
+		return env.errorEmbed(newError(errCodeCommandUnknownAction, args[1]))
 	case "reset":
 		if len(args) < 2 {
-			return NewErrorEmbed("Server Settings - Reset Error", "You must specify a setting to reset.")
+			return env.errorEmbed(newError(errCodeSettingResetMissing))
 		}
 		switch args[1] {
 		case "joinmsg":
@@ -650,9 +844,9 @@ func commandSettingsServer(args []string, env *CommandEnvironment) *discordgo.Me
 			guildSettings[env.Guild.ID].APIInviteChannel = ""
 			guildSettings[env.Guild.ID].APIInviteKey = ""
 		default:
-			return NewErrorEmbed("Server Settings - Reset Error", "Error finding the setting ``"+args[1]+"``.")
+			return env.errorEmbed(newError(errCodeCommandUnknownSetting, args[1]))
 		}
 		return NewGenericEmbed("Server Settings - Reset", "Successfully reset the settings for ``"+args[1]+"``.")
 	}
-	return NewErrorEmbed("Server Settings Error", "Error finding the setting ``"+args[0]+"``.")
+	return env.errorEmbed(newError(errCodeCommandUnknownSetting, args[0]))
 }
@@ -31,14 +31,50 @@ type StarboardEntry struct {
 	SourceMessageID    string //The source message ID
 	StarboardChannelID string //The channel ID the starboard entry message resides in (just in case the starboard channel changes and messages need to be moved to a new channel)
 	StarboardMessageID string //The starboard entry's message ID
+	SourceAuthorID     string //The user ID of the source message's author (empty for entries created before this was tracked)
 	Stars              int    //The amount of stars on this entry
 }
 
+// backfillStarboardAuthors resolves the authors of starboard entries created before authors were tracked
+// Each guild is only locked while reading and updating its entries, never while waiting on Discord
+// Entries whose source message no longer exists can't be resolved and keep an empty author
+// - lockedGuildID: The guild whose data is already locked by the caller, if any
+func backfillStarboardAuthors(lockedGuildID string) {
+	defer recoverHandler("backfillStarboardAuthors", "")
+
+	for guildID, starboard := range starboards {
+		initializeGuildData(guildID)
+		unlock := lockUserDataGuild(guildID, lockedGuildID)
+		missing := make([]StarboardEntry, 0)
+		for _, entry := range starboard.StarboardEntries {
+			if entry.SourceAuthorID == "" {
+				missing = append(missing, entry)
+			}
+		}
+		unlock()
+
+		for _, entry := range missing {
+			message, err := botData().DiscordSession.ChannelMessage(entry.SourceChannelID, entry.SourceMessageID)
+			if err != nil || message.Author == nil {
+				continue
+			}
+
+			unlock := lockUserDataGuild(guildID, lockedGuildID)
+			for i := range starboard.StarboardEntries {
+				if starboard.StarboardEntries[i].SourceMessageID == entry.SourceMessageID {
+					starboard.StarboardEntries[i].SourceAuthorID = message.Author.ID
+				}
+			}
+			unlock()
+		}
+	}
+}
+
 func commandStarboard(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	switch args[0] {
 	case "debug":
-		if env.User.ID != botData.BotOwnerID {
-			NewErrorEmbed("Command Error - Not Authorized (NA)", "You are not authorized to use this command.")
+		if env.User.ID != botData().BotOwnerID {
+			return env.errorEmbed(newError(errCodeCommandNotAuthorized))
 		}
 		starboard := starboards[env.Guild.ID]
 		json, _ := json.MarshalIndent(starboard, "", "")
@@ -59,7 +95,7 @@ func commandStarboard(args []string, env *CommandEnvironment) *discordgo.Message
 
 		minimum, err := strconv.Atoi(args[1])
 		if err != nil {
-			return NewErrorEmbed("Starboard Error", "``"+args[1]+"`` is not a valid number.")
+			return env.errorEmbed(wrapError(errCodeCommandInvalidNumber, err, args[1]))
 		}
 
 		starboards[env.Guild.ID].MinimumStars = minimum
@@ -90,13 +126,13 @@ func commandStarboard(args []string, env *CommandEnvironment) *discordgo.Message
 				SetColor(0xFFE200)
 
 			for i, starboardEntry := range starboardEntries {
-				sourceMessage, err := botData.DiscordSession.ChannelMessage(starboardEntry.SourceChannelID, starboardEntry.SourceMessageID)
+				sourceMessage, err := botData().DiscordSession.ChannelMessage(starboardEntry.SourceChannelID, starboardEntry.SourceMessageID)
 				if err != nil {
 					starboardEntries = append(starboards[env.Guild.ID].StarboardEntries[:i], starboards[env.Guild.ID].StarboardEntries[i+1])
 					i--
 					continue
 				}
-				sourceChannel, err := botData.DiscordSession.Channel(starboardEntry.SourceChannelID)
+				sourceChannel, err := botData().DiscordSession.Channel(starboardEntry.SourceChannelID)
 				if err != nil {
 					starboardEntries = append(starboards[env.Guild.ID].StarboardEntries[:i], starboards[env.Guild.ID].StarboardEntries[i+1])
 					i--
@@ -136,7 +172,7 @@ func commandStarboard(args []string, env *CommandEnvironment) *discordgo.Message
 			starboards[env.Guild.ID].ChannelID = ""
 			return NewGenericEmbed("Starboard", "Unset the previous starboard channel.")
 		}
-		return NewErrorEmbed("Starboard Error", "You must specify ``set`` instead of ``"+args[1]+"`` to set the current channel as the starboard channel.")
+		return env.errorEmbed(newError(errCodeStarboardSetExpected, args[1]))
 	case "nsfwchannel":
 		if len(args) == 1 {
 			if starboards[env.Guild.ID].NSFWChannelID == "" {
@@ -146,7 +182,7 @@ func commandStarboard(args []string, env *CommandEnvironment) *discordgo.Message
 		}
 		if args[1] == "set" {
 			if !env.Channel.NSFW {
-				return NewErrorEmbed("Starboard Error", "You must mark this channel as NSFW before you can use it as the NSFW starboard channel.")
+				return env.errorEmbed(newError(errCodeStarboardNSFWRequired))
 			}
 			starboards[env.Guild.ID].NSFWChannelID = env.Channel.ID
 			return NewGenericEmbed("Starboard", "Set the NSFW starboard channel to <#"+env.Channel.ID+">.")
@@ -155,14 +191,14 @@ func commandStarboard(args []string, env *CommandEnvironment) *discordgo.Message
 			starboards[env.Guild.ID].NSFWChannelID = ""
 			return NewGenericEmbed("Starboard", "Unset the previous NSFW starboard channel.")
 		}
-		return NewErrorEmbed("Starboard Error", "You must specify ``set`` instead of ``"+args[1]+"`` to set the current channel as the NSFW starboard channel.")
+		return env.errorEmbed(newError(errCodeStarboardNSFWSetExpected, args[1]))
 	case "emoji":
 		if len(args) == 1 {
 			return NewGenericEmbed("Starboard", "Emoji: "+starboards[env.Guild.ID].Emoji)
 		}
 		if strings.Contains(args[1], ":") {
 			//starboards[env.Guild.ID].Emoji = GetStringInBetween(args[1], ":", ">")
-			return NewErrorEmbed("Starboard Error", "Custom emojis are not permitted at this time.")
+			return env.errorEmbed(newError(errCodeStarboardCustomEmoji))
 		}
 		starboards[env.Guild.ID].Emoji = args[1]
 		return NewGenericEmbed("Starboard", "Set the emoji to "+args[1]+".")
@@ -172,7 +208,7 @@ func commandStarboard(args []string, env *CommandEnvironment) *discordgo.Message
 		}
 		if strings.Contains(args[1], ":") {
 			//starboards[env.Guild.ID].NSFWEmoji = GetStringInBetween(args[1], ":", ">")
-			return NewErrorEmbed("Starboard Error", "Custom emojis are not permitted at this time.")
+			return env.errorEmbed(newError(errCodeStarboardCustomEmoji))
 		}
 		starboards[env.Guild.ID].NSFWEmoji = args[1]
 		return NewGenericEmbed("Starboard", "Set the NSFW emoji to "+args[1]+".")
@@ -196,18 +232,25 @@ func commandStarboard(args []string, env *CommandEnvironment) *discordgo.Message
 
 			return NewGenericEmbed("Starboard", "Successfully disabled selfstar.")
 		default:
-			return NewErrorEmbed("Starboard Error", "Unknown value ``"+args[1]+"``. Please use either ``enable`` or ``disable``.")
+			return env.errorEmbed(newError(errCodeStarboardToggleInvalid, args[1]))
 		}
 	}
-	return NewErrorEmbed("Starboard Error", "Error finding the setting ``"+args[0]+"``.")
+	return env.errorEmbed(newError(errCodeCommandUnknownSetting, args[0]))
 }
 
 func discordMessageReactionAdd(session *discordgo.Session, reaction *discordgo.MessageReactionAdd) {
+	defer recoverHandler("messageReactionAdd", reaction.GuildID)
+
 	channel, err := session.Channel(reaction.ChannelID)
 	if err != nil {
 		return
 	}
 
+	//Starboards are guarded by their guild's lock, the same as when they're changed by commands
+	initializeGuildData(channel.GuildID)
+	guildData[channel.GuildID].Lock()
+	defer guildData[channel.GuildID].Unlock()
+
 	if _, exists := starboards[channel.GuildID]; exists == false {
 		return
 	}
@@ -260,13 +303,15 @@ func discordMessageReactionAdd(session *discordgo.Session, reaction *discordgo.M
 	entry := createStarboardEntry(stars, message, channel)
 
 	//Check to see if the entry already exists, and if so, update it instead of creating a new one
-	for _, starboardEntry := range starboards[channel.GuildID].StarboardEntries {
+	for i, starboardEntry := range starboards[channel.GuildID].StarboardEntries {
 		if starboardEntry.SourceMessageID == message.ID {
 			if channel.NSFW {
 				session.ChannelMessageEditEmbed(starboards[channel.GuildID].NSFWChannelID, starboardEntry.StarboardMessageID, entry)
 			} else {
 				session.ChannelMessageEditEmbed(starboards[channel.GuildID].ChannelID, starboardEntry.StarboardMessageID, entry)
 			}
+			starboards[channel.GuildID].StarboardEntries[i].Stars = stars
+			publishEvent(channel.GuildID, EventStarboardUpdated, starboards[channel.GuildID].StarboardEntries[i])
 			return
 		}
 	}
@@ -275,12 +320,14 @@ func discordMessageReactionAdd(session *discordgo.Session, reaction *discordgo.M
 	if channel.NSFW {
 		starboardMessage, err := session.ChannelMessageSendEmbed(starboards[channel.GuildID].NSFWChannelID, entry)
 		if err != nil {
+			ErrorStarboard.With("guild", channel.GuildID, "channel", starboards[channel.GuildID].NSFWChannelID, "message", message.ID).Printf("Error posting starboard entry: %v", err)
 			return
 		}
 
 		starboards[channel.GuildID].StarboardEntries = append(starboards[channel.GuildID].StarboardEntries, StarboardEntry{
 			SourceChannelID:    channel.ID,
 			SourceMessageID:    message.ID,
+			SourceAuthorID:     message.Author.ID,
 			StarboardChannelID: starboards[channel.GuildID].ChannelID,
 			StarboardMessageID: starboardMessage.ID,
 			Stars:              stars,
@@ -288,24 +335,34 @@ func discordMessageReactionAdd(session *discordgo.Session, reaction *discordgo.M
 	} else {
 		starboardMessage, err := session.ChannelMessageSendEmbed(starboards[channel.GuildID].ChannelID, entry)
 		if err != nil {
+			ErrorStarboard.With("guild", channel.GuildID, "channel", starboards[channel.GuildID].ChannelID, "message", message.ID).Printf("Error posting starboard entry: %v", err)
 			return
 		}
 
 		starboards[channel.GuildID].StarboardEntries = append(starboards[channel.GuildID].StarboardEntries, StarboardEntry{
 			SourceChannelID:    channel.ID,
 			SourceMessageID:    message.ID,
+			SourceAuthorID:     message.Author.ID,
 			StarboardChannelID: starboards[channel.GuildID].ChannelID,
 			StarboardMessageID: starboardMessage.ID,
 			Stars:              stars,
 		})
 	}
+	publishEvent(channel.GuildID, EventStarboardCreated, starboards[channel.GuildID].StarboardEntries[len(starboards[channel.GuildID].StarboardEntries)-1])
 }
 func discordMessageReactionRemove(session *discordgo.Session, reaction *discordgo.MessageReactionRemove) {
+	defer recoverHandler("messageReactionRemove", reaction.GuildID)
+
 	channel, err := session.Channel(reaction.ChannelID)
 	if err != nil {
 		return
 	}
 
+	//Starboards are guarded by their guild's lock, the same as when they're changed by commands
+	initializeGuildData(channel.GuildID)
+	guildData[channel.GuildID].Lock()
+	defer guildData[channel.GuildID].Unlock()
+
 	if _, exists := starboards[channel.GuildID]; !exists {
 		return
 	}
@@ -354,6 +411,7 @@ func discordMessageReactionRemove(session *discordgo.Session, reaction *discordg
 				}
 
 				starboards[channel.GuildID].StarboardEntries = append(starboards[channel.GuildID].StarboardEntries[:i], starboards[channel.GuildID].StarboardEntries[i+1:]...)
+				publishEvent(channel.GuildID, EventStarboardRemoved, starboardEntry)
 
 				return
 			}
@@ -374,6 +432,7 @@ func discordMessageReactionRemove(session *discordgo.Session, reaction *discordg
 				session.ChannelMessageEditEmbed(starboards[channel.GuildID].ChannelID, starboardEntry.StarboardMessageID, entry)
 				starboards[channel.GuildID].StarboardEntries[i].Stars = stars
 			}
+			publishEvent(channel.GuildID, EventStarboardUpdated, starboards[channel.GuildID].StarboardEntries[i])
 			return
 		}
 	}
@@ -382,35 +441,47 @@ func discordMessageReactionRemove(session *discordgo.Session, reaction *discordg
 	if channel.NSFW {
 		starboardMessage, err := session.ChannelMessageSendEmbed(starboards[channel.GuildID].NSFWChannelID, entry)
 		if err != nil {
+			ErrorStarboard.With("guild", channel.GuildID, "channel", starboards[channel.GuildID].NSFWChannelID, "message", message.ID).Printf("Error posting starboard entry: %v", err)
 			return
 		}
 
 		starboards[channel.GuildID].StarboardEntries = append(starboards[channel.GuildID].StarboardEntries, StarboardEntry{
 			SourceChannelID:    channel.ID,
 			SourceMessageID:    message.ID,
+			SourceAuthorID:     message.Author.ID,
 			StarboardChannelID: starboards[channel.GuildID].ChannelID,
 			StarboardMessageID: starboardMessage.ID,
 		})
 	} else {
 		starboardMessage, err := session.ChannelMessageSendEmbed(starboards[channel.GuildID].ChannelID, entry)
 		if err != nil {
+			ErrorStarboard.With("guild", channel.GuildID, "channel", starboards[channel.GuildID].ChannelID, "message", message.ID).Printf("Error posting starboard entry: %v", err)
 			return
 		}
 
 		starboards[channel.GuildID].StarboardEntries = append(starboards[channel.GuildID].StarboardEntries, StarboardEntry{
 			SourceChannelID:    channel.ID,
 			SourceMessageID:    message.ID,
+			SourceAuthorID:     message.Author.ID,
 			StarboardChannelID: starboards[channel.GuildID].ChannelID,
 			StarboardMessageID: starboardMessage.ID,
 		})
 	}
+	publishEvent(channel.GuildID, EventStarboardCreated, starboards[channel.GuildID].StarboardEntries[len(starboards[channel.GuildID].StarboardEntries)-1])
 }
 func discordMessageReactionRemoveAll(session *discordgo.Session, reaction *discordgo.MessageReactionRemoveAll) {
+	defer recoverHandler("messageReactionRemoveAll", reaction.GuildID)
+
 	channel, err := session.Channel(reaction.ChannelID)
 	if err != nil {
 		return
 	}
 
+	//Starboards are guarded by their guild's lock, the same as when they're changed by commands
+	initializeGuildData(channel.GuildID)
+	guildData[channel.GuildID].Lock()
+	defer guildData[channel.GuildID].Unlock()
+
 	if _, exists := starboards[channel.GuildID]; !exists {
 		return
 	}
@@ -443,6 +514,7 @@ func discordMessageReactionRemoveAll(session *discordgo.Session, reaction *disco
 				session.ChannelMessageDelete(starboards[channel.GuildID].ChannelID, starboardEntry.StarboardMessageID)
 			}
 			starboards[channel.GuildID].StarboardEntries = append(starboards[channel.GuildID].StarboardEntries[:i], starboards[channel.GuildID].StarboardEntries[i+1:]...)
+			publishEvent(channel.GuildID, EventStarboardRemoved, starboardEntry)
 			return
 		}
 	}
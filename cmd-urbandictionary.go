@@ -15,7 +15,7 @@ import (
 func commandUrbanDictionary(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	results, err := urbandictionary.Query(strings.Join(args, " "))
 	if err != nil {
-		return NewErrorEmbed("Urban Dictionary Error", "There was an error getting a result for that term.")
+		return env.errorEmbed(wrapError(errCodeUrbanDictionaryFailed, err))
 	}
 
 	linkExp := regexp.MustCompile(`\[([^\]]*)\]`)
@@ -18,26 +18,26 @@ func commandVoiceJoin(args []string, env *CommandEnvironment) *discordgo.Message
 			return NewGenericEmbed("Voice", "Joined the voice channel.")
 		}
 	}
-	return NewErrorEmbed("Voice Error", "You must join the voice channel to use before using the join command.")
+	return env.errorEmbed(newError(errCodeVoiceUserNotInChannel, env.Command))
 }
 
 func commandVoiceLeave(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	VoiceInit(env.Guild.ID)
 
 	if voiceData[env.Guild.ID].VoiceConnection == nil {
-		return NewErrorEmbed("Voice Error", botData.BotName+"is not currently in a voice channel.")
+		return env.errorEmbed(newError(errCodeVoiceBotNotInChannel, botData().BotName))
 	}
 
 	for _, voiceState := range env.Guild.VoiceStates {
 		if voiceState.UserID == env.Message.Author.ID && voiceState.ChannelID == voiceData[env.Guild.ID].VoiceConnection.ChannelID {
 			voiceData[env.Guild.ID].Stop()
 			if err := voiceData[env.Guild.ID].Disconnect(); err != nil {
-				return NewErrorEmbed("Voice Error", "There was an error leaving the voice channel.")
+				return env.errorEmbed(errVoiceLeaveChannel.Wrap(err))
 			}
 			return NewGenericEmbed("Voice", "Left the voice channel.")
 		}
 	}
-	return NewErrorEmbed("Voice Error", "You must join the voice channel "+botData.BotName+" is in before using the leave command.")
+	return env.errorEmbed(newError(errCodeVoiceUserWrongChannel, botData().BotName, env.Command))
 }
 
 func commandPlay(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
@@ -51,7 +51,7 @@ func commandPlay(args []string, env *CommandEnvironment) *discordgo.MessageEmbed
 	for _, voiceState := range env.Guild.VoiceStates {
 		if voiceState.UserID == env.Message.Author.ID {
 			if voiceData[env.Guild.ID].IsConnected() && voiceState.ChannelID != voiceData[env.Guild.ID].VoiceConnection.ChannelID {
-				return NewErrorEmbed("Voice Error", "You must join the voice channel "+botData.BotName+" is in before using the play command.")
+				return env.errorEmbed(newError(errCodeVoiceUserWrongChannel, botData().BotName, env.Command))
 			}
 			foundVoiceChannel = true
 			voiceData[env.Guild.ID].Connect(env.Guild.ID, voiceState.ChannelID)
@@ -59,7 +59,7 @@ func commandPlay(args []string, env *CommandEnvironment) *discordgo.MessageEmbed
 		}
 	}
 	if !foundVoiceChannel {
-		return NewErrorEmbed("Voice Error", "You must join the voice channel to use before using the play command.")
+		return env.errorEmbed(newError(errCodeVoiceUserNotInChannel, env.Command))
 	}
 
 	voiceData[env.Guild.ID].SetTextChannel(env.Channel.ID)
@@ -71,7 +71,7 @@ func commandPlay(args []string, env *CommandEnvironment) *discordgo.MessageEmbed
 		if err != nil {
 			queryURL, err := YouTubeGetQuery(strings.Join(args, " "))
 			if err != nil {
-				return NewErrorEmbed("Voice Error", "There was an error getting a result for the specified query.")
+				return env.errorEmbed(wrapError(errCodeVoiceQueryFailed, err))
 			}
 			mediaURL = queryURL
 		} else {
@@ -79,7 +79,7 @@ func commandPlay(args []string, env *CommandEnvironment) *discordgo.MessageEmbed
 		}
 	} else {
 		if len(env.Message.Attachments) > 0 {
-			botData.DiscordSession.ChannelMessageSendEmbed(env.Channel.ID, NewEmbed().
+			botData().DiscordSession.ChannelMessageSendEmbed(env.Channel.ID, NewEmbed().
 				SetTitle("Voice").
 				SetDescription("Please wait a moment as we add all "+strconv.Itoa(len(env.Message.Attachments))+" attachments to the queue...\n\nThe first result added will automatically begin playing. During this process, it may feel as if other commands are slow or don't work; give them some time to process.").
 				SetColor(0x1DB954).MessageEmbed)
@@ -91,7 +91,7 @@ func commandPlay(args []string, env *CommandEnvironment) *discordgo.MessageEmbed
 
 				queueEntry, err := createQueueEntry(attachment.URL)
 				if err != nil {
-					botData.DiscordSession.ChannelMessageSendEmbed(env.Channel.ID, NewErrorEmbed("Voice Error", "Error finding audio info for attachment "+strconv.Itoa(i+1)+"."))
+					botData().DiscordSession.ChannelMessageSendEmbed(env.Channel.ID, env.errorEmbed(wrapError(errCodeVoiceAttachmentFailed, err, i+1)))
 					continue
 				}
 				queueEntry.Requester = env.Member.User
@@ -103,7 +103,7 @@ func commandPlay(args []string, env *CommandEnvironment) *discordgo.MessageEmbed
 
 		if voiceData[env.Guild.ID].NowPlaying != nil {
 			if voiceData[env.Guild.ID].IsStreaming() {
-				return NewErrorEmbed("Voice Error", "There is already audio playing.")
+				return env.errorEmbed(errVoicePlayAlreadyStreaming)
 			}
 			queueEntry := voiceData[env.Guild.ID].NowPlaying.Entry
 			go voiceData[env.Guild.ID].Play(queueEntry, true)
@@ -111,7 +111,7 @@ func commandPlay(args []string, env *CommandEnvironment) *discordgo.MessageEmbed
 		}
 		if len(voiceData[env.Guild.ID].Entries) > 0 {
 			if voiceData[env.Guild.ID].IsStreaming() {
-				return NewErrorEmbed("Voice Error", "There is already audio playing.")
+				return env.errorEmbed(errVoicePlayAlreadyStreaming)
 			}
 			queueEntry := voiceData[env.Guild.ID].Entries[0]
 			voiceData[env.Guild.ID].QueueRemove(0)
@@ -122,103 +122,100 @@ func commandPlay(args []string, env *CommandEnvironment) *discordgo.MessageEmbed
 	if mediaURL != "" {
 		queueEntry, err := createQueueEntry(mediaURL)
 		if err != nil {
-			return NewErrorEmbed("Voice Error", "There was an error finding a service to handle the specified URL.")
+			return env.errorEmbed(wrapError(errCodeVoiceNoService, err))
+		}
+		if !serviceEnabled(env.Guild.ID, queueEntry.ServiceName) {
+			return env.errorEmbed(newError(errCodeVoiceServiceDisabled, queueEntry.ServiceName))
 		}
 		if env.Member == nil {
-			return NewErrorEmbed("Voice Error", "There was an error figuring out who requested the track.")
+			return env.errorEmbed(newError(errCodeVoiceNoRequester))
 		}
 		queueEntry.Requester = env.Member.User
 		go voiceData[env.Guild.ID].Play(queueEntry, true)
 		return nil
 	}
 
-	return NewErrorEmbed("Voice Error", "Could not find any audio to play.")
+	return env.errorEmbed(newError(errCodeVoiceNothingToPlay))
 }
 
 func commandStop(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	VoiceInit(env.Guild.ID)
 
 	if !voiceData[env.Guild.ID].IsConnected() {
-		return NewErrorEmbed("Voice Error", botData.BotName+" is not currently in a voice channel.")
+		return env.errorEmbed(newError(errCodeVoiceBotNotInChannel, botData().BotName))
 	}
 
 	for _, voiceState := range env.Guild.VoiceStates {
 		if voiceState.UserID == env.Message.Author.ID && voiceState.ChannelID == voiceData[env.Guild.ID].VoiceConnection.ChannelID {
 			if voiceData[env.Guild.ID].IsStreaming() {
 				if err := voiceData[env.Guild.ID].Stop(); err != nil {
-					return NewErrorEmbed("Voice Error", "There was an error stopping the audio playback.")
+					return env.errorEmbed(wrapError(errCodeVoiceStopFailed, err))
 				}
 				return NewGenericEmbed("Voice", "Stopped the audio playback.")
 			}
-			return NewErrorEmbed("Voice Error", "There is no audio currently playing.")
+			return env.errorEmbed(errVoiceNotStreaming)
 		}
 	}
-	return NewErrorEmbed("Voice Error", "You must join the voice channel "+botData.BotName+" is in before using the "+env.Command+" command.")
+	return env.errorEmbed(newError(errCodeVoiceUserWrongChannel, botData().BotName, env.Command))
 }
 
 func commandSkip(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	VoiceInit(env.Guild.ID)
 
 	if !voiceData[env.Guild.ID].IsConnected() {
-		return NewErrorEmbed("Voice Error", botData.BotName+" is not currently in a voice channel.")
+		return env.errorEmbed(newError(errCodeVoiceBotNotInChannel, botData().BotName))
 	}
 
 	for _, voiceState := range env.Guild.VoiceStates {
 		if voiceState.UserID == env.Message.Author.ID && voiceState.ChannelID == voiceData[env.Guild.ID].VoiceConnection.ChannelID {
 			if voiceData[env.Guild.ID].IsStreaming() {
 				if err := voiceData[env.Guild.ID].Skip(); err != nil {
-					return NewErrorEmbed("Voice Error", "There was an error skipping the audio playback.")
+					return env.errorEmbed(wrapError(errCodeVoiceSkipFailed, err))
 				}
 				return nil
 			}
-			return NewErrorEmbed("Voice Error", "There is no audio currently playing.")
+			return env.errorEmbed(errVoiceNotStreaming)
 		}
 	}
-	return NewErrorEmbed("Voice Error", "You must join the voice channel "+botData.BotName+" is in before using the "+env.Command+" command.")
+	return env.errorEmbed(newError(errCodeVoiceUserWrongChannel, botData().BotName, env.Command))
 }
 
 func commandPause(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	VoiceInit(env.Guild.ID)
 
 	if !voiceData[env.Guild.ID].IsConnected() {
-		return NewErrorEmbed("Voice Error", botData.BotName+" is not currently in a voice channel.")
+		return env.errorEmbed(newError(errCodeVoiceBotNotInChannel, botData().BotName))
 	}
 
 	for _, voiceState := range env.Guild.VoiceStates {
 		if voiceState.UserID == env.Message.Author.ID && voiceState.ChannelID == voiceData[env.Guild.ID].VoiceConnection.ChannelID {
-			isPaused, err := voiceData[env.Guild.ID].Pause()
+			_, err := voiceData[env.Guild.ID].Pause()
 			if err != nil {
-				if isPaused {
-					return NewErrorEmbed("Voice Error", "Already paused the audio.")
-				}
-				return NewErrorEmbed("Voice Error", "There is no audio currently playing.")
+				return env.errorEmbed(err)
 			}
 			return NewGenericEmbed("Voice", "Paused the audio playback.")
 		}
 	}
-	return NewErrorEmbed("Voice Error", "You must join the voice channel "+botData.BotName+" is in before using the "+env.Command+" command.")
+	return env.errorEmbed(newError(errCodeVoiceUserWrongChannel, botData().BotName, env.Command))
 }
 
 func commandResume(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	VoiceInit(env.Guild.ID)
 
 	if !voiceData[env.Guild.ID].IsConnected() {
-		return NewErrorEmbed("Voice Error", botData.BotName+" is not currently in a voice channel.")
+		return env.errorEmbed(newError(errCodeVoiceBotNotInChannel, botData().BotName))
 	}
 
 	for _, voiceState := range env.Guild.VoiceStates {
 		if voiceState.UserID == env.Message.Author.ID && voiceState.ChannelID == voiceData[env.Guild.ID].VoiceConnection.ChannelID {
-			isPaused, err := voiceData[env.Guild.ID].Resume()
+			_, err := voiceData[env.Guild.ID].Resume()
 			if err != nil {
-				if isPaused {
-					return NewErrorEmbed("Voice Error", "Already playing audio.")
-				}
-				return NewErrorEmbed("Voice Error", "There is no audio currently playing.")
+				return env.errorEmbed(err)
 			}
 			return NewGenericEmbed("Voice", "Resumed the audio playback.")
 		}
 	}
-	return NewErrorEmbed("Voice Error", "You must join the voice channel "+botData.BotName+" to use before using the "+env.Command+" command.")
+	return env.errorEmbed(newError(errCodeVoiceUserWrongChannel, botData().BotName, env.Command))
 }
 
 func commandVolume(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
@@ -226,18 +223,18 @@ func commandVolume(args []string, env *CommandEnvironment) *discordgo.MessageEmb
 	/*
 		volume, err := strconv.Atoi(args[0])
 		if err != nil {
-			return NewErrorEmbed("Volume Error", "``"+args[0]+"`` is not a valid number.")
+			return env.errorEmbed(wrapError(errCodeCommandInvalidNumber, err, args[0]))
 		}
 
 		if volume < 0 || volume > 100 {
-			return NewErrorEmbed("Volume Error", "You must specify a volume level from 0 to 100, with 100 being normal volume.")
+			return env.errorEmbed(newError(errCodeVoiceVolumeRange))
 		}
 
 		if voiceData[env.Guild.ID].EncodingOptions == nil {
 			voiceData[env.Guild.ID].EncodingOptions = encodeOptionsPresetHigh
 		}
 		voiceData[env.Guild.ID].EncodingOptions.Volume = float64(volume) * 0.01
-		return NewErrorEmbed("Volume", "Set the volume for audio playback to "+args[0]+".")
+		return NewGenericEmbed("Volume", "Set the volume for audio playback to "+args[0]+".")
 	*/
 
 	return NewGenericEmbed("Volume", "Volume adjustment in real time via this command is disabled at this time. While attempts proved to successfully change the volume, it was accompanied by static noise distortion and thus is not ready for production.\n"+
@@ -289,6 +286,7 @@ func commandShuffle(args []string, env *CommandEnvironment) *discordgo.MessageEm
 
 func commandYouTube(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	VoiceInit(env.Guild.ID)
+	defer guildData[env.Guild.ID].TouchYouTubeResults(env.Message.Author.ID)
 
 	page := &VoiceServiceYouTubeResultNav{}
 
@@ -296,52 +294,52 @@ func commandYouTube(args []string, env *CommandEnvironment) *discordgo.MessageEm
 	case "search", "s":
 		query := strings.Join(args[1:], " ")
 		if query == "" {
-			return NewErrorEmbed("YouTube Error", "You must enter a search query to use before using the "+args[0]+" command.")
+			return env.errorEmbed(newError(errCodeSearchQueryMissing, args[0]))
 		}
 
 		if guildData[env.Guild.ID].YouTubeResults == nil {
 			guildData[env.Guild.ID].YouTubeResults = make(map[string]*VoiceServiceYouTubeResultNav)
 		}
 
-		guildData[env.Guild.ID].YouTubeResults[env.Message.Author.ID] = &VoiceServiceYouTubeResultNav{}
+		guildData[env.Guild.ID].YouTubeResults[env.Message.Author.ID] = &VoiceServiceYouTubeResultNav{MaxResults: int64(guildBotOptions(env.Guild.ID).YouTubeMaxResults)}
 
 		page = guildData[env.Guild.ID].YouTubeResults[env.Message.Author.ID]
 		err := page.Search(query)
 		if err != nil {
-			return NewErrorEmbed("YouTube Error", "There was an error getting a result for the specified query.")
+			return env.errorEmbed(wrapError(errCodeVoiceQueryFailed, err))
 		}
 	case "next", "n", "forward", "+":
 		if guildData[env.Guild.ID].YouTubeResults == nil {
-			return NewErrorEmbed("YouTube Error", "No search session is in progress.")
+			return env.errorEmbed(newError(errCodeSearchNoSession))
 		}
 
 		page = guildData[env.Guild.ID].YouTubeResults[env.Message.Author.ID]
 		err := page.Next()
 		if err != nil {
-			return NewErrorEmbed("YouTube Error", "There was an error finding the next page.")
+			return env.errorEmbed(wrapError(errCodeSearchNextFailed, err))
 		}
 	case "prev", "previous", "p", "back", "-":
 		if guildData[env.Guild.ID].YouTubeResults == nil {
-			return NewErrorEmbed("YouTube Error", "No search session is in progress.")
+			return env.errorEmbed(newError(errCodeSearchNoSession))
 		}
 
 		page = guildData[env.Guild.ID].YouTubeResults[env.Message.Author.ID]
 		err := page.Prev()
 		if err != nil {
-			return NewErrorEmbed("YouTube Error", "There was an error finding the previous page.")
+			return env.errorEmbed(wrapError(errCodeSearchPrevFailed, err))
 		}
 	case "cancel", "c":
 		if guildData[env.Guild.ID].YouTubeResults[env.Message.Author.ID] != nil {
 			guildData[env.Guild.ID].YouTubeResults[env.Message.Author.ID] = nil
 			return NewGenericEmbedAdvanced("YouTube", "Cancelled the search session.", 0xFF0000)
 		}
-		return NewErrorEmbed("YouTube Error", "No search session is in progress.")
+		return env.errorEmbed(newError(errCodeSearchNoSession))
 	case "select", "choose", "play":
 		if guildData[env.Guild.ID].YouTubeResults == nil {
-			return NewErrorEmbed("YouTube Error", "No search session is in progress.")
+			return env.errorEmbed(newError(errCodeSearchNoSession))
 		}
 		if len(args) < 2 {
-			return NewErrorEmbed("YouTube Error", "You must specify which search result to select.")
+			return env.errorEmbed(newError(errCodeSearchSelectionMissing))
 		}
 
 		page = guildData[env.Guild.ID].YouTubeResults[env.Message.Author.ID]
@@ -349,10 +347,10 @@ func commandYouTube(args []string, env *CommandEnvironment) *discordgo.MessageEm
 
 		selection, err := strconv.Atoi(args[1])
 		if err != nil {
-			return NewErrorEmbed("YouTube Error", "``"+args[1]+"`` is not a valid number.")
+			return env.errorEmbed(wrapError(errCodeCommandInvalidNumber, err, args[1]))
 		}
 		if selection > len(results) || selection <= 0 {
-			return NewErrorEmbed("YouTube Error", "An invalid selection was specified.")
+			return env.errorEmbed(newError(errCodeSearchSelectionInvalid))
 		}
 
 		foundVoiceChannel := false
@@ -364,7 +362,7 @@ func commandYouTube(args []string, env *CommandEnvironment) *discordgo.MessageEm
 			}
 		}
 		if !foundVoiceChannel {
-			return NewErrorEmbed("YouTube Error", "You must join the voice channel to use before using the "+args[0]+" command.")
+			return env.errorEmbed(newError(errCodeVoiceUserNotInChannel, args[0]))
 		}
 
 		//Update channel ID to send voice messages to
@@ -375,13 +373,13 @@ func commandYouTube(args []string, env *CommandEnvironment) *discordgo.MessageEm
 
 		queueEntry, err := createQueueEntry(resultURL)
 		if err != nil {
-			return NewErrorEmbed("YouTube Error", "There was an error getting info for the result.")
+			return env.errorEmbed(wrapError(errCodeVoiceResultFailed, err))
 		}
 		queueEntry.Requester = env.Member.User
 		go voiceData[env.Guild.ID].Play(queueEntry, true)
 		return nil
 	default:
-		return NewErrorEmbed("YouTube Error", "Unknown command ``"+args[0]+"``.")
+		return env.errorEmbed(newError(errCodeCommandUnknownAction, args[0]))
 	}
 
 	commandList := env.BotPrefix + env.Command + " play N - Plays result N"
@@ -396,7 +394,7 @@ func commandYouTube(args []string, env *CommandEnvironment) *discordgo.MessageEm
 
 	results, err := page.GetResults()
 	if err != nil {
-		return NewErrorEmbed("YouTube Error", "No search results were found.")
+		return env.errorEmbed(wrapError(errCodeSearchNoResults, err))
 	}
 	responseEmbed := NewEmbed().
 		SetTitle("YouTube Search Results - Page " + strconv.Itoa(page.PageNumber)).
@@ -405,7 +403,7 @@ func commandYouTube(args []string, env *CommandEnvironment) *discordgo.MessageEm
 
 	fields := []*discordgo.MessageEmbedField{}
 	for i := 0; i < len(results); i++ {
-		videoInfo, err := botData.BotClients.YTDL.GetVideo("https://youtube.com/watch?v="+results[i].Id.VideoId)
+		videoInfo, err := botData().BotClients.YTDL.GetVideo("https://youtube.com/watch?v="+results[i].Id.VideoId)
 		if err != nil {
 			fields = append(fields, &discordgo.MessageEmbedField{Name: "Result #" + strconv.Itoa(i+1), Value: "Error fetching info for [this video](https://youtube.com/watch?v=" + results[i].Id.VideoId + ")"})
 		} else {
@@ -423,6 +421,7 @@ func commandYouTube(args []string, env *CommandEnvironment) *discordgo.MessageEm
 
 func commandSpotify(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	VoiceInit(env.Guild.ID)
+	defer guildData[env.Guild.ID].TouchSpotifyResults(env.Message.Author.ID)
 
 	page := &VoiceServiceSpotifyResultNav{}
 
@@ -430,83 +429,83 @@ func commandSpotify(args []string, env *CommandEnvironment) *discordgo.MessageEm
 	case "search", "s":
 		query := strings.Join(args[1:], " ")
 		if query == "" {
-			return NewErrorEmbed("Spotify Error", "You must enter a search query to use before using the "+args[0]+" command.")
+			return env.errorEmbed(newError(errCodeSearchQueryMissing, args[0]))
 		}
 
 		if guildData[env.Guild.ID].SpotifyResults == nil {
 			guildData[env.Guild.ID].SpotifyResults = make(map[string]*VoiceServiceSpotifyResultNav)
 		}
 
-		guildData[env.Guild.ID].SpotifyResults[env.Message.Author.ID] = &VoiceServiceSpotifyResultNav{}
+		guildData[env.Guild.ID].SpotifyResults[env.Message.Author.ID] = &VoiceServiceSpotifyResultNav{MaxResults: guildBotOptions(env.Guild.ID).SpotifyMaxResults}
 
 		page = guildData[env.Guild.ID].SpotifyResults[env.Message.Author.ID]
 		err := page.Search(query)
 		if err != nil {
-			return NewErrorEmbed("Spotify Error", "There was an error getting a result for the specified query.")
+			return env.errorEmbed(wrapError(errCodeVoiceQueryFailed, err))
 		}
 	case "playlist", "list":
 		playlistURL := strings.Join(args[1:], " ")
 		if playlistURL == "" {
-			return NewErrorEmbed("Spotify Error", "You must enter a playlist URL to use before using the "+args[0]+" command.")
+			return env.errorEmbed(newError(errCodeSpotifyPlaylistMissing, args[0]))
 		}
 
 		if guildData[env.Guild.ID].SpotifyResults == nil {
 			guildData[env.Guild.ID].SpotifyResults = make(map[string]*VoiceServiceSpotifyResultNav)
 		}
 
-		guildData[env.Guild.ID].SpotifyResults[env.Message.Author.ID] = &VoiceServiceSpotifyResultNav{}
+		guildData[env.Guild.ID].SpotifyResults[env.Message.Author.ID] = &VoiceServiceSpotifyResultNav{MaxResults: guildBotOptions(env.Guild.ID).SpotifyMaxResults}
 		guildData[env.Guild.ID].SpotifyResults[env.Message.Author.ID].GuildID = env.Guild.ID
 
 		waitEmbed := NewEmbed().
 			SetTitle("Spotify").
 			SetDescription("Please wait a while as we fetch the tracks from the specified playlist...\n\nDuring this process, it may feel as if other commands are slow or don't work; give them some time to process.\nYou may cancel at any moment with ``" + env.BotPrefix + env.Command + " cancel``. Once cancelled, the tracks gathered so far will still be displayed.").
 			SetColor(0x1DB954).MessageEmbed
-		botData.DiscordSession.ChannelMessageSendEmbed(env.Channel.ID, waitEmbed)
+		botData().DiscordSession.ChannelMessageSendEmbed(env.Channel.ID, waitEmbed)
 
 		page = guildData[env.Guild.ID].SpotifyResults[env.Message.Author.ID]
 		err := page.Playlist(playlistURL)
 		if err != nil {
-			return NewErrorEmbed("Spotify Error", "There was an error getting a result for the specified playlist.")
+			return env.errorEmbed(wrapError(errCodeSpotifyPlaylistFailed, err))
 		}
 	case "next", "n", "forward", "+":
 		if guildData[env.Guild.ID].SpotifyResults == nil {
-			return NewErrorEmbed("Spotify Error", "No search session is in progress.")
+			return env.errorEmbed(newError(errCodeSearchNoSession))
 		}
 
 		page = guildData[env.Guild.ID].SpotifyResults[env.Message.Author.ID]
 		err := page.Next()
 		if err != nil {
-			return NewErrorEmbed("Spotify Error", "There was an error finding the next page.")
+			return env.errorEmbed(wrapError(errCodeSearchNextFailed, err))
 		}
 	case "prev", "previous", "p", "back", "-":
 		if guildData[env.Guild.ID].SpotifyResults == nil {
-			return NewErrorEmbed("Spotify Error", "No search session is in progress.")
+			return env.errorEmbed(newError(errCodeSearchNoSession))
 		}
 
 		page = guildData[env.Guild.ID].SpotifyResults[env.Message.Author.ID]
 		err := page.Prev()
 		if err != nil {
-			return NewErrorEmbed("Spotify Error", "There was an error finding the previous page.")
+			return env.errorEmbed(wrapError(errCodeSearchPrevFailed, err))
 		}
 	case "jump", "page":
 		if guildData[env.Guild.ID].SpotifyResults == nil {
-			return NewErrorEmbed("Spotify Error", "No search session is in progress.")
+			return env.errorEmbed(newError(errCodeSearchNoSession))
 		}
 
 		pageNumber, err := strconv.Atoi(args[1])
 		if err != nil {
-			return NewErrorEmbed("Spotify Error", "Invalid page number ``"+args[1]+"``.")
+			return env.errorEmbed(wrapError(errCodeCommandInvalidPage, err, args[1]))
 		}
 
 		page = guildData[env.Guild.ID].SpotifyResults[env.Message.Author.ID]
 		err = page.Jump(pageNumber)
 		if err != nil {
-			return NewErrorEmbed("Spotify Error", "There was an error finding page ``"+args[1]+"``.")
+			return env.errorEmbed(wrapError(errCodeSearchJumpFailed, err, args[1]))
 		}
 	case "cancel", "c":
 		page = guildData[env.Guild.ID].SpotifyResults[env.Message.Author.ID]
 		if page == nil {
-			return NewErrorEmbed("Spotify Error", "No Spotify session is in progress.")
+			return env.errorEmbed(newError(errCodeSearchNoSession))
 		}
 
 		if page.AddingAll {
@@ -518,10 +517,10 @@ func commandSpotify(args []string, env *CommandEnvironment) *discordgo.MessageEm
 		return NewGenericEmbedAdvanced("Spotify", "Cancelled the Spotify session.", 0x1DB954)
 	case "select", "choose", "play":
 		if guildData[env.Guild.ID].SpotifyResults == nil {
-			return NewErrorEmbed("Spotify Error", "No search session is in progress.")
+			return env.errorEmbed(newError(errCodeSearchNoSession))
 		}
 		if len(args) < 2 {
-			return NewErrorEmbed("Spotify Error", "You must specify which result to select.")
+			return env.errorEmbed(newError(errCodeSearchSelectionMissing))
 		}
 
 		page = guildData[env.Guild.ID].SpotifyResults[env.Message.Author.ID]
@@ -538,7 +537,7 @@ func commandSpotify(args []string, env *CommandEnvironment) *discordgo.MessageEm
 				}
 			}
 			if !foundVoiceChannel {
-				return NewErrorEmbed("Spotify Error", "You must join the voice channel to use before using the "+args[0]+" command.")
+				return env.errorEmbed(newError(errCodeVoiceUserNotInChannel, args[0]))
 			}
 
 			//Update channel ID to send voice messages to
@@ -548,7 +547,7 @@ func commandSpotify(args []string, env *CommandEnvironment) *discordgo.MessageEm
 				SetTitle("Spotify").
 				SetDescription("Please wait a while as we add all " + strconv.Itoa(page.TotalResults) + " results to the queue...\n\nThe first result added will automatically begin playing. During this process, it may feel as if other commands are slow or don't work; give them some time to process.\nYou may cancel at any moment with ``" + env.BotPrefix + env.Command + " cancel``. Cancelling will not remove any results added to the queue during this process.").
 				SetColor(0x1DB954).MessageEmbed
-			botData.DiscordSession.ChannelMessageSendEmbed(env.Channel.ID, waitEmbed)
+			botData().DiscordSession.ChannelMessageSendEmbed(env.Channel.ID, waitEmbed)
 
 			page.AddingAll = true
 
@@ -566,7 +565,7 @@ func commandSpotify(args []string, env *CommandEnvironment) *discordgo.MessageEm
 
 				queueEntry, err := createQueueEntry(resultURL)
 				if err != nil {
-					return NewErrorEmbed("Voice Error", "There was an error getting info for result "+strconv.Itoa(i)+".")
+					return env.errorEmbed(wrapError(errCodeVoiceResultNumberFailed, err, i))
 				}
 				queueEntry.Requester = env.Member.User
 
@@ -592,7 +591,7 @@ func commandSpotify(args []string, env *CommandEnvironment) *discordgo.MessageEm
 				}
 			}
 			if !foundVoiceChannel {
-				return NewErrorEmbed("Spotify Error", "You must join the voice channel to use before using the "+args[0]+" command.")
+				return env.errorEmbed(newError(errCodeVoiceUserNotInChannel, args[0]))
 			}
 
 			//Update channel ID to send voice messages to
@@ -602,7 +601,7 @@ func commandSpotify(args []string, env *CommandEnvironment) *discordgo.MessageEm
 				SetTitle("Spotify").
 				SetDescription("Please wait a moment as we add all " + strconv.Itoa(len(page.Results)) + " results to the queue...\n\nThe first result added will automatically begin playing. During this process, it may feel as if other commands are slow or don't work; give them some time to process.\nYou may cancel at any moment with ``" + env.BotPrefix + env.Command + " cancel``. Cancelling will not remove any results added to the queue during this process.").
 				SetColor(0x1DB954).MessageEmbed
-			botData.DiscordSession.ChannelMessageSendEmbed(env.Channel.ID, waitEmbed)
+			botData().DiscordSession.ChannelMessageSendEmbed(env.Channel.ID, waitEmbed)
 
 			page.AddingAll = true
 
@@ -624,7 +623,7 @@ func commandSpotify(args []string, env *CommandEnvironment) *discordgo.MessageEm
 
 				queueEntry, err := createQueueEntry(resultURL)
 				if err != nil {
-					return NewErrorEmbed("Voice Error", "There was an error getting info for result "+strconv.Itoa(i)+".")
+					return env.errorEmbed(wrapError(errCodeVoiceResultNumberFailed, err, i))
 				}
 				queueEntry.Requester = env.Member.User
 
@@ -643,10 +642,10 @@ func commandSpotify(args []string, env *CommandEnvironment) *discordgo.MessageEm
 		default:
 			selection, err := strconv.Atoi(args[1])
 			if err != nil {
-				return NewErrorEmbed("Spotify Error", "``"+args[1]+"`` is not a valid number.")
+				return env.errorEmbed(wrapError(errCodeCommandInvalidNumber, err, args[1]))
 			}
 			if selection > len(results) || selection <= 0 {
-				return NewErrorEmbed("Spotify Error", "An invalid selection was specified.")
+				return env.errorEmbed(newError(errCodeSearchSelectionInvalid))
 			}
 
 			foundVoiceChannel := false
@@ -658,7 +657,7 @@ func commandSpotify(args []string, env *CommandEnvironment) *discordgo.MessageEm
 				}
 			}
 			if !foundVoiceChannel {
-				return NewErrorEmbed("Spotify Error", "You must join the voice channel to use before using the "+args[0]+" command.")
+				return env.errorEmbed(newError(errCodeVoiceUserNotInChannel, args[0]))
 			}
 
 			//Update channel ID to send voice messages to
@@ -671,23 +670,23 @@ func commandSpotify(args []string, env *CommandEnvironment) *discordgo.MessageEm
 
 				queueEntry, err := createQueueEntry(resultURL)
 				if err != nil {
-					return NewErrorEmbed("Voice Error", "There was an error getting info for the result.")
+					return env.errorEmbed(wrapError(errCodeVoiceResultFailed, err))
 				}
 				queueEntry.Requester = env.Member.User
 
 				go voiceData[env.Guild.ID].Play(queueEntry, true)
 				return nil
 			case "artist":
-				artistInfo, err := botData.BotClients.Spotify.GetArtistInfo(result.URI)
+				artistInfo, err := botData().BotClients.Spotify.GetArtistInfo(result.URI)
 				if err != nil {
-					return NewErrorEmbed("Spotify Error", "Error fetching info for the specified result.")
+					return env.errorEmbed(wrapError(errCodeSpotifyResultFailed, err))
 				}
 
 				waitEmbed := NewEmbed().
 					SetTitle("Spotify").
 					SetDescription("Please wait a moment as we add the top " + strconv.Itoa(len(artistInfo.TopTracks)) + " tracks to the queue...\n\nThe first result added will automatically begin playing. During this process, it may feel as if other commands are slow or don't work; give them some time to process.\nYou may cancel at any moment with ``" + env.BotPrefix + env.Command + " cancel``. Cancelling will not remove any results added to the queue during this process.").
 					SetColor(0x1DB954).MessageEmbed
-				botData.DiscordSession.ChannelMessageSendEmbed(env.Channel.ID, waitEmbed)
+				botData().DiscordSession.ChannelMessageSendEmbed(env.Channel.ID, waitEmbed)
 
 				page.AddingAll = true
 
@@ -704,7 +703,7 @@ func commandSpotify(args []string, env *CommandEnvironment) *discordgo.MessageEm
 
 					queueEntry, err := createQueueEntry(resultURL)
 					if err != nil {
-						return NewErrorEmbed("Voice Error", "There was an error getting info for the result.")
+						return env.errorEmbed(wrapError(errCodeVoiceResultFailed, err))
 					}
 					queueEntry.Requester = env.Member.User
 
@@ -721,9 +720,9 @@ func commandSpotify(args []string, env *CommandEnvironment) *discordgo.MessageEm
 
 				return NewGenericEmbedAdvanced("Spotify", "Finished adding all "+strconv.Itoa(page.AddedSoFar)+" tracks to the queue.", 0x1DB954)
 			case "album":
-				albumInfo, err := botData.BotClients.Spotify.GetAlbumInfo(result.URI)
+				albumInfo, err := botData().BotClients.Spotify.GetAlbumInfo(result.URI)
 				if err != nil {
-					return NewErrorEmbed("Spotify Error", "Error fetching info for the specified result.")
+					return env.errorEmbed(wrapError(errCodeSpotifyResultFailed, err))
 				}
 
 				totalTracks := 0
@@ -735,7 +734,7 @@ func commandSpotify(args []string, env *CommandEnvironment) *discordgo.MessageEm
 					SetTitle("Spotify").
 					SetDescription("Please wait a moment as we add all " + strconv.Itoa(totalTracks) + " tracks to the queue...\n\nThe first result added will automatically begin playing. During this process, it may feel as if other commands are slow or don't work; give them some time to process.\nYou may cancel at any moment with ``" + env.BotPrefix + env.Command + " cancel``. Cancelling will not remove any results added to the queue during this process.").
 					SetColor(0x1DB954).MessageEmbed
-				botData.DiscordSession.ChannelMessageSendEmbed(env.Channel.ID, waitEmbed)
+				botData().DiscordSession.ChannelMessageSendEmbed(env.Channel.ID, waitEmbed)
 
 				page.AddingAll = true
 
@@ -753,7 +752,7 @@ func commandSpotify(args []string, env *CommandEnvironment) *discordgo.MessageEm
 
 						queueEntry, err := createQueueEntry(resultURL)
 						if err != nil {
-							return NewErrorEmbed("Voice Error", "There was an error getting info for the result.")
+							return env.errorEmbed(wrapError(errCodeVoiceResultFailed, err))
 						}
 						queueEntry.Requester = env.Member.User
 
@@ -776,12 +775,12 @@ func commandSpotify(args []string, env *CommandEnvironment) *discordgo.MessageEm
 			}
 		}
 	default:
-		return NewErrorEmbed("Spotify Error", "Unknown command ``"+args[0]+"``.")
+		return env.errorEmbed(newError(errCodeCommandUnknownAction, args[0]))
 	}
 
 	results, err := page.GetResults()
 	if err != nil {
-		return NewErrorEmbed("Spotify Error", "No search results were found.")
+		return env.errorEmbed(wrapError(errCodeSearchNoResults, err))
 	}
 
 	spotifyEmbed := NewEmbed().
@@ -816,7 +815,7 @@ func commandSpotify(args []string, env *CommandEnvironment) *discordgo.MessageEm
 	for i := 0; i < len(results); i++ {
 		switch results[i].GetType() {
 		case "artist":
-			artistInfo, err := botData.BotClients.Spotify.GetArtistInfo(results[i].URI)
+			artistInfo, err := botData().BotClients.Spotify.GetArtistInfo(results[i].URI)
 			if err != nil {
 				fields = append(fields, &discordgo.MessageEmbedField{Name: "Result #" + strconv.Itoa(i+1) + " - Artist", Value: "Error fetching info for [this artist](https://open.spotify.com/artist/" + results[i].ID + ")"})
 			} else {
@@ -825,7 +824,7 @@ func commandSpotify(args []string, env *CommandEnvironment) *discordgo.MessageEm
 				fields = append(fields, &discordgo.MessageEmbedField{Name: "Result #" + strconv.Itoa(i+1) + " - Artist", Value: "[" + artist + "](https://open.spotify.com/artist/" + results[i].ID + ")"})
 			}
 		case "track":
-			trackInfo, err := botData.BotClients.Spotify.GetTrackInfo(results[i].URI)
+			trackInfo, err := botData().BotClients.Spotify.GetTrackInfo(results[i].URI)
 			if err != nil {
 				fields = append(fields, &discordgo.MessageEmbedField{Name: "Result #" + strconv.Itoa(i+1) + " - Track", Value: "Error fetching info for [this track](https://open.spotify.com/track/" + results[i].ID + ")"})
 			} else {
@@ -852,7 +851,7 @@ func commandSpotify(args []string, env *CommandEnvironment) *discordgo.MessageEm
 				}
 			}
 		case "album":
-			albumInfo, err := botData.BotClients.Spotify.GetAlbumInfo(results[i].URI)
+			albumInfo, err := botData().BotClients.Spotify.GetAlbumInfo(results[i].URI)
 			if err != nil {
 				fields = append(fields, &discordgo.MessageEmbedField{Name: "Result #" + strconv.Itoa(i+1) + " - Album", Value: "Error fetching info for [this album](https://open.spotify.com/album/" + results[i].ID + ")"})
 			} else {
@@ -875,7 +874,7 @@ func commandSpotify(args []string, env *CommandEnvironment) *discordgo.MessageEm
 			}
 		case "user":
 			playlistURI := results[i].GetID()
-			playlistInfo, err := botData.BotClients.Spotify.GetPlaylist("spotify:user:" + url.QueryEscape(playlistURI[0]) + ":playlist:" + playlistURI[1])
+			playlistInfo, err := botData().BotClients.Spotify.GetPlaylist("spotify:user:" + url.QueryEscape(playlistURI[0]) + ":playlist:" + playlistURI[1])
 			if err != nil {
 				//fields = append(fields, &discordgo.MessageEmbedField{Name: "Result #" + strconv.Itoa(i+1) + " - Playlist", Value: "Error fetching info for [this playlist](https://open.spotify.com/user/" + playlistURI[0] + "/playlist/" + playlistURI[1] + ")"})
 				fields = append(fields, &discordgo.MessageEmbedField{Name: "Result #" + strconv.Itoa(i+1) + " - Playlist", Value: "Error fetching info for playlist debug: " + fmt.Sprintf("%v", err)})
@@ -905,21 +904,21 @@ func commandQueue(args []string, env *CommandEnvironment) *discordgo.MessageEmbe
 
 				return NewGenericEmbed("Queue", "Cleared all "+strconv.Itoa(queueLength)+" entries from the queue.")
 			}
-			return NewErrorEmbed("Queue Error", "There are no entries in the queue to clear.")
+			return env.errorEmbed(newError(errCodeQueueEmpty))
 		case "remove":
 			if len(args) == 1 {
-				return NewErrorEmbed("Queue Error", "You must specify which queue entries to remove.")
+				return env.errorEmbed(newError(errCodeQueueRemoveMissing))
 			}
 
 			for _, queueEntry := range args[1:] {
 				queueEntryNumber, err := strconv.Atoi(queueEntry)
 				if err != nil {
-					return NewErrorEmbed("Queue Error", "``"+queueEntry+"`` is not a valid number.")
+					return env.errorEmbed(wrapError(errCodeCommandInvalidNumber, err, queueEntry))
 				}
 				queueEntryNumber--
 
 				if queueEntryNumber >= len(voiceData[env.Guild.ID].Entries) || queueEntryNumber < 0 {
-					return NewErrorEmbed("Queue Error", "``"+queueEntry+"`` is not a valid queue entry.")
+					return env.errorEmbed(newError(errCodeQueueEntryInvalid, queueEntry))
 				}
 			}
 
@@ -940,6 +939,7 @@ func commandQueue(args []string, env *CommandEnvironment) *discordgo.MessageEmbe
 			}
 
 			voiceData[env.Guild.ID].Entries = newAudioQueue
+			voiceData[env.Guild.ID].queueChanged()
 
 			if len(args) > 2 {
 				return NewGenericEmbed("Queue", "Successfully removed the specified queue entries.")
@@ -947,20 +947,21 @@ func commandQueue(args []string, env *CommandEnvironment) *discordgo.MessageEmbe
 			return NewGenericEmbed("Queue", "Successfully removed the specified queue entry.")
 		case "copy":
 			if len(args) == 1 {
-				return NewErrorEmbed("Queue Error", "You must specify which guild queue(s) to copy.")
+				return env.errorEmbed(newError(errCodeQueueCopyMissing))
 			}
 
 			for _, guildID := range args[1:] {
 				if _, exists := guildData[guildID]; exists == false {
-					return NewErrorEmbed("Queue Error", "The guild ID ``"+guildID+"`` does not point to a known guild.")
+					return env.errorEmbed(newError(errCodeQueueGuildUnknown, guildID))
 				}
 			}
 
 			copiedGuilds := make([]string, 0)
 			for _, guildID := range args[1:] {
 				if voice, exists := voiceData[guildID]; exists { //Just in case it doesn't exist anymore when we reach this point, we all know how edge cases go
-					if voice.NowPlaying.Entry.Metadata.StreamURL != "" || len(voice.Entries) > 0 {
-						if voice.NowPlaying.Entry.Metadata.StreamURL != "" {
+					nowPlaying := voice.NowPlaying != nil && voice.NowPlaying.Entry != nil && voice.NowPlaying.Entry.Metadata != nil && voice.NowPlaying.Entry.Metadata.StreamURL != ""
+					if nowPlaying || len(voice.Entries) > 0 {
+						if nowPlaying {
 							voiceData[env.Guild.ID].Entries = append(voiceData[env.Guild.ID].Entries, voice.NowPlaying.Entry)
 						}
 						if len(voice.Entries) > 0 {
@@ -969,11 +970,17 @@ func commandQueue(args []string, env *CommandEnvironment) *discordgo.MessageEmbe
 							}
 						}
 
-						guildState, _ := botData.DiscordSession.State.Guild(guildID)
-						copiedGuilds = append(copiedGuilds, guildState.Name)
+						if guildState, err := botData().DiscordSession.State.Guild(guildID); err == nil {
+							copiedGuilds = append(copiedGuilds, guildState.Name)
+						} else {
+							copiedGuilds = append(copiedGuilds, guildID)
+						}
 					}
 				}
 			}
+			if len(copiedGuilds) > 0 {
+				voiceData[env.Guild.ID].queueChanged()
+			}
 
 			if len(copiedGuilds) == 1 {
 				return NewGenericEmbed("Queue", "Successfully copied the queue from "+copiedGuilds[0]+".")
@@ -986,7 +993,7 @@ func commandQueue(args []string, env *CommandEnvironment) *discordgo.MessageEmbe
 	if len(args) >= 1 {
 		num, err := strconv.Atoi(args[0])
 		if err != nil {
-			return NewErrorEmbed("Queue Error", "Invalid page number ``"+args[0]+"``.")
+			return env.errorEmbed(wrapError(errCodeCommandInvalidPage, err, args[0]))
 		}
 		pageNumber = num
 	}
@@ -1068,7 +1075,7 @@ func commandQueue(args []string, env *CommandEnvironment) *discordgo.MessageEmbe
 
 	pagedQueueList, totalPages, err := page(queueList, pageNumber, 10)
 	if err != nil {
-		return NewErrorEmbed("Queue Error", fmt.Sprintf("%v", err))
+		return env.errorEmbed(wrapError(errCodeCommandPageNotFound, err, pageNumber))
 	}
 
 	queueColor := 0x1C1C1C
@@ -1095,17 +1102,17 @@ func commandNowPlaying(args []string, env *CommandEnvironment) *discordgo.Messag
 	if voiceData[env.Guild.ID].IsStreaming() {
 		return voiceData[env.Guild.ID].GetNowPlayingDurationEmbed(voiceData[env.Guild.ID].NowPlaying.Entry)
 	}
-	return NewErrorEmbed("Now Playing Error", "There is no audio currently playing.")
+	return env.errorEmbed(errVoiceNotStreaming)
 }
 
 func commandLyrics(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	if !voiceData[env.Guild.ID].IsStreaming() {
-		return NewErrorEmbed("Lyrics Error", "There is no audio currently playing.")
+		return env.errorEmbed(errVoiceNotStreaming)
 	}
 
-	lyrics, err := botData.BotClients.Lyrics.Search(voiceData[env.Guild.ID].NowPlaying.Entry.Metadata.Title, voiceData[env.Guild.ID].NowPlaying.Entry.Metadata.Artists[0].Name)
+	lyrics, err := botData().BotClients.Lyrics.Search(voiceData[env.Guild.ID].NowPlaying.Entry.Metadata.Title, voiceData[env.Guild.ID].NowPlaying.Entry.Metadata.Artists[0].Name)
 	if err != nil {
-		return NewErrorEmbed("Lyrics Error", "There was an error fetching the lyrics for the current track.")
+		return env.errorEmbed(wrapError(errCodeVoiceLyricsFailed, err))
 	}
 
 	return NewEmbed().
@@ -10,9 +10,9 @@ import (
 func commandXKCD(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	switch args[0] {
 	case "latest":
-		latestComic, err := botData.BotClients.XKCD.Latest(context.Background())
+		latestComic, err := botData().BotClients.XKCD.Latest(context.Background())
 		if err != nil {
-			return NewErrorEmbed("xkcd Error", "There was an error fetching the latest xkcd comic.")
+			return env.errorEmbed(wrapError(errCodeXKCDLatestFailed, err))
 		}
 		return NewEmbed().
 			SetTitle("xkcd - #" + strconv.Itoa(latestComic.Number)).
@@ -20,14 +20,14 @@ func commandXKCD(args []string, env *CommandEnvironment) *discordgo.MessageEmbed
 			SetImage(latestComic.ImageURL).
 			SetColor(0x96A8C8).MessageEmbed
 	case "random":
-		latestComic, err := botData.BotClients.XKCD.Latest(context.Background())
+		latestComic, err := botData().BotClients.XKCD.Latest(context.Background())
 		if err != nil {
-			return NewErrorEmbed("xkcd Error", "There was an error figuring out the latest xkcd comic number.")
+			return env.errorEmbed(wrapError(errCodeXKCDLatestNumberFailed, err))
 		}
 
-		randomComic, err := botData.BotClients.XKCD.Get(context.Background(), randomInRange(1, latestComic.Number+1))
+		randomComic, err := botData().BotClients.XKCD.Get(context.Background(), randomInRange(1, latestComic.Number+1))
 		if err != nil {
-			return NewErrorEmbed("xkcd Error", "There was an error fetching a random xkcd comic.")
+			return env.errorEmbed(wrapError(errCodeXKCDRandomFailed, err))
 		}
 		return NewEmbed().
 			SetTitle("xkcd - #" + strconv.Itoa(randomComic.Number)).
@@ -37,12 +37,12 @@ func commandXKCD(args []string, env *CommandEnvironment) *discordgo.MessageEmbed
 	default:
 		comicNumber, err := strconv.Atoi(args[0])
 		if err != nil {
-			return NewErrorEmbed("xkcd Error", "``"+args[0]+"`` is not a valid number.")
+			return env.errorEmbed(wrapError(errCodeCommandInvalidNumber, err, args[0]))
 		}
 
-		comic, err := botData.BotClients.XKCD.Get(context.Background(), comicNumber)
+		comic, err := botData().BotClients.XKCD.Get(context.Background(), comicNumber)
 		if err != nil {
-			return NewErrorEmbed("xkcd Error", "There was an error fetching xkcd comic #"+args[0]+".")
+			return env.errorEmbed(wrapError(errCodeXKCDComicFailed, err, args[0]))
 		}
 		return NewEmbed().
 			SetTitle("xkcd - #" + args[0]).
@@ -2,6 +2,7 @@ package main
 
 import (
 	"strings"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 )
@@ -18,6 +19,8 @@ type Command struct {
 
 	IsAdministrative bool //Whether or not this command requires the user to be a bot admin
 
+	Feature string //The name of the feature this command belongs to, if it can be disabled per guild
+
 	IsAdvancedCommand bool                                                                 //Whether or not this command uses advanced parameters
 	AdvancedFunction  func([]CommandArgument, *CommandEnvironment) *discordgo.MessageEmbed //The function value of what to execute when the command is ran
 }
@@ -47,28 +50,74 @@ type CommandEnvironment struct {
 	UpdatedMessageEvent bool
 }
 
-func initCommands() {
+// logger returns the given logger with the guild, channel, user and command of the command environment
+func (env *CommandEnvironment) logger(logger *Logger) *Logger {
+	fields := []interface{}{"command", env.Command}
+	if env.Guild != nil {
+		fields = append(fields, "guild", env.Guild.ID)
+	}
+	if env.Channel != nil {
+		fields = append(fields, "channel", env.Channel.ID)
+	}
+	if env.User != nil {
+		fields = append(fields, "user", env.User.ID)
+	}
+	return logger.With(fields...)
+}
+
+// locale returns the Discord locale to respond to the command environment in
+func (env *CommandEnvironment) locale() string {
+	if env.Guild != nil && env.Guild.PreferredLocale != "" {
+		return env.Guild.PreferredLocale
+	}
+	return "en"
+}
+
+// guildLocale returns the Discord locale to respond in outside of a command, such as in a guild's text channel
+func guildLocale(guildID string) string {
+	if guild, err := botData().DiscordSession.State.Guild(guildID); err == nil && guild.PreferredLocale != "" {
+		return guild.PreferredLocale
+	}
+	return "en"
+}
+
+// channelLocale returns the Discord locale of the guild a channel belongs to
+func channelLocale(channelID string) string {
+	if channel, err := botData().DiscordSession.State.Channel(channelID); err == nil {
+		return guildLocale(channel.GuildID)
+	}
+	return "en"
+}
+
+// errorEmbed logs the given error at the level of its severity and returns an error embed for it in the locale of the command environment
+func (env *CommandEnvironment) errorEmbed(err error) *discordgo.MessageEmbed {
+	clinetErr := asClinetError(err)
+	env.logger(clinetErr.Severity().logger()).With("code", clinetErr.Code).Println(clinetErr.Error())
+	return getErrorEmbed(clinetErr, env.locale())
+}
+
+func (configData *BotData) initCommands() {
 	//Initialize the commands map
-	botData.Commands = make(map[string]*Command)
+	configData.Commands = make(map[string]*Command)
 
 	//All user-accessible commands with no parameters
-	botData.Commands["about"] = &Command{Function: commandAbout, HelpText: "Displays information about " + botData.BotName + " and how to use it."}
-	botData.Commands["invite"] = &Command{Function: commandInvite, HelpText: "Displays available invite links for " + botData.BotName + "."}
-	botData.Commands["donate"] = &Command{Function: commandDonate, HelpText: "Displays available donation links for " + botData.BotName + "."}
-	botData.Commands["source"] = &Command{Function: commandSource, HelpText: "Displays available source code links for " + botData.BotName + "."}
-	botData.Commands["version"] = &Command{Function: commandVersion, HelpText: "Displays the current version of " + botData.BotName + "."}
-	botData.Commands["credits"] = &Command{Function: commandCredits, HelpText: "Displays a list of credits for the creation and functionality of " + botData.BotName + "."}
-	botData.Commands["roll"] = &Command{Function: commandRoll, HelpText: "Rolls a dice."}
-	botData.Commands["doubleroll"] = &Command{Function: commandDoubleRoll, HelpText: "Rolls two die."}
-	botData.Commands["coinflip"] = &Command{Function: commandCoinFlip, HelpText: "Flips a coin."}
-	botData.Commands["join"] = &Command{Function: commandVoiceJoin, HelpText: "Joins the current voice channel.", RequiredPermissions: discordgo.PermissionVoiceConnect}
-	botData.Commands["leave"] = &Command{Function: commandVoiceLeave, HelpText: "Leaves the current voice channel.", RequiredPermissions: discordgo.PermissionVoiceConnect}
-	botData.Commands["ping"] = &Command{Function: commandPing, HelpText: "Returns the ping average to Discord."}
+	configData.Commands["about"] = &Command{Function: commandAbout, HelpText: "Displays information about " + configData.BotName + " and how to use it."}
+	configData.Commands["invite"] = &Command{Function: commandInvite, HelpText: "Displays available invite links for " + configData.BotName + "."}
+	configData.Commands["donate"] = &Command{Function: commandDonate, HelpText: "Displays available donation links for " + configData.BotName + "."}
+	configData.Commands["source"] = &Command{Function: commandSource, HelpText: "Displays available source code links for " + configData.BotName + "."}
+	configData.Commands["version"] = &Command{Function: commandVersion, HelpText: "Displays the current version of " + configData.BotName + "."}
+	configData.Commands["credits"] = &Command{Function: commandCredits, HelpText: "Displays a list of credits for the creation and functionality of " + configData.BotName + "."}
+	configData.Commands["roll"] = &Command{Function: commandRoll, HelpText: "Rolls a dice."}
+	configData.Commands["doubleroll"] = &Command{Function: commandDoubleRoll, HelpText: "Rolls two die."}
+	configData.Commands["coinflip"] = &Command{Function: commandCoinFlip, HelpText: "Flips a coin."}
+	configData.Commands["join"] = &Command{Function: commandVoiceJoin, HelpText: "Joins the current voice channel.", RequiredPermissions: discordgo.PermissionVoiceConnect}
+	configData.Commands["leave"] = &Command{Function: commandVoiceLeave, HelpText: "Leaves the current voice channel.", RequiredPermissions: discordgo.PermissionVoiceConnect}
+	configData.Commands["ping"] = &Command{Function: commandPing, HelpText: "Returns the ping average to Discord."}
 
 	//All user-accessible info commands with or without parameters
-	botData.Commands["botinfo"] = &Command{Function: commandBotInfo, HelpText: "Displays info about the bot's current state."}
-	botData.Commands["serverinfo"] = &Command{Function: commandServerInfo, HelpText: "Displays info about the current server."}
-	botData.Commands["userinfo"] = &Command{
+	configData.Commands["botinfo"] = &Command{Function: commandBotInfo, HelpText: "Displays info about the bot's current state."}
+	configData.Commands["serverinfo"] = &Command{Function: commandServerInfo, HelpText: "Displays info about the current server."}
+	configData.Commands["userinfo"] = &Command{
 		Function: commandUserInfo,
 		HelpText: "Displays info about the current or specified user.",
 		Arguments: []CommandArgument{
@@ -77,7 +126,7 @@ func initCommands() {
 	}
 
 	//All user-accessible commands with parameters
-	botData.Commands["help"] = &Command{
+	configData.Commands["help"] = &Command{
 		Function: commandHelp,
 		HelpText: "Displays a list of commands you have permission to use.",
 		Arguments: []CommandArgument{
@@ -85,7 +134,7 @@ func initCommands() {
 			{Name: "command", Description: "The command to view help for", ArgType: "string"},
 		},
 	}
-	botData.Commands["translate"] = &Command{
+	configData.Commands["translate"] = &Command{
 		Function: commandTranslate,
 		HelpText: "Translates a given message to the specified language.",
 		RequiredArguments: []string{
@@ -98,7 +147,7 @@ func initCommands() {
 			{Name: "message", Description: "The message to translate", ArgType: "string"},
 		},
 	}
-	botData.Commands["nnid"] = &Command{
+	configData.Commands["nnid"] = &Command{
 		Function: commandNNID,
 		HelpText: "Checks whether the specified NNID exists or not.",
 		RequiredArguments: []string{
@@ -108,7 +157,7 @@ func initCommands() {
 			{Name: "username", Description: "The NNID to check for", ArgType: "string"},
 		},
 	}
-	botData.Commands["remind"] = &Command{
+	configData.Commands["remind"] = &Command{
 		Function: commandRemind,
 		HelpText: "Reminds you with the written message at the specified time.",
 		RequiredArguments: []string{
@@ -120,7 +169,7 @@ func initCommands() {
 			{Name: "remove", Description: "Deletes the specified remind entry or entries", ArgType: "number(s)"},
 		},
 	}
-	botData.Commands["hewwo"] = &Command{
+	configData.Commands["hewwo"] = &Command{
 		Function: commandHewwo,
 		HelpText: "Hewwo!!! (´・ω・｀)",
 		RequiredArguments: []string{
@@ -130,7 +179,7 @@ func initCommands() {
 			{Name: "message", Description: "The text to translate to Hewwo", ArgType: "string"},
 		},
 	}
-	botData.Commands["minecraft"] = &Command{
+	configData.Commands["minecraft"] = &Command{
 		Function: commandMinecraft,
 		HelpText: "Displays information about a specified user or server.",
 		RequiredArguments: []string{
@@ -142,7 +191,7 @@ func initCommands() {
 			{Name: "server", Description: "Displays infromation about the specified server", ArgType: "ip(:port)"},
 		},
 	}
-	botData.Commands["zalgo"] = &Command{
+	configData.Commands["zalgo"] = &Command{
 		Function: commandZalgo,
 		HelpText: "Mystifies your text.",
 		RequiredArguments: []string{
@@ -152,7 +201,7 @@ func initCommands() {
 			{Name: "message", Description: "The text to mystify", ArgType: "string"},
 		},
 	}
-	botData.Commands["nlp"] = &Command{
+	configData.Commands["nlp"] = &Command{
 		Function: commandNLP,
 		HelpText: "Raw natural language processing in Discord. Powered by Prose:tm:.",
 		RequiredArguments: []string{
@@ -162,7 +211,7 @@ func initCommands() {
 			{Name: "message", Description: "The message to parse", ArgType: "string"},
 		},
 	}
-	botData.Commands["image"] = &Command{
+	configData.Commands["image"] = &Command{
 		IsAdvancedCommand: true,
 		AdvancedFunction:  commandImageAdv,
 		HelpText:          "Allows you to manipulate images with various effects.",
@@ -192,7 +241,7 @@ func initCommands() {
 			{Name: "width", Description: "Sets the width", ArgType: "number"},
 		},
 	}
-	botData.Commands["screenshot"] = &Command{
+	configData.Commands["screenshot"] = &Command{
 		Function: commandScreenshot,
 		HelpText: "Takes a screenshot of a website.",
 		RequiredArguments: []string{
@@ -202,7 +251,7 @@ func initCommands() {
 			{Name: "url", Description: "The URL to take a screenshot of", ArgType: "url"},
 		},
 	}
-	botData.Commands["cve"] = &Command{
+	configData.Commands["cve"] = &Command{
 		Function: commandCVE,
 		HelpText: "Fetches information about a specified CVE.",
 		RequiredArguments: []string{
@@ -212,7 +261,7 @@ func initCommands() {
 			{Name: "cve", Description: "The CVE ID to fetch information about", ArgType: "string"},
 		},
 	}
-	botData.Commands["geoip"] = &Command{
+	configData.Commands["geoip"] = &Command{
 		Function: commandGeoIP,
 		HelpText: "Performs a GeoIP lookup on the specified IP/hostname.",
 		RequiredArguments: []string{
@@ -222,8 +271,9 @@ func initCommands() {
 			{Name: "IP/hostname", Description: "The IP or hostname to perform a GeoIP lookup on", ArgType: "IP address/hostname"},
 		},
 	}
-	if botData.BotOptions.UseXKCD {
-		botData.Commands["xkcd"] = &Command{
+	if configData.BotOptions.UseXKCD {
+		configData.Commands["xkcd"] = &Command{
+			Feature:  "xkcd",
 			Function: commandXKCD,
 			HelpText: "Displays an XKCD comic depending on the requested type or comic number.",
 			RequiredArguments: []string{
@@ -236,8 +286,9 @@ func initCommands() {
 			},
 		}
 	}
-	if botData.BotOptions.UseImgur {
-		botData.Commands["imgur"] = &Command{
+	if configData.BotOptions.UseImgur {
+		configData.Commands["imgur"] = &Command{
+			Feature:  "imgur",
 			Function: commandImgur,
 			HelpText: "Displays info about the specified Imgur image or album URL.",
 			RequiredArguments: []string{
@@ -248,8 +299,9 @@ func initCommands() {
 			},
 		}
 	}
-	if botData.BotOptions.UseGitHub {
-		botData.Commands["github"] = &Command{
+	if configData.BotOptions.UseGitHub {
+		configData.Commands["github"] = &Command{
+			Feature:  "github",
 			Function: commandGitHub,
 			HelpText: "Displays info about the specified GitHub user or repo and fetches trending users and repositories.",
 			RequiredArguments: []string{
@@ -262,7 +314,7 @@ func initCommands() {
 			},
 		}
 	}
-	botData.Commands["urbandictionary"] = &Command{
+	configData.Commands["urbandictionary"] = &Command{
 		Function: commandUrbanDictionary,
 		HelpText: "Displays the definition of a term according to the Urban Dictionary.",
 		RequiredArguments: []string{
@@ -272,15 +324,15 @@ func initCommands() {
 			{Name: "term", Description: "The term to fetch a definition for", ArgType: "string"},
 		},
 	}
-	botData.Commands["balance"] = &Command{
+	configData.Commands["balance"] = &Command{
 		Function: commandBalance,
 		HelpText: "Displays the user's current balance.",
 	}
-	botData.Commands["daily"] = &Command{
+	configData.Commands["daily"] = &Command{
 		Function: commandDaily,
 		HelpText: "Lets the user receive credits daily.",
 	}
-	botData.Commands["transfer"] = &Command{
+	configData.Commands["transfer"] = &Command{
 		Function: commandTransfer,
 		HelpText: "Transfers credits to another user.",
 		RequiredArguments: []string{
@@ -294,7 +346,7 @@ func initCommands() {
 	}
 
 	//Voice commands
-	botData.Commands["play"] = &Command{
+	configData.Commands["play"] = &Command{
 		Function: commandPlay,
 		HelpText: "Plays either the first result from a YouTube search query or the specified stream URL in the user's voice channel.",
 		Arguments: []CommandArgument{
@@ -302,23 +354,23 @@ func initCommands() {
 			{Name: "url", Description: "The YouTube, Spotify, SoundCloud, Bandcamp or direct audio/video URL to play", ArgType: "string"},
 		},
 	}
-	botData.Commands["stop"] = &Command{
+	configData.Commands["stop"] = &Command{
 		Function: commandStop,
 		HelpText: "Stops the audio playback in the user's voice channel.",
 	}
-	botData.Commands["skip"] = &Command{
+	configData.Commands["skip"] = &Command{
 		Function: commandSkip,
 		HelpText: "Skips to the next queue entry in the user's voice channel.",
 	}
-	botData.Commands["pause"] = &Command{
+	configData.Commands["pause"] = &Command{
 		Function: commandPause,
 		HelpText: "Pauses the audio playback in the user's voice channel.",
 	}
-	botData.Commands["resume"] = &Command{
+	configData.Commands["resume"] = &Command{
 		Function: commandResume,
 		HelpText: "Resumes the audio playback in the user's voice channel.",
 	}
-	botData.Commands["volume"] = &Command{
+	configData.Commands["volume"] = &Command{
 		Function: commandVolume,
 		HelpText: "Sets the volume level for the next audio playback.",
 		RequiredArguments: []string{
@@ -328,7 +380,7 @@ func initCommands() {
 			{Name: "volume", Description: "The volume level to use", ArgType: "number [0 - 512]"},
 		},
 	}
-	botData.Commands["repeat"] = &Command{
+	configData.Commands["repeat"] = &Command{
 		Function: commandRepeat,
 		HelpText: "Switches queue playback between three modes: no repeat, repeat queue, and repeat now playing.",
 		Arguments: []CommandArgument{
@@ -337,11 +389,12 @@ func initCommands() {
 			{Name: "now playing", Description: "Enables repeat now playing mode", ArgType: ""},
 		},
 	}
-	botData.Commands["shuffle"] = &Command{
+	configData.Commands["shuffle"] = &Command{
 		Function: commandShuffle,
 		HelpText: "Toggles queue shuffling during playback.",
 	}
-	botData.Commands["youtube"] = &Command{
+	configData.Commands["youtube"] = &Command{
+		Feature:  "youtube",
 		Function: commandYouTube,
 		HelpText: "Allows you to navigate YouTube search results to select what to add to the queue.",
 		RequiredArguments: []string{
@@ -355,7 +408,8 @@ func initCommands() {
 			{Name: "play", Description: "Plays the chosen search result from the current page", ArgType: "number"},
 		},
 	}
-	botData.Commands["spotify"] = &Command{
+	configData.Commands["spotify"] = &Command{
+		Feature:  "spotify",
 		Function: commandSpotify,
 		HelpText: "Allows you to search Spotify search results and playlists to select to what to add to the queue.",
 		RequiredArguments: []string{
@@ -373,7 +427,7 @@ func initCommands() {
 			{Name: "play view", Description: "Plays every track result on the current page", ArgType: ""},
 		},
 	}
-	botData.Commands["queue"] = &Command{
+	configData.Commands["queue"] = &Command{
 		Function: commandQueue,
 		HelpText: "Lists and manages entries in the queue.",
 		Arguments: []CommandArgument{
@@ -381,17 +435,18 @@ func initCommands() {
 			{Name: "remove", Description: "Removes the specified queue entry or entries", ArgType: "number"},
 		},
 	}
-	botData.Commands["nowplaying"] = &Command{
+	configData.Commands["nowplaying"] = &Command{
 		Function: commandNowPlaying,
 		HelpText: "Displays the now playing entry.",
 	}
-	botData.Commands["lyrics"] = &Command{
+	configData.Commands["lyrics"] = &Command{
+		Feature:  "lyrics",
 		Function: commandLyrics,
 		HelpText: "Displays the lyrics for the currently playing track.",
 	}
 
 	//All moderation commands with parameters
-	botData.Commands["purge"] = &Command{
+	configData.Commands["purge"] = &Command{
 		Function:            commandPurge,
 		HelpText:            "Purges the specified amount of messages from the channel, up to 100 messages at a time.",
 		RequiredPermissions: discordgo.PermissionManageMessages,
@@ -403,7 +458,7 @@ func initCommands() {
 			{Name: "user(s)", Description: "The user(s) to delete the messages from within the specified amount of messages", ArgType: "mention"},
 		},
 	}
-	botData.Commands["kick"] = &Command{
+	configData.Commands["kick"] = &Command{
 		Function:            commandKick,
 		HelpText:            "Kicks the specified user(s) from the server.",
 		RequiredPermissions: discordgo.PermissionKickMembers,
@@ -415,7 +470,7 @@ func initCommands() {
 			{Name: "reason", Description: "The reason for the kick", ArgType: "string"},
 		},
 	}
-	botData.Commands["ban"] = &Command{
+	configData.Commands["ban"] = &Command{
 		Function:            commandBan,
 		HelpText:            "Bans the specified user(s) from the server.",
 		RequiredPermissions: discordgo.PermissionBanMembers,
@@ -428,7 +483,7 @@ func initCommands() {
 			{Name: "reason", Description: "The reason for the ban", ArgType: "string"},
 		},
 	}
-	botData.Commands["hackban"] = &Command{
+	configData.Commands["hackban"] = &Command{
 		IsAdvancedCommand:   true,
 		AdvancedFunction:    commandHackBan,
 		HelpText:            "Bans the specified user ID(s) from the server.",
@@ -443,7 +498,7 @@ func initCommands() {
 		},
 	}
 
-	botData.Commands["server"] = &Command{
+	configData.Commands["server"] = &Command{
 		Function:            commandSettingsServer,
 		HelpText:            "Changes the specified settings for the server.",
 		RequiredPermissions: discordgo.PermissionAdministrator,
@@ -458,11 +513,12 @@ func initCommands() {
 			{Name: "tips", Description: "Enables or disables logging events for this channel", ArgType: "enable/disable"},
 			{Name: "autosendnowplaying", Description: "Enables or disables automatically sending now playing embeds without user interaction", ArgType: "enable/disable"},
 			{Name: "invitegen", Description: "Manages invite link generation via the API", ArgType: ""},
+			{Name: "webhook", Description: "Manages the channels webhooks can post to", ArgType: ""},
 			{Name: "reset", Description: "Resets the specified setting to the default/empty value", ArgType: "string"},
 		},
 	}
 
-	botData.Commands["roleme"] = &Command{
+	configData.Commands["roleme"] = &Command{
 		IsAdvancedCommand:   true,
 		AdvancedFunction:    commandRoleMe,
 		HelpText:            "Allows you to manage the roleme events list. No arguments will list the roleme events.",
@@ -476,7 +532,7 @@ func initCommands() {
 			{Name: "delete", Description: "Deletes the specified roleme entry", ArgType: "number"},
 		},
 	}
-	botData.Commands["bot"] = &Command{
+	configData.Commands["bot"] = &Command{
 		Function:            commandSettingsBot,
 		HelpText:            "Changes the specified settings for the bot within this server.",
 		RequiredPermissions: discordgo.PermissionAdministrator,
@@ -485,9 +541,10 @@ func initCommands() {
 		},
 		Arguments: []CommandArgument{
 			{Name: "prefix", Description: "Sets the bot command prefix", ArgType: "string"},
+			{Name: "feature", Description: "Enables or disables features in this server", ArgType: "list/enable/disable/reset (feature)"},
 		},
 	}
-	botData.Commands["user"] = &Command{
+	configData.Commands["user"] = &Command{
 		Function: commandSettingsUser,
 		HelpText: "Changes the specified settings for the user.",
 		RequiredArguments: []string{
@@ -497,10 +554,11 @@ func initCommands() {
 			{Name: "about/aboutme/description/desc/info", Description: "Sets your aboutme or views the aboutme of another user", ArgType: "string/mention"},
 			{Name: "timezone", Description: "Sets the timezone to use", ArgType: "timezone"},
 			{Name: "social", Description: "Manages your socials", ArgType: ""},
+			{Name: "data", Description: "Exports or deletes the data stored about you", ArgType: ""},
 		},
 	}
 
-	botData.Commands["starboard"] = &Command{
+	configData.Commands["starboard"] = &Command{
 		Function:            commandStarboard,
 		HelpText:            "Manages the guild's starboard.",
 		RequiredPermissions: discordgo.PermissionAdministrator,
@@ -519,7 +577,8 @@ func initCommands() {
 		},
 	}
 
-	botData.Commands["feed"] = &Command{
+	configData.Commands["feed"] = &Command{
+		Feature:             "feed",
 		IsAdvancedCommand:   true,
 		AdvancedFunction:    commandFeed,
 		HelpText:            "Manages the guild's various RSS and Atom feeds.",
@@ -538,40 +597,57 @@ func initCommands() {
 	}
 
 	//Alternate commands for pre-established commands
-	botData.Commands["?"] = &Command{IsAlternateOf: "help"}
-	botData.Commands["commands"] = &Command{IsAlternateOf: "help"}
-	botData.Commands["ver"] = &Command{IsAlternateOf: "version"}
-	botData.Commands["v"] = &Command{IsAlternateOf: "version"}
-	botData.Commands["rolldouble"] = &Command{IsAlternateOf: "doubleroll"}
-	botData.Commands["flipcoin"] = &Command{IsAlternateOf: "coinflip"}
-	botData.Commands["img"] = &Command{IsAlternateOf: "image"}
-	botData.Commands["gh"] = &Command{IsAlternateOf: "github"}
-	botData.Commands["yt"] = &Command{IsAlternateOf: "youtube"}
-	botData.Commands["sp"] = &Command{IsAlternateOf: "sp"}
-	botData.Commands["np"] = &Command{IsAlternateOf: "nowplaying"}
-	botData.Commands["q"] = &Command{IsAlternateOf: "queue"}
-	botData.Commands["loop"] = &Command{IsAlternateOf: "repeat"}
-	botData.Commands["next"] = &Command{IsAlternateOf: "skip"}
-	botData.Commands["ud"] = &Command{IsAlternateOf: "urbandictionary"}
-	botData.Commands["owo"] = &Command{IsAlternateOf: "hewwo"}
-	botData.Commands["uwu"] = &Command{IsAlternateOf: "hewwo"}
-	botData.Commands["mc"] = &Command{IsAlternateOf: "minecraft"}
-	botData.Commands["guildinfo"] = &Command{IsAlternateOf: "serverinfo"}
-	botData.Commands["ss"] = &Command{IsAlternateOf: "screenshot"}
-	botData.Commands["credits"] = &Command{IsAlternateOf: "balance"}
-	botData.Commands["cash"] = &Command{IsAlternateOf: "balance"}
-	botData.Commands["money"] = &Command{IsAlternateOf: "balance"}
-	botData.Commands["nightly"] = &Command{IsAlternateOf: "daily"}
-	botData.Commands["send"] = &Command{IsAlternateOf: "transfer"}
-	botData.Commands["googletranslate"] = &Command{IsAlternateOf: "translate"}
-	botData.Commands["gtranslate"] = &Command{IsAlternateOf: "translate"}
+	configData.Commands["?"] = &Command{IsAlternateOf: "help"}
+	configData.Commands["commands"] = &Command{IsAlternateOf: "help"}
+	configData.Commands["ver"] = &Command{IsAlternateOf: "version"}
+	configData.Commands["v"] = &Command{IsAlternateOf: "version"}
+	configData.Commands["rolldouble"] = &Command{IsAlternateOf: "doubleroll"}
+	configData.Commands["flipcoin"] = &Command{IsAlternateOf: "coinflip"}
+	configData.Commands["img"] = &Command{IsAlternateOf: "image"}
+	configData.Commands["gh"] = &Command{IsAlternateOf: "github"}
+	configData.Commands["yt"] = &Command{IsAlternateOf: "youtube"}
+	configData.Commands["sp"] = &Command{IsAlternateOf: "spotify"}
+	configData.Commands["np"] = &Command{IsAlternateOf: "nowplaying"}
+	configData.Commands["q"] = &Command{IsAlternateOf: "queue"}
+	configData.Commands["loop"] = &Command{IsAlternateOf: "repeat"}
+	configData.Commands["next"] = &Command{IsAlternateOf: "skip"}
+	configData.Commands["ud"] = &Command{IsAlternateOf: "urbandictionary"}
+	configData.Commands["owo"] = &Command{IsAlternateOf: "hewwo"}
+	configData.Commands["uwu"] = &Command{IsAlternateOf: "hewwo"}
+	configData.Commands["mc"] = &Command{IsAlternateOf: "minecraft"}
+	configData.Commands["guildinfo"] = &Command{IsAlternateOf: "serverinfo"}
+	configData.Commands["ss"] = &Command{IsAlternateOf: "screenshot"}
+	configData.Commands["credits"] = &Command{IsAlternateOf: "balance"}
+	configData.Commands["cash"] = &Command{IsAlternateOf: "balance"}
+	configData.Commands["money"] = &Command{IsAlternateOf: "balance"}
+	configData.Commands["nightly"] = &Command{IsAlternateOf: "daily"}
+	configData.Commands["send"] = &Command{IsAlternateOf: "transfer"}
+	configData.Commands["googletranslate"] = &Command{IsAlternateOf: "translate"}
+	configData.Commands["gtranslate"] = &Command{IsAlternateOf: "translate"}
+
+	//Drop the alternates of commands that are disabled by the bot options
+	for name, command := range configData.Commands {
+		if command.IsAlternateOf != "" {
+			if _, exists := configData.Commands[command.IsAlternateOf]; !exists {
+				delete(configData.Commands, name)
+			}
+		}
+	}
 
 	//Administrative commands for bot owners
-	botData.Commands["reload"] = &Command{Function: commandReload, HelpText: "Reloads the bot configuration.", IsAdministrative: true}
-	botData.Commands["restart"] = &Command{Function: commandRestart, HelpText: "Restarts the bot in case something goes awry.", IsAdministrative: true}
-	botData.Commands["update"] = &Command{Function: commandUpdate, HelpText: "Updates the bot to the latest git repo commit.", IsAdministrative: true}
-	botData.Commands["debug"] = &Command{Function: commandDebug, HelpText: "Toggles debug mode.", IsAdministrative: true}
-	botData.Commands["sudo"] = &Command{
+	configData.Commands["reload"] = &Command{Function: commandReload, HelpText: "Reloads the bot configuration.", IsAdministrative: true}
+	configData.Commands["restart"] = &Command{Function: commandRestart, HelpText: "Restarts the bot in case something goes awry.", IsAdministrative: true}
+	configData.Commands["update"] = &Command{Function: commandUpdate, HelpText: "Updates the bot to the latest git repo commit.", IsAdministrative: true}
+	configData.Commands["rollback"] = &Command{Function: commandRollback, HelpText: "Rolls the bot back to the build before the last update.", IsAdministrative: true}
+	configData.Commands["debug"] = &Command{
+		Function:         commandDebug,
+		HelpText:         "Toggles debug mode, which logs everything. Use level to view or change the log levels.",
+		IsAdministrative: true,
+		Arguments: []CommandArgument{
+			{Name: "level", Description: "Lists the log levels, or sets the log level of everything or of the specified subsystem", ArgType: "<debug/info/warning/error> <api/feed/starboard/voice>"},
+		},
+	}
+	configData.Commands["sudo"] = &Command{
 		Function:         commandSudo,
 		HelpText:         "Runs a command as the specified user.",
 		IsAdministrative: true,
@@ -584,7 +660,19 @@ func initCommands() {
 			{Name: "arguments", Description: "Optional additional arguments to pass to the command", ArgType: "N/A"},
 		},
 	}
-	botData.Commands["status"] = &Command{
+	configData.Commands["userdata"] = &Command{
+		Function:         commandUserData,
+		HelpText:         "Exports or deletes the data stored about the specified user.",
+		IsAdministrative: true,
+		RequiredArguments: []string{
+			"action", "user",
+		},
+		Arguments: []CommandArgument{
+			{Name: "export", Description: "Sends a copy of the user's data to your DMs", ArgType: "mention/ID"},
+			{Name: "delete", Description: "Deletes all of the user's data", ArgType: "mention/ID"},
+		},
+	}
+	configData.Commands["status"] = &Command{
 		Function:         commandStatus,
 		HelpText:         "Sets the bot's status message.",
 		IsAdministrative: true,
@@ -600,21 +688,30 @@ func initCommands() {
 	}
 }
 
-func callCommand(commandName string, args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
-	if command, exists := botData.Commands[commandName]; exists {
+func callCommand(commandName string, args []string, env *CommandEnvironment) (responseEmbed *discordgo.MessageEmbed) {
+	if command, exists := botData().Commands[commandName]; exists {
+		metricCommands.Inc(commandName)
+		defer metricCommandDuration.ObserveSince(time.Now(), commandName)
+		defer recoverCommand(commandName, env, &responseEmbed)
+
 		if command.IsAlternateOf != "" {
-			if commandAlternate, exists := botData.Commands[command.IsAlternateOf]; exists {
+			if commandAlternate, exists := botData().Commands[command.IsAlternateOf]; exists {
 				command = commandAlternate
 			} else {
 				return nil
 			}
 		}
-		if command.IsAdministrative && env.User.ID != botData.BotOwnerID {
-			return NewErrorEmbed("Command Error - Not Authorized (NA)", "I'm sorry Dave, I'm afraid I can't do that.")
+		env.logger(Debug).Printf("Running command with %d arguments", len(args))
+		if command.IsAdministrative && env.User.ID != botData().BotOwnerID {
+			env.logger(Warning).Println("Denied an administrative command to a user that isn't the bot owner")
+			return env.errorEmbed(newError(errCodeCommandNotAuthorized))
+		}
+		if command.Feature != "" && !featureEnabled(env.Guild.ID, command.Feature) {
+			return env.errorEmbed(newError(errCodeCommandFeatureOff, command.Feature))
 		}
 		if command.RequiredPermissions != 0 {
-			if permissionsAllowed, _ := MemberHasPermission(botData.DiscordSession, env.Guild.ID, env.User.ID, env.Channel.ID, command.RequiredPermissions); permissionsAllowed == false {
-				return NewErrorEmbed("Command Error - No Permissions (NP)", "Just what do you think you're doing, Dave?")
+			if permissionsAllowed, _ := MemberHasPermission(botData().DiscordSession, env.Guild.ID, env.User.ID, env.Channel.ID, command.RequiredPermissions); permissionsAllowed == false {
+				return env.errorEmbed(newError(errCodeCommandNoPermissions))
 			}
 		}
 		if len(args) >= len(command.RequiredArguments) {
@@ -651,9 +748,9 @@ func callCommand(commandName string, args []string, env *CommandEnvironment) *di
 }
 
 func getCommandUsage(commandName, title string, env *CommandEnvironment) *discordgo.MessageEmbed {
-	command := botData.Commands[commandName]
+	command := botData().Commands[commandName]
 	if command.IsAlternateOf != "" {
-		command = botData.Commands[command.IsAlternateOf]
+		command = botData().Commands[command.IsAlternateOf]
 	}
 
 	parameterFields := []*discordgo.MessageEmbedField{}
@@ -1,9 +1,11 @@
 package main
 
 import (
-	"errors"
+	"fmt"
+	"net"
+	"net/url"
 	"regexp"
-	"strconv"
+	"strings"
 
 	"github.com/mmcdole/gofeed"
 
@@ -71,15 +73,17 @@ type BotClients struct {
 
 // BotKeys stores all bot keys for using external services
 type BotKeys struct {
-	DuckDuckGoAppName  string                   `json:"ddgAppName"`
-	GeniusAccessToken  string                   `json:"geniusAccessToken"`
-	ImgurClientID      string                   `json:"imgurClientID"`
-	SoundCloudClientID string                   `json:"soundcloudClientID"`
-	SpotifyHost        string                   `json:"spotifyHost"`
-	SpotifyPass        string                   `json:"spotifyPass"`
-	WolframAppID       string                   `json:"wolframAppID"`
-	YouTubeAPIKey      string                   `json:"youtubeAPIKey"`
-	Ninty              fennel.ClientInformation `json:"ninty"`
+	DiscordClientID     string                   `json:"discordClientID"`     //The client ID of the bot's application for logging in to the API, defaults to the bot's user ID
+	DiscordClientSecret string                   `json:"discordClientSecret"` //The client secret of the bot's application for logging in to the API
+	DuckDuckGoAppName   string                   `json:"ddgAppName"`
+	GeniusAccessToken   string                   `json:"geniusAccessToken"`
+	ImgurClientID       string                   `json:"imgurClientID"`
+	SoundCloudClientID  string                   `json:"soundcloudClientID"`
+	SpotifyHost         string                   `json:"spotifyHost"`
+	SpotifyPass         string                   `json:"spotifyPass"`
+	WolframAppID        string                   `json:"wolframAppID"`
+	YouTubeAPIKey       string                   `json:"youtubeAPIKey"`
+	Ninty               fennel.ClientInformation `json:"ninty"`
 }
 
 // BotOptions stores all bot options
@@ -106,12 +110,63 @@ type BotOptions struct {
 	AudioEncoding             *dca.EncodeOptions `json:"audioEncoding"`
 	API                       APIConfig          `json:"api"`
 	FeedFrequency             int                `json:"feedFrequency"` //Default interval in seconds for checking for new feed entries
+	GuildData                 GuildDataConfig    `json:"guildData"`            //Lifetimes and limits for per-guild query and session tracking
+	ConfigWatchFrequency      int                `json:"configWatchFrequency"` //Interval in seconds for checking the configuration file for changes to reload, 0 to disable
+	ShutdownTimeout           int                `json:"shutdownTimeout"`      //How long in seconds to wait for a clean shutdown before exiting anyway
+	Sharding                  ShardingConfig     `json:"sharding"`             //How to split the bot's guilds across multiple connections to Discord
+	Updates                   UpdatesConfig      `json:"updates"`              //How to keep and roll back builds from the update command
+	Logging                   LoggingConfig      `json:"logging"`              //How to log and how to rotate the log files
+}
+
+// LoggingConfig stores configurations for the log level and format, and for rotating the log files
+type LoggingConfig struct {
+	Level       string            `json:"level"`       //The minimum level to log at: debug, info, warning or error
+	Subsystems  map[string]string `json:"subsystems"`  //The minimum level to log at for specific subsystems, where key = api, feed, starboard or voice
+	Format      string            `json:"format"`      //The format to write log messages in: text or json
+	MaxSize     int               `json:"maxSize"`     //The size in megabytes to rotate a log file at, 0 to disable
+	RotateEvery int               `json:"rotateEvery"` //How often in hours to rotate a log file, 0 to disable
+	MaxBackups  int               `json:"maxBackups"`  //How many rotated log files to keep, 0 to keep all of them
+	MaxAge      int               `json:"maxAge"`      //How long in days to keep rotated log files, 0 to keep them forever
+}
+
+// UpdatesConfig stores configurations for keeping previous builds and rolling back bad updates
+type UpdatesConfig struct {
+	KeepBinaries   int `json:"keepBinaries"`   //How many previous builds to keep for rolling back to
+	RollbackWindow int `json:"rollbackWindow"` //How long in seconds after an update that crash looping rolls it back automatically
+}
+
+// ShardingConfig stores configurations for splitting the bot across multiple bot processes, one per shard
+type ShardingConfig struct {
+	ShardCount int `json:"shardCount"` //The number of shards to run, 0 = use the number recommended by Discord
+}
+
+// GuildDataConfig stores configurations for expiring per-guild query and session tracking
+type GuildDataConfig struct {
+	QueryLifetime        int `json:"queryLifetime"`        //How long in seconds a query's response is tracked for edits and deletions
+	SessionLifetime      int `json:"sessionLifetime"`      //How long in seconds an unused YouTube or Spotify search session is kept
+	ConversationLifetime int `json:"conversationLifetime"` //How long in seconds an unused Wolfram|Alpha conversation is kept
+	MaxQueries           int `json:"maxQueries"`           //The maximum amount of queries to track per guild, oldest are evicted first
+	MaxSessions          int `json:"maxSessions"`          //The maximum amount of sessions of each type to keep per guild, least recently used are evicted first
+	SweepFrequency       int `json:"sweepFrequency"`       //Interval in seconds for sweeping expired queries and sessions
 }
 
 // APIConfig stores configurations for the API
 type APIConfig struct {
-	Enabled bool   `json:"enabled"`
-	Host    string `json:"host"`
+	Enabled          bool               `json:"enabled"`
+	Host             string             `json:"host"`
+	PublicURL        string             `json:"publicURL"`        //The URL users reach the API at, which Discord sends users back to after logging in
+	SessionLifetime  int                `json:"sessionLifetime"`  //How many hours a session from logging in with Discord lasts
+	WebhookRateLimit int                `json:"webhookRateLimit"` //How many messages each webhook key may post per minute, unless the key has its own limit
+	CORSOrigins      []string           `json:"corsOrigins"`      //The origins of pages allowed to call the API from a browser, such as a dashboard hosted elsewhere, or * for any
+	TrustProxy       bool               `json:"trustProxy"`       //Whether to take the client's IP from the X-Forwarded-For or X-Real-IP headers, only when behind a reverse proxy
+	RateLimit        APIRateLimitConfig `json:"rateLimit"`
+}
+
+// APIRateLimitConfig stores configurations for limiting how often the API may be called
+type APIRateLimitConfig struct {
+	PerIP    int `json:"perIP"`    //How many requests each IP may make per minute, 0 to disable
+	PerToken int `json:"perToken"` //How many requests each API token or session may make per minute, 0 to disable
+	Invites  int `json:"invites"`  //How many invites may be generated for each guild per hour
 }
 
 // CustomResponseQuery stores a custom response
@@ -141,51 +196,257 @@ type TipMessage struct {
 	Examples    []string `json:"examples"`
 }
 
-// PrepConfig checks the configuration for consistency and invalid errors
-func (configData *BotData) PrepConfig() error {
+// ConfigIssue describes a single problem found in the configuration
+type ConfigIssue struct {
+	Path    string //The JSON path to the offending option, ex: botOptions.maxPingCount
+	Message string //What's wrong with the option
+}
+
+func (issue *ConfigIssue) Error() string {
+	return "config:" + issue.Path + ": " + issue.Message
+}
+
+// ConfigReport holds every problem found in the configuration
+type ConfigReport struct {
+	Errors   []*ConfigIssue //Problems that prevent the configuration from being used
+	Warnings []*ConfigIssue //Problems the bot can run with, but probably aren't intended
+}
+
+func (report *ConfigReport) errorf(path, format string, a ...interface{}) {
+	report.Errors = append(report.Errors, &ConfigIssue{Path: path, Message: fmt.Sprintf(format, a...)})
+}
+
+func (report *ConfigReport) warnf(path, format string, a ...interface{}) {
+	report.Warnings = append(report.Warnings, &ConfigIssue{Path: path, Message: fmt.Sprintf(format, a...)})
+}
+
+// Errs returns the errors of the report as a list of errors
+func (report *ConfigReport) Errs() []error {
+	errs := make([]error, 0)
+	for _, issue := range report.Errors {
+		errs = append(errs, issue)
+	}
+	return errs
+}
+
+// PrepConfig checks the configuration for consistency and invalid errors, then prepares it for usage
+// Every problem is collected into the returned report rather than stopping at the first
+func (configData *BotData) PrepConfig() *ConfigReport {
+	report := &ConfigReport{}
+
 	//Bot config checks
 	if configData.BotToken == "" {
-		return errors.New("config:{botName: \"\"}")
+		report.errorf("botToken", "must be set")
 	}
 	if configData.CommandPrefix == "" {
-		return errors.New("config:{cmdPrefix: \"\"}")
+		report.errorf("cmdPrefix", "must be set")
+	}
+	if configData.BotOwnerID == "" {
+		report.warnf("botOwnerID", "is not set, administrative commands and crash reports will be unavailable")
 	}
 
 	//Value checks
 	if configData.BotOptions.MaxPingCount > 5 || configData.BotOptions.MaxPingCount <= 0 {
-		return errors.New("config:{botOptions:{maxPingCount}} must be 1, 2, 3, 4, or 5")
+		report.errorf("botOptions.maxPingCount", "must be 1, 2, 3, 4, or 5")
 	}
 	if configData.BotOptions.HelpMaxResults > EmbedLimitField || configData.BotOptions.HelpMaxResults <= 0 {
-		return errors.New("config:{botOptions:{helpMaxResults}} must be between 1 to " + strconv.Itoa(EmbedLimitField))
+		report.errorf("botOptions.helpMaxResults", "must be between 1 to %d", EmbedLimitField)
 	}
 	if configData.BotOptions.YouTubeMaxResults > EmbedLimitField || configData.BotOptions.YouTubeMaxResults <= 0 {
-		return errors.New("config:{botOptions:{youtubeMaxResults}} must be between 1 to " + strconv.Itoa(EmbedLimitField))
+		report.errorf("botOptions.youtubeMaxResults", "must be between 1 to %d", EmbedLimitField)
+	}
+	if configData.BotOptions.SpotifyMaxResults > EmbedLimitField || configData.BotOptions.SpotifyMaxResults <= 0 {
+		report.errorf("botOptions.spotifyMaxResults", "must be between 1 to %d", EmbedLimitField)
+	}
+	if configData.BotOptions.FeedFrequency <= 0 {
+		report.errorf("botOptions.feedFrequency", "must be greater than 0")
+	} else if configData.BotOptions.FeedFrequency < 60 {
+		report.warnf("botOptions.feedFrequency", "checking feeds more often than once a minute may get the bot rate limited")
+	}
+	if configData.BotOptions.ConfigWatchFrequency < 0 {
+		report.errorf("botOptions.configWatchFrequency", "must not be negative")
+	}
+	if configData.BotOptions.ShutdownTimeout <= 0 {
+		report.errorf("botOptions.shutdownTimeout", "must be greater than 0")
+	}
+	if configData.BotOptions.Sharding.ShardCount < 0 {
+		report.errorf("botOptions.sharding.shardCount", "must not be negative")
+	}
+	if configData.BotOptions.Updates.KeepBinaries < 1 {
+		report.errorf("botOptions.updates.keepBinaries", "must be at least 1 to be able to roll back")
+	}
+	if configData.BotOptions.Updates.RollbackWindow < 0 {
+		report.errorf("botOptions.updates.rollbackWindow", "must not be negative")
+	}
+	if _, err := parseLogLevel(configData.BotOptions.Logging.Level); err != nil {
+		report.errorf("botOptions.logging.level", "%v", err)
+	}
+	for subsystem, level := range configData.BotOptions.Logging.Subsystems {
+		if !isLogSubsystem(subsystem) {
+			report.errorf("botOptions.logging.subsystems."+subsystem, "unknown subsystem, must be one of %s", strings.Join(logSubsystems, ", "))
+		} else if _, err := parseLogLevel(level); err != nil {
+			report.errorf("botOptions.logging.subsystems."+subsystem, "%v", err)
+		}
+	}
+	if configData.BotOptions.Logging.Format != "text" && configData.BotOptions.Logging.Format != "json" {
+		report.errorf("botOptions.logging.format", "must be text or json")
+	}
+	if configData.BotOptions.Logging.MaxSize < 0 {
+		report.errorf("botOptions.logging.maxSize", "must not be negative")
+	}
+	if configData.BotOptions.Logging.RotateEvery < 0 {
+		report.errorf("botOptions.logging.rotateEvery", "must not be negative")
+	}
+	if configData.BotOptions.Logging.MaxBackups < 0 {
+		report.errorf("botOptions.logging.maxBackups", "must not be negative")
+	}
+	if configData.BotOptions.Logging.MaxAge < 0 {
+		report.errorf("botOptions.logging.maxAge", "must not be negative")
+	}
+	if configData.BotOptions.AudioEncoding == nil {
+		report.errorf("botOptions.audioEncoding", "must be set")
+	} else if err := configData.BotOptions.AudioEncoding.Validate(); err != nil {
+		report.errorf("botOptions.audioEncoding", "%v", err)
+	}
+	if configData.BotOptions.API.Enabled {
+		if _, port, err := net.SplitHostPort(configData.BotOptions.API.Host); err != nil {
+			report.errorf("botOptions.api.host", "must be a host:port address: %v", err)
+		} else if _, err := net.LookupPort("tcp", port); err != nil {
+			report.errorf("botOptions.api.host", "invalid port: %v", err)
+		}
+		if configData.BotOptions.API.PublicURL != "" {
+			if publicURL, err := url.Parse(configData.BotOptions.API.PublicURL); err != nil || (publicURL.Scheme != "http" && publicURL.Scheme != "https") || publicURL.Host == "" {
+				report.errorf("botOptions.api.publicURL", "must be an http or https URL")
+			}
+		} else if configData.BotKeys.DiscordClientSecret != "" {
+			report.warnf("botOptions.api.publicURL", "must be set to log in to the API with Discord")
+		}
+		if configData.BotOptions.API.SessionLifetime <= 0 {
+			report.errorf("botOptions.api.sessionLifetime", "must be positive")
+		}
+		if configData.BotOptions.API.WebhookRateLimit <= 0 {
+			report.errorf("botOptions.api.webhookRateLimit", "must be positive")
+		}
+		for _, origin := range configData.BotOptions.API.CORSOrigins {
+			if origin == "*" {
+				continue
+			}
+			if originURL, err := url.Parse(origin); err != nil || (originURL.Scheme != "http" && originURL.Scheme != "https") || originURL.Host == "" || strings.TrimSuffix(originURL.Path, "/") != "" {
+				report.errorf("botOptions.api.corsOrigins", "%s must be a scheme and host, such as https://dashboard.example.com", origin)
+			}
+		}
+		if configData.BotOptions.API.RateLimit.PerIP < 0 {
+			report.errorf("botOptions.api.rateLimit.perIP", "must not be negative")
+		}
+		if configData.BotOptions.API.RateLimit.PerToken < 0 {
+			report.errorf("botOptions.api.rateLimit.perToken", "must not be negative")
+		}
+		if configData.BotOptions.API.RateLimit.Invites <= 0 {
+			report.errorf("botOptions.api.rateLimit.invites", "must be positive")
+		}
+	}
+
+	//Guild data defaults
+	if configData.BotOptions.GuildData.QueryLifetime < 0 {
+		report.errorf("botOptions.guildData.queryLifetime", "must not be negative")
+	}
+	if configData.BotOptions.GuildData.SessionLifetime < 0 {
+		report.errorf("botOptions.guildData.sessionLifetime", "must not be negative")
+	}
+	if configData.BotOptions.GuildData.ConversationLifetime < 0 {
+		report.errorf("botOptions.guildData.conversationLifetime", "must not be negative")
+	}
+	if configData.BotOptions.GuildData.MaxQueries < 0 {
+		report.errorf("botOptions.guildData.maxQueries", "must not be negative")
+	}
+	if configData.BotOptions.GuildData.MaxSessions < 0 {
+		report.errorf("botOptions.guildData.maxSessions", "must not be negative")
+	}
+	if configData.BotOptions.GuildData.SweepFrequency < 0 {
+		report.errorf("botOptions.guildData.sweepFrequency", "must not be negative")
+	}
+	if configData.BotOptions.GuildData.QueryLifetime == 0 {
+		configData.BotOptions.GuildData.QueryLifetime = 86400
+	}
+	if configData.BotOptions.GuildData.SessionLifetime == 0 {
+		configData.BotOptions.GuildData.SessionLifetime = 3600
+	}
+	if configData.BotOptions.GuildData.ConversationLifetime == 0 {
+		configData.BotOptions.GuildData.ConversationLifetime = 3600
+	}
+	if configData.BotOptions.GuildData.MaxQueries == 0 {
+		configData.BotOptions.GuildData.MaxQueries = 1000
+	}
+	if configData.BotOptions.GuildData.MaxSessions == 0 {
+		configData.BotOptions.GuildData.MaxSessions = 100
+	}
+	if configData.BotOptions.GuildData.SweepFrequency == 0 {
+		configData.BotOptions.GuildData.SweepFrequency = 600
 	}
 
 	//Bot key checks
 	if configData.BotOptions.UseDuckDuckGo && configData.BotKeys.DuckDuckGoAppName == "" {
-		return errors.New("config:{botOptions:{useDuckDuckGo: true}} not permitted, config:{botKeys:{ddgAppName: \"\"}}")
+		report.errorf("botKeys.ddgAppName", "must be set when botOptions.useDuckDuckGo is true")
 	}
 	if configData.BotOptions.UseImgur && configData.BotKeys.ImgurClientID == "" {
-		return errors.New("config:{botOptions:{useImgur: true}} not permitted, config:{botKeys:{imgurClientID: \"\"}}")
+		report.errorf("botKeys.imgurClientID", "must be set when botOptions.useImgur is true")
 	}
 	if configData.BotOptions.UseSoundCloud && configData.BotKeys.SoundCloudClientID == "" {
-		return errors.New("config:{botOptions:{useSoundCloud: true}} not permitted, config:{botKeys:{soundcloudClientID: \"\"}}")
+		report.errorf("botKeys.soundcloudClientID", "must be set when botOptions.useSoundCloud is true")
+	}
+	if configData.BotOptions.UseSpotify {
+		if configData.BotKeys.SpotifyHost == "" {
+			report.errorf("botKeys.spotifyHost", "must be set when botOptions.useSpotify is true")
+		}
+		if configData.BotKeys.SpotifyPass == "" {
+			report.warnf("botKeys.spotifyPass", "is not set, the Spotify host must not require a password")
+		}
+	}
+	if configData.BotOptions.UseLyrics && configData.BotKeys.GeniusAccessToken == "" {
+		report.warnf("botKeys.geniusAccessToken", "is not set, lyrics will not be searched for on Genius")
 	}
 	if configData.BotOptions.UseWolframAlpha && configData.BotKeys.WolframAppID == "" {
-		return errors.New("config:{botOptions:{useWolframAlpha: true}} not permitted, config:{botKeys:{wolframAppID: \"\"}}")
+		report.errorf("botKeys.wolframAppID", "must be set when botOptions.useWolframAlpha is true")
 	}
 	if configData.BotOptions.UseYouTube && configData.BotKeys.YouTubeAPIKey == "" {
-		return errors.New("config:{botOptions:{useYouTube: true}} not permitted, config:{botKeys:{youtubeAPIKey: \"\"}}")
+		report.errorf("botKeys.youtubeAPIKey", "must be set when botOptions.useYouTube is true")
 	}
 
 	//Custom response checks
 	for i, customResponse := range configData.CustomResponses {
+		path := fmt.Sprintf("customResponses[%d]", i)
 		regexp, err := regexp.Compile(customResponse.Expression)
 		if err != nil {
-			return err
+			report.errorf(path+".expression", "invalid regular expression: %v", err)
+			continue
 		}
 		configData.CustomResponses[i].Regexp = regexp
+
+		if len(customResponse.Responses) == 0 && len(customResponse.CmdResponses) == 0 {
+			report.warnf(path, "has no responses or cmdResponses and will never respond")
+		}
+	}
+
+	//Status and tip message checks
+	if len(configData.CustomStatuses) == 0 {
+		report.warnf("customStatuses", "is empty, the bot's presence will not be set")
 	}
-	return nil
+	for i, status := range configData.CustomStatuses {
+		path := fmt.Sprintf("customStatuses[%d]", i)
+		if status == nil {
+			report.errorf(path, "must not be null")
+			continue
+		}
+		if status.Type < discordgo.ActivityTypeGame || status.Type > discordgo.ActivityTypeCustom {
+			report.errorf(path+".type", "unknown activity type %d", status.Type)
+		}
+		if status.Type == discordgo.ActivityTypeStreaming && status.URL == "" {
+			report.warnf(path+".url", "is not set for a streaming status")
+		}
+	}
+	if len(configData.TipMessages) == 0 {
+		report.warnf("tipMessages", "is empty, tip messages will not be sent")
+	}
+
+	return report
 }
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/jonas747/dca"
+	"gopkg.in/yaml.v3"
+)
+
+/*
+	The configuration is built up in layers, where each layer overrides the ones before it:
+	1. Defaults, from defaultBotData()
+	2. The configuration file, in JSON, YAML or TOML depending on its extension
+	3. Environment variables, named CLINET_ followed by the uppercased JSON path joined by underscores (ex: CLINET_BOTKEYS_YOUTUBEAPIKEY)
+	4. Command-line flags that were explicitly set
+
+	Any environment variable can instead be suffixed with _FILE to read its value from a file, which is useful for container secrets.
+	Values that aren't strings, numbers or booleans (lists, maps and foreign structs) are given as JSON.
+*/
+
+const envPrefix = "CLINET"
+
+var (
+	//Shorthand environment variable names, where key = alias and value = canonical name
+	envAliases = map[string]string{
+		"CLINET_BOT_TOKEN":    "CLINET_BOTTOKEN",
+		"CLINET_BOT_OWNER_ID": "CLINET_BOTOWNERID",
+		"CLINET_BOT_PREFIX":   "CLINET_CMDPREFIX",
+	}
+
+	//Flags that override the configuration when explicitly set, these are forwarded to the bot process
+	configLayerFlags = []string{"prefix", "api", "apihost"}
+)
+
+// defaultBotData returns a configuration with the defaults that the configuration file and environment are layered on top of
+func defaultBotData() *BotData {
+	configData := &BotData{
+		CommandPrefix: "cli$",
+		BotOptions: BotOptions{
+			MaxPingCount:      4,
+			HelpMaxResults:    8,
+			SendTypingEvent:   true,
+			YouTubeMaxResults: 8,
+			SpotifyMaxResults: 8,
+			FeedFrequency:     3600,
+			ShutdownTimeout:   15,
+			Updates: UpdatesConfig{
+				KeepBinaries:   3,
+				RollbackWindow: 600,
+			},
+			Logging: LoggingConfig{
+				Level:       "info",
+				Format:      "text",
+				MaxSize:     10,
+				RotateEvery: 24,
+				MaxBackups:  7,
+				MaxAge:      30,
+			},
+			API: APIConfig{
+				Host:             ":8080",
+				SessionLifetime:  168,
+				WebhookRateLimit: 30,
+				RateLimit: APIRateLimitConfig{
+					PerIP:    300,
+					PerToken: 120,
+					Invites:  10,
+				},
+			},
+		},
+	}
+	audioEncoding := *dca.StdEncodeOptions
+	configData.BotOptions.AudioEncoding = &audioEncoding
+
+	return configData
+}
+
+// loadConfigLayers builds a configuration from the defaults, the given configuration file, the environment and the command-line flags
+func loadConfigLayers(file string) (*BotData, error) {
+	configData := defaultBotData()
+
+	if err := applyConfigFile(configData, file); err != nil {
+		return nil, err
+	}
+	if err := applyEnvOverlay(reflect.ValueOf(configData).Elem(), envPrefix); err != nil {
+		return nil, err
+	}
+	applyFlagOverlay(configData)
+
+	return configData, nil
+}
+
+func applyConfigFile(configData *BotData, file string) error {
+	configFileData, err := ioutil.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			Warning.Printf("Configuration file %s doesn't exist, using the defaults and environment only", file)
+			return nil
+		}
+		return err
+	}
+
+	//YAML and TOML are decoded generically and re-encoded as JSON, so every format shares the same field names
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml":
+		layer := make(map[string]interface{})
+		if err = yaml.Unmarshal(configFileData, &layer); err != nil {
+			return fmt.Errorf("error parsing %s: %v", file, err)
+		}
+		configFileData, err = json.Marshal(layer)
+	case ".toml":
+		layer := make(map[string]interface{})
+		if err = toml.Unmarshal(configFileData, &layer); err != nil {
+			return fmt.Errorf("error parsing %s: %v", file, err)
+		}
+		configFileData, err = json.Marshal(layer)
+	}
+	if err != nil {
+		return fmt.Errorf("error parsing %s: %v", file, err)
+	}
+
+	configParser := json.NewDecoder(bytes.NewReader(configFileData))
+	if err = configParser.Decode(configData); err != nil {
+		return fmt.Errorf("error parsing %s: %v", file, err)
+	}
+	return nil
+}
+
+// applyEnvOverlay walks the JSON-tagged fields of a struct and overrides each one that has a matching environment variable
+func applyEnvOverlay(value reflect.Value, prefix string) error {
+	valueType := value.Type()
+	for i := 0; i < valueType.NumField(); i++ {
+		field := valueType.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		envName := prefix + "_" + strings.ToUpper(name)
+
+		//Only our own structs are walked, anything else is set as a whole
+		if field.Type.Kind() == reflect.Struct && field.Type.PkgPath() == valueType.PkgPath() {
+			if err := applyEnvOverlay(value.Field(i), envName); err != nil {
+				return err
+			}
+			continue
+		}
+
+		envValue, found, err := lookupEnv(envName)
+		if err != nil {
+			return err
+		}
+		if !found {
+			continue
+		}
+		if err := setFieldFromString(value.Field(i), envValue); err != nil {
+			return fmt.Errorf("error parsing %s: %v", envName, err)
+		}
+	}
+	return nil
+}
+
+// lookupEnv finds the value of an environment variable by its name, its aliases, or a file named by either with the _FILE suffix
+func lookupEnv(envName string) (string, bool, error) {
+	envNames := []string{envName}
+	for alias, canonical := range envAliases {
+		if canonical == envName {
+			envNames = append(envNames, alias)
+		}
+	}
+
+	for _, name := range envNames {
+		if envValue, found := os.LookupEnv(name); found {
+			return envValue, true, nil
+		}
+		if secretFile, found := os.LookupEnv(name + "_FILE"); found {
+			secret, err := ioutil.ReadFile(secretFile)
+			if err != nil {
+				return "", false, fmt.Errorf("error reading %s_FILE: %v", name, err)
+			}
+			return strings.TrimRight(string(secret), "\r\n"), true, nil
+		}
+	}
+	return "", false, nil
+}
+
+func setFieldFromString(field reflect.Value, envValue string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(envValue)
+	case reflect.Bool:
+		boolValue, err := strconv.ParseBool(envValue)
+		if err != nil {
+			return err
+		}
+		field.SetBool(boolValue)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		intValue, err := strconv.ParseInt(envValue, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(intValue)
+	case reflect.Float32, reflect.Float64:
+		floatValue, err := strconv.ParseFloat(envValue, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(floatValue)
+	default:
+		return json.Unmarshal([]byte(envValue), field.Addr().Interface())
+	}
+	return nil
+}
+
+// applyFlagOverlay overrides the configuration with the command-line flags that were explicitly set
+func applyFlagOverlay(configData *BotData) {
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "prefix":
+			configData.CommandPrefix = flagPrefix
+		case "api":
+			configData.BotOptions.API.Enabled = flagAPI
+		case "apihost":
+			configData.BotOptions.API.Host = flagAPIHost
+		}
+	})
+}
+
+// configLayerArgs returns the explicitly set configuration flags as arguments to pass on to a bot process
+func configLayerArgs() []string {
+	args := make([]string, 0)
+	flag.Visit(func(f *flag.Flag) {
+		for _, name := range configLayerFlags {
+			if f.Name == name {
+				args = append(args, "-"+f.Name+"="+f.Value.String())
+			}
+		}
+	})
+	return args
+}
+
+// runConfigCheck validates the layered configuration and prints a report, returning the exit code to use
+func runConfigCheck(file string) int {
+	configData, err := loadConfigLayers(file)
+	if err != nil {
+		fmt.Printf("ERROR   %v\n", err)
+		fmt.Println("Configuration check failed: 1 error, 0 warnings")
+		return 1
+	}
+
+	report := configData.PrepConfig()
+	for _, issue := range report.Errors {
+		fmt.Printf("ERROR   %s: %s\n", issue.Path, issue.Message)
+	}
+	for _, issue := range report.Warnings {
+		fmt.Printf("WARNING %s: %s\n", issue.Path, issue.Message)
+	}
+
+	if len(report.Errors) > 0 {
+		fmt.Printf("Configuration check failed: %d errors, %d warnings\n", len(report.Errors), len(report.Warnings))
+		return 1
+	}
+	fmt.Printf("Configuration check passed: %d warnings\n", len(report.Warnings))
+	return 0
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+/*
+	The dashboard is a small web UI for managing guilds without chat commands, served from the API host at /dashboard.
+	It's plain HTML, CSS and JavaScript compiled into the binary, and does everything through the same API endpoints scripts use.
+*/
+
+//go:embed dashboard
+var dashboardFiles embed.FS
+
+// Dashboard returns the handler serving the dashboard's files
+func Dashboard() http.Handler {
+	files, err := fs.Sub(dashboardFiles, "dashboard")
+	if err != nil {
+		panic(err) //The embedded directory always exists
+	}
+	fileServer := http.StripPrefix("/dashboard", http.FileServer(http.FS(files)))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		//The dashboard is authenticated by the session cookie, so lock down what it can load and who can frame it
+		w.Header().Set("Content-Security-Policy", "default-src 'self'; img-src 'self' https://cdn.discordapp.com https://i.ytimg.com data:; connect-src 'self' ws://"+r.Host+" wss://"+r.Host+"; frame-ancestors 'none'; base-uri 'none'; form-action 'self'")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("Referrer-Policy", "same-origin")
+		fileServer.ServeHTTP(w, r)
+	})
+}
+
+func apiGetRoot(w http.ResponseWriter, r *http.Request) {
+	http.Redirect(w, r, "/dashboard", http.StatusFound)
+}
@@ -0,0 +1,706 @@
+"use strict";
+
+/*
+	The dashboard talks to the API with the session cookie set by logging in with Discord.
+	Pages are addressed by the URL hash (#/guild/{id}/{tab}), so the browser's back button works without a server-side router.
+*/
+
+const state = {
+	me: null,       //Who's logged in, from /auth/me
+	user: null,     //Their Discord user
+	guilds: null,   //The guilds they share with the bot
+	socket: null,   //The event stream of the guild being viewed, if any
+	timers: [],     //Intervals to clear when leaving a page
+};
+
+const guildTabs = [
+	{id: "settings", name: "Settings", manager: true},
+	{id: "starboard", name: "Starboard", manager: true},
+	{id: "feeds", name: "Feeds", manager: true},
+	{id: "voice", name: "Voice", manager: false},
+	{id: "reminders", name: "Reminders", manager: false},
+];
+
+//el creates an element with the given attributes and children, where strings become text so nothing is ever parsed as HTML
+function el(tag, attrs, ...children) {
+	const element = document.createElement(tag);
+	for (const [key, value] of Object.entries(attrs || {})) {
+		if (value === undefined || value === null || value === false) {
+			continue;
+		}
+		if (key.startsWith("on")) {
+			element.addEventListener(key.slice(2), value);
+		} else if (key in element && key !== "list") {
+			element[key] = value;
+		} else {
+			element.setAttribute(key, value === true ? "" : value);
+		}
+	}
+	for (const child of children.flat()) {
+		if (child !== undefined && child !== null && child !== false) {
+			element.append(child instanceof Node ? child : String(child));
+		}
+	}
+	return element;
+}
+
+//APIError holds an error response from the API
+class APIError extends Error {
+	constructor(status, body) {
+		super(body && body.error ? body.error : "Request failed with status " + status);
+		this.status = status;
+		this.code = body && body.code;
+		this.fields = (body && body.fields) || [];
+	}
+}
+
+async function api(method, path, body) {
+	const options = {method: method, credentials: "same-origin", headers: {}};
+	if (body !== undefined) {
+		options.headers["Content-Type"] = "application/json";
+		options.body = JSON.stringify(body);
+	}
+	const response = await fetch(path, options);
+	if (response.status === 204) {
+		return null;
+	}
+	const data = await response.json().catch(() => null);
+	if (!response.ok || (data && data.code && data.error && !Array.isArray(data))) {
+		throw new APIError(response.status, data);
+	}
+	return data;
+}
+
+function toast(message, isError) {
+	const element = document.getElementById("toast");
+	element.textContent = message;
+	element.className = isError ? "error" : "";
+	element.hidden = false;
+	clearTimeout(toast.timeout);
+	toast.timeout = setTimeout(() => { element.hidden = true; }, 4000);
+}
+
+function formatDuration(seconds) {
+	seconds = Math.max(0, Math.floor(seconds || 0));
+	const minutes = Math.floor(seconds / 60);
+	const rest = String(seconds % 60).padStart(2, "0");
+	if (minutes >= 60) {
+		return Math.floor(minutes / 60) + ":" + String(minutes % 60).padStart(2, "0") + ":" + rest;
+	}
+	return minutes + ":" + rest;
+}
+
+function setPage(...children) {
+	const app = document.getElementById("app");
+	app.replaceChildren(...children);
+}
+
+function leavePage() {
+	state.timers.forEach(clearInterval);
+	state.timers = [];
+	if (state.socket) {
+		state.socket.onclose = null;
+		state.socket.close();
+		state.socket = null;
+	}
+}
+
+//
+// Account
+//
+
+async function loadAccount() {
+	try {
+		state.me = await api("GET", "/auth/me");
+	} catch (err) {
+		if (err.status === 401) {
+			state.me = null;
+			return;
+		}
+		throw err;
+	}
+	state.user = await api("GET", "/api/v0/user/" + state.me.userID).catch(() => null);
+}
+
+function renderAccount() {
+	const account = document.getElementById("account");
+	if (!state.me) {
+		account.replaceChildren();
+		return;
+	}
+	const name = state.user ? state.user.username : state.me.userID;
+	const avatar = state.user && state.user.avatar ? "https://cdn.discordapp.com/avatars/" + state.user.id + "/" + state.user.avatar + ".png?size=64" : null;
+	account.replaceChildren(
+		avatar ? el("img", {class: "avatar", src: avatar, alt: ""}) : null,
+		el("span", {}, name),
+		el("a", {href: "#/me"}, "Your settings"),
+		el("button", {onclick: logout}, "Log out"),
+	);
+}
+
+async function logout() {
+	await api("POST", "/auth/logout").catch(() => null);
+	state.me = null;
+	state.user = null;
+	state.guilds = null;
+	renderAccount();
+	location.hash = "#/";
+	route();
+}
+
+function renderLogin() {
+	setPage(el("div", {class: "card"},
+		el("h1", {}, "Clinet Dashboard"),
+		el("p", {}, "Log in with Discord to manage your servers, the music queue, your reminders and more."),
+		el("a", {class: "button primary", href: "/auth/login?redirect=" + encodeURIComponent("/dashboard")}, "Log in with Discord"),
+	));
+}
+
+//
+// Guild picker
+//
+
+async function renderGuildPicker() {
+	if (!state.guilds) {
+		state.guilds = await api("GET", "/api/v0/user/" + state.me.userID + "/guilds");
+	}
+	if (state.guilds.length === 0) {
+		setPage(el("h1", {}, "Your servers"), el("p", {class: "muted"}, "You don't share any servers with the bot yet."));
+		return;
+	}
+	setPage(
+		el("h1", {}, "Your servers"),
+		el("div", {class: "guilds"}, state.guilds.map((guild) => el("a", {class: "guild", href: "#/guild/" + guild.id + "/" + (guild.manager ? "settings" : "voice")},
+			guild.iconURL ? el("img", {src: guild.iconURL, alt: ""}) : el("span", {class: "icon"}, guild.name.charAt(0)),
+			el("div", {},
+				el("div", {}, guild.name),
+				el("div", {class: "muted"}, guild.manager ? "Manager" : "Member"),
+			),
+		))),
+	);
+}
+
+async function renderGuild(guildID, tabID) {
+	if (!state.guilds) {
+		state.guilds = await api("GET", "/api/v0/user/" + state.me.userID + "/guilds");
+	}
+	const guild = state.guilds.find((guild) => guild.id === guildID);
+	if (!guild) {
+		setPage(el("p", {}, "You aren't in that server, or the bot isn't."), el("a", {href: "#/"}, "Back to your servers"));
+		return;
+	}
+
+	const tabs = guildTabs.filter((tab) => guild.manager || !tab.manager);
+	const tab = tabs.find((tab) => tab.id === tabID) || tabs[0];
+	const content = el("div", {}, el("p", {class: "muted"}, "Loading..."));
+	setPage(
+		el("h1", {}, guild.name),
+		el("nav", {class: "tabs"}, tabs.map((other) => el("a", {href: "#/guild/" + guild.id + "/" + other.id, class: other.id === tab.id ? "active" : ""}, other.name))),
+		content,
+	);
+
+	const renderers = {settings: renderGuildSettings, starboard: renderStarboard, feeds: renderFeeds, voice: renderVoice, reminders: renderReminders};
+	await renderers[tab.id](guild, content);
+}
+
+//
+// Settings forms, built from the layout endpoints
+//
+
+//guildOptions loads the channels and roles of a guild for picking them in forms
+async function guildOptions(guildID) {
+	const [channels, guild] = await Promise.all([
+		api("GET", "/api/v0/guild/" + guildID + "/channels"),
+		api("GET", "/api/v0/guild/" + guildID),
+	]);
+	const roles = (guild.roles || []).filter((role) => role.id !== guildID && !role.managed).sort((a, b) => b.position - a.position);
+	return {channels: channels, roles: roles};
+}
+
+//setPath sets the value at a dotted path within an object, creating objects along the way
+function setPath(object, path, value) {
+	const names = path.split(".");
+	let current = object;
+	for (const name of names.slice(0, -1)) {
+		current[name] = current[name] || {};
+		current = current[name];
+	}
+	current[names[names.length - 1]] = value;
+}
+
+//SettingsForm renders a form for every setting in a layout and keeps track of which ones changed
+class SettingsForm {
+	constructor(layout, values, options) {
+		this.layout = layout;
+		this.options = options || {channels: [], roles: []};
+		this.changes = {};
+		this.errors = {};
+		this.root = el("form", {onsubmit: (event) => event.preventDefault()});
+		this.root.append(...this.renderFields(layout.fields, values || {}));
+	}
+
+	renderFields(fields, values) {
+		const elements = [];
+		let section = "";
+		for (const field of fields) {
+			if (field.section && field.section !== section) {
+				elements.push(el("h3", {class: "section-heading"}, field.section));
+			}
+			section = field.section || "";
+			elements.push(this.renderField(field, values[field.name]));
+		}
+		return elements;
+	}
+
+	renderField(field, value) {
+		if (field.type === "object" && field.fields) {
+			return el("fieldset", {}, el("legend", {}, field.name), field.description ? el("p", {class: "muted"}, field.description) : null, this.renderFields(field.fields, value || {}));
+		}
+
+		const id = "setting-" + (field.path || field.name).replace(/\./g, "-");
+		const error = el("div", {class: "error", hidden: true});
+		if (field.path) {
+			this.errors[field.path] = error;
+		}
+		return el("div", {class: "field"},
+			el("label", {for: id}, field.name),
+			field.description ? el("p", {class: "description"}, field.description) : null,
+			this.renderInput(field, value, id),
+			error,
+		);
+	}
+
+	change(field, value) {
+		this.changes[field.path] = value;
+	}
+
+	renderInput(field, value, id) {
+		const disabled = field.readOnly || !field.path;
+		if (field.type === "boolean" && !field.nullable) {
+			return el("input", {id: id, type: "checkbox", checked: !!value, disabled: disabled, onchange: (event) => this.change(field, event.target.checked)});
+		}
+		if (field.type === "boolean") {
+			const current = value === null || value === undefined ? "" : String(value);
+			return el("select", {id: id, disabled: disabled, onchange: (event) => this.change(field, event.target.value === "" ? null : event.target.value === "true")},
+				[["", "Default"], ["true", "On"], ["false", "Off"]].map(([optionValue, label]) => el("option", {value: optionValue, selected: optionValue === current}, label)));
+		}
+		if (field.format === "channel") {
+			const channels = this.options.channels.filter((channel) => channel.type === "text" || channel.type === "news");
+			return el("select", {id: id, disabled: disabled, onchange: (event) => this.change(field, event.target.value)},
+				el("option", {value: ""}, "None"),
+				channels.map((channel) => el("option", {value: channel.id, selected: channel.id === value}, "#" + channel.name)));
+		}
+		if (field.type === "list" && field.format === "role") {
+			const selected = new Set(value || []);
+			return el("select", {id: id, multiple: true, disabled: disabled, onchange: (event) => this.change(field, Array.from(event.target.selectedOptions, (option) => option.value))},
+				this.options.roles.map((role) => el("option", {value: role.id, selected: selected.has(role.id)}, "@" + role.name)));
+		}
+		if (field.type === "list" && field.items && (field.items.type === "string" || field.items.type === "integer")) {
+			const numeric = field.items.type === "integer";
+			return el("textarea", {id: id, disabled: disabled, placeholder: "One per line", value: (value || []).join("\n"), onchange: (event) => {
+				const items = event.target.value.split("\n").map((item) => item.trim()).filter((item) => item !== "");
+				this.change(field, numeric ? items.map(Number) : items);
+			}});
+		}
+		if (field.values && field.values.length > 0 && field.type !== "list") {
+			return el("select", {id: id, disabled: disabled, onchange: (event) => this.change(field, field.values[event.target.selectedIndex])},
+				field.values.map((allowed) => el("option", {selected: allowed === value}, String(allowed))));
+		}
+		if (field.type === "integer" || field.type === "number") {
+			return el("input", {id: id, type: "number", step: field.type === "integer" ? "1" : "any", disabled: disabled, value: value === null || value === undefined ? "" : value, onchange: (event) => this.change(field, event.target.value === "" && field.nullable ? null : Number(event.target.value))});
+		}
+		if (field.type === "string") {
+			const secret = field.format === "secret";
+			return el("input", {id: id, type: secret ? "password" : "text", disabled: disabled, autocomplete: "off",
+				value: secret ? "" : (value || ""), placeholder: secret && value ? "Unchanged" : "",
+				onchange: (event) => this.change(field, event.target.value)});
+		}
+		return el("input", {id: id, type: "text", disabled: true, value: value === null || value === undefined ? "" : JSON.stringify(value)});
+	}
+
+	//body returns the changed settings as the nested object the API expects
+	body() {
+		const body = {};
+		for (const [path, value] of Object.entries(this.changes)) {
+			setPath(body, path, value);
+		}
+		return body;
+	}
+
+	showErrors(fields) {
+		for (const error of Object.values(this.errors)) {
+			error.hidden = true;
+		}
+		for (const field of fields) {
+			const error = this.errors[field.field];
+			if (error) {
+				error.textContent = field.error;
+				error.hidden = false;
+			} else {
+				toast(field.field + ": " + field.error, true);
+			}
+		}
+	}
+
+	//save sends every changed setting to the endpoint, showing why any change was rejected next to it
+	async save(endpoint) {
+		if (Object.keys(this.changes).length === 0) {
+			toast("Nothing changed.");
+			return null;
+		}
+		try {
+			const saved = await api("PATCH", endpoint, this.body());
+			this.changes = {};
+			this.showErrors([]);
+			toast("Saved.");
+			return saved;
+		} catch (err) {
+			this.showErrors(err.fields);
+			toast(err.message, true);
+			return null;
+		}
+	}
+}
+
+async function renderGuildSettings(guild, content) {
+	const endpoint = "/api/v0/guild/" + guild.id + "/settings";
+	const [layout, settings, options] = await Promise.all([
+		api("GET", "/api/v0/layout/guild"),
+		api("GET", endpoint),
+		guildOptions(guild.id),
+	]);
+	const editable = layout.fields.filter((field) => field.name !== "feeds");
+	const form = new SettingsForm({fields: editable}, settings, options);
+	content.replaceChildren(
+		layout.description ? el("p", {class: "muted"}, layout.description) : null,
+		form.root,
+		el("div", {class: "actions"}, el("button", {class: "primary", onclick: () => form.save(endpoint)}, "Save changes")),
+	);
+}
+
+async function renderUserSettings() {
+	const endpoint = "/api/v0/user/" + state.me.userID + "/settings";
+	const [layout, settings] = await Promise.all([
+		api("GET", "/api/v0/layout/user"),
+		api("GET", endpoint).catch(() => ({})),
+	]);
+	const form = new SettingsForm(layout, settings);
+	setPage(
+		el("h1", {}, "Your settings"),
+		form.root,
+		el("div", {class: "actions"}, el("button", {class: "primary", onclick: () => form.save(endpoint)}, "Save changes")),
+	);
+}
+
+//
+// Starboard
+//
+
+async function renderStarboard(guild, content) {
+	const [layout, starboard, options] = await Promise.all([
+		api("GET", "/api/v0/layout/guild/starboard"),
+		api("GET", "/api/v0/guild/" + guild.id + "/starboard").catch((err) => {
+			if (err.code === "API_NOT_FOUND") {
+				return null;
+			}
+			throw err;
+		}),
+		guildOptions(guild.id),
+	]);
+	if (!starboard) {
+		content.replaceChildren(el("p", {class: "muted"}, "The starboard hasn't been set up in this server yet."));
+		return;
+	}
+
+	const channelNames = new Map(options.channels.map((channel) => [channel.id, "#" + channel.name]));
+	const entries = (starboard.StarboardEntries || []).slice().sort((a, b) => b.Stars - a.Stars);
+	const settingFields = layout.fields.filter((field) => field.name !== "StarboardEntries");
+	const form = new SettingsForm({fields: settingFields}, starboard, options);
+
+	content.replaceChildren(
+		el("div", {class: "card"},
+			el("h2", {}, "Entries"),
+			entries.length === 0 ? el("p", {class: "muted"}, "No messages have made it onto the starboard yet.") :
+				el("table", {},
+					el("thead", {}, el("tr", {}, el("th", {}, "Stars"), el("th", {}, "Channel"), el("th", {}, "Author"), el("th", {}, ""))),
+					el("tbody", {}, entries.map((entry) => el("tr", {},
+						el("td", {}, "⭐ " + entry.Stars),
+						el("td", {}, channelNames.get(entry.SourceChannelID) || entry.SourceChannelID),
+						el("td", {}, entry.SourceAuthorID || "Unknown"),
+						el("td", {}, el("a", {href: "https://discord.com/channels/" + guild.id + "/" + entry.SourceChannelID + "/" + entry.SourceMessageID, target: "_blank", rel: "noopener"}, "Jump to message")),
+					))),
+				),
+		),
+		el("h2", {}, "Starboard settings"),
+		el("p", {class: "muted"}, "These can be changed with the starboard command."),
+		form.root,
+	);
+}
+
+//
+// Reminders
+//
+
+async function renderReminders(guild, content) {
+	const endpoint = "/api/v0/guild/" + guild.id + "/reminders";
+	const reminders = await api("GET", endpoint);
+	if (reminders.length === 0) {
+		content.replaceChildren(el("p", {class: "muted"}, "You don't have any reminders in this server."));
+		return;
+	}
+
+	content.replaceChildren(el("table", {},
+		el("thead", {}, el("tr", {}, el("th", {}, "When"), el("th", {}, "Reminder"), el("th", {}, ""))),
+		el("tbody", {}, reminders.map((reminder, index) => el("tr", {},
+			el("td", {}, new Date(reminder.timeRemind).toLocaleString()),
+			el("td", {}, reminder.message),
+			el("td", {}, el("button", {class: "danger", onclick: async () => {
+				try {
+					await api("DELETE", endpoint + "/" + index);
+					toast("Deleted the reminder.");
+					renderReminders(guild, content);
+				} catch (err) {
+					toast(err.message, true);
+				}
+			}}, "Delete")),
+		))),
+	));
+}
+
+//
+// Feeds
+//
+
+async function renderFeeds(guild, content) {
+	const endpoint = "/api/v0/guild/" + guild.id + "/feeds";
+	const [feeds, options] = await Promise.all([api("GET", endpoint), guildOptions(guild.id)]);
+	const textChannels = options.channels.filter((channel) => channel.type === "text" || channel.type === "news");
+	const channelSelect = (selected) => el("select", {}, textChannels.map((channel) => el("option", {value: channel.id, selected: channel.id === selected}, "#" + channel.name)));
+
+	const request = async (method, path, body, message) => {
+		try {
+			await api(method, path, body);
+			toast(message);
+			renderFeeds(guild, content);
+		} catch (err) {
+			toast(err.message, true);
+		}
+	};
+
+	const newURL = el("input", {type: "url", placeholder: "https://example.com/feed.xml", required: true});
+	const newChannel = channelSelect("");
+	const newFrequency = el("input", {type: "number", min: "1", placeholder: "Default"});
+
+	content.replaceChildren(
+		el("div", {class: "card"},
+			el("h2", {}, "Add a feed"),
+			el("div", {class: "row"},
+				el("label", {}, "Feed URL", newURL),
+				el("label", {}, "Channel", newChannel),
+				el("label", {}, "Check every (seconds)", newFrequency),
+				el("button", {class: "primary", onclick: () => {
+					const body = {url: newURL.value.trim(), channelID: newChannel.value};
+					if (newFrequency.value !== "") {
+						body.frequency = Number(newFrequency.value);
+					}
+					request("POST", endpoint, body, "Added the feed.");
+				}}, "Add"),
+			),
+		),
+		feeds.length === 0 ? el("p", {class: "muted"}, "There are no feeds in this server.") :
+			el("table", {},
+				el("thead", {}, el("tr", {}, el("th", {}, "Feed"), el("th", {}, "Channel"), el("th", {}, "Check every (seconds)"), el("th", {}, ""))),
+				el("tbody", {}, feeds.map((feed, index) => {
+					const channel = channelSelect(feed.channelID);
+					const frequency = el("input", {type: "number", min: "1", value: feed.frequency});
+					return el("tr", {},
+						el("td", {}, el("a", {href: feed.link || feed.url, target: "_blank", rel: "noopener"}, feed.title || feed.url)),
+						el("td", {}, channel),
+						el("td", {}, frequency),
+						el("td", {}, el("div", {class: "actions"},
+							el("button", {onclick: () => request("PATCH", endpoint + "/" + index, {channelID: channel.value, frequency: Number(frequency.value)}, "Saved the feed.")}, "Save"),
+							el("button", {class: "danger", onclick: () => request("DELETE", endpoint + "/" + index, undefined, "Removed the feed.")}, "Remove"),
+						)),
+					);
+				})),
+			),
+	);
+}
+
+//
+// Voice
+//
+
+async function renderVoice(guild, content) {
+	const endpoint = "/api/v0/guild/" + guild.id + "/voice";
+	const live = el("span", {class: "live muted"}, "Not live");
+	const nowPlaying = el("div", {class: "card"});
+	const controls = el("div", {class: "card"});
+	const queue = el("div", {class: "card"});
+	content.replaceChildren(el("div", {class: "actions"}, live), nowPlaying, controls, queue);
+
+	let status = null;
+	let statusTime = 0;
+
+	const act = async (method, path, body) => {
+		try {
+			await api(method, endpoint + path, body);
+			await refresh();
+		} catch (err) {
+			toast(err.message, true);
+		}
+	};
+
+	const renderNowPlaying = () => {
+		const playing = status && status.nowPlaying;
+		if (!playing) {
+			nowPlaying.replaceChildren(el("h2", {}, "Now playing"), el("p", {class: "muted"}, status && status.connected ? "Nothing is playing." : "The bot isn't in a voice channel."));
+			return;
+		}
+		const entry = playing.entry;
+		let position = playing.position;
+		if (!playing.paused) {
+			position += (Date.now() - statusTime) / 1000;
+		}
+		position = entry.duration ? Math.min(position, entry.duration) : position;
+		const percent = entry.duration ? (position / entry.duration) * 100 : 0;
+		nowPlaying.replaceChildren(
+			el("h2", {}, "Now playing"),
+			el("div", {class: "nowplaying"},
+				entry.artworkURL || entry.thumbnailURL ? el("img", {src: entry.artworkURL || entry.thumbnailURL, alt: ""}) : null,
+				el("div", {style: "flex: 1"},
+					el("div", {}, el("a", {href: entry.url, target: "_blank", rel: "noopener"}, entry.title)),
+					el("div", {class: "muted"}, entry.artists.map((artist) => artist.name).join(", ") + (entry.service ? " on " + entry.service : "")),
+					el("div", {class: "progress"}, el("div", {style: "width: " + percent.toFixed(1) + "%"})),
+					el("div", {class: "muted"}, formatDuration(position) + " / " + formatDuration(entry.duration) + (playing.paused ? " (paused)" : "")),
+				),
+			),
+		);
+	};
+
+	const renderControls = () => {
+		const playing = status && status.nowPlaying;
+		const url = el("input", {type: "url", placeholder: "A link to a song, video or playlist"});
+		controls.replaceChildren(
+			el("div", {class: "row"},
+				url,
+				el("button", {class: "primary", onclick: () => url.value.trim() && act("POST", "/queue", {url: url.value.trim()})}, "Play"),
+			),
+			el("div", {class: "actions"},
+				el("button", {disabled: !playing, onclick: () => act("POST", playing && playing.paused ? "/resume" : "/pause")}, playing && playing.paused ? "Resume" : "Pause"),
+				el("button", {disabled: !playing, onclick: () => act("POST", "/skip")}, "Skip"),
+				el("button", {class: "danger", disabled: !playing, onclick: () => act("POST", "/stop")}, "Stop"),
+				el("label", {}, "Repeat ",
+					el("select", {style: "width: auto", onchange: (event) => act("PUT", "/repeat", {repeat: event.target.value})},
+						[["none", "Off"], ["queue", "Queue"], ["nowplaying", "Now playing"]].map(([value, label]) => el("option", {value: value, selected: status && status.repeat === value}, label))),
+				),
+				el("label", {}, el("input", {type: "checkbox", checked: status && status.shuffle, onchange: (event) => act("PUT", "/shuffle", {shuffle: event.target.checked})}), " Shuffle"),
+			),
+			el("p", {class: "muted"}, "Playing and controlling playback requires being in the bot's voice channel."),
+		);
+	};
+
+	const renderQueue = async () => {
+		const entries = await api("GET", endpoint + "/queue");
+		if (entries.length === 0) {
+			queue.replaceChildren(el("h2", {}, "Queue"), el("p", {class: "muted"}, "The queue is empty."));
+			return;
+		}
+		queue.replaceChildren(el("h2", {}, "Queue"), el("table", {},
+			el("tbody", {}, entries.map((entry, index) => el("tr", {},
+				el("td", {}, String(index + 1)),
+				el("td", {}, el("a", {href: entry.url, target: "_blank", rel: "noopener"}, entry.title), el("div", {class: "muted"}, entry.artists.map((artist) => artist.name).join(", "))),
+				el("td", {}, formatDuration(entry.duration)),
+				el("td", {}, el("div", {class: "actions"},
+					el("button", {disabled: index === 0, title: "Move up", onclick: () => act("POST", "/queue/" + index + "/move", {to: index - 1})}, "↑"),
+					el("button", {disabled: index === entries.length - 1, title: "Move down", onclick: () => act("POST", "/queue/" + index + "/move", {to: index + 1})}, "↓"),
+					el("button", {class: "danger", onclick: () => act("DELETE", "/queue/" + index)}, "Remove"),
+				)),
+			))),
+		));
+	};
+
+	const refresh = async () => {
+		status = await api("GET", endpoint);
+		statusTime = Date.now();
+		renderNowPlaying();
+		renderControls();
+		await renderQueue();
+	};
+
+	await refresh();
+	state.timers.push(setInterval(renderNowPlaying, 1000));
+
+	//Refresh whenever something changes instead of polling, falling back to polling if the event stream drops
+	const connect = () => {
+		const scheme = location.protocol === "https:" ? "wss://" : "ws://";
+		const socket = new WebSocket(scheme + location.host + "/ws?guild=" + guild.id + "&type=track.started,track.ended,queue.changed");
+		state.socket = socket;
+		socket.onopen = () => {
+			live.textContent = "Live";
+			live.classList.add("connected");
+		};
+		socket.onmessage = (message) => {
+			const event = JSON.parse(message.data);
+			if (event.type !== "hello") {
+				refresh().catch((err) => toast(err.message, true));
+			}
+		};
+		socket.onclose = () => {
+			live.textContent = "Reconnecting...";
+			live.classList.remove("connected");
+			state.socket = null;
+			setTimeout(() => {
+				if (location.hash === "#/guild/" + guild.id + "/voice") {
+					refresh().catch(() => null);
+					connect();
+				}
+			}, 5000);
+		};
+	};
+	connect();
+}
+
+//
+// Routing
+//
+
+async function route() {
+	leavePage();
+	const parts = location.hash.replace(/^#\/?/, "").split("/").filter((part) => part !== "");
+	try {
+		if (!state.me) {
+			renderLogin();
+			return;
+		}
+		if (parts[0] === "guild" && parts[1]) {
+			await renderGuild(parts[1], parts[2]);
+		} else if (parts[0] === "me") {
+			await renderUserSettings();
+		} else {
+			await renderGuildPicker();
+		}
+	} catch (err) {
+		if (err.status === 401) {
+			state.me = null;
+			renderAccount();
+			renderLogin();
+			return;
+		}
+		setPage(el("p", {class: "error"}, "Something went wrong: " + err.message), el("a", {href: "#/"}, "Back to your servers"));
+	}
+}
+
+async function start() {
+	try {
+		await loadAccount();
+	} catch (err) {
+		setPage(el("p", {}, "Couldn't reach the API: " + err.message));
+		return;
+	}
+	renderAccount();
+	window.addEventListener("hashchange", route);
+	route();
+}
+
+start();
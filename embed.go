@@ -292,15 +292,6 @@ func NewGenericEmbedAdvanced(embedTitle, embedMsg string, embedColor int) *disco
 	return genericEmbed
 }
 
-// NewErrorEmbed creates a new error embed
-func NewErrorEmbed(errorTitle, errorMsg string, replacements ...interface{}) *discordgo.MessageEmbed {
-	errorEmbed := NewEmbed().
-		SetTitle(errorTitle).
-		SetDescription(fmt.Sprintf(errorMsg, replacements...)).
-		SetColor(0xb40000).MessageEmbed
-	return errorEmbed
-}
-
 // NewErrorEmbedAdvanced creates a new error embed with a custom color
 func NewErrorEmbedAdvanced(errorTitle, errorMsg string, errorColor int) *discordgo.MessageEmbed {
 	errorEmbed := NewEmbed().
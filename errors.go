@@ -3,62 +3,469 @@ package main
 import (
 	"errors"
 	"fmt"
-	"hash"
-	"hash/fnv"
+	"net/http"
+	"sort"
+	"strings"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
 )
 
+// ErrorSeverity describes who an error is meant for and how loudly it should be logged
+type ErrorSeverity int
+
+const (
+	SeverityUser    ErrorSeverity = iota //The user made a mistake, nothing is wrong with the bot
+	SeverityWarning                      //Something outside of the bot failed, like Discord or an external service
+	SeverityError                        //Something is wrong with the bot itself
+)
+
+var errorSeverityNames = map[ErrorSeverity]string{
+	SeverityUser:    "user",
+	SeverityWarning: "warning",
+	SeverityError:   "error",
+}
+
+func (severity ErrorSeverity) String() string {
+	return errorSeverityNames[severity]
+}
+
+// MarshalText encodes the severity by name, so the error catalog reads the same everywhere it's served
+func (severity ErrorSeverity) MarshalText() ([]byte, error) {
+	return []byte(severity.String()), nil
+}
+
+// ErrorDefinition describes an error code in the error catalog
+type ErrorDefinition struct {
+	Code     string            `json:"code"`     //The stable code users are shown and support looks up, ex: VOICE_NOT_CONNECTED
+	Title    string            `json:"title"`    //The title of error embeds, ex: Voice Error
+	Severity ErrorSeverity     `json:"severity"` //Who the error is meant for
+	Messages map[string]string `json:"messages"` //The user-facing message templates in fmt syntax, where key = language code and en is the fallback
+}
+
+// Message returns the message template of the definition in the given Discord locale, falling back to its base language and then to English
+func (definition *ErrorDefinition) Message(locale string) string {
+	if message, exists := definition.Messages[locale]; exists {
+		return message
+	}
+	if message, exists := definition.Messages[strings.SplitN(locale, "-", 2)[0]]; exists {
+		return message
+	}
+	return definition.Messages["en"]
+}
+
+// errorCatalog holds every error code the bot can report, where key = code
+// Codes must never change once released, as users report them and support looks them up by code
+var errorCatalog = make(map[string]*ErrorDefinition)
+
+// defineError adds an error code to the error catalog with its English message template
+func defineError(code, title string, severity ErrorSeverity, message string) string {
+	errorCatalog[code] = &ErrorDefinition{Code: code, Title: title, Severity: severity, Messages: map[string]string{"en": message}}
+	return code
+}
+
+// Error codes
+var (
+	errCodeInternal = defineError("INTERNAL", "Internal Error", SeverityError, "Something went wrong on our end.")
+
+	errCodeCommandInternal         = defineError("COMMAND_INTERNAL", "Command Error - Internal Error (IE)", SeverityError, "Something went wrong while running this command. If it keeps happening, give the bot owner this reference ID: ``%s``")
+	errCodeCommandNotAuthorized    = defineError("COMMAND_NOT_AUTHORIZED", "Command Error - Not Authorized (NA)", SeverityUser, "I'm sorry Dave, I'm afraid I can't do that.")
+	errCodeCommandFeatureOff       = defineError("COMMAND_FEATURE_DISABLED", "Command Error - Feature Disabled (FD)", SeverityUser, "The feature ``%s`` is disabled in this server.")
+	errCodeCommandNoPermissions    = defineError("COMMAND_NO_PERMISSIONS", "Command Error - No Permissions (NP)", SeverityUser, "Just what do you think you're doing, Dave?")
+	errCodeCommandInvalidNumber    = defineError("COMMAND_INVALID_NUMBER", "Command Error", SeverityUser, "``%s`` is not a valid number.")
+	errCodeCommandInvalidPage      = defineError("COMMAND_INVALID_PAGE", "Command Error", SeverityUser, "Invalid page number ``%s``.")
+	errCodeCommandPageNotFound     = defineError("COMMAND_PAGE_NOT_FOUND", "Command Error", SeverityUser, "Page ``%d`` doesn't exist.")
+	errCodeCommandUnknownAction    = defineError("COMMAND_UNKNOWN_ACTION", "Command Error", SeverityUser, "Unknown command ``%s``.")
+	errCodeCommandUnknownSetting   = defineError("COMMAND_UNKNOWN_SETTING", "Command Error", SeverityUser, "Error finding the setting ``%s``.")
+	errCodeCommandUnknownArgument  = defineError("COMMAND_UNKNOWN_ARGUMENT", "Command Error", SeverityUser, "Unknown argument ``%s``.")
+	errCodeCommandMixedArguments   = defineError("COMMAND_MIXED_ARGUMENTS", "Command Error", SeverityUser, "You cannot mix arguments dictating what to do!")
+	errCodeCommandInvalidUser      = defineError("COMMAND_INVALID_USER", "Command Error", SeverityUser, "Invalid user ``%s``.")
+	errCodeTimezoneUnset           = defineError("TIMEZONE_NOT_SET", "Timezone Error", SeverityUser, "Please set a timezone first!\n\nEx: ``%suser timezone America/New_York``")
+	errCodeTimezoneInvalid         = defineError("TIMEZONE_INVALID", "Timezone Error", SeverityUser, "You have an invalid timezone set, please set a new one first!\n\nEx: ``%suser timezone America/New_York``")
+	errCodeVoiceUserNotInChannel   = defineError("VOICE_USER_NOT_IN_CHANNEL", "Voice Error", SeverityUser, "You must join the voice channel to use before using the %s command.")
+	errCodeVoiceUserWrongChannel   = defineError("VOICE_USER_WRONG_CHANNEL", "Voice Error", SeverityUser, "You must join the voice channel %s is in before using the %s command.")
+	errCodeVoiceBotNotInChannel    = defineError("VOICE_BOT_NOT_IN_CHANNEL", "Voice Error", SeverityUser, "%s is not currently in a voice channel.")
+	errCodeVoiceQueryFailed        = defineError("VOICE_QUERY_FAILED", "Voice Error", SeverityWarning, "There was an error getting a result for the specified query.")
+	errCodeVoiceAttachmentFailed   = defineError("VOICE_ATTACHMENT_FAILED", "Voice Error", SeverityWarning, "Error finding audio info for attachment %d.")
+	errCodeVoiceNoService          = defineError("VOICE_NO_SERVICE", "Voice Error", SeverityUser, "There was an error finding a service to handle the specified URL.")
+	errCodeVoiceServiceDisabled    = defineError("VOICE_SERVICE_DISABLED", "Voice Error", SeverityUser, "%s is disabled in this server.")
+	errCodeVoiceNoRequester        = defineError("VOICE_NO_REQUESTER", "Voice Error", SeverityError, "There was an error figuring out who requested the track.")
+	errCodeVoiceNothingToPlay      = defineError("VOICE_NOTHING_TO_PLAY", "Voice Error", SeverityUser, "Could not find any audio to play.")
+	errCodeVoiceStopFailed         = defineError("VOICE_STOP_FAILED", "Voice Error", SeverityError, "There was an error stopping the audio playback.")
+	errCodeVoiceSkipFailed         = defineError("VOICE_SKIP_FAILED", "Voice Error", SeverityError, "There was an error skipping the audio playback.")
+	errCodeVoiceLyricsFailed       = defineError("VOICE_LYRICS_FAILED", "Lyrics Error", SeverityWarning, "There was an error fetching the lyrics for the current track.")
+	errCodeVoiceVolumeRange        = defineError("VOICE_VOLUME_OUT_OF_RANGE", "Volume Error", SeverityUser, "You must specify a volume level from 0 to 100, with 100 being normal volume.")
+	errCodeVoiceResultFailed       = defineError("VOICE_RESULT_FAILED", "Voice Error", SeverityWarning, "There was an error getting info for the result.")
+	errCodeVoiceResultNumberFailed = defineError("VOICE_RESULT_NUMBER_FAILED", "Voice Error", SeverityWarning, "There was an error getting info for result %d.")
+	errCodeVoiceResumeFailed       = defineError("VOICE_RESUME_FAILED", "Voice Error", SeverityWarning, "Unable to resume your audio playback, you may need to play it again.")
+	errCodeQueueEmpty              = defineError("QUEUE_EMPTY", "Queue Error", SeverityUser, "There are no entries in the queue to clear.")
+	errCodeQueueRemoveMissing      = defineError("QUEUE_REMOVE_MISSING", "Queue Error", SeverityUser, "You must specify which queue entries to remove.")
+	errCodeQueueEntryInvalid       = defineError("QUEUE_ENTRY_INVALID", "Queue Error", SeverityUser, "``%s`` is not a valid queue entry.")
+	errCodeQueueCopyMissing        = defineError("QUEUE_COPY_MISSING", "Queue Error", SeverityUser, "You must specify which guild queue(s) to copy.")
+	errCodeQueueGuildUnknown       = defineError("QUEUE_GUILD_UNKNOWN", "Queue Error", SeverityUser, "The guild ID ``%s`` does not point to a known guild.")
+	errCodeSearchQueryMissing      = defineError("SEARCH_QUERY_MISSING", "Search Error", SeverityUser, "You must enter a search query to use before using the %s command.")
+	errCodeSearchNoSession         = defineError("SEARCH_NO_SESSION", "Search Error", SeverityUser, "No search session is in progress.")
+	errCodeSearchNextFailed        = defineError("SEARCH_NEXT_PAGE_FAILED", "Search Error", SeverityWarning, "There was an error finding the next page.")
+	errCodeSearchPrevFailed        = defineError("SEARCH_PREVIOUS_PAGE_FAILED", "Search Error", SeverityWarning, "There was an error finding the previous page.")
+	errCodeSearchJumpFailed        = defineError("SEARCH_JUMP_FAILED", "Search Error", SeverityWarning, "There was an error finding page ``%s``.")
+	errCodeSearchSelectionMissing  = defineError("SEARCH_SELECTION_MISSING", "Search Error", SeverityUser, "You must specify which search result to select.")
+	errCodeSearchSelectionInvalid  = defineError("SEARCH_SELECTION_INVALID", "Search Error", SeverityUser, "An invalid selection was specified.")
+	errCodeSearchNoResults         = defineError("SEARCH_NO_RESULTS", "Search Error", SeverityUser, "No search results were found.")
+	errCodeSpotifyPlaylistMissing  = defineError("SPOTIFY_PLAYLIST_MISSING", "Spotify Error", SeverityUser, "You must enter a playlist URL to use before using the %s command.")
+	errCodeSpotifyPlaylistFailed   = defineError("SPOTIFY_PLAYLIST_FAILED", "Spotify Error", SeverityWarning, "There was an error getting a result for the specified playlist.")
+	errCodeSpotifyResultFailed     = defineError("SPOTIFY_RESULT_FAILED", "Spotify Error", SeverityWarning, "Error fetching info for the specified result.")
+	errCodeQueryNoService          = defineError("QUERY_NO_SERVICE", "Query Error", SeverityUser, "We couldn't find a service to handle your query.\nMake sure you're using proper grammar and query structure where applicable.")
+
+	errCodeFeedExists           = defineError("FEED_EXISTS", "Feed Error", SeverityUser, "Feed ``%s`` already exists.")
+	errCodeFeedFrequency        = defineError("FEED_FREQUENCY_TOO_LOW", "Feed Error", SeverityUser, "Frequency must not be lower than %d seconds.")
+	errCodeFeedFetchFailed      = defineError("FEED_FETCH_FAILED", "Feed Error", SeverityWarning, "There was an error fetching the feed ``%s``.")
+	errCodeFeedAddMissing       = defineError("FEED_ADD_MISSING", "Feed Error", SeverityUser, "You must specify a feed to add when using the ``-add`` argument.")
+	errCodeFeedURLInvalid       = defineError("FEED_URL_INVALID", "Feed Error", SeverityUser, "``%s`` is not a valid URL.")
+	errCodeFeedFrequencyMissing = defineError("FEED_FREQUENCY_MISSING", "Feed Error", SeverityUser, "You must specify a post check frequency to use when using the ``-%s`` argument.")
+	errCodeFeedEditMissing      = defineError("FEED_EDIT_MISSING", "Feed Error", SeverityUser, "You must specify a feed entry to edit when using the ``-edit`` argument.")
+	errCodeFeedRemoveMissing    = defineError("FEED_REMOVE_MISSING", "Feed Error", SeverityUser, "You must specify a feed entry to remove when using the ``-remove`` argument.")
+	errCodeFeedEntryInvalid     = defineError("FEED_ENTRY_INVALID", "Feed Error", SeverityUser, "``%s`` is not a valid feed entry.")
+
+	errCodeWebhookKeyInvalid     = defineError("WEBHOOK_KEY_INVALID", "Webhook Error", SeverityUser, "Invalid webhook key.")
+	errCodeWebhookRateLimited    = defineError("WEBHOOK_RATE_LIMITED", "Webhook Error", SeverityUser, "This key may only post %d messages per minute.")
+	errCodeWebhookChannelUnknown = defineError("WEBHOOK_CHANNEL_UNKNOWN", "Webhook Error", SeverityUser, "Unknown webhook channel ``%s``.")
+	errCodeWebhookAliasInvalid   = defineError("WEBHOOK_ALIAS_INVALID", "Webhook Error", SeverityUser, "Channel aliases must be 1 to 32 lowercase letters, numbers, dashes or underscores.")
+	errCodeWebhookEmpty          = defineError("WEBHOOK_EMPTY", "Webhook Error", SeverityUser, "A message needs content or an embed.")
+	errCodeWebhookTooLong        = defineError("WEBHOOK_TOO_LONG", "Webhook Error", SeverityUser, "Messages must not be longer than %d characters.")
+	errCodeWebhookPostFailed     = defineError("WEBHOOK_POST_FAILED", "Webhook Error", SeverityWarning, "There was an error posting the message.")
+	errCodeWebhookAliasMissing   = defineError("WEBHOOK_ALIAS_MISSING", "Webhook Error", SeverityUser, "You must specify the alias of the webhook channel to remove.")
+	errCodeWebhookKeyMissing     = defineError("WEBHOOK_KEY_MISSING", "Webhook Error", SeverityUser, "You must specify the ID of an existing webhook key to revoke.")
+
+	errCodeSettingUnknown             = defineError("SETTING_UNKNOWN", "Settings Error", SeverityUser, "Unknown setting.")
+	errCodeSettingReadOnly            = defineError("SETTING_READ_ONLY", "Settings Error", SeverityUser, "This setting can't be changed.")
+	errCodeSettingType                = defineError("SETTING_INVALID_TYPE", "Settings Error", SeverityUser, "Expected %s.")
+	errCodeSettingNegative            = defineError("SETTING_NEGATIVE", "Settings Error", SeverityUser, "Must not be negative.")
+	errCodeSettingID                  = defineError("SETTING_INVALID_ID", "Settings Error", SeverityUser, "``%s`` isn't a valid Discord ID.")
+	errCodeSettingChannel             = defineError("SETTING_UNKNOWN_CHANNEL", "Settings Error", SeverityUser, "``%s`` isn't a channel in this server.")
+	errCodeSettingRole                = defineError("SETTING_UNKNOWN_ROLE", "Settings Error", SeverityUser, "``%s`` isn't a role in this server.")
+	errCodeSettingPrefix              = defineError("SETTING_INVALID_PREFIX", "Bot Settings - Command Prefix Error", SeverityUser, "The command prefix must not contain spaces.")
+	errCodeSettingTimezone            = defineError("SETTING_INVALID_TIMEZONE", "User Settings - Timezone Error", SeverityUser, "Invalid timezone ``%s``.")
+	errCodeSettingSwitchFC            = defineError("SETTING_INVALID_SWITCH_FC", "User Settings - Socials Error", SeverityUser, "Invalid Switch friend code.")
+	errCodeSettingNNIDUnchecked       = defineError("SETTING_NNID_UNCHECKED", "User Settings - Socials Error", SeverityWarning, "There was an error checking if that NNID exists.")
+	errCodeSettingNNIDUnknown         = defineError("SETTING_NNID_UNKNOWN", "User Settings - Socials Error", SeverityUser, "That NNID doesn't exist!")
+	errCodeSettingFilterWordEmpty     = defineError("SETTING_FILTER_WORD_EMPTY", "Server Settings - Swear Filter Error", SeverityUser, "Filtered words must not be empty.")
+	errCodeSettingFilterWordDuplicate = defineError("SETTING_FILTER_WORD_DUPLICATE", "Server Settings - Swear Filter Error", SeverityUser, "``%s`` is already in the filter.")
+	errCodeSettingFeatureUnknown      = defineError("SETTING_FEATURE_UNKNOWN", "Bot Settings - Features Error", SeverityUser, "Unknown feature ``%s``. Use ``%sbot feature list`` to see all features.")
+	errCodeSettingFeatureDisabled     = defineError("SETTING_FEATURE_DISABLED", "Bot Settings - Features Error", SeverityUser, "The feature ``%s`` is disabled by the bot and can't be enabled in this server.")
+	errCodeSettingAboutMeMissing      = defineError("SETTING_ABOUTME_MISSING", "User Settings - About Me Error", SeverityUser, "You must specify an aboutme to view it.")
+	errCodeSettingTimezoneMissing     = defineError("SETTING_TIMEZONE_MISSING", "User Settings - Timezone Error", SeverityUser, "You must specify a timezone to view it.")
+	errCodeSettingSocialMissing       = defineError("SETTING_SOCIAL_MISSING", "User Settings - Socials Error", SeverityUser, "You must specify a social identifier to set it.")
+	errCodeSettingSocialUnknown       = defineError("SETTING_SOCIAL_UNKNOWN", "User Settings - Socials Error", SeverityUser, "Unknown social ``%s``.")
+	errCodeSettingSocialAlreadySet    = defineError("SETTING_SOCIAL_ALREADY_SET", "User Settings - Socials Error", SeverityUser, "You have already set that %s.")
+	errCodeSettingSocialNotSet        = defineError("SETTING_SOCIAL_NOT_SET", "User Settings - Socials Error", SeverityUser, "You haven't set your %s.")
+	errCodeSettingAboutMeNotFound     = defineError("SETTING_ABOUTME_NOT_FOUND", "About Me - Error", SeverityUser, "Error finding the aboutme for <@!%s>.")
+	errCodeSettingAboutMeUserNotFound = defineError("SETTING_ABOUTME_USER_NOT_FOUND", "About Me - Error", SeverityUser, "Error finding the user <@!%s>.")
+	errCodeSettingFilterAddMissing    = defineError("SETTING_FILTER_ADD_MISSING", "Server Settings - Swear Filter Error", SeverityUser, "You must specify one or more words to add to the filter.")
+	errCodeSettingFilterRemoveMissing = defineError("SETTING_FILTER_REMOVE_MISSING", "Server Settings - Swear Filter Error", SeverityUser, "You must specify one or more words to remove from the filter.")
+	errCodeSettingLogEnableFailed     = defineError("SETTING_LOG_ENABLE_FAILED", "Server Settings - Log Error", SeverityError, "Unable to enable all logging events.")
+	errCodeSettingResetMissing        = defineError("SETTING_RESET_MISSING", "Server Settings - Reset Error", SeverityUser, "You must specify a setting to reset.")
+
+	errCodeUserDataExportFailed      = defineError("USERDATA_EXPORT_FAILED", "User Data Error", SeverityWarning, "There was an error sending the data export to your DMs. Make sure you can receive direct messages from server members and try again.")
+	errCodeUserDataDeleteUnconfirmed = defineError("USERDATA_DELETE_UNCONFIRMED", "User Data", SeverityUser, "This will permanently delete your settings, balance, socials, reminders, starboard entries and active sessions across every server.\n\nServer-managed lists such as bot admins and starboard blacklists are controlled by server administrators and won't be changed.\n\nTo continue, use ``%suser data delete confirm``.")
+	errCodeUserDataPurgeUnconfirmed  = defineError("USERDATA_PURGE_UNCONFIRMED", "User Data", SeverityUser, "This will permanently delete the settings, balance, socials, reminders, starboard entries and active sessions of <@!%s> across every server.\n\nTo continue, use ``%suserdata %s %s confirm``.")
+
+	errCodeReloadRejected        = defineError("RELOAD_REJECTED", "Reload Error", SeverityWarning, "The bot configuration was rejected and the running configuration was left untouched:%s")
+	errCodeUpdateGoMissing       = defineError("UPDATE_GO_MISSING", "Update Error", SeverityError, "Unable to execute ``go version``. Make sure Go [%s] is installed on the host machine.\n\n%s\n```%v```")
+	errCodeUpdateGovvvMissing    = defineError("UPDATE_GOVVV_MISSING", "Update Error", SeverityError, "Unable to execute ``govvv``. Make sure govvv is installed on the host machine.```%s```")
+	errCodeUpdateTempDirFailed   = defineError("UPDATE_TEMP_DIR_FAILED", "Update Error", SeverityError, "Error creating a temporary directory to store the Clinet git repository in.")
+	errCodeUpdateCloneFailed     = defineError("UPDATE_CLONE_FAILED", "Update Error", SeverityWarning, "Error cloning the git repo.")
+	errCodeUpdateHeadFailed      = defineError("UPDATE_HEAD_FAILED", "Update Error", SeverityWarning, "Error finding the HEAD of the git repo.")
+	errCodeUpdateCommitFailed    = defineError("UPDATE_COMMIT_FAILED", "Update Error", SeverityWarning, "Error fetching the HEAD commit of the git repo.")
+	errCodeUpdateBuildFailed     = defineError("UPDATE_BUILD_FAILED", "Update Error", SeverityError, "Unable to build %s ``%s``.\n\n```%s```")
+	errCodeUpdateBuildMissing    = defineError("UPDATE_BUILD_MISSING", "Update Error", SeverityError, "Unable to find the updated build of %s ``%s``.\n\n```%v```")
+	errCodeUpdateVerifyFailed    = defineError("UPDATE_VERIFY_FAILED", "Update Error", SeverityError, "The updated build of %s ``%s`` failed verification and was not installed.\n\n```%v```")
+	errCodeUpdateInstallFailed   = defineError("UPDATE_INSTALL_FAILED", "Update Error", SeverityError, "Unable to install the updated build of %s ``%s``.\n\n```%v```")
+	errCodeUpdateSpawnFailed     = defineError("UPDATE_SPAWN_FAILED", "Update Error", SeverityError, "Unable to spawn the updated bot process.")
+	errCodeUpdateRolledBack      = defineError("UPDATE_ROLLED_BACK", "Update Error", SeverityWarning, "The update kept crashing, so %s was rolled back to ``%s``.")
+	errCodeRollbackFailed        = defineError("ROLLBACK_FAILED", "Rollback Error", SeverityError, "Unable to roll back %s.\n\n```%v```")
+	errCodeRollbackSpawnFailed   = defineError("ROLLBACK_SPAWN_FAILED", "Rollback Error", SeverityError, "Unable to spawn the rolled back bot process.")
+	errCodeSudoNotMember         = defineError("SUDO_NOT_MEMBER", "Sudo Error", SeverityUser, "Specified user does not exist in current guild.")
+	errCodeStatusURLMissing      = defineError("STATUS_URL_MISSING", "Status Error", SeverityUser, "You must specify the URL of the stream before the status message!")
+	errCodeStatusTypeUnknown     = defineError("STATUS_TYPE_UNKNOWN", "Status Error", SeverityUser, "Unknown status type: %s")
+	errCodeStatusFailed          = defineError("STATUS_FAILED", "Status Error", SeverityWarning, "There was an error setting the new status!")
+	errCodeHelpInvalid           = defineError("HELP_INVALID", "Help Error", SeverityUser, "Invalid command or page number.")
+	errCodeDebugLogLevelUnknown  = defineError("DEBUG_LOG_LEVEL_UNKNOWN", "Debug Error", SeverityUser, "Unknown log level ``%s``, must be debug, info, warning or error.")
+	errCodeDebugSubsystemUnknown = defineError("DEBUG_SUBSYSTEM_UNKNOWN", "Debug Error", SeverityUser, "Unknown subsystem ``%s``, must be one of: %s")
+
+	errCodePurgeAmountRange      = defineError("PURGE_AMOUNT_OUT_OF_RANGE", "Purge Error", SeverityUser, "Amount of messages to purge must be between 1 and 100.")
+	errCodePurgeFetchFailed      = defineError("PURGE_FETCH_FAILED", "Purge Error", SeverityWarning, "An error occurred fetching the last %s messages.")
+	errCodePurgeUserDeleteFailed = defineError("PURGE_USER_DELETE_FAILED", "Purge Error", SeverityWarning, "An error occurred deleting the last %s messages from the specified user(s).")
+	errCodePurgeDeleteFailed     = defineError("PURGE_DELETE_FAILED", "Purge Error", SeverityWarning, "An error occurred deleting the last %s messages.")
+	errCodeKickUsersMissing      = defineError("KICK_USERS_MISSING", "Kick Error", SeverityUser, "You must specify which user(s) to kick from the server.")
+	errCodeKickSelf              = defineError("KICK_SELF", "Kick Error", SeverityUser, "You can't kick yourself!")
+	errCodeKickFailed            = defineError("KICK_FAILED", "Kick Error", SeverityWarning, "An error occurred kicking <@%s>. Please consider manually kicking and report this issue to a developer.")
+	errCodeBanUsersMissing       = defineError("BAN_USERS_MISSING", "Ban Error", SeverityUser, "You must specify which user(s) to ban from the server.")
+	errCodeBanSelf               = defineError("BAN_SELF", "Ban Error", SeverityUser, "You can't ban yourself!")
+	errCodeBanDaysRange          = defineError("BAN_DAYS_OUT_OF_RANGE", "Ban Error", SeverityUser, "You may only delete up to and including 7 days worth of messages from these users.")
+	errCodeBanFailed             = defineError("BAN_FAILED", "Ban Error", SeverityWarning, "An error occurred banning <@%s>. Please consider manually banning and report this issue to a developer.")
+	errCodeHackBanDaysMissing    = defineError("HACKBAN_DAYS_MISSING", "HackBan Error", SeverityUser, "You must specify how many days of messages to delete if you use the ``-days`` argument.")
+	errCodeHackBanDaysInvalid    = defineError("HACKBAN_DAYS_INVALID", "HackBan Error", SeverityUser, "Invalid days ``%s``.")
+	errCodeHackBanIDMissing      = defineError("HACKBAN_ID_MISSING", "HackBan Error", SeverityUser, "You must specify the ID of the user to hackban if you use the ``-id`` argument.")
+	errCodeHackBanReasonMissing  = defineError("HACKBAN_REASON_MISSING", "HackBan Error", SeverityUser, "You must specify the reason for hackbanning if you use the ``-reason`` argument.")
+	errCodeHackBanUsersMissing   = defineError("HACKBAN_USERS_MISSING", "HackBan Error", SeverityUser, "You must specify which user IDs to hackban.")
+	errCodeHackBanFailed         = defineError("HACKBAN_FAILED", "HackBan Error", SeverityWarning, "There was an error hackbanning the following users:\n%s")
+
+	errCodeRoleMeValueMissing     = defineError("ROLEME_VALUE_MISSING", "RoleMe Error", SeverityUser, "You must supply a value to the %s argument.")
+	errCodeRoleMeRoleUnknown      = defineError("ROLEME_ROLE_UNKNOWN", "RoleMe Error", SeverityUser, "Error finding role %s.")
+	errCodeRoleMeChannelUnknown   = defineError("ROLEME_CHANNEL_UNKNOWN", "RoleMe Error", SeverityUser, "Error finding channel %s.")
+	errCodeRoleMeAddDuplicate     = defineError("ROLEME_ADD_DUPLICATE", "RoleMe Error", SeverityUser, "You cannot specify the same role to add twice.")
+	errCodeRoleMeAddConflict      = defineError("ROLEME_ADD_CONFLICT", "RoleMe Error", SeverityUser, "You cannot specify a role to add if the role is already specified to be removed.")
+	errCodeRoleMeRemoveDuplicate  = defineError("ROLEME_REMOVE_DUPLICATE", "RoleMe Error", SeverityUser, "You cannot specify the same role to remove twice.")
+	errCodeRoleMeRemoveConflict   = defineError("ROLEME_REMOVE_CONFLICT", "RoleMe Error", SeverityUser, "You cannot specify a role to remove if the role is already specified to be added.")
+	errCodeRoleMeChannelDuplicate = defineError("ROLEME_CHANNEL_DUPLICATE", "RoleMe Error", SeverityUser, "You cannot specify the same channel twice.")
+	errCodeRoleMeTriggerDuplicate = defineError("ROLEME_TRIGGER_DUPLICATE", "RoleMe Error", SeverityUser, "You cannot specify the same trigger twice.")
+	errCodeRoleMeDeleteDuplicate  = defineError("ROLEME_DELETE_DUPLICATE", "RoleMe Error", SeverityUser, "You cannot specify the same event to delete twice.")
+	errCodeRoleMeEntryInvalid     = defineError("ROLEME_ENTRY_INVALID", "RoleMe Error", SeverityUser, "Invalid entry number ``%s``.")
+	errCodeRoleMeEntryUnknown     = defineError("ROLEME_ENTRY_UNKNOWN", "RoleMe Error", SeverityUser, "Unknown entry number ``%s``.")
+	errCodeRoleMeRolesMissing     = defineError("ROLEME_ROLES_MISSING", "RoleMe Error", SeverityUser, "You must specify either one or more roles to add or one or more roles to remove.")
+	errCodeRoleMeTriggersMissing  = defineError("ROLEME_TRIGGERS_MISSING", "RoleMe Error", SeverityUser, "You must specify one or more triggers to trigger this roleme event.")
+	errCodeRoleMeTriggerExists    = defineError("ROLEME_TRIGGER_EXISTS", "RoleMe Error", SeverityUser, "The trigger ``%s`` already exists!")
+	errCodeRoleMeEditFailed       = defineError("ROLEME_EDIT_FAILED", "RoleMe Error", SeverityWarning, "There were some errors editing your roles. :c")
+
+	errCodeStarboardSetExpected     = defineError("STARBOARD_SET_EXPECTED", "Starboard Error", SeverityUser, "You must specify ``set`` instead of ``%s`` to set the current channel as the starboard channel.")
+	errCodeStarboardNSFWSetExpected = defineError("STARBOARD_NSFW_SET_EXPECTED", "Starboard Error", SeverityUser, "You must specify ``set`` instead of ``%s`` to set the current channel as the NSFW starboard channel.")
+	errCodeStarboardNSFWRequired    = defineError("STARBOARD_NSFW_REQUIRED", "Starboard Error", SeverityUser, "You must mark this channel as NSFW before you can use it as the NSFW starboard channel.")
+	errCodeStarboardCustomEmoji     = defineError("STARBOARD_CUSTOM_EMOJI", "Starboard Error", SeverityUser, "Custom emojis are not permitted at this time.")
+	errCodeStarboardToggleInvalid   = defineError("STARBOARD_TOGGLE_INVALID", "Starboard Error", SeverityUser, "Unknown value ``%s``. Please use either ``enable`` or ``disable``.")
+
+	errCodeRemindEntryInvalid = defineError("REMIND_ENTRY_INVALID", "Remind Error", SeverityUser, "``%s`` is not a valid remind entry.")
+	errCodeRemindTimeUnknown  = defineError("REMIND_TIME_UNKNOWN", "Remind Error", SeverityUser, "There was an error figuring out what time to remind you with this message at.")
+	errCodeRemindTimePassed   = defineError("REMIND_TIME_PASSED", "Remind Error", SeverityUser, "That time was %s!")
+
+	errCodeTransferUserMissing  = defineError("TRANSFER_USER_MISSING", "Transfer Error", SeverityUser, "You must specify a user to transfer credits to.")
+	errCodeTransferUserMultiple = defineError("TRANSFER_USER_MULTIPLE", "Transfer Error", SeverityUser, "You cannot specify more than one user to transfer credits to.")
+	errCodeTransferTooLow       = defineError("TRANSFER_TOO_LOW", "Transfer Error", SeverityUser, "You cannot transfer less than __$1__ in credits.")
+	errCodeTransferToBot        = defineError("TRANSFER_TO_BOT", "Transfer Error", SeverityUser, "You cannot transfer credits to a bot!")
+	errCodeTransferInsufficient = defineError("TRANSFER_INSUFFICIENT", "Transfer Error", SeverityUser, "You have insufficient credits to perform this transfer.")
+
+	errCodeImageFetchFailed   = defineError("IMAGE_FETCH_FAILED", "Image Error", SeverityWarning, "Unable to fetch attachment %d.")
+	errCodeImageDecodeFailed  = defineError("IMAGE_DECODE_FAILED", "Image Error", SeverityUser, "Unable to decode attachment %d as an image.")
+	errCodeImageEffectInvalid = defineError("IMAGE_EFFECT_INVALID", "Image Error", SeverityUser, "Invalid value ``%s`` for the ``%s`` effect.")
+	errCodeImageEffectUnknown = defineError("IMAGE_EFFECT_UNKNOWN", "Image Error", SeverityUser, "Unknown effect ``%s``.")
+	errCodeImageEncodeFailed  = defineError("IMAGE_ENCODE_FAILED", "Image Error", SeverityError, "Unable to encode processed image.")
+	errCodeImageUploadFailed  = defineError("IMAGE_UPLOAD_FAILED", "Image Error", SeverityWarning, "Unable to upload processed image %d.")
+	errCodeImageNotFound      = defineError("IMAGE_NOT_FOUND", "Image Error", SeverityUser, "Unable to find any attached images or any images in the past 100 messages.")
+
+	errCodeTranslateFailed          = defineError("TRANSLATE_FAILED", "Translate Error", SeverityWarning, "Failed to translate: %v")
+	errCodeTranslateMessageMissing  = defineError("TRANSLATE_MESSAGE_MISSING", "Translate Error", SeverityUser, "You must specify the message to translate!")
+	errCodeTranslateLanguageUnknown = defineError("TRANSLATE_LANGUAGE_UNKNOWN", "Translate Error", SeverityUser, "Unknown target language: %s")
+	errCodeScreenshotAddressInvalid = defineError("SCREENSHOT_ADDRESS_INVALID", "Screenshot Error", SeverityUser, "Unknown address format ``%s``.")
+	errCodeScreenshotUnreachable    = defineError("SCREENSHOT_UNREACHABLE", "Screenshot Error", SeverityWarning, "The website ``%s`` does not exist or is currently unreachable.")
+	errCodeScreenshotInvalid        = defineError("SCREENSHOT_INVALID", "Screenshot Error", SeverityWarning, "The API failed to respond with a valid screenshot.")
+	errCodeScreenshotUnexpected     = defineError("SCREENSHOT_UNEXPECTED_RESPONSE", "Screenshot Error", SeverityWarning, "The API failed to respond in an expected way.")
+	errCodeScreenshotProcessFailed  = defineError("SCREENSHOT_PROCESS_FAILED", "Screenshot Error", SeverityError, "Unexpected error processing screenshot.")
+	errCodeScreenshotUploadFailed   = defineError("SCREENSHOT_UPLOAD_FAILED", "Screenshot Error", SeverityWarning, "Unexpected error uploading screenshot.")
+
+	errCodeGitHubArgsMissing         = defineError("GITHUB_ARGS_MISSING", "GitHub Error", SeverityUser, "Not enough arguments. Type ``%shelp %s`` for command usage.")
+	errCodeGitHubTrendingTimeInvalid = defineError("GITHUB_TRENDING_TIME_INVALID", "GitHub Error", SeverityUser, "Invalid trending time ``%s``. Type ``%shelp %s`` for command usage.")
+	errCodeGitHubTrendingTypeInvalid = defineError("GITHUB_TRENDING_TYPE_INVALID", "GitHub Error", SeverityUser, "Invalid trending type ``%s``. Type ``%shelp %s`` for command usage.")
+	errCodeGitHubTrendingReposFailed = defineError("GITHUB_TRENDING_REPOS_FAILED", "GitHub Error", SeverityWarning, "There was an error fetching the trending repositories.")
+	errCodeGitHubTrendingDevsFailed  = defineError("GITHUB_TRENDING_DEVELOPERS_FAILED", "GitHub Error", SeverityWarning, "There was an error fetching the trending developers.")
+	errCodeGitHubUserFailed          = defineError("GITHUB_USER_FAILED", "GitHub Error", SeverityWarning, "There was an error fetching information about the specified user.")
+	errCodeGitHubRepoFailed          = defineError("GITHUB_REPO_FAILED", "GitHub Error", SeverityWarning, "There was an error fetching information about the specified repo.")
+	errCodeNNIDCheckFailed           = defineError("NNID_CHECK_FAILED", "NNID Error", SeverityWarning, "Error checking for user ``%s``.")
+	errCodeNNIDPIDFailed             = defineError("NNID_PID_FAILED", "NNID Error", SeverityWarning, "Error getting pid for user ``%s``.")
+	errCodeNNIDMiiFailed             = defineError("NNID_MII_FAILED", "NNID Error", SeverityWarning, "Error getting mii for user ``%s``.")
+	errCodeXKCDLatestFailed          = defineError("XKCD_LATEST_FAILED", "xkcd Error", SeverityWarning, "There was an error fetching the latest xkcd comic.")
+	errCodeXKCDLatestNumberFailed    = defineError("XKCD_LATEST_NUMBER_FAILED", "xkcd Error", SeverityWarning, "There was an error figuring out the latest xkcd comic number.")
+	errCodeXKCDRandomFailed          = defineError("XKCD_RANDOM_FAILED", "xkcd Error", SeverityWarning, "There was an error fetching a random xkcd comic.")
+	errCodeXKCDComicFailed           = defineError("XKCD_COMIC_FAILED", "xkcd Error", SeverityWarning, "There was an error fetching xkcd comic #%s.")
+	errCodeMinecraftUserUnknown      = defineError("MINECRAFT_USER_UNKNOWN", "Minecraft Error", SeverityUser, "Invalid or unknown username ``%s``.")
+	errCodeMinecraftServerUnknown    = defineError("MINECRAFT_SERVER_UNKNOWN", "Minecraft Error", SeverityUser, "Invalid or unknown server ``%s``.")
+	errCodeGeoIPFailed               = defineError("GEOIP_FAILED", "GeoIP Error", SeverityWarning, "There was an error with the GeoIP utility.")
+	errCodeGeoIPLookupFailed         = defineError("GEOIP_LOOKUP_FAILED", "GeoIP Error", SeverityUser, "%s")
+	errCodeUrbanDictionaryFailed     = defineError("URBAN_DICTIONARY_FAILED", "Urban Dictionary Error", SeverityWarning, "There was an error getting a result for that term.")
+	errCodeImgurFailed               = defineError("IMGUR_FAILED", "Imgur Error", SeverityWarning, "There was an error fetching information about the specified URL.")
+	errCodeCVEFailed                 = defineError("CVE_FAILED", "CVE Error", SeverityWarning, "There was an error fetching information about CVE ``%s``.")
+	errCodeNLPDocumentFailed         = defineError("NLP_DOCUMENT_FAILED", "Natural Language Processing - Error", SeverityError, "There was an error creating a document of your message.")
+
+	errCodeAPISettingsInvalid  = defineError("API_SETTINGS_INVALID", "API Error", SeverityUser, "invalid settings")
+	errCodeAPIParamMissing     = defineError("API_PARAM_MISSING", "API Error", SeverityUser, "%s must not be empty")
+	errCodeAPIParamInvalid     = defineError("API_PARAM_INVALID", "API Error", SeverityUser, "%s invalid")
+	errCodeAPINotFound         = defineError("API_NOT_FOUND", "API Error", SeverityUser, "specified %s has no %s")
+	errCodeAPIInviteFailed     = defineError("API_INVITE_FAILED", "API Error", SeverityWarning, "error generating invite")
+	errCodeAPIUnknownError     = defineError("API_UNKNOWN_ERROR_CODE", "API Error", SeverityUser, "specified error code is unknown")
+	errCodeAPIUnauthorized     = defineError("API_UNAUTHORIZED", "API Error", SeverityUser, "authentication required")
+	errCodeAPIForbidden        = defineError("API_FORBIDDEN", "API Error", SeverityUser, "not allowed to access %s")
+	errCodeAPITokenInvalid     = defineError("API_TOKEN_INVALID", "API Error", SeverityUser, "invalid or expired API token")
+	errCodeAPIOAuth2Disabled   = defineError("API_OAUTH2_DISABLED", "API Error", SeverityUser, "logging in with Discord isn't configured")
+	errCodeAPIOAuth2Failed     = defineError("API_OAUTH2_FAILED", "API Error", SeverityWarning, "error logging in with Discord")
+	errCodeAPIOpenAPIFailed    = defineError("API_OPENAPI_FAILED", "API Error", SeverityError, "error generating the OpenAPI document")
+	errCodeAPIRateLimited      = defineError("API_RATE_LIMITED", "API Error", SeverityUser, "too many requests, only %d are allowed per minute")
+	errCodeAPIInviteLimited    = defineError("API_INVITE_RATE_LIMITED", "API Error", SeverityUser, "too many invites, only %d are allowed per hour")
+	errCodeIPCTokenInvalid     = defineError("IPC_TOKEN_INVALID", "IPC Error", SeverityWarning, "invalid IPC token")
+	errCodeIPCBadRequest       = defineError("IPC_BAD_REQUEST", "IPC Error", SeverityError, "error parsing %s")
+	errCodeIPCShardUnavailable = defineError("IPC_SHARD_UNAVAILABLE", "IPC Error", SeverityWarning, "shard %d for guildID is unavailable")
+)
+
+// Voice errors, returned by the voice functions and compared against with errors.Is
 var (
-	fnvHash hash.Hash32 = fnv.New32a()
-
-	errVoiceJoinAlreadyInChannel = errors.New("voice: error joining channel, already in selected voice channel")
-	errVoiceJoinBusy             = errors.New("voice: error joining channel, busy in another channel")
-	errVoiceJoinChannel          = errors.New("voice: error joining channel")
-	errVoiceJoinChangeChannel    = errors.New("voice: error changing channel")
-	errVoiceLeaveChannel         = errors.New("voice: error leaving channel")
-	errVoiceLeaveNotConnected    = errors.New("voice: error leaving channel, not connected")
-	errVoiceNotStreaming         = errors.New("voice: not streaming")
-	errVoicePausedAlready        = errors.New("voice: already paused")
-	errVoicePlayAlreadyStreaming = errors.New("voice: error playing audio, already streaming")
-	errVoicePlayInvalidURL       = errors.New("voice: error playing audio, invalid URL")
-	errVoicePlayMuted            = errors.New("voice: error playing audio, muted")
-	errVoicePlayNotConnected     = errors.New("voice: error playing audio, not connected")
-	errVoicePlayingAlready       = errors.New("voice: already playing")
-	errVoiceSkippedManually      = errors.New("voice: skipped audio manually")
-	errVoiceStoppedManually      = errors.New("voice: stopped audio manually")
+	errVoiceJoinAlreadyInChannel = newError(defineError("VOICE_ALREADY_IN_CHANNEL", "Voice Error", SeverityUser, "Already connected to the specified voice channel."))
+	errVoiceJoinBusy             = newError(defineError("VOICE_BUSY", "Voice Error", SeverityUser, "Busy streaming in another voice channel."))
+	errVoiceJoinChannel          = newError(defineError("VOICE_JOIN_FAILED", "Voice Error", SeverityWarning, "Error joining the voice channel."))
+	errVoiceJoinChangeChannel    = newError(defineError("VOICE_CHANGE_CHANNEL_FAILED", "Voice Error", SeverityWarning, "Error changing to the voice channel."))
+	errVoiceLeaveChannel         = newError(defineError("VOICE_LEAVE_FAILED", "Voice Error", SeverityWarning, "There was an error leaving the voice channel."))
+	errVoiceLeaveNotConnected    = newError(defineError("VOICE_NOT_CONNECTED", "Voice Error", SeverityUser, "Not connected to a voice channel."))
+	errVoiceNotStreaming         = newError(defineError("VOICE_NOT_STREAMING", "Voice Error", SeverityUser, "There is no audio currently playing."))
+	errVoicePausedAlready        = newError(defineError("VOICE_ALREADY_PAUSED", "Voice Error", SeverityUser, "Already paused the audio."))
+	errVoicePlayAlreadyStreaming = newError(defineError("VOICE_ALREADY_STREAMING", "Voice Error", SeverityUser, "There is already audio playing."))
+	errVoicePlayInvalidURL       = newError(defineError("VOICE_INVALID_URL", "Voice Error", SeverityUser, "The specified URL can't be played."))
+	errVoicePlayMuted            = newError(defineError("VOICE_MUTED", "Voice Error", SeverityUser, "Can't play audio while muted."))
+	errVoicePlayNotConnected     = newError(defineError("VOICE_PLAY_NOT_CONNECTED", "Voice Error", SeverityUser, "Can't play audio without being in a voice channel."))
+	errVoicePlayingAlready       = newError(defineError("VOICE_ALREADY_PLAYING", "Voice Error", SeverityUser, "Already playing audio."))
+	errVoiceSkippedManually      = newError(defineError("VOICE_SKIPPED", "Voice Error", SeverityUser, "Skipped the audio manually."))
+	errVoiceStoppedManually      = newError(defineError("VOICE_STOPPED", "Voice Error", SeverityUser, "Stopped the audio manually."))
 )
 
-func getErrorMessage(err error) (errHash, errMsg string) {
-	errHash = hashError(err)
-
-	switch err {
-	case errVoiceJoinAlreadyInChannel:
-		errMsg = "Already connected to the specified voice channel."
-	case errVoiceJoinBusy:
-		errMsg = "Busy streaming in another voice channel."
-	case errVoiceJoinChannel:
-		errMsg = "Error joining the voice channel."
-	case errVoiceLeaveChannel:
-		errMsg = "Error leaving the voice channel."
-	case errVoiceLeaveNotConnected:
-		errMsg = "Not connected to a voice channel."
+// translateError adds a translation of an error code's message template, where locale is a Discord locale or its base language
+func translateError(code, locale, message string) {
+	errorCatalog[code].Messages[locale] = message
+}
+
+func init() {
+	//Spanish
+	translateError(errCodeCommandInternal, "es", "Algo salió mal al ejecutar este comando. Si sigue pasando, dale al dueño del bot este ID de referencia: ``%s``")
+	translateError(errCodeCommandNotAuthorized, "es", "Lo siento, Dave, me temo que no puedo hacer eso.")
+	translateError(errCodeCommandFeatureOff, "es", "La función ``%s`` está desactivada en este servidor.")
+	translateError(errCodeCommandNoPermissions, "es", "¿Qué crees que estás haciendo, Dave?")
+	translateError(errCodeCommandInvalidNumber, "es", "``%s`` no es un número válido.")
+	translateError(errCodeCommandInvalidPage, "es", "Número de página no válido ``%s``.")
+	translateError(errCodeCommandPageNotFound, "es", "La página ``%d`` no existe.")
+	translateError(errCodeCommandUnknownAction, "es", "Comando desconocido ``%s``.")
+	translateError(errCodeCommandUnknownSetting, "es", "No se encontró el ajuste ``%s``.")
+	translateError(errCodeTimezoneUnset, "es", "¡Primero configura una zona horaria!\n\nEj: ``%suser timezone America/New_York``")
+	translateError(errCodeVoiceUserNotInChannel, "es", "Debes unirte al canal de voz que quieres usar antes de usar el comando %s.")
+	translateError(errVoiceNotStreaming.Code, "es", "No se está reproduciendo audio en este momento.")
+
+	//German
+	translateError(errCodeCommandNotAuthorized, "de", "Es tut mir leid, Dave, aber das kann ich nicht tun.")
+	translateError(errCodeCommandFeatureOff, "de", "Die Funktion ``%s`` ist auf diesem Server deaktiviert.")
+	translateError(errCodeCommandInvalidNumber, "de", "``%s`` ist keine gültige Zahl.")
+	translateError(errCodeCommandUnknownAction, "de", "Unbekannter Befehl ``%s``.")
+}
+
+// ClinetError is an error from the error catalog, carrying the arguments for its message template and what caused it
+type ClinetError struct {
+	Code  string
+	Args  []interface{}
+	Cause error
+}
+
+// newError returns an error for the given code, with the given arguments for its message template
+func newError(code string, args ...interface{}) *ClinetError {
+	if _, exists := errorCatalog[code]; !exists {
+		panic("errors: undefined error code " + code)
+	}
+	return &ClinetError{Code: code, Args: args}
+}
+
+// wrapError returns an error for the given code that was caused by another error
+func wrapError(code string, cause error, args ...interface{}) *ClinetError {
+	err := newError(code, args...)
+	err.Cause = cause
+	return err
+}
+
+// With returns a copy of the error with the given arguments for its message template
+func (err *ClinetError) With(args ...interface{}) *ClinetError {
+	return &ClinetError{Code: err.Code, Args: args, Cause: err.Cause}
+}
+
+// Wrap returns a copy of the error caused by another error
+func (err *ClinetError) Wrap(cause error) *ClinetError {
+	return &ClinetError{Code: err.Code, Args: err.Args, Cause: cause}
+}
+
+func (err *ClinetError) Error() string {
+	msg := err.Code + ": " + err.Message("en")
+	if err.Cause != nil {
+		msg += ": " + err.Cause.Error()
 	}
+	return msg
+}
 
-	return
+func (err *ClinetError) Unwrap() error {
+	return err.Cause
 }
 
-func getErrorEmbed(title string, err error) *discordgo.MessageEmbed {
-	errHash, errMsg := getErrorMessage(err)
+// Is reports errors as matching when they share the same code, so copies made by With and Wrap still match their sentinel
+func (err *ClinetError) Is(target error) bool {
+	targetErr, ok := target.(*ClinetError)
+	return ok && targetErr.Code == err.Code
+}
+
+// Definition returns the catalog entry of the error's code
+func (err *ClinetError) Definition() *ErrorDefinition {
+	return errorCatalog[err.Code]
+}
+
+// Severity returns the severity of the error's code
+func (err *ClinetError) Severity() ErrorSeverity {
+	return err.Definition().Severity
+}
+
+// Message returns the user-facing message of the error in the given Discord locale
+func (err *ClinetError) Message(locale string) string {
+	template := err.Definition().Message(locale)
+	if len(err.Args) == 0 {
+		return template
+	}
+	return fmt.Sprintf(template, err.Args...)
+}
+
+// asClinetError returns the catalog error within the given error, wrapping errors from outside the catalog as internal errors
+func asClinetError(err error) *ClinetError {
+	var clinetErr *ClinetError
+	if errors.As(err, &clinetErr) {
+		return clinetErr
+	}
+	return wrapError(errCodeInternal, err)
+}
+
+// logger returns the logger errors of the severity are logged at, so mistakes made by users don't fill the error log
+func (severity ErrorSeverity) logger() *Logger {
+	switch severity {
+	case SeverityError:
+		return Error
+	case SeverityWarning:
+		return Warning
+	}
+	return Debug
+}
+
+// getErrorEmbed returns an error embed with the user-facing message of the error in the given Discord locale, and its code in the footer
+func getErrorEmbed(err error, locale string) *discordgo.MessageEmbed {
+	clinetErr := asClinetError(err)
 	return NewEmbed().
-		AddField(title+" Error", errMsg).
-		SetFooter("Error Code: " + errHash).
-		SetColor(0xFF0000).MessageEmbed
+		SetTitle(clinetErr.Definition().Title).
+		SetDescription(clinetErr.Message(locale)).
+		SetFooter("Error Code: " + clinetErr.Code).
+		SetColor(0xb40000).MessageEmbed
 }
 
-func hashError(err error) string {
-	fnvHash.Write([]byte(fmt.Sprintf("%v", err)))
-	defer fnvHash.Reset()
+func apiGetErrors(w http.ResponseWriter, r *http.Request) {
+	definitions := make([]*ErrorDefinition, 0, len(errorCatalog))
+	for _, definition := range errorCatalog {
+		definitions = append(definitions, definition)
+	}
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].Code < definitions[j].Code
+	})
+	render.JSON(w, r, definitions)
+}
 
-	return fmt.Sprintf("%x", fnvHash.Sum(nil))
+func apiGetError(w http.ResponseWriter, r *http.Request) {
+	definition, exists := errorCatalog[strings.ToUpper(chi.URLParam(r, "code"))]
+	if !exists {
+		render.Status(r, http.StatusNotFound)
+		render.JSON(w, r, errAPI(newError(errCodeAPIUnknownError)))
+		return
+	}
+	render.JSON(w, r, definition)
 }
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"regexp"
+	"testing"
+)
+
+// TestErrorMessageLocales checks that error messages fall back from a Discord locale to its base language and then to English
+func TestErrorMessageLocales(t *testing.T) {
+	err := newError(errCodeCommandInvalidNumber, "abc")
+	for locale, expected := range map[string]string{
+		"es-ES": "``abc`` no es un número válido.",
+		"de":    "``abc`` ist keine gültige Zahl.",
+		"fr":    "``abc`` is not a valid number.",
+		"en-US": "``abc`` is not a valid number.",
+	} {
+		if message := err.Message(locale); message != expected {
+			t.Errorf("Message in %s is %q, expected %q", locale, message, expected)
+		}
+	}
+}
+
+// TestErrorTranslationVerbs fails when a translation doesn't use the same format verbs as the English message, as both are given the same arguments
+func TestErrorTranslationVerbs(t *testing.T) {
+	verbs := regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+	for code, definition := range errorCatalog {
+		expected := verbs.FindAllString(definition.Messages["en"], -1)
+		for locale, message := range definition.Messages {
+			found := verbs.FindAllString(message, -1)
+			if len(found) != len(expected) {
+				t.Errorf("Error %s in %s has %d format verbs, expected %d", code, locale, len(found), len(expected))
+				continue
+			}
+			for i := range found {
+				if found[i] != expected[i] {
+					t.Errorf("Error %s in %s uses %s where English uses %s", code, locale, found[i], expected[i])
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/go-chi/render"
+)
+
+/*
+	Events are published as things happen in a guild, and streamed to API clients over WebSockets (see apiws.go).
+	When sharded, only the first shard serves the API, so every other shard forwards the events it publishes to the first shard over IPC.
+*/
+
+const (
+	EventTrackStarted     = "track.started"     //An entry started playing in a voice channel
+	EventTrackEnded       = "track.ended"       //An entry stopped playing in a voice channel
+	EventQueueChanged     = "queue.changed"     //Entries were added to, removed from or moved within the queue
+	EventStarboardCreated = "starboard.created" //A message made it onto the starboard
+	EventStarboardUpdated = "starboard.updated" //A message on the starboard gained or lost stars
+	EventStarboardRemoved = "starboard.removed" //A message fell off the starboard
+	EventLog              = "log"               //An event was logged to the guild's logging channel
+	EventReminderFired    = "reminder.fired"    //A reminder was sent to a user, which only that user can see
+	EventFeedPosted       = "feed.posted"       //A new post from a feed was sent to a channel
+
+	eventBufferSize     = 64  //How many events a subscriber can fall behind by before it's disconnected
+	eventForwardBacklog = 256 //How many events a shard can hold while forwarding them to the first shard before dropping them
+)
+
+var (
+	//How much access to a guild is needed to see each type of event
+	eventAccess = map[string]GuildAccess{
+		EventTrackStarted:     GuildAccessMember,
+		EventTrackEnded:       GuildAccessMember,
+		EventQueueChanged:     GuildAccessMember,
+		EventStarboardCreated: GuildAccessMember,
+		EventStarboardUpdated: GuildAccessMember,
+		EventStarboardRemoved: GuildAccessMember,
+		EventLog:              GuildAccessManager,
+		EventReminderFired:    GuildAccessMember,
+		EventFeedPosted:       GuildAccessMember,
+	}
+
+	//Everything currently listening for events
+	eventSubscribers     = make(map[*EventSubscriber]bool)
+	eventSubscribersLock sync.RWMutex
+
+	//Events waiting to be forwarded to the first shard
+	eventForwardQueue = make(chan *Event, eventForwardBacklog)
+	eventForwardOnce  sync.Once
+)
+
+// Event holds something that happened in a guild
+type Event struct {
+	Type    string      `json:"type"`
+	GuildID string      `json:"guildID,omitempty"`
+	UserID  string      `json:"userID,omitempty"` //The only user that can see the event, if it's private to them
+	Time    time.Time   `json:"time"`
+	Data    interface{} `json:"data"`
+}
+
+// EventTrack holds an entry that started or stopped playing
+type EventTrack struct {
+	Entry  *APIVoiceEntry `json:"entry"`
+	Reason string         `json:"reason,omitempty"` //Why it stopped playing, one of finished, skipped, stopped or error
+}
+
+// EventQueue holds the state of a queue after it changed
+type EventQueue struct {
+	Length int `json:"length"`
+}
+
+// EventLogEntry holds an event that was logged to a guild's logging channel
+type EventLogEntry struct {
+	Event string                  `json:"event"` //The name of the logged event, as in the logging settings
+	Embed *discordgo.MessageEmbed `json:"embed"`
+}
+
+// EventFeedPost holds a post sent from a feed
+type EventFeedPost struct {
+	FeedURL   string `json:"feedURL"`
+	FeedTitle string `json:"feedTitle"`
+	ChannelID string `json:"channelID"` //The channel the post was sent to
+	Title     string `json:"title"`     //The title of the post
+	URL       string `json:"url"`       //The link to the post
+}
+
+// EventSubscriber receives every event that passes its filter
+type EventSubscriber struct {
+	Events chan *Event       //Where matching events are sent
+	Lagged chan struct{}     //Closed if the subscriber fell too far behind and stopped receiving events
+	Filter func(*Event) bool //Whether or not to send an event to the subscriber
+
+	lagOnce sync.Once
+}
+
+// subscribeEvents starts sending every event that passes the given filter to a new subscriber
+func subscribeEvents(filter func(*Event) bool) *EventSubscriber {
+	subscriber := &EventSubscriber{
+		Events: make(chan *Event, eventBufferSize),
+		Lagged: make(chan struct{}),
+		Filter: filter,
+	}
+
+	eventSubscribersLock.Lock()
+	eventSubscribers[subscriber] = true
+	eventSubscribersLock.Unlock()
+	return subscriber
+}
+
+// Unsubscribe stops sending events to the subscriber
+func (subscriber *EventSubscriber) Unsubscribe() {
+	eventSubscribersLock.Lock()
+	delete(eventSubscribers, subscriber)
+	eventSubscribersLock.Unlock()
+}
+
+// publishEvent publishes an event for everyone with access to the guild
+func publishEvent(guildID, eventType string, data interface{}) {
+	publish(&Event{Type: eventType, GuildID: guildID, Time: time.Now(), Data: data})
+}
+
+// publishUserEvent publishes an event that only the given user can see
+func publishUserEvent(guildID, userID, eventType string, data interface{}) {
+	publish(&Event{Type: eventType, GuildID: guildID, UserID: userID, Time: time.Now(), Data: data})
+}
+
+func publish(event *Event) {
+	if shardCount > 1 && shardID != 0 {
+		forwardEvent(event)
+		return
+	}
+	deliverEvent(event)
+}
+
+// deliverEvent sends an event to every subscriber it passes the filter of, without waiting on any of them
+func deliverEvent(event *Event) {
+	eventSubscribersLock.RLock()
+	defer eventSubscribersLock.RUnlock()
+
+	for subscriber := range eventSubscribers {
+		if subscriber.Filter != nil && !subscriber.Filter(event) {
+			continue
+		}
+		select {
+		case subscriber.Events <- event:
+		default:
+			subscriber.lagOnce.Do(func() { close(subscriber.Lagged) })
+		}
+	}
+}
+
+// forwardEvent queues an event to be forwarded to the first shard, starting the forwarder if it isn't running yet
+func forwardEvent(event *Event) {
+	eventForwardOnce.Do(func() { go forwardEvents() })
+	select {
+	case eventForwardQueue <- event:
+	default:
+		WarningAPI.With("guild", event.GuildID, "event", event.Type).Println("Dropped an event, as the first shard isn't keeping up")
+	}
+}
+
+// forwardEvents forwards queued events to the first shard in batches
+func forwardEvents() {
+	for event := range eventForwardQueue {
+		events := []*Event{event}
+		for len(events) < eventForwardBacklog && len(eventForwardQueue) > 0 {
+			events = append(events, <-eventForwardQueue)
+		}
+
+		shardAddrs, err := ipcShardAddrs()
+		if err == nil && shardAddrs[0] == "" {
+			err = newError(errCodeIPCShardUnavailable, 0)
+		}
+		if err == nil {
+			err = ipcRequest("POST", "http://"+shardAddrs[0]+"/events", events, nil)
+		}
+		if err != nil {
+			ErrorAPI.Printf("Error forwarding %d events to the first shard: %v", len(events), err)
+		}
+	}
+}
+
+func ipcPostEvents(w http.ResponseWriter, r *http.Request) {
+	events := make([]*Event, 0)
+	if err := json.NewDecoder(r.Body).Decode(&events); err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, errAPI(wrapError(errCodeIPCBadRequest, err, "events")))
+		return
+	}
+
+	for _, event := range events {
+		deliverEvent(event)
+	}
+	render.NoContent(w, r)
+}
+
+// eventTypes returns every type of event, in order
+func eventTypes() []string {
+	return []string{EventTrackStarted, EventTrackEnded, EventQueueChanged, EventStarboardCreated, EventStarboardUpdated, EventStarboardRemoved, EventLog, EventReminderFired, EventFeedPosted}
+}
@@ -8,7 +8,7 @@ import (
 )
 
 func discordMessageCreate(session *discordgo.Session, event *discordgo.MessageCreate) {
-	defer recoverPanic()
+	defer recoverHandler("messageCreate", event.GuildID)
 
 	message, err := session.ChannelMessage(event.ChannelID, event.ID) //Make it easier to keep track of what's happening
 	if err != nil {
@@ -21,7 +21,7 @@ func discordMessageCreate(session *discordgo.Session, event *discordgo.MessageCr
 	go handleMessage(session, message, false)
 }
 func discordMessageUpdate(session *discordgo.Session, event *discordgo.MessageUpdate) {
-	defer recoverPanic()
+	defer recoverHandler("messageUpdate", event.GuildID)
 
 	message, err := session.ChannelMessage(event.ChannelID, event.ID) //Make it easier to keep track of what's happening
 	if err != nil {
@@ -34,7 +34,7 @@ func discordMessageUpdate(session *discordgo.Session, event *discordgo.MessageUp
 	go handleMessage(session, message, true)
 }
 func discordMessageDelete(session *discordgo.Session, event *discordgo.MessageDelete) {
-	defer recoverPanic()
+	defer recoverHandler("messageDelete", event.GuildID)
 
 	message := event //Make it easier to keep track of what's happening
 
@@ -59,7 +59,7 @@ func discordMessageDelete(session *discordgo.Session, event *discordgo.MessageDe
 	}
 }
 func discordMessageDeleteBulk(session *discordgo.Session, event *discordgo.MessageDeleteBulk) {
-	defer recoverPanic()
+	defer recoverHandler("messageDeleteBulk", event.GuildID)
 
 	messages := event.Messages
 	channelID := event.ChannelID
@@ -88,6 +88,8 @@ func discordMessageDeleteBulk(session *discordgo.Session, event *discordgo.Messa
 }
 
 func discordChannelCreate(session *discordgo.Session, channel *discordgo.ChannelCreate) {
+	defer recoverHandler("channelCreate", channel.GuildID)
+
 	settings, guildFound := guildSettings[channel.GuildID]
 	if guildFound {
 		if settings.LogSettings.LoggingEnabled && settings.LogSettings.LoggingEvents.ChannelCreate {
@@ -109,7 +111,7 @@ func discordChannelCreate(session *discordgo.Session, channel *discordgo.Channel
 
 				channelCreateEmbed.InlineAllFields()
 
-				session.ChannelMessageSendEmbed(settings.LogSettings.LoggingChannel, channelCreateEmbed.MessageEmbed)
+				sendLogEmbed(session, channel.GuildID, "channelCreate", channelCreateEmbed.MessageEmbed)
 			case discordgo.ChannelTypeGuildVoice:
 				channelCreateEmbed := NewEmbed().
 					SetTitle("Logging Event - Channel Create").
@@ -128,7 +130,7 @@ func discordChannelCreate(session *discordgo.Session, channel *discordgo.Channel
 
 				channelCreateEmbed.InlineAllFields()
 
-				session.ChannelMessageSendEmbed(settings.LogSettings.LoggingChannel, channelCreateEmbed.MessageEmbed)
+				sendLogEmbed(session, channel.GuildID, "channelCreate", channelCreateEmbed.MessageEmbed)
 			case discordgo.ChannelTypeGuildCategory:
 				channelCreateEmbed := NewEmbed().
 					SetTitle("Logging Event - Channel Create").
@@ -138,12 +140,14 @@ func discordChannelCreate(session *discordgo.Session, channel *discordgo.Channel
 					InlineAllFields().
 					SetColor(0x1C1C1C).MessageEmbed
 
-				session.ChannelMessageSendEmbed(settings.LogSettings.LoggingChannel, channelCreateEmbed)
+				sendLogEmbed(session, channel.GuildID, "channelCreate", channelCreateEmbed)
 			}
 		}
 	}
 }
 func discordChannelUpdate(session *discordgo.Session, channel *discordgo.ChannelUpdate) {
+	defer recoverHandler("channelUpdate", channel.GuildID)
+
 	settings, guildFound := guildSettings[channel.GuildID]
 	if guildFound {
 		if settings.LogSettings.LoggingEnabled && settings.LogSettings.LoggingEvents.ChannelUpdate {
@@ -169,7 +173,7 @@ func discordChannelUpdate(session *discordgo.Session, channel *discordgo.Channel
 
 				channelUpdateEmbed.InlineAllFields()
 
-				session.ChannelMessageSendEmbed(settings.LogSettings.LoggingChannel, channelUpdateEmbed.MessageEmbed)
+				sendLogEmbed(session, channel.GuildID, "channelUpdate", channelUpdateEmbed.MessageEmbed)
 			case discordgo.ChannelTypeGuildVoice:
 				channelUpdateEmbed := NewEmbed().
 					SetTitle("Logging Event - Channel Update").
@@ -188,7 +192,7 @@ func discordChannelUpdate(session *discordgo.Session, channel *discordgo.Channel
 
 				channelUpdateEmbed.InlineAllFields()
 
-				session.ChannelMessageSendEmbed(settings.LogSettings.LoggingChannel, channelUpdateEmbed.MessageEmbed)
+				sendLogEmbed(session, channel.GuildID, "channelUpdate", channelUpdateEmbed.MessageEmbed)
 			case discordgo.ChannelTypeGuildCategory:
 				channelUpdateEmbed := NewEmbed().
 					SetTitle("Logging Event - Channel Update").
@@ -198,12 +202,14 @@ func discordChannelUpdate(session *discordgo.Session, channel *discordgo.Channel
 					InlineAllFields().
 					SetColor(0x1C1C1C).MessageEmbed
 
-				session.ChannelMessageSendEmbed(settings.LogSettings.LoggingChannel, channelUpdateEmbed)
+				sendLogEmbed(session, channel.GuildID, "channelUpdate", channelUpdateEmbed)
 			}
 		}
 	}
 }
 func discordChannelDelete(session *discordgo.Session, channel *discordgo.ChannelDelete) {
+	defer recoverHandler("channelDelete", channel.GuildID)
+
 	settings, guildFound := guildSettings[channel.GuildID]
 	if guildFound {
 		if settings.LogSettings.LoggingEnabled && settings.LogSettings.LoggingEvents.ChannelDelete {
@@ -224,7 +230,7 @@ func discordChannelDelete(session *discordgo.Session, channel *discordgo.Channel
 
 				channelDeleteEmbed.InlineAllFields()
 
-				session.ChannelMessageSendEmbed(settings.LogSettings.LoggingChannel, channelDeleteEmbed.MessageEmbed)
+				sendLogEmbed(session, channel.GuildID, "channelDelete", channelDeleteEmbed.MessageEmbed)
 			case discordgo.ChannelTypeGuildVoice:
 				channelDeleteEmbed := NewEmbed().
 					SetTitle("Logging Event - Channel Delete").
@@ -241,7 +247,7 @@ func discordChannelDelete(session *discordgo.Session, channel *discordgo.Channel
 
 				channelDeleteEmbed.InlineAllFields()
 
-				session.ChannelMessageSendEmbed(settings.LogSettings.LoggingChannel, channelDeleteEmbed.MessageEmbed)
+				sendLogEmbed(session, channel.GuildID, "channelDelete", channelDeleteEmbed.MessageEmbed)
 			case discordgo.ChannelTypeGuildCategory:
 				channelDeleteEmbed := NewEmbed().
 					SetTitle("Logging Event - Channel Delete").
@@ -250,12 +256,14 @@ func discordChannelDelete(session *discordgo.Session, channel *discordgo.Channel
 					InlineAllFields().
 					SetColor(0x1C1C1C).MessageEmbed
 
-				session.ChannelMessageSendEmbed(settings.LogSettings.LoggingChannel, channelDeleteEmbed)
+				sendLogEmbed(session, channel.GuildID, "channelDelete", channelDeleteEmbed)
 			}
 		}
 	}
 }
 func discordGuildUpdate(session *discordgo.Session, guild *discordgo.GuildUpdate) {
+	defer recoverHandler("guildUpdate", guild.ID)
+
 	settings, guildFound := guildSettings[guild.ID]
 	if guildFound {
 		if settings.LogSettings.LoggingEnabled && settings.LogSettings.LoggingEvents.GuildUpdate {
@@ -270,13 +278,13 @@ func discordGuildUpdate(session *discordgo.Session, guild *discordgo.GuildUpdate
 			}
 			afkChannel := "None"
 			if guild.AfkChannelID != "" {
-				channel, err := botData.DiscordSession.Channel(guild.AfkChannelID)
+				channel, err := botData().DiscordSession.Channel(guild.AfkChannelID)
 				if err == nil && channel.Type == discordgo.ChannelTypeGuildVoice {
 					afkChannel = ":speaker: " + channel.Name
 				}
 			}
 
-			session.ChannelMessageSendEmbed(settings.LogSettings.LoggingChannel, NewEmbed().
+			sendLogEmbed(session, guild.ID, "guildUpdate", NewEmbed().
 				SetTitle("Logging Event - Guild Update").
 				SetDescription("The guild was updated.").
 				AddField("Guild Name", guild.Name).
@@ -293,10 +301,12 @@ func discordGuildUpdate(session *discordgo.Session, guild *discordgo.GuildUpdate
 	}
 }
 func discordGuildBanAdd(session *discordgo.Session, guild *discordgo.GuildBanAdd) {
+	defer recoverHandler("guildBanAdd", guild.GuildID)
+
 	settings, guildFound := guildSettings[guild.GuildID]
 	if guildFound {
 		if settings.LogSettings.LoggingEnabled && settings.LogSettings.LoggingEvents.GuildBanAdd {
-			session.ChannelMessageSendEmbed(settings.LogSettings.LoggingChannel, NewEmbed().
+			sendLogEmbed(session, guild.GuildID, "guildBanAdd", NewEmbed().
 				SetTitle("Logging Event - Ban Add").
 				SetDescription("A member was banned from the server.").
 				AddField("User ID", guild.User.ID).
@@ -309,10 +319,12 @@ func discordGuildBanAdd(session *discordgo.Session, guild *discordgo.GuildBanAdd
 	}
 }
 func discordGuildBanRemove(session *discordgo.Session, guild *discordgo.GuildBanRemove) {
+	defer recoverHandler("guildBanRemove", guild.GuildID)
+
 	settings, guildFound := guildSettings[guild.GuildID]
 	if guildFound {
 		if settings.LogSettings.LoggingEnabled && settings.LogSettings.LoggingEvents.GuildBanRemove {
-			session.ChannelMessageSendEmbed(settings.LogSettings.LoggingChannel, NewEmbed().
+			sendLogEmbed(session, guild.GuildID, "guildBanRemove", NewEmbed().
 				SetTitle("Logging Event - Ban Remove").
 				SetDescription("A member was unbanned from the server.").
 				AddField("User ID", guild.User.ID).
@@ -325,6 +337,8 @@ func discordGuildBanRemove(session *discordgo.Session, guild *discordgo.GuildBan
 	}
 }
 func discordGuildMemberAdd(session *discordgo.Session, member *discordgo.GuildMemberAdd) {
+	defer recoverHandler("guildMemberAdd", member.GuildID)
+
 	_, guildFound := guildSettings[member.GuildID]
 	if guildFound {
 		if guildSettings[member.GuildID].UserJoinMessage != "" && guildSettings[member.GuildID].UserJoinMessageChannel != "" {
@@ -353,7 +367,7 @@ func discordGuildMemberAdd(session *discordgo.Session, member *discordgo.GuildMe
 				joinedAtTimeFormatted = joinedAtMonth + " " + strconv.Itoa(joinedAtDay) + ", " + strconv.Itoa(joinedAtYear) + " at " + strconv.Itoa(joinedAtHour) + ":" + strconv.Itoa(joinedAtMinute) + ":" + strconv.Itoa(joinedAtSecond)
 			}
 
-			session.ChannelMessageSendEmbed(guildSettings[member.GuildID].LogSettings.LoggingChannel, NewEmbed().
+			sendLogEmbed(session, member.GuildID, "guildMemberAdd", NewEmbed().
 				SetTitle("Logging Event - User Joined").
 				SetDescription("A new member joined the server.").
 				AddField("Joined At", joinedAtTimeFormatted).
@@ -370,6 +384,8 @@ func discordGuildMemberAdd(session *discordgo.Session, member *discordgo.GuildMe
 	}
 }
 func discordGuildMemberRemove(session *discordgo.Session, member *discordgo.GuildMemberRemove) {
+	defer recoverHandler("guildMemberRemove", member.GuildID)
+
 	_, guildFound := guildSettings[member.GuildID]
 	if guildFound {
 		if guildSettings[member.GuildID].UserLeaveMessage != "" && guildSettings[member.GuildID].UserLeaveMessageChannel != "" {
@@ -397,7 +413,7 @@ func discordGuildMemberRemove(session *discordgo.Session, member *discordgo.Guil
 				joinedAtTimeFormatted = joinedAtMonth + " " + strconv.Itoa(joinedAtDay) + ", " + strconv.Itoa(joinedAtYear) + " at " + strconv.Itoa(joinedAtHour) + ":" + strconv.Itoa(joinedAtMinute) + ":" + strconv.Itoa(joinedAtSecond)
 			}
 
-			session.ChannelMessageSendEmbed(guildSettings[member.GuildID].LogSettings.LoggingChannel, NewEmbed().
+			sendLogEmbed(session, member.GuildID, "guildMemberRemove", NewEmbed().
 				SetTitle("Logging Event - User Left").
 				SetDescription("A member left the server.").
 				AddField("Joined At", joinedAtTimeFormatted).
@@ -429,11 +445,13 @@ func discordUserUpdate(session *discordgo.Session, user *discordgo.UserUpdate) {
 
 }
 func discordVoiceStateUpdate(session *discordgo.Session, voiceState *discordgo.VoiceStateUpdate) {
+	defer recoverHandler("voiceStateUpdate", voiceState.GuildID)
+
 	settings, guildFound := guildSettings[voiceState.GuildID]
 	if guildFound {
 		if settings.LogSettings.LoggingEnabled && settings.LogSettings.LoggingEvents.VoiceStateUpdate {
 			if voiceState.ChannelID == "" {
-				session.ChannelMessageSendEmbed(settings.LogSettings.LoggingChannel, NewEmbed().
+				sendLogEmbed(session, voiceState.GuildID, "voiceStateUpdate", NewEmbed().
 					SetTitle("Logging Event - Voice State Update").
 					SetDescription("A voice state was updated.").
 					AddField("User", "<@"+voiceState.UserID+">").
@@ -447,7 +465,7 @@ func discordVoiceStateUpdate(session *discordgo.Session, voiceState *discordgo.V
 					return
 				}
 
-				session.ChannelMessageSendEmbed(settings.LogSettings.LoggingChannel, NewEmbed().
+				sendLogEmbed(session, voiceState.GuildID, "voiceStateUpdate", NewEmbed().
 					SetTitle("Logging Event - Voice State Update").
 					SetDescription("A voice state was updated.").
 					AddField("User", "<@"+voiceState.UserID+">").
@@ -467,3 +485,9 @@ func discordVoiceStateUpdate(session *discordgo.Session, voiceState *discordgo.V
 		}
 	}
 }
+
+// sendLogEmbed sends a logging event to the guild's logging channel, and to API clients streaming the guild's events
+func sendLogEmbed(session *discordgo.Session, guildID, event string, embed *discordgo.MessageEmbed) {
+	session.ChannelMessageSendEmbed(guildSettings[guildID].LogSettings.LoggingChannel, embed)
+	publishEvent(guildID, EventLog, &EventLogEntry{Event: event, Embed: embed})
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// GuildBotOptions holds a guild's overrides of the global bot options, where nil = use the global setting
+// A guild can only narrow the global bot options: a feature disabled globally stays disabled, and limits are capped at the global value
+type GuildBotOptions struct {
+	UseCustomResponses *bool `json:"useCustomResponses,omitempty"` //Whether custom responses to queries can be used
+	UseDuckDuckGo      *bool `json:"useDuckDuckGo,omitempty"`      //Whether DuckDuckGo instant answers can be used
+	UseFeed            *bool `json:"useFeed,omitempty"`            //Whether RSS and Atom feeds can be used
+	UseGitHub          *bool `json:"useGitHub,omitempty"`          //Whether GitHub user and repository info can be used
+	UseImgur           *bool `json:"useImgur,omitempty"`           //Whether Imgur image and album info can be used
+	UseLyrics          *bool `json:"useLyrics,omitempty"`          //Whether song lyrics can be used
+	UseSoundCloud      *bool `json:"useSoundCloud,omitempty"`      //Whether SoundCloud playback can be used
+	UseSpotify         *bool `json:"useSpotify,omitempty"`         //Whether Spotify search and playback can be used
+	UseWolframAlpha    *bool `json:"useWolframAlpha,omitempty"`    //Whether Wolfram|Alpha answers to queries can be used
+	UseXKCD            *bool `json:"useXKCD,omitempty"`            //Whether xkcd comics can be used
+	UseYouTube         *bool `json:"useYouTube,omitempty"`         //Whether YouTube search and playback can be used
+	YouTubeMaxResults  *int  `json:"youtubeMaxResults,omitempty"`  //The most YouTube search results to list
+	SpotifyMaxResults  *int  `json:"spotifyMaxResults,omitempty"`  //The most Spotify search results to list
+}
+
+// guildSettingsJSON decodes guild settings without calling GuildSettings.UnmarshalJSON again
+type guildSettingsJSON GuildSettings
+
+// UnmarshalJSON decodes guild settings, carrying over the bot options of guilds saved before they moved from botOptions to botFeatures
+func (settings *GuildSettings) UnmarshalJSON(data []byte) error {
+	legacy := &struct {
+		*guildSettingsJSON
+		BotOptions *BotOptions `json:"botOptions"` //The bot options of this guild before they became overrides
+	}{guildSettingsJSON: (*guildSettingsJSON)(settings)}
+	if err := json.Unmarshal(data, legacy); err != nil {
+		return err
+	}
+	if legacy.BotOptions != nil {
+		settings.BotOptions.migrate(legacy.BotOptions)
+	}
+	return nil
+}
+
+// migrate fills in the overrides that aren't set yet from a guild's old bot options
+// The old options were never enforced, so every toggle was saved as false unless the file was edited by hand
+// Toggles are only carried over when at least one of them is true, otherwise every feature would end up disabled
+func (options *GuildBotOptions) migrate(legacy *BotOptions) {
+	edited := false
+	for _, feature := range features {
+		if *feature.Global(legacy) {
+			edited = true
+		}
+	}
+	if edited {
+		for _, feature := range features {
+			if override := feature.Guild(options); *override == nil {
+				enabled := *feature.Global(legacy)
+				*override = &enabled
+			}
+		}
+	}
+
+	if options.YouTubeMaxResults == nil && legacy.YouTubeMaxResults > 0 {
+		maxResults := legacy.YouTubeMaxResults
+		options.YouTubeMaxResults = &maxResults
+	}
+	if options.SpotifyMaxResults == nil && legacy.SpotifyMaxResults > 0 {
+		maxResults := legacy.SpotifyMaxResults
+		options.SpotifyMaxResults = &maxResults
+	}
+}
+
+// Feature holds a service that can be toggled globally in the bot options and per guild in the guild settings
+type Feature struct {
+	Name        string                                //The name used to refer to the feature in commands
+	Description string                                //A description of what the feature provides
+	ServiceName string                                //The name of the query or voice service this feature provides, if any
+	Global      func(options *BotOptions) *bool       //Returns the global toggle for this feature
+	Guild       func(options *GuildBotOptions) **bool //Returns the guild override for this feature
+}
+
+var (
+	//Contains every feature that can be toggled per guild
+	features = []*Feature{
+		{Name: "customresponses", Description: "Custom responses to queries", ServiceName: "custom responses",
+			Global: func(options *BotOptions) *bool { return &options.UseCustomResponses }, Guild: func(options *GuildBotOptions) **bool { return &options.UseCustomResponses }},
+		{Name: "duckduckgo", Description: "DuckDuckGo instant answers to queries", ServiceName: "DuckDuckGo",
+			Global: func(options *BotOptions) *bool { return &options.UseDuckDuckGo }, Guild: func(options *GuildBotOptions) **bool { return &options.UseDuckDuckGo }},
+		{Name: "feed", Description: "RSS and Atom feeds",
+			Global: func(options *BotOptions) *bool { return &options.UseFeed }, Guild: func(options *GuildBotOptions) **bool { return &options.UseFeed }},
+		{Name: "github", Description: "GitHub user and repository info",
+			Global: func(options *BotOptions) *bool { return &options.UseGitHub }, Guild: func(options *GuildBotOptions) **bool { return &options.UseGitHub }},
+		{Name: "imgur", Description: "Imgur image and album info",
+			Global: func(options *BotOptions) *bool { return &options.UseImgur }, Guild: func(options *GuildBotOptions) **bool { return &options.UseImgur }},
+		{Name: "lyrics", Description: "Song lyrics",
+			Global: func(options *BotOptions) *bool { return &options.UseLyrics }, Guild: func(options *GuildBotOptions) **bool { return &options.UseLyrics }},
+		{Name: "soundcloud", Description: "SoundCloud playback", ServiceName: "SoundCloud",
+			Global: func(options *BotOptions) *bool { return &options.UseSoundCloud }, Guild: func(options *GuildBotOptions) **bool { return &options.UseSoundCloud }},
+		{Name: "spotify", Description: "Spotify search and playback", ServiceName: "Spotify",
+			Global: func(options *BotOptions) *bool { return &options.UseSpotify }, Guild: func(options *GuildBotOptions) **bool { return &options.UseSpotify }},
+		{Name: "wolframalpha", Description: "Wolfram|Alpha answers to queries", ServiceName: "Wolfram|Alpha",
+			Global: func(options *BotOptions) *bool { return &options.UseWolframAlpha }, Guild: func(options *GuildBotOptions) **bool { return &options.UseWolframAlpha }},
+		{Name: "xkcd", Description: "XKCD comics",
+			Global: func(options *BotOptions) *bool { return &options.UseXKCD }, Guild: func(options *GuildBotOptions) **bool { return &options.UseXKCD }},
+		{Name: "youtube", Description: "YouTube search and playback", ServiceName: "YouTube",
+			Global: func(options *BotOptions) *bool { return &options.UseYouTube }, Guild: func(options *GuildBotOptions) **bool { return &options.UseYouTube }},
+	}
+)
+
+// getFeature returns the feature with the given name, or nil if there isn't one
+func getFeature(name string) *Feature {
+	for _, feature := range features {
+		if strings.EqualFold(feature.Name, name) {
+			return feature
+		}
+	}
+	return nil
+}
+
+// guildBotOptions returns the bot options for a guild, with the guild's overrides resolved against the global bot options
+func guildBotOptions(guildID string) BotOptions {
+	options := botData().BotOptions
+
+	settings, exists := guildSettings[guildID]
+	if !exists {
+		return options
+	}
+
+	for _, feature := range features {
+		if override := *feature.Guild(&settings.BotOptions); override != nil {
+			*feature.Global(&options) = *feature.Global(&options) && *override
+		}
+	}
+	if settings.BotOptions.YouTubeMaxResults != nil && *settings.BotOptions.YouTubeMaxResults > 0 && *settings.BotOptions.YouTubeMaxResults < options.YouTubeMaxResults {
+		options.YouTubeMaxResults = *settings.BotOptions.YouTubeMaxResults
+	}
+	if settings.BotOptions.SpotifyMaxResults != nil && *settings.BotOptions.SpotifyMaxResults > 0 && *settings.BotOptions.SpotifyMaxResults < options.SpotifyMaxResults {
+		options.SpotifyMaxResults = *settings.BotOptions.SpotifyMaxResults
+	}
+
+	return options
+}
+
+// featureEnabled returns whether or not the named feature is enabled in a guild
+func featureEnabled(guildID, name string) bool {
+	feature := getFeature(name)
+	if feature == nil {
+		return true
+	}
+	options := guildBotOptions(guildID)
+	return *feature.Global(&options)
+}
+
+// serviceEnabled returns whether or not the named query or voice service is enabled in a guild
+func serviceEnabled(guildID, serviceName string) bool {
+	for _, feature := range features {
+		if feature.ServiceName == serviceName {
+			return featureEnabled(guildID, feature.Name)
+		}
+	}
+	return true
+}
@@ -6,6 +6,7 @@ require (
 	4d63.com/embedfiles v1.0.0 // indirect
 	4d63.com/tz v1.2.0
 	github.com/AlekSi/pointer v1.1.0 // indirect
+	github.com/BurntSushi/toml v1.2.1
 	github.com/JoshuaDoes/duckduckgolang v0.0.0-20180207042607-60cbd040f6f4
 	github.com/JoshuaDoes/go-cve v0.0.0-20180802130150-ddfd9d0080df
 	github.com/JoshuaDoes/go-soundcloud v0.0.0-20200213054747-92817728d24c
@@ -31,6 +32,7 @@ require (
 	github.com/go-playground/colors v1.2.0
 	github.com/golang/groupcache v0.0.0-20210331224755-41bb18bfe9da // indirect
 	github.com/google/go-github v17.0.0+incompatible
+	github.com/gorilla/websocket v1.4.2
 	github.com/google/go-querystring v1.1.0 // indirect
 	github.com/gopherjs/gopherjs v0.0.0-20210202160940-bed99a852dfe // indirect
 	github.com/gosimple/slug v1.9.0 // indirect
@@ -70,4 +72,5 @@ require (
 	google.golang.org/grpc v1.37.0 // indirect
 	gopkg.in/sourcemap.v1 v1.0.5 // indirect
 	gopkg.in/src-d/go-git.v4 v4.13.1
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -1,9 +1,20 @@
 package main
 
 import (
+	"sort"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/JoshuaDoes/go-wolfram"
+	"github.com/robfig/cron"
+)
+
+var (
+	//Runs sweepGuildData every guildDataSweepFrequency seconds, replaced whenever the frequency changes
+	guildDataSweeper        *cron.Cron
+	guildDataSweepFrequency int
+	guildDataSweeperLock    sync.Mutex
 )
 
 // GuildData holds data specific to a guild
@@ -14,4 +25,179 @@ type GuildData struct {
 	YouTubeResults       map[string]*VoiceServiceYouTubeResultNav `json:"youtubeResults,omitempty"`
 	SpotifyResults       map[string]*VoiceServiceSpotifyResultNav `json:"spotifyResults,omitempty"`
 	WolframConversations map[string]*wolfram.Conversation         `json:"wolframConversations,omitempty"`
+
+	LastUsed GuildDataLastUsed `json:"lastUsed"` //When each user's sessions were last used, so they can expire
+}
+
+// GuildDataLastUsed holds the last time each user's sessions were used, where key = user ID
+type GuildDataLastUsed struct {
+	YouTubeResults       map[string]time.Time `json:"youtubeResults,omitempty"`
+	SpotifyResults       map[string]time.Time `json:"spotifyResults,omitempty"`
+	WolframConversations map[string]time.Time `json:"wolframConversations,omitempty"`
+}
+
+// TouchYouTubeResults marks a user's YouTube search session as used, if one exists
+func (guildData *GuildData) TouchYouTubeResults(userID string) {
+	if guildData.YouTubeResults[userID] != nil {
+		touchLastUsed(&guildData.LastUsed.YouTubeResults, userID)
+	}
+}
+
+// TouchSpotifyResults marks a user's Spotify session as used, if one exists
+func (guildData *GuildData) TouchSpotifyResults(userID string) {
+	if guildData.SpotifyResults[userID] != nil {
+		touchLastUsed(&guildData.LastUsed.SpotifyResults, userID)
+	}
+}
+
+// TouchWolframConversation marks a user's Wolfram|Alpha conversation as used, if one exists
+func (guildData *GuildData) TouchWolframConversation(userID string) {
+	if guildData.WolframConversations[userID] != nil {
+		touchLastUsed(&guildData.LastUsed.WolframConversations, userID)
+	}
+}
+
+// Sweep evicts queries and sessions that have outlived their lifetimes or exceed the per-guild limits, returning how many were evicted
+// The caller must hold the guild data lock
+func (guildData *GuildData) Sweep(config GuildDataConfig, now time.Time) int {
+	evicted := 0
+
+	queryTimes := make(map[string]time.Time)
+	for messageID, query := range guildData.Queries {
+		if query == nil {
+			delete(guildData.Queries, messageID)
+			evicted++
+			continue
+		}
+		if query.Created.IsZero() {
+			query.Created = now //Queries tracked before expiry existed get a full lifetime from now
+		}
+		queryTimes[messageID] = query.Created
+	}
+	for _, messageID := range expiredKeys(queryTimes, time.Duration(config.QueryLifetime)*time.Second, config.MaxQueries, now) {
+		delete(guildData.Queries, messageID)
+		evicted++
+	}
+
+	for userID, results := range guildData.YouTubeResults {
+		if results == nil {
+			delete(guildData.YouTubeResults, userID)
+			continue
+		}
+		if guildData.LastUsed.YouTubeResults[userID].IsZero() {
+			touchLastUsed(&guildData.LastUsed.YouTubeResults, userID)
+		}
+	}
+	for _, userID := range expiredKeys(guildData.LastUsed.YouTubeResults, time.Duration(config.SessionLifetime)*time.Second, config.MaxSessions, now) {
+		delete(guildData.YouTubeResults, userID)
+		evicted++
+	}
+	pruneLastUsed(guildData.LastUsed.YouTubeResults, func(userID string) bool { return guildData.YouTubeResults[userID] != nil })
+
+	for userID, results := range guildData.SpotifyResults {
+		if results == nil {
+			delete(guildData.SpotifyResults, userID)
+			continue
+		}
+		if results.AddingAll {
+			touchLastUsed(&guildData.LastUsed.SpotifyResults, userID) //Don't pull a session out from under a playlist that's still being queued
+			continue
+		}
+		if guildData.LastUsed.SpotifyResults[userID].IsZero() {
+			touchLastUsed(&guildData.LastUsed.SpotifyResults, userID)
+		}
+	}
+	for _, userID := range expiredKeys(guildData.LastUsed.SpotifyResults, time.Duration(config.SessionLifetime)*time.Second, config.MaxSessions, now) {
+		delete(guildData.SpotifyResults, userID)
+		evicted++
+	}
+	pruneLastUsed(guildData.LastUsed.SpotifyResults, func(userID string) bool { return guildData.SpotifyResults[userID] != nil })
+
+	for userID, conversation := range guildData.WolframConversations {
+		if conversation == nil {
+			delete(guildData.WolframConversations, userID)
+			continue
+		}
+		if guildData.LastUsed.WolframConversations[userID].IsZero() {
+			touchLastUsed(&guildData.LastUsed.WolframConversations, userID)
+		}
+	}
+	for _, userID := range expiredKeys(guildData.LastUsed.WolframConversations, time.Duration(config.ConversationLifetime)*time.Second, config.MaxSessions, now) {
+		delete(guildData.WolframConversations, userID)
+		evicted++
+	}
+	pruneLastUsed(guildData.LastUsed.WolframConversations, func(userID string) bool { return guildData.WolframConversations[userID] != nil })
+
+	return evicted
+}
+
+// scheduleGuildDataSweeper starts sweeping guild data every frequency seconds, replacing the previous schedule if it changed
+func scheduleGuildDataSweeper(frequency int) {
+	guildDataSweeperLock.Lock()
+	defer guildDataSweeperLock.Unlock()
+
+	if guildDataSweeper != nil {
+		if frequency == guildDataSweepFrequency {
+			return
+		}
+		guildDataSweeper.Stop()
+	}
+
+	guildDataSweeper = cron.New()
+	guildDataSweeper.AddFunc("@every "+strconv.Itoa(frequency)+"s", func() { sweepGuildData() })
+	guildDataSweeper.Start()
+	guildDataSweepFrequency = frequency
+}
+
+// sweepGuildData sweeps the guild data of every guild for expired queries and sessions
+func sweepGuildData() {
+	defer recoverHandler("sweepGuildData", "")
+
+	now := time.Now()
+	evicted := 0
+	for _, data := range guildData {
+		data.Lock()
+		evicted += data.Sweep(botData().BotOptions.GuildData, now)
+		data.Unlock()
+	}
+
+	if evicted > 0 {
+		Debug.Printf("Swept %d expired queries and sessions from guild data", evicted)
+		stateSaveAll()
+	}
+}
+
+func touchLastUsed(lastUsed *map[string]time.Time, key string) {
+	if *lastUsed == nil {
+		*lastUsed = make(map[string]time.Time)
+	}
+	(*lastUsed)[key] = time.Now()
+}
+
+func pruneLastUsed(lastUsed map[string]time.Time, exists func(key string) bool) {
+	for key := range lastUsed {
+		if !exists(key) {
+			delete(lastUsed, key)
+		}
+	}
+}
+
+// expiredKeys returns the keys that have outlived the given lifetime, followed by the oldest keys beyond the given limit
+func expiredKeys(times map[string]time.Time, lifetime time.Duration, limit int, now time.Time) []string {
+	expired := make([]string, 0)
+	alive := make([]string, 0)
+	for key, when := range times {
+		if now.Sub(when) > lifetime {
+			expired = append(expired, key)
+		} else {
+			alive = append(alive, key)
+		}
+	}
+
+	if limit > 0 && len(alive) > limit {
+		sort.Slice(alive, func(i, j int) bool { return times[alive[i]].Before(times[alive[j]]) })
+		expired = append(expired, alive[:len(alive)-limit]...)
+	}
+
+	return expired
 }
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/render"
+)
+
+var (
+	//How long a health check probe may take before the process is considered unresponsive
+	healthProbeTimeout = 5 * time.Second
+)
+
+// HealthCheck holds the result of a single health or readiness check
+type HealthCheck struct {
+	Name   string `json:"name"`
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail,omitempty"` //Why the check failed, or anything else worth knowing about it
+}
+
+// HealthReport holds the results of every health or readiness check of a process
+type HealthReport struct {
+	Status string                `json:"status"` //ok if every check passed, otherwise failing
+	Checks []*HealthCheck        `json:"checks"`
+	Shards map[int]*HealthReport `json:"shards,omitempty"` //The report of every other shard, where key = shard ID
+}
+
+// newHealthReport builds a report from the given checks
+func newHealthReport(checks ...*HealthCheck) *HealthReport {
+	report := &HealthReport{Status: "ok", Checks: checks}
+	for _, check := range checks {
+		if !check.OK {
+			report.Status = "failing"
+		}
+	}
+	return report
+}
+
+// OK returns whether or not every check of the report passed, including those of every other shard
+func (report *HealthReport) OK() bool {
+	if report.Status != "ok" {
+		return false
+	}
+	for _, shardReport := range report.Shards {
+		if !shardReport.OK() {
+			return false
+		}
+	}
+	return true
+}
+
+// Failing returns the name and detail of every check that failed
+func (report *HealthReport) Failing() []string {
+	failing := make([]string, 0)
+	for _, check := range report.Checks {
+		if !check.OK {
+			failing = append(failing, check.Name+": "+check.Detail)
+		}
+	}
+	for id, shardReport := range report.Shards {
+		for _, check := range shardReport.Failing() {
+			failing = append(failing, "shard "+strconv.Itoa(id)+" "+check)
+		}
+	}
+	return failing
+}
+
+// livenessReport checks that the process is alive and responsive, which is what the supervisor's watchdog relies on
+func livenessReport() *HealthReport {
+	return newHealthReport(checkGateway(), checkResponsive())
+}
+
+// readinessReport checks that the process is ready to serve commands and API requests
+func readinessReport() *HealthReport {
+	return newHealthReport(checkDiscordSession(), checkDiscordReady(), checkStateLoaded(), checkClients())
+}
+
+// checkGateway checks that Discord is still acknowledging the gateway heartbeats
+func checkGateway() *HealthCheck {
+	check := &HealthCheck{Name: "gateway", OK: true}
+	if botData().DiscordSession == nil {
+		check.Detail = "starting up"
+		return check
+	}
+
+	botData().DiscordSession.RLock()
+	lastHeartbeatAck := botData().DiscordSession.LastHeartbeatAck
+	botData().DiscordSession.RUnlock()
+
+	if since := time.Since(lastHeartbeatAck); since >= heartbeatStaleAfter {
+		check.OK = false
+		check.Detail = "no heartbeat acknowledged for " + since.Round(time.Second).String()
+	}
+	return check
+}
+
+// checkResponsive checks that the Discord session isn't stuck behind a lock, which would stall every event handler
+// Reloads aren't probed, as they hold their lock while building new clients over the network and never block event handlers, which keep
+// using the running configuration until the new one is swapped in
+func checkResponsive() *HealthCheck {
+	check := &HealthCheck{Name: "responsive", OK: true}
+
+	probed := make(chan bool, 1)
+	go func() {
+		if botData().DiscordSession != nil {
+			botData().DiscordSession.RLock()
+			botData().DiscordSession.RUnlock()
+		}
+		probed <- true
+	}()
+
+	select {
+	case <-probed:
+	case <-time.After(healthProbeTimeout):
+		check.OK = false
+		check.Detail = "probe didn't finish within " + healthProbeTimeout.String()
+	}
+	return check
+}
+
+func checkDiscordSession() *HealthCheck {
+	check := &HealthCheck{Name: "discordSession", OK: true}
+	if botData().DiscordSession == nil {
+		check.OK = false
+		check.Detail = "not created yet"
+		return check
+	}
+
+	botData().DiscordSession.RLock()
+	dataReady := botData().DiscordSession.DataReady
+	botData().DiscordSession.RUnlock()
+
+	if !dataReady {
+		check.OK = false
+		check.Detail = "not connected to Discord"
+	}
+	return check
+}
+
+func checkDiscordReady() *HealthCheck {
+	check := &HealthCheck{Name: "discordReady", OK: isReady}
+	if !isReady {
+		check.Detail = "waiting for Discord's ready event"
+	}
+	return check
+}
+
+func checkStateLoaded() *HealthCheck {
+	check := &HealthCheck{Name: "stateLoaded", OK: stateLoaded}
+	if !stateLoaded {
+		check.Detail = "the state hasn't been loaded yet"
+	}
+	return check
+}
+
+// checkClients checks that the client of every external service enabled in the configuration was initialized
+func checkClients() *HealthCheck {
+	check := &HealthCheck{Name: "clients", OK: true}
+
+	clients := []struct {
+		name        string
+		enabled     bool
+		initialized bool
+	}{
+		{"duckduckgo", botData().BotOptions.UseDuckDuckGo, botData().BotClients.DuckDuckGo != nil},
+		{"feed", botData().BotOptions.UseFeed, botData().BotClients.FeedParser != nil},
+		{"github", botData().BotOptions.UseGitHub, botData().BotClients.GitHub != nil},
+		{"imgur", botData().BotOptions.UseImgur, botData().BotClients.Imgur.HTTPClient != nil},
+		{"ninty", botData().BotOptions.UseNinty, botData().BotClients.Ninty != nil},
+		{"soundcloud", botData().BotOptions.UseSoundCloud, botData().BotClients.SoundCloud != nil},
+		{"spotify", botData().BotOptions.UseSpotify, botData().BotClients.Spotify != nil},
+		{"wolframalpha", botData().BotOptions.UseWolframAlpha, botData().BotClients.Wolfram != nil},
+		{"xkcd", botData().BotOptions.UseXKCD, botData().BotClients.XKCD != nil},
+		{"youtube", botData().BotOptions.UseYouTube, botData().BotClients.YouTube != nil},
+	}
+	for _, client := range clients {
+		if client.enabled && !client.initialized {
+			check.OK = false
+			if check.Detail != "" {
+				check.Detail += ", "
+			}
+			check.Detail += client.name
+		}
+	}
+	if !check.OK {
+		check.Detail = "not initialized: " + check.Detail
+	}
+	return check
+}
+
+// allReadinessReport checks the readiness of this shard, along with every other shard when sharded
+func allReadinessReport() *HealthReport {
+	report := readinessReport()
+	if shardCount <= 1 {
+		return report
+	}
+
+	report.Shards = make(map[int]*HealthReport)
+	shardAddrs, err := ipcShardAddrs()
+	if err != nil {
+		ErrorAPI.Printf("Error finding the other shards to check the readiness of: %v", err)
+	}
+	for id := 0; id < shardCount; id++ {
+		if id == shardID {
+			continue
+		}
+		shardReport := newHealthReport(&HealthCheck{Name: "ipc", OK: false, Detail: "shard hasn't registered with the IPC hub"})
+		if addr, exists := shardAddrs[id]; exists {
+			remoteReport := &HealthReport{}
+			if err := ipcRequest("GET", "http://"+addr+"/readiness", nil, remoteReport); err != nil {
+				shardReport = newHealthReport(&HealthCheck{Name: "ipc", OK: false, Detail: err.Error()})
+			} else {
+				shardReport = remoteReport
+			}
+		}
+		report.Shards[id] = shardReport
+	}
+	return report
+}
+
+// renderHealthReport responds with the given report, with a 503 status code if any of its checks failed
+func renderHealthReport(w http.ResponseWriter, r *http.Request, report *HealthReport) {
+	if !report.OK() {
+		render.Status(r, http.StatusServiceUnavailable)
+	}
+	render.JSON(w, r, report)
+}
+
+func apiGetHealthz(w http.ResponseWriter, r *http.Request) {
+	renderHealthReport(w, r, livenessReport())
+}
+
+func apiGetReadyz(w http.ResponseWriter, r *http.Request) {
+	renderHealthReport(w, r, allReadinessReport())
+}
+
+func ipcGetReadiness(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, readinessReport())
+}
@@ -23,19 +23,39 @@ func initializeGuildSettings(guildID string) {
 }
 
 func initializeUserSettings(userID string) {
+	userSettingsLock.Lock()
+	defer userSettingsLock.Unlock()
+
 	_, userSettingsExists := userSettings[userID]
 	if !userSettingsExists {
 		userSettings[userID] = &UserSettings{}
 	}
 }
 
+// copyUserSettings returns a copy of a user's settings and whether or not they have any, which can be read without holding userSettingsLock
+func copyUserSettings(userID string) (UserSettings, bool) {
+	userSettingsLock.RLock()
+	defer userSettingsLock.RUnlock()
+
+	if settings, exists := userSettings[userID]; exists {
+		return *settings, true
+	}
+	return UserSettings{}, false
+}
+
 func initializeStarboard(guildID string) {
 	_, starboardExists := starboards[guildID]
 	if !starboardExists {
-		starboards[guildID] = &Starboard{}
-		starboards[guildID].Emoji = "⭐"
-		starboards[guildID].NSFWEmoji = "💦"
-		starboards[guildID].AllowSelfStar = false
-		starboards[guildID].MinimumStars = 2
+		starboards[guildID] = newStarboard()
+	}
+}
+
+// newStarboard returns a starboard with the default settings
+func newStarboard() *Starboard {
+	return &Starboard{
+		Emoji:         "⭐",
+		NSFWEmoji:     "💦",
+		AllowSelfStar: false,
+		MinimumStars:  2,
 	}
 }
@@ -0,0 +1,465 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+)
+
+/*
+	When sharded, the bot processes talk to each other over HTTP on the loopback interface.
+	The master process runs an IPC hub that every bot process registers its own IPC server with, so they can find each other.
+	Every request carries a random token generated by the master process, which is passed to each bot process through its environment.
+*/
+
+const (
+	ipcTokenEnv    = "CLINET_IPC_TOKEN"
+	ipcTokenHeader = "X-Clinet-IPC-Token"
+)
+
+var (
+	//The address of the master process's IPC hub and the token to authenticate with, empty if not sharded
+	ipcHubAddr string
+	ipcToken   string
+
+	//The HTTP client used for IPC requests
+	ipcClient = &http.Client{Timeout: 5 * time.Second}
+
+	//Used for IPC requests that wait on Discord, such as exporting or deleting a user's data
+	ipcSlowClient = &http.Client{Timeout: 2 * time.Minute}
+
+	//Contains the JSON of every field of every user's settings as of the last sync with the other shards, where key = user ID
+	//Each user's fields are keyed by their JSON path, ex: socials.psn
+	userSettingsSynced     = make(map[string]map[string]string)
+	userSettingsSyncedLock sync.Mutex
+)
+
+// IPCHub holds the address of every shard's IPC server, and is run by the master process
+type IPCHub struct {
+	sync.RWMutex
+
+	Addr   string         //The address the hub is listening on
+	Token  string         //The token every IPC request must carry
+	Shards map[int]string //The address of each shard's IPC server, where key = shard ID
+}
+
+// IPCRegistration holds a bot process's request to register its IPC server with the hub
+type IPCRegistration struct {
+	Addr string `json:"addr"`
+}
+
+// startIPCHub starts the IPC hub on a random loopback port
+func startIPCHub() (*IPCHub, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	hub := &IPCHub{
+		Addr:   listener.Addr().String(),
+		Token:  hex.EncodeToString(token),
+		Shards: make(map[int]string),
+	}
+
+	router := chi.NewRouter()
+	router.Use(ipcAuth(hub.Token))
+	router.Get("/shards", hub.getShards)
+	router.Post("/shards/{shardID}", hub.postShard)
+
+	go func() {
+		if err := http.Serve(listener, router); err != nil {
+			Error.Printf("Error running the IPC hub: %v", err)
+		}
+	}()
+
+	Debug.Printf("Started the IPC hub on %s", hub.Addr)
+	return hub, nil
+}
+
+func (hub *IPCHub) getShards(w http.ResponseWriter, r *http.Request) {
+	hub.RLock()
+	defer hub.RUnlock()
+
+	render.JSON(w, r, hub.Shards)
+}
+
+func (hub *IPCHub) postShard(w http.ResponseWriter, r *http.Request) {
+	shardID, err := strconv.Atoi(chi.URLParam(r, "shardID"))
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, errAPI(newError(errCodeAPIParamInvalid, "shardID")))
+		return
+	}
+
+	registration := &IPCRegistration{}
+	if err := json.NewDecoder(r.Body).Decode(registration); err != nil || registration.Addr == "" {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, errAPI(newError(errCodeAPIParamMissing, "addr")))
+		return
+	}
+
+	hub.Lock()
+	hub.Shards[shardID] = registration.Addr
+	hub.Unlock()
+
+	Debug.Printf("Shard %d registered its IPC server on %s", shardID, registration.Addr)
+	render.NoContent(w, r)
+}
+
+// ipcAuth rejects any request that doesn't carry the IPC token
+func ipcAuth(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if subtle.ConstantTimeCompare([]byte(r.Header.Get(ipcTokenHeader)), []byte(token)) != 1 {
+				render.Status(r, http.StatusUnauthorized)
+				render.JSON(w, r, errAPI(newError(errCodeIPCTokenInvalid)))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// startIPCServer starts this shard's IPC server on a random loopback port and registers it with the hub
+func startIPCServer() error {
+	ipcToken = os.Getenv(ipcTokenEnv)
+	os.Unsetenv(ipcTokenEnv) //Keep it away from anything this process spawns
+	if ipcToken == "" {
+		return fmt.Errorf("missing %s", ipcTokenEnv)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+
+	router := chi.NewRouter()
+	router.Use(ipcAuth(ipcToken))
+	router.Get("/stats", ipcGetStats)
+	router.Post("/usersettings", ipcPostUserSettings)
+	router.Get("/metricfamilies", ipcGetMetrics)
+	router.Get("/readiness", ipcGetReadiness)
+	router.Get("/access/{guildID}/{userID}", ipcGetAccess)
+	router.Get("/guilds/{userID}", ipcGetGuilds)
+	router.Get("/userdata/{userID}", ipcGetUserData)
+	router.Delete("/userdata/{userID}", ipcDeleteUserData)
+	router.Post("/events", ipcPostEvents)
+	router.Mount("/", APIRouter()) //Serves API requests proxied from the first shard for the guilds this shard owns
+
+	go func() {
+		if err := http.Serve(listener, router); err != nil {
+			Error.Printf("Error running the IPC server: %v", err)
+		}
+	}()
+
+	registration := &IPCRegistration{Addr: listener.Addr().String()}
+	for attempt := 1; ; attempt++ {
+		err = ipcRequest("POST", "http://"+ipcHubAddr+"/shards/"+strconv.Itoa(shardID), registration, nil)
+		if err == nil || attempt >= 5 {
+			break
+		}
+		time.Sleep(time.Duration(attempt) * time.Second)
+	}
+	if err != nil {
+		return fmt.Errorf("error registering with the IPC hub: %v", err)
+	}
+
+	Debug.Printf("Started the IPC server on %s", registration.Addr)
+	return nil
+}
+
+func ipcGetStats(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, localShardStats())
+}
+
+func ipcGetAccess(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, localGuildAccess(chi.URLParam(r, "guildID"), chi.URLParam(r, "userID")))
+}
+
+func ipcGetGuilds(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, localUserGuilds(chi.URLParam(r, "userID"), r.URL.Query().Get("all") == "true"))
+}
+
+func ipcGetUserData(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, localExportUserData(chi.URLParam(r, "userID"), ""))
+}
+
+func ipcDeleteUserData(w http.ResponseWriter, r *http.Request) {
+	result := localPurgeUserData(chi.URLParam(r, "userID"), "")
+	stateSaveAll()
+	render.JSON(w, r, result)
+}
+
+func ipcPostUserSettings(w http.ResponseWriter, r *http.Request) {
+	changes := make(map[string]map[string]json.RawMessage)
+	if err := json.NewDecoder(r.Body).Decode(&changes); err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, errAPI(wrapError(errCodeIPCBadRequest, err, "user settings")))
+		return
+	}
+
+	userSettingsLock.Lock()
+	defer userSettingsLock.Unlock()
+	userSettingsSyncedLock.Lock()
+	defer userSettingsSyncedLock.Unlock()
+
+	for userID, changedFields := range changes {
+		if changedFields == nil {
+			delete(userSettings, userID)
+			delete(userSettingsSynced, userID)
+			continue
+		}
+
+		//Only the fields changed on the other shard are replaced, so changes made here to other fields aren't lost
+		fields := make(map[string]string)
+		if existing, exists := userSettings[userID]; exists {
+			fields = userSettingsFields(existing)
+		}
+		synced := userSettingsSynced[userID]
+		if synced == nil {
+			synced = make(map[string]string)
+			userSettingsSynced[userID] = synced
+		}
+		for path, value := range changedFields {
+			if string(value) == "null" {
+				delete(fields, path)
+				delete(synced, path)
+				continue
+			}
+			fields[path] = string(value)
+			synced[path] = string(value)
+		}
+
+		settings, err := userSettingsFromFields(fields)
+		if err != nil {
+			Error.Printf("Error syncing the settings of user %s: %v", userID, err)
+			continue
+		}
+		if existing, exists := userSettings[userID]; exists {
+			*existing = *settings
+		} else {
+			userSettings[userID] = settings
+		}
+	}
+
+	render.NoContent(w, r)
+}
+
+// ipcRequest sends an authenticated IPC request, encoding the body and decoding the response as JSON where given
+func ipcRequest(method, url string, body, out interface{}) error {
+	return ipcRequestWith(ipcClient, method, url, body, out)
+}
+
+// ipcRequestWith sends an authenticated IPC request with the given client, encoding the body and decoding the response as JSON where given
+func ipcRequestWith(client *http.Client, method, url string, body, out interface{}) error {
+	var bodyReader *bytes.Reader
+	if body != nil {
+		bodyJSON, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		bodyReader = bytes.NewReader(bodyJSON)
+	} else {
+		bodyReader = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequest(method, url, bodyReader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set(ipcTokenHeader, ipcToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s: %s", method, url, resp.Status)
+	}
+	if out != nil {
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+	return nil
+}
+
+// ipcShardAddrs returns the address of every shard's IPC server, where key = shard ID
+func ipcShardAddrs() (map[int]string, error) {
+	shardAddrs := make(map[int]string)
+	err := ipcRequest("GET", "http://"+ipcHubAddr+"/shards", nil, &shardAddrs)
+	return shardAddrs, err
+}
+
+// userSettingsFields returns the JSON of every field of a user's settings, where key = JSON path
+// Nested objects are split into their own fields, so changes to different socials are synced separately
+func userSettingsFields(settings *UserSettings) map[string]string {
+	fields := make(map[string]string)
+	settingsJSON, _ := json.Marshal(settings)
+	flattenJSONFields("", settingsJSON, fields)
+	return fields
+}
+
+func flattenJSONFields(path string, value json.RawMessage, fields map[string]string) {
+	object := make(map[string]json.RawMessage)
+	if len(value) > 0 && value[0] == '{' && json.Unmarshal(value, &object) == nil {
+		for name, fieldValue := range object {
+			if path == "" {
+				flattenJSONFields(name, fieldValue, fields)
+			} else {
+				flattenJSONFields(path+"."+name, fieldValue, fields)
+			}
+		}
+		return
+	}
+	fields[path] = string(value)
+}
+
+// userSettingsFromFields returns the user settings made of the given fields, as returned by userSettingsFields
+func userSettingsFromFields(fields map[string]string) (*UserSettings, error) {
+	object := make(map[string]interface{})
+	for path, value := range fields {
+		names := strings.Split(path, ".")
+		parent := object
+		for _, name := range names[:len(names)-1] {
+			child, ok := parent[name].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				parent[name] = child
+			}
+			parent = child
+		}
+		parent[names[len(names)-1]] = json.RawMessage(value)
+	}
+
+	objectJSON, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+	settings := &UserSettings{}
+	return settings, json.Unmarshal(objectJSON, settings)
+}
+
+// snapshotUserSettings records every user's settings as already synced, such as after loading them from the state
+func snapshotUserSettings() {
+	userSettingsLock.RLock()
+	defer userSettingsLock.RUnlock()
+	userSettingsSyncedLock.Lock()
+	defer userSettingsSyncedLock.Unlock()
+
+	userSettingsSynced = make(map[string]map[string]string)
+	for userID, settings := range userSettings {
+		userSettingsSynced[userID] = userSettingsFields(settings)
+	}
+}
+
+// syncUserSettings sends the fields of every user's settings that changed since the last sync to the other shards
+// User settings aren't tied to a guild, so every shard keeps a full copy of them, and merges in only the fields another shard changed
+func syncUserSettings() {
+	if shardCount <= 1 {
+		return
+	}
+
+	userSettingsLock.RLock()
+	userSettingsSyncedLock.Lock()
+	changes := make(map[string]map[string]json.RawMessage) //A nil user means their settings were removed, and a null field that it was cleared
+	for userID, settings := range userSettings {
+		fields := userSettingsFields(settings)
+		synced := userSettingsSynced[userID]
+		changedFields := make(map[string]json.RawMessage)
+		for path, value := range fields {
+			if syncedValue, exists := synced[path]; !exists || syncedValue != value {
+				changedFields[path] = json.RawMessage(value)
+			}
+		}
+		for path := range synced {
+			if _, exists := fields[path]; !exists {
+				changedFields[path] = json.RawMessage("null")
+			}
+		}
+		if len(changedFields) > 0 {
+			changes[userID] = changedFields
+		}
+		userSettingsSynced[userID] = fields
+	}
+	for userID := range userSettingsSynced {
+		if _, exists := userSettings[userID]; !exists {
+			changes[userID] = nil
+			delete(userSettingsSynced, userID)
+		}
+	}
+	userSettingsSyncedLock.Unlock()
+	userSettingsLock.RUnlock()
+
+	if len(changes) == 0 {
+		return
+	}
+
+	shardAddrs, err := ipcShardAddrs()
+	if err != nil {
+		Error.Printf("Error finding the other shards to sync user settings with: %v", err)
+		return
+	}
+	for id, addr := range shardAddrs {
+		if id == shardID {
+			continue
+		}
+		if err := ipcRequest("POST", "http://"+addr+"/usersettings", changes, nil); err != nil {
+			Error.Printf("Error syncing user settings with shard %d: %v", id, err)
+		}
+	}
+}
+
+// isIPCRequest returns whether or not an API request was proxied from the first shard, which already authenticated it
+func isIPCRequest(r *http.Request) bool {
+	return ipcToken != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get(ipcTokenHeader)), []byte(ipcToken)) == 1
+}
+
+// shardProxy forwards API requests for a guild to the shard that owns it
+func shardProxy(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		guildID := chi.URLParam(r, "guildID")
+		if shardCount <= 1 || ownsGuild(guildID) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		owner := shardForGuild(guildID)
+		shardAddrs, err := ipcShardAddrs()
+		if err != nil || shardAddrs[owner] == "" {
+			render.Status(r, http.StatusServiceUnavailable)
+			render.JSON(w, r, errAPI(wrapError(errCodeIPCShardUnavailable, err, owner)))
+			return
+		}
+
+		proxy := httputil.NewSingleHostReverseProxy(&url.URL{Scheme: "http", Host: shardAddrs[owner]})
+		r.Header.Set(ipcTokenHeader, ipcToken)
+		r.Header.Del(apiPrincipalHeader) //The owning shard trusts this header, so it must only ever come from us
+		if principal := apiPrincipal(r); principal != nil {
+			principalJSON, _ := json.Marshal(principal)
+			r.Header.Set(apiPrincipalHeader, string(principalJSON))
+		}
+		proxy.ServeHTTP(w, r)
+	})
+}
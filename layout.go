@@ -0,0 +1,184 @@
+package main
+
+import (
+	"reflect"
+	"time"
+)
+
+//go:generate go run layoutgen.go GuildSettings Starboard RoleMe UserSettings
+
+/*
+	Layouts describe every setting a guild or user has, so dashboards can render forms for them without hard-coding each one.
+	They're built from the settings structs themselves: names, types and defaults through reflection, and descriptions from the comments on each field through layout_gen.go.
+	Run go generate whenever a settings struct changes to keep the descriptions up to date.
+*/
+
+const (
+	settingPermissionManageGuild = "manageGuild" //Anyone who can manage the guild
+	settingPermissionUser        = "user"        //Only the user the settings belong to
+)
+
+// SettingDoc holds the comments describing a settings struct or one of its fields
+type SettingDoc struct {
+	Description string //The comment describing it
+	Section     string //The comment heading the group of fields it's in, if any
+}
+
+// SettingLayout describes every setting of a settings struct
+type SettingLayout struct {
+	Name        string                `json:"name"`
+	Description string                `json:"description"`
+	Endpoint    string                `json:"endpoint,omitempty"` //Where the settings can be retrieved and changed, if anywhere
+	Fields      []*SettingFieldLayout `json:"fields"`
+}
+
+// SettingFieldLayout describes a setting
+type SettingFieldLayout struct {
+	Name        string                `json:"name,omitempty"`    //The JSON name of the setting, empty for the items of a list
+	Path        string                `json:"path,omitempty"`    //The path to change the setting at, empty within lists and settings that can't be changed through the API at all
+	Type        string                `json:"type"`              //One of boolean, string, integer, number, timestamp, list or object
+	Format      string                `json:"format,omitempty"`  //What the value or each item of a list refers to, such as channel, role or timezone
+	Description string                `json:"description"`       //What the setting does
+	Section     string                `json:"section,omitempty"` //The group of settings it belongs to, if any
+	Nullable    bool                  `json:"nullable"`          //Whether or not the setting can be null
+	Values      []interface{}         `json:"values,omitempty"`  //Every value the setting allows, if there's a fixed set of them
+	Default     interface{}           `json:"default"`           //The value the setting starts with
+	Permission  string                `json:"permission"`        //Who can change the setting
+	ReadOnly    bool                  `json:"readOnly"`          //Whether or not the setting can only be changed through commands
+	Fields      []*SettingFieldLayout `json:"fields,omitempty"`  //The settings within an object
+	Items       *SettingFieldLayout   `json:"items,omitempty"`   //The layout of each item in a list
+}
+
+// settingLayouts returns the layout of every kind of settings
+func settingLayouts() []*SettingLayout {
+	return []*SettingLayout{guildSettingsLayout(), guildStarboardLayout(), guildRoleLayout(), userSettingsLayout()}
+}
+
+func guildSettingsLayout() *SettingLayout {
+	return newSettingLayout("guild", "/api/v0/guild/{guildID}/settings", &GuildSettings{BotPrefix: botData().CommandPrefix}, guildSettingRules, settingPermissionManageGuild)
+}
+
+func guildStarboardLayout() *SettingLayout {
+	return newSettingLayout("guild/starboard", "/api/v0/guild/{guildID}/starboard", newStarboard(), nil, settingPermissionManageGuild)
+}
+
+func guildRoleLayout() *SettingLayout {
+	return newSettingLayout("guild/role", "", &RoleMe{}, nil, settingPermissionManageGuild)
+}
+
+func userSettingsLayout() *SettingLayout {
+	return newSettingLayout("user", "/api/v0/user/{userID}/settings", &UserSettings{}, userSettingRules, settingPermissionUser)
+}
+
+// newSettingLayout describes a settings struct, given a pointer to one holding the default settings and the rules for changing them
+func newSettingLayout(name, endpoint string, defaults interface{}, rules []*SettingRule, permission string) *SettingLayout {
+	value := reflect.ValueOf(defaults).Elem()
+	return &SettingLayout{
+		Name:        name,
+		Description: settingDescription(value.Type().Name()).Description,
+		Endpoint:    endpoint,
+		Fields:      settingFieldLayouts(value, "", rules != nil, rules, permission),
+	}
+}
+
+// settingFieldLayouts describes every setting within a struct at the given path, which is only addressable if the struct isn't within a list
+func settingFieldLayouts(value reflect.Value, path string, addressable bool, rules []*SettingRule, permission string) []*SettingFieldLayout {
+	fields := make([]*SettingFieldLayout, 0)
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		jsonName := settingJSONName(field)
+		if jsonName == "" || field.Anonymous {
+			continue
+		}
+
+		fieldPath := jsonName
+		if path != "" {
+			fieldPath = path + "." + jsonName
+		}
+
+		doc := settingDescription(value.Type().Name() + "." + field.Name)
+		fieldLayout := settingFieldLayout(value.Field(i), fieldPath, addressable, rules, permission)
+		fieldLayout.Name = jsonName
+		fieldLayout.Description = doc.Description
+		fieldLayout.Section = doc.Section
+		fields = append(fields, fieldLayout)
+	}
+	return fields
+}
+
+// settingFieldLayout describes a setting holding the given default value
+func settingFieldLayout(value reflect.Value, path string, addressable bool, rules []*SettingRule, permission string) *SettingFieldLayout {
+	layout := &SettingFieldLayout{Permission: permission, ReadOnly: true}
+	if addressable {
+		layout.Path = path
+		if rule := settingRule(rules, path); rule != nil {
+			layout.ReadOnly = false
+			if rule.Path == path {
+				layout.Format = rule.Format
+			}
+		}
+	}
+
+	isNil := false
+	valueType := value.Type()
+	if valueType.Kind() == reflect.Ptr {
+		layout.Nullable = true
+		valueType = valueType.Elem()
+		isNil = value.IsNil()
+		if isNil {
+			value = reflect.Zero(valueType)
+		} else {
+			value = value.Elem()
+		}
+	}
+
+	switch valueType.Kind() {
+	case reflect.Bool:
+		layout.Type = "boolean"
+		if layout.Nullable {
+			layout.Values = []interface{}{nil, true, false}
+		}
+	case reflect.String:
+		layout.Type = "string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		layout.Type = "integer"
+	case reflect.Float32, reflect.Float64:
+		layout.Type = "number"
+	case reflect.Slice, reflect.Array:
+		layout.Type = "list"
+		layout.Items = settingFieldLayout(reflect.Zero(valueType.Elem()), path, false, nil, permission)
+		if value.Len() == 0 {
+			layout.Default = []interface{}{}
+			return layout
+		}
+	case reflect.Struct:
+		if valueType == reflect.TypeOf(time.Time{}) {
+			layout.Type = "timestamp"
+			break
+		}
+		layout.Type = "object"
+		if valueType.PkgPath() == reflect.TypeOf(SettingLayout{}).PkgPath() {
+			layout.Fields = settingFieldLayouts(value, path, addressable, rules, permission)
+			for _, field := range layout.Fields {
+				layout.ReadOnly = layout.ReadOnly && field.ReadOnly
+			}
+		}
+		return layout //Objects have a default for each of their settings instead
+	default:
+		layout.Type = "object"
+		return layout
+	}
+
+	if !isNil {
+		layout.Default = value.Interface()
+	}
+	return layout
+}
+
+// settingDescription returns the comments describing a settings struct or field, where key = type name or type name + "." + field name
+func settingDescription(key string) *SettingDoc {
+	if doc, exists := settingDocs[key]; exists {
+		return doc
+	}
+	return &SettingDoc{}
+}
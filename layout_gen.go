@@ -0,0 +1,131 @@
+// Code generated by go run layoutgen.go GuildSettings Starboard RoleMe UserSettings; DO NOT EDIT.
+
+package main
+
+// settingDocs holds the comments describing each settings struct and its fields, where key = type name or type name + "." + field name
+var settingDocs = map[string]*SettingDoc{
+	"CustomResponseQuery":                         {Description: "CustomResponseQuery stores a custom response"},
+	"CustomResponseQuery.CmdResponses":            {Description: ""},
+	"CustomResponseQuery.Expression":              {Description: ""},
+	"CustomResponseQuery.Regexp":                  {Description: ""},
+	"CustomResponseQuery.Responses":               {Description: ""},
+	"CustomResponseReply":                         {Description: "CustomResponseReply stores a custom response's reply"},
+	"CustomResponseReply.ResponseEmbed":           {Description: ""},
+	"CustomResponseReplyCmd":                      {Description: "CustomResponseReplyCmd stores a custom response's command to execute"},
+	"CustomResponseReplyCmd.Arguments":            {Description: ""},
+	"CustomResponseReplyCmd.CommandName":          {Description: ""},
+	"Feed":                                        {Description: "A wrapper for *gofeed.Feed"},
+	"Feed.ChannelID":                              {Description: "The channel to post new feed entries to"},
+	"Feed.FeedURL":                                {Description: "The URL to the feed"},
+	"Feed.Frequency":                              {Description: "How often to check for new feed entries in seconds"},
+	"GuildBotOptions":                             {Description: "GuildBotOptions holds a guild's overrides of the global bot options, where nil = use the global setting. A guild can only narrow the global bot options: a feature disabled globally stays disabled, and limits are capped at the global value"},
+	"GuildBotOptions.SpotifyMaxResults":           {Description: "The most Spotify search results to list"},
+	"GuildBotOptions.UseCustomResponses":          {Description: "Whether custom responses to queries can be used"},
+	"GuildBotOptions.UseDuckDuckGo":               {Description: "Whether DuckDuckGo instant answers can be used"},
+	"GuildBotOptions.UseFeed":                     {Description: "Whether RSS and Atom feeds can be used"},
+	"GuildBotOptions.UseGitHub":                   {Description: "Whether GitHub user and repository info can be used"},
+	"GuildBotOptions.UseImgur":                    {Description: "Whether Imgur image and album info can be used"},
+	"GuildBotOptions.UseLyrics":                   {Description: "Whether song lyrics can be used"},
+	"GuildBotOptions.UseSoundCloud":               {Description: "Whether SoundCloud playback can be used"},
+	"GuildBotOptions.UseSpotify":                  {Description: "Whether Spotify search and playback can be used"},
+	"GuildBotOptions.UseWolframAlpha":             {Description: "Whether Wolfram|Alpha answers to queries can be used"},
+	"GuildBotOptions.UseXKCD":                     {Description: "Whether xkcd comics can be used"},
+	"GuildBotOptions.UseYouTube":                  {Description: "Whether YouTube search and playback can be used"},
+	"GuildBotOptions.YouTubeMaxResults":           {Description: "The most YouTube search results to list"},
+	"GuildSettings":                               {Description: "GuildSettings holds settings specific to a guild"},
+	"GuildSettings.APIInviteChannel":              {Description: "The channel to use for server-side invite link generation"},
+	"GuildSettings.APIInviteKey":                  {Description: "The key to use for server-side invite link generation"},
+	"GuildSettings.AllowVoice":                    {Description: "Whether voice commands should be usable in this guild"},
+	"GuildSettings.AutoSendNowPlaying":            {Description: "Whether or not the Now Playing embed should be sent each time a new track is automatically started without user interaction"},
+	"GuildSettings.BotAdminRoles":                 {Description: "An array of role IDs that can admin the bot without the guild administrator permission"},
+	"GuildSettings.BotAdminUsers":                 {Description: "An array of user IDs that can admin the bot without a guild administrator role"},
+	"GuildSettings.BotOptions":                    {Description: "The bot options to use in this guild (true gets overridden if global bot config is false)"},
+	"GuildSettings.BotPrefix":                     {Description: "The bot prefix to use in this guild"},
+	"GuildSettings.CustomResponses":               {Description: "An array of custom responses specific to the guild"},
+	"GuildSettings.Feeds":                         {Description: "A list of feeds for the current guild"},
+	"GuildSettings.LogSettings":                   {Description: "Logging settings"},
+	"GuildSettings.RoleMeList":                    {Description: "An array of rolemes specific to this guild"},
+	"GuildSettings.SwearFilter":                   {Description: "The swear filter settings specific to this guild"},
+	"GuildSettings.TipsChannel":                   {Description: "The channel to post tip messages to"},
+	"GuildSettings.UserJoinMessage":               {Description: "A message to send when a user joins"},
+	"GuildSettings.UserJoinMessageChannel":        {Description: "The channel to send the user join message to"},
+	"GuildSettings.UserLeaveMessage":              {Description: "A message to send when a user leaves"},
+	"GuildSettings.UserLeaveMessageChannel":       {Description: "The channel to send the user leave message to"},
+	"GuildSettings.WebhookChannels":               {Description: "The channels webhooks can post to, where key = alias and value = channel ID"},
+	"GuildSettings.WebhookKeys":                   {Description: "The keys that can post to the webhook channels"},
+	"LogEvents":                                   {Description: "LogEvents holds logging events and whether or not they're enabled"},
+	"LogEvents.ChannelCreate":                     {Description: "Triggered when a channel is created", Section: "Events received from Discord"},
+	"LogEvents.ChannelDelete":                     {Description: "Triggered when a channel is deleted", Section: "Events received from Discord"},
+	"LogEvents.ChannelUpdate":                     {Description: "Triggered when a channel is changed", Section: "Events received from Discord"},
+	"LogEvents.GuildBanAdd":                       {Description: "Triggered when a user is banned", Section: "Events received from Discord"},
+	"LogEvents.GuildBanRemove":                    {Description: "Triggered when a user is unbanned", Section: "Events received from Discord"},
+	"LogEvents.GuildEmojisUpdate":                 {Description: "Triggered when the emojis are changed", Section: "Events received from Discord"},
+	"LogEvents.GuildMemberAdd":                    {Description: "Triggered when a user joins", Section: "Events received from Discord"},
+	"LogEvents.GuildMemberRemove":                 {Description: "Triggered when a user leaves", Section: "Events received from Discord"},
+	"LogEvents.GuildRoleCreate":                   {Description: "Triggered when a role is created", Section: "Events received from Discord"},
+	"LogEvents.GuildRoleDelete":                   {Description: "Triggered when a role is deleted", Section: "Events received from Discord"},
+	"LogEvents.GuildRoleUpdate":                   {Description: "Triggered when a role is changed", Section: "Events received from Discord"},
+	"LogEvents.GuildUpdate":                       {Description: "Triggered when the guild is changed", Section: "Events received from Discord"},
+	"LogEvents.SwearDetect":                       {Description: "Triggered if a user uses a blacklisted (swear) word", Section: "Custom events"},
+	"LogEvents.UserModlog":                        {Description: "Triggered if a user's modlog is updated globally", Section: "Custom events"},
+	"LogEvents.UserUpdate":                        {Description: "Triggered when a user changes their profile", Section: "Events received from Discord"},
+	"LogEvents.VoiceStateUpdate":                  {Description: "Triggered when a user joins, leaves or moves between voice channels", Section: "Events received from Discord"},
+	"LogEvents.WebhookPost":                       {Description: "Triggered when a webhook key posts a message", Section: "Custom events"},
+	"LogSettings":                                 {Description: "LogSettings holds settings specific to logging"},
+	"LogSettings.LoggingChannel":                  {Description: "The channel to log guild events to"},
+	"LogSettings.LoggingEnabled":                  {Description: "Whether or not logging enabled"},
+	"LogSettings.LoggingEvents":                   {Description: "The events to log"},
+	"RoleMe":                                      {Description: "RoleMe stores a roleme event"},
+	"RoleMe.AddRoles":                             {Description: "An array of roles to add"},
+	"RoleMe.CaseSensitive":                        {Description: "Whether or not the trigger message should be case-sensitive"},
+	"RoleMe.ChannelIDs":                           {Description: "An array of channel IDs to apply this roleme event to"},
+	"RoleMe.RemoveRoles":                          {Description: "An array of roles to remove"},
+	"RoleMe.Triggers":                             {Description: "An array of messages to trigger this roleme event"},
+	"Socials":                                     {Description: "Socials holds socials information"},
+	"Socials.NNID":                                {Description: "Nintendo Network ID"},
+	"Socials.PSN":                                 {Description: "PlayStation Network"},
+	"Socials.SwitchFC":                            {Description: "Nintendo Switch friend code"},
+	"Socials.Xbox":                                {Description: "Xbox Live"},
+	"Starboard":                                   {Description: "Starboard holds data specific to a guild's starboard"},
+	"Starboard.Active":                            {Description: "Whether or not the starboard is active"},
+	"Starboard.AllowSelfStar":                     {Description: "Whether or not a user may star their own message to add it to the starboard"},
+	"Starboard.BlacklistChannels":                 {Description: "A list of channel IDs to exclude from the starboard"},
+	"Starboard.BlacklistUsers":                    {Description: "A list of user IDs to exclude from the starboard"},
+	"Starboard.ChannelID":                         {Description: "The channel to use as a starboard"},
+	"Starboard.Emoji":                             {Description: "The emoji to use for the starboard"},
+	"Starboard.MinimumStars":                      {Description: "The minimum amount of stars that must exist for a starboard entry to be made"},
+	"Starboard.NSFWChannelID":                     {Description: "The NSFW channel to use as a starboard for NSFW channels"},
+	"Starboard.NSFWEmoji":                         {Description: "The emoji to use for the NSFW starboard"},
+	"Starboard.StarboardEntries":                  {Description: "A list of starboard entries in a string map, where key = reaction message ID and value = starboard entry message ID"},
+	"StarboardEntry":                              {Description: "StarboardEntry holds data specific to a starboard entry. The only two things *discordgo.Session.ChannelMessage() needs are the channel IDs and the message IDs. Mistakes were made in early attempts by not storing the channel IDs, resulting in being unable to check messages for reaction updates without an event being triggered"},
+	"StarboardEntry.SourceAuthorID":               {Description: "The user ID of the source message's author (empty for entries created before this was tracked)"},
+	"StarboardEntry.SourceChannelID":              {Description: "The channel ID the source message resides in"},
+	"StarboardEntry.SourceMessageID":              {Description: "The source message ID"},
+	"StarboardEntry.StarboardChannelID":           {Description: "The channel ID the starboard entry message resides in (just in case the starboard channel changes and messages need to be moved to a new channel)"},
+	"StarboardEntry.StarboardMessageID":           {Description: "The starboard entry's message ID"},
+	"StarboardEntry.Stars":                        {Description: "The amount of stars on this entry"},
+	"SwearFilter":                                 {Description: "SwearFilter contains settings for the swear filter"},
+	"SwearFilter.AllowAdminBypass":                {Description: "Allows members with the administrative permission to bypass the filter", Section: "Options to tell the swear filter how to operate"},
+	"SwearFilter.AllowBotOwnerBypass":             {Description: "Allows the user set in botData().BotOwnerID to bypass the filter", Section: "Options to tell the swear filter how to operate"},
+	"SwearFilter.BlacklistedWords":                {Description: "A list of words to blacklist"},
+	"SwearFilter.DisableMultiWhitespaceStripping": {Description: "Disables stripping down multiple whitespaces (ex: hello[space][space]world -> hello[space]world)", Section: "Options to tell the swear filter how to operate"},
+	"SwearFilter.DisableNormalize":                {Description: "Disables normalization of alphabetic characters if set to true (ex: à -> a)", Section: "Options to tell the swear filter how to operate"},
+	"SwearFilter.DisableSpacedBypass":             {Description: "Disables testing for spaced bypasses (if hell is in filter, look for occurrences of h and detect only alphabetic characters that follow; ex: h[space]e[space]l[space]l[space] -> hell)", Section: "Options to tell the swear filter how to operate"},
+	"SwearFilter.DisableSpacedTab":                {Description: "Disables converting tabs to singular spaces (ex: [tab][tab] -> [space][space])", Section: "Options to tell the swear filter how to operate"},
+	"SwearFilter.DisableZeroWidthStripping":       {Description: "Disables stripping zero-width spaces", Section: "Options to tell the swear filter how to operate"},
+	"SwearFilter.Enabled":                         {Description: "Whether or not the swear filter is enabled"},
+	"SwearFilter.WarningDeleteTimeout":            {Description: "How many seconds to wait before deleting the warning message (0 = no timeout)", Section: "Options to tell the swear filter how to operate"},
+	"UserSettings":                                {Description: "UserSettings holds settings specific to a user"},
+	"UserSettings.AboutMe":                        {Description: "An aboutme set by the user", Section: "Basic info"},
+	"UserSettings.Balance":                        {Description: "A balance to use as virtual currency for up-and-coming ideas"},
+	"UserSettings.DailyNext":                      {Description: "The next time the user is able to use the daily credits command"},
+	"UserSettings.Socials":                        {Description: "Social media, gamertags, etc", Section: "Socials"},
+	"UserSettings.Timezone":                       {Description: "A timezone set by the user to use in other functions", Section: "Basic info"},
+	"WebhookKey":                                  {Description: "WebhookKey holds a key that can post messages to a guild's webhook channels"},
+	"WebhookKey.Created":                          {Description: ""},
+	"WebhookKey.Hash":                             {Description: "The SHA-256 hash of the key, never included in responses"},
+	"WebhookKey.ID":                               {Description: ""},
+	"WebhookKey.LastUsed":                         {Description: ""},
+	"WebhookKey.Name":                             {Description: ""},
+	"WebhookKey.RateLimit":                        {Description: "How many messages the key may post per minute, 0 uses botOptions.api.webhookRateLimit"},
+}
@@ -0,0 +1,175 @@
+//go:build ignore
+// +build ignore
+
+// layoutgen writes layout_gen.go, which holds the comments describing the settings structs given as arguments and every struct they hold.
+// Comments can't be read through reflection, so this runs through go generate whenever a settings struct changes.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const layoutGenFile = "layout_gen.go"
+
+// typeDecl holds a struct type found in the package along with its doc comment
+type typeDecl struct {
+	Struct *ast.StructType
+	Doc    *ast.CommentGroup
+}
+
+// settingDoc mirrors SettingDoc in layout.go
+type settingDoc struct {
+	Description string
+	Section     string
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, "usage: go run layoutgen.go <struct>...")
+		os.Exit(1)
+	}
+
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, ".", func(info os.FileInfo) bool {
+		name := info.Name()
+		return name != "layoutgen.go" && name != layoutGenFile && !strings.HasSuffix(name, "_test.go")
+	}, parser.ParseComments)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error parsing package: %v\n", err)
+		os.Exit(1)
+	}
+
+	types := make(map[string]*typeDecl)
+	for _, file := range pkgs["main"].Files {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				structType, ok := typeSpec.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+				doc := typeSpec.Doc
+				if doc == nil {
+					doc = genDecl.Doc
+				}
+				types[typeSpec.Name.Name] = &typeDecl{Struct: structType, Doc: doc}
+			}
+		}
+	}
+
+	docs := make(map[string]*settingDoc)
+	queue := os.Args[1:]
+	for len(queue) > 0 {
+		typeName := queue[0]
+		queue = queue[1:]
+		if _, done := docs[typeName]; done {
+			continue
+		}
+		decl, exists := types[typeName]
+		if !exists {
+			fmt.Fprintf(os.Stderr, "struct %s not found\n", typeName)
+			os.Exit(1)
+		}
+		docs[typeName] = &settingDoc{Description: commentText(decl.Doc)}
+
+		section := ""
+		lastLine := 0
+		for _, field := range decl.Struct.Fields.List {
+			if field.Doc != nil {
+				section = commentText(field.Doc) //A comment above a field starts a section of fields
+			} else if fset.Position(field.Pos()).Line > lastLine+1 {
+				section = "" //A blank line ends it
+			}
+			lastLine = fset.Position(field.End()).Line
+
+			for _, name := range field.Names {
+				if !name.IsExported() {
+					continue
+				}
+				docs[typeName+"."+name.Name] = &settingDoc{Description: commentText(field.Comment), Section: section}
+			}
+			if localType := localTypeName(field.Type); localType != "" && types[localType] != nil && len(field.Names) > 0 {
+				queue = append(queue, localType)
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(docs))
+	for key := range docs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	out := &bytes.Buffer{}
+	fmt.Fprintf(out, "// Code generated by go run layoutgen.go %s; DO NOT EDIT.\n\n", strings.Join(os.Args[1:], " "))
+	fmt.Fprintf(out, "package main\n\n")
+	fmt.Fprintf(out, "// settingDocs holds the comments describing each settings struct and its fields, where key = type name or type name + \".\" + field name\n")
+	fmt.Fprintf(out, "var settingDocs = map[string]*SettingDoc{\n")
+	for _, key := range keys {
+		doc := docs[key]
+		fmt.Fprintf(out, "\t%s: {Description: %s", strconv.Quote(key), strconv.Quote(doc.Description))
+		if doc.Section != "" {
+			fmt.Fprintf(out, ", Section: %s", strconv.Quote(doc.Section))
+		}
+		fmt.Fprintf(out, "},\n")
+	}
+	fmt.Fprintf(out, "}\n")
+
+	source, err := format.Source(out.Bytes())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error formatting %s: %v\n", layoutGenFile, err)
+		os.Exit(1)
+	}
+	if err := ioutil.WriteFile(layoutGenFile, source, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "error writing %s: %v\n", layoutGenFile, err)
+		os.Exit(1)
+	}
+}
+
+// commentText returns a comment as a single line, ending each line that doesn't end a sentence already with a period
+func commentText(comment *ast.CommentGroup) string {
+	if comment == nil {
+		return ""
+	}
+	lines := make([]string, 0)
+	for _, line := range strings.Split(comment.Text(), "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" {
+			continue
+		}
+		if len(lines) > 0 && !strings.ContainsAny(lines[len(lines)-1][len(lines[len(lines)-1])-1:], ".!?:") {
+			lines[len(lines)-1] += "."
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, " ")
+}
+
+// localTypeName returns the name of the type declared in this package that a field holds, if any, looking through pointers, slices and maps
+func localTypeName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return expr.Name
+	case *ast.StarExpr:
+		return localTypeName(expr.X)
+	case *ast.ArrayType:
+		return localTypeName(expr.Elt)
+	case *ast.MapType:
+		return localTypeName(expr.Value)
+	}
+	return ""
+}
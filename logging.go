@@ -1,49 +1,460 @@
 package main
 
 import (
+	"encoding/json"
+	"fmt"
 	"io"
-	"io/ioutil"
-	"log"
 	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LogLevel is how severe a log message is, from least to most severe
+type LogLevel int
+
+// Log levels, from least to most severe
+const (
+	LogDebug LogLevel = iota
+	LogInfo
+	LogWarning
+	LogError
 )
 
 var (
-	//logFlags contains a list of flags to use when logging information
-	logFlags = log.Ldate | log.Ltime | log.Lmicroseconds | log.Lshortfile
+	//The names of every log level, as used in the configuration and the debug command
+	logLevelNames = map[LogLevel]string{LogDebug: "debug", LogInfo: "info", LogWarning: "warning", LogError: "error"}
+
+	//The subsystems that have their own loggers, and so can be given their own log level
+	logSubsystems = []string{"api", "feed", "starboard", "voice"}
+
+	//Where and how every logger writes, along with the levels they log at
+	logOutput = &LogOutput{Format: "text", Level: LogInfo, SubsystemLevels: make(map[string]LogLevel)}
+
+	//Contains a pointer to the current log file
+	logFile *RotatingFile
 
 	//Debug logs debugging and tracing information
-	Debug *log.Logger
+	Debug *Logger
 
 	//Info logs information the reader can use to know what is happening
-	Info *log.Logger
+	Info *Logger
 
 	//Warning logs ignoreable issues that the reader may wish to know about
-	Warning *log.Logger
+	Warning *Logger
 
 	//Error logs information that the reader should use to resolve breaking issues
-	Error *log.Logger
+	Error *Logger
 
 	//Same as above, but for the API
-	DebugAPI   *log.Logger
-	InfoAPI    *log.Logger
-	WarningAPI *log.Logger
-	ErrorAPI   *log.Logger
+	DebugAPI   *Logger
+	InfoAPI    *Logger
+	WarningAPI *Logger
+	ErrorAPI   *Logger
+
+	//Same as above, but for voice playback
+	DebugVoice   *Logger
+	InfoVoice    *Logger
+	WarningVoice *Logger
+	ErrorVoice   *Logger
+
+	//Same as above, but for feeds
+	DebugFeed   *Logger
+	InfoFeed    *Logger
+	WarningFeed *Logger
+	ErrorFeed   *Logger
+
+	//Same as above, but for starboards
+	DebugStarboard   *Logger
+	InfoStarboard    *Logger
+	WarningStarboard *Logger
+	ErrorStarboard   *Logger
 )
 
-func initLogging(logFile *os.File, processType, debug string) {
-	Debug = log.New(ioutil.Discard, "["+processType+"] DEBUG: ", logFlags)
-	if debug == "true" {
-		Debug = log.New(io.MultiWriter(logFile, os.Stdout), "["+processType+"] DEBUG: ", logFlags)
+func (level LogLevel) String() string {
+	return logLevelNames[level]
+}
+
+// parseLogLevel returns the log level with the given name
+func parseLogLevel(name string) (LogLevel, error) {
+	for level, levelName := range logLevelNames {
+		if strings.EqualFold(name, levelName) {
+			return level, nil
+		}
+	}
+	return LogInfo, fmt.Errorf("unknown log level %q, must be debug, info, warning or error", name)
+}
+
+// isLogSubsystem returns whether or not the given name is a subsystem with its own loggers
+func isLogSubsystem(name string) bool {
+	for _, subsystem := range logSubsystems {
+		if name == subsystem {
+			return true
+		}
+	}
+	return false
+}
+
+// LogOutput holds where and how every logger writes, along with the levels they log at
+type LogOutput struct {
+	sync.RWMutex
+
+	ProcessType     string              //The type of process writing the logs, ex: BOT or MAIN
+	Format          string              //The format to write log messages in, either text or json
+	File            io.Writer           //The log file to write to alongside the console
+	Level           LogLevel            //The minimum level to log at
+	SubsystemLevels map[string]LogLevel //The minimum level to log at for specific subsystems, overriding Level
+}
+
+// Logger writes log messages of a single level for a single subsystem, along with any fields attached to it
+type Logger struct {
+	level     LogLevel
+	subsystem string        //The subsystem the logger belongs to, empty for the main loggers
+	fields    []interface{} //Key-value pairs to write with every log message
+}
+
+// With returns a copy of the logger that writes the given key-value pairs with every log message
+// Ex: Info.With("guild", guildID, "user", userID).Println("Something happened")
+func (logger *Logger) With(keysAndValues ...interface{}) *Logger {
+	if len(keysAndValues)%2 != 0 {
+		keysAndValues = append(keysAndValues, "")
+	}
+	fields := make([]interface{}, 0, len(logger.fields)+len(keysAndValues))
+	fields = append(fields, logger.fields...)
+	fields = append(fields, keysAndValues...)
+	return &Logger{level: logger.level, subsystem: logger.subsystem, fields: fields}
+}
+
+// Enabled returns whether or not the logger's messages are currently being written
+func (logger *Logger) Enabled() bool {
+	logOutput.RLock()
+	defer logOutput.RUnlock()
+
+	minLevel := logOutput.Level
+	if subsystemLevel, exists := logOutput.SubsystemLevels[logger.subsystem]; exists {
+		minLevel = subsystemLevel
+	}
+	return logger.level >= minLevel
+}
+
+// Printf writes a log message, formatted in the manner of fmt.Printf
+func (logger *Logger) Printf(format string, v ...interface{}) {
+	if logger.Enabled() {
+		logger.output(fmt.Sprintf(format, v...))
+	}
+}
+
+// Println writes a log message, formatted in the manner of fmt.Println
+func (logger *Logger) Println(v ...interface{}) {
+	if logger.Enabled() {
+		logger.output(fmt.Sprintln(v...))
+	}
+}
+
+// Print writes a log message, formatted in the manner of fmt.Print
+func (logger *Logger) Print(v ...interface{}) {
+	if logger.Enabled() {
+		logger.output(fmt.Sprint(v...))
+	}
+}
+
+func (logger *Logger) output(msg string) {
+	now := time.Now()
+	caller := "???:0"
+	if _, file, line, ok := runtime.Caller(2); ok { //Skip output and the Print function that called it
+		caller = filepath.Base(file) + ":" + strconv.Itoa(line)
+	}
+	msg = strings.TrimRight(msg, "\n")
+
+	logOutput.Lock()
+	defer logOutput.Unlock()
+
+	var line []byte
+	if logOutput.Format == "json" {
+		line = logger.formatJSON(now, caller, msg)
+	} else {
+		line = logger.formatText(now, caller, msg)
+	}
+
+	if logOutput.File != nil {
+		logOutput.File.Write(line)
+	}
+	if logger.level >= LogError {
+		os.Stderr.Write(line)
+	} else {
+		os.Stdout.Write(line)
+	}
+}
+
+// formatText formats a log message as a line of text, ex:
+// [BOT-1/VOICE] INFO: 2021/06/01 12:00:00.000000 voice.go:120: Playing song guild=123 channel=456
+func (logger *Logger) formatText(now time.Time, caller, msg string) []byte {
+	prefix := logOutput.ProcessType
+	if logOutput.ProcessType == "BOT" && shardCount > 1 {
+		prefix += "-" + strconv.Itoa(shardID)
+	}
+	if logger.subsystem != "" {
+		prefix += "/" + strings.ToUpper(logger.subsystem)
+	}
+
+	line := &strings.Builder{}
+	fmt.Fprintf(line, "[%s] %s: %s %s: %s", prefix, strings.ToUpper(logger.level.String()), now.Format("2006/01/02 15:04:05.000000"), caller, msg)
+	for i := 0; i < len(logger.fields); i += 2 {
+		value := fmt.Sprint(logger.fields[i+1])
+		if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+			value = strconv.Quote(value)
+		}
+		fmt.Fprintf(line, " %v=%s", logger.fields[i], value)
+	}
+	line.WriteString("\n")
+	return []byte(line.String())
+}
+
+// formatJSON formats a log message as a line of JSON, ex:
+// {"caller":"voice.go:120","guild":"123","level":"info","msg":"Playing song","process":"BOT","shard":1,"subsystem":"voice","time":"..."}
+func (logger *Logger) formatJSON(now time.Time, caller, msg string) []byte {
+	entry := make(map[string]interface{})
+	for i := 0; i < len(logger.fields); i += 2 {
+		value := logger.fields[i+1]
+		switch v := value.(type) {
+		case error:
+			value = v.Error()
+		case fmt.Stringer:
+			value = v.String()
+		}
+		entry[fmt.Sprint(logger.fields[i])] = value
+	}
+	entry["time"] = now.Format(time.RFC3339Nano)
+	entry["level"] = logger.level.String()
+	entry["process"] = logOutput.ProcessType
+	entry["caller"] = caller
+	entry["msg"] = msg
+	if logger.subsystem != "" {
+		entry["subsystem"] = logger.subsystem
+	}
+	if logOutput.ProcessType == "BOT" && shardCount > 1 {
+		entry["shard"] = shardID
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		line, _ = json.Marshal(map[string]interface{}{"time": entry["time"], "level": entry["level"], "process": entry["process"], "caller": caller, "msg": msg, "logError": err.Error()})
+	}
+	return append(line, '\n')
+}
+
+// newLoggers returns a logger for each level of the given subsystem
+func newLoggers(subsystem string) (debug, info, warning, err *Logger) {
+	return &Logger{level: LogDebug, subsystem: subsystem}, &Logger{level: LogInfo, subsystem: subsystem}, &Logger{level: LogWarning, subsystem: subsystem}, &Logger{level: LogError, subsystem: subsystem}
+}
+
+// initLogging creates every logger, writing to the rotating log file at logPath alongside the console, or only to the console if logPath is empty
+func initLogging(logPath string, processType string, debug bool) error {
+	Debug, Info, Warning, Error = newLoggers("")
+	DebugAPI, InfoAPI, WarningAPI, ErrorAPI = newLoggers("api")
+	DebugVoice, InfoVoice, WarningVoice, ErrorVoice = newLoggers("voice")
+	DebugFeed, InfoFeed, WarningFeed, ErrorFeed = newLoggers("feed")
+	DebugStarboard, InfoStarboard, WarningStarboard, ErrorStarboard = newLoggers("starboard")
+
+	logOutput.Lock()
+	defer logOutput.Unlock()
+
+	logOutput.ProcessType = processType
+	if logPath != "" {
+		file, err := openRotatingFile(logPath)
+		if err != nil {
+			return err
+		}
+		logFile = file
+		logOutput.File = logFile
+	}
+	if debug {
+		logOutput.Level = LogDebug
+	}
+	return nil
+}
+
+// configureLogging applies the given logging configuration, which should already have been checked by PrepConfig
+// Levels set at runtime with the debug command are replaced by the configured ones
+func configureLogging(config LoggingConfig) {
+	logOutput.Lock()
+	logOutput.Format = config.Format
+	logOutput.Level, _ = parseLogLevel(config.Level)
+	if debug {
+		logOutput.Level = LogDebug //The debug flag always wins over the configuration
+	}
+	logOutput.SubsystemLevels = make(map[string]LogLevel)
+	for subsystem, levelName := range config.Subsystems {
+		if level, err := parseLogLevel(levelName); err == nil {
+			logOutput.SubsystemLevels[subsystem] = level
+		}
+	}
+	logOutput.Unlock()
+
+	if logFile != nil {
+		logFile.Configure(config)
+	}
+	if apiAuditLog != nil {
+		apiAuditLog.Configure(config) //The audit log is kept like any other log
+	}
+}
+
+// setLogLevel sets the minimum level to log at for the given subsystem, or for everything if the subsystem is empty
+func setLogLevel(subsystem string, level LogLevel) {
+	logOutput.Lock()
+	defer logOutput.Unlock()
+
+	if subsystem == "" {
+		logOutput.Level = level
+		return
+	}
+	logOutput.SubsystemLevels[subsystem] = level
+}
+
+// logLevels returns the minimum level to log at for everything, where key = "", and for each subsystem
+func logLevels() map[string]LogLevel {
+	logOutput.RLock()
+	defer logOutput.RUnlock()
+
+	levels := map[string]LogLevel{"": logOutput.Level}
+	for _, subsystem := range logSubsystems {
+		levels[subsystem] = logOutput.Level
+		if level, exists := logOutput.SubsystemLevels[subsystem]; exists {
+			levels[subsystem] = level
+		}
+	}
+	return levels
+}
+
+// RotatingFile is a log file that's rotated once it grows too large or too old, keeping a limited amount of rotated files
+// Rotated files have the time they were rotated appended to their path, ex: clinet.bot.log.20210601-120000.000000000
+type RotatingFile struct {
+	sync.Mutex
+
+	Path        string        //The path to the current log file
+	MaxSize     int64         //The size in bytes to rotate at, 0 = never rotate for size
+	RotateEvery time.Duration //How often to rotate, aligned to UTC (24 hours rotates at midnight UTC), 0 = never rotate for age
+	MaxBackups  int           //How many rotated files to keep, 0 = keep all of them
+	MaxAge      time.Duration //How long to keep rotated files for, 0 = keep them forever
+
+	file      *os.File
+	size      int64
+	lastWrite time.Time
+}
+
+func openRotatingFile(path string) (*RotatingFile, error) {
+	rotatingFile := &RotatingFile{Path: path}
+	if err := rotatingFile.open(); err != nil {
+		return nil, err
 	}
-	Info = log.New(io.MultiWriter(logFile, os.Stdout), "["+processType+"] INFO: ", logFlags)
-	Warning = log.New(io.MultiWriter(logFile, os.Stdout), "["+processType+"] WARNING: ", logFlags)
-	Error = log.New(io.MultiWriter(logFile, os.Stderr), "["+processType+"] ERROR: ", logFlags)
+	return rotatingFile, nil
+}
+
+func (rotatingFile *RotatingFile) open() error {
+	file, err := os.OpenFile(rotatingFile.Path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0666)
+	if err != nil {
+		return err
+	}
+	fileInfo, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	rotatingFile.file = file
+	rotatingFile.size = fileInfo.Size()
+	rotatingFile.lastWrite = fileInfo.ModTime() //A file left from before a restart still rotates on time
+	return nil
+}
+
+func (rotatingFile *RotatingFile) Write(p []byte) (int, error) {
+	rotatingFile.Lock()
+	defer rotatingFile.Unlock()
+
+	now := time.Now()
+	if rotatingFile.file != nil && rotatingFile.shouldRotate(now, len(p)) {
+		if err := rotatingFile.rotate(now); err != nil {
+			fmt.Fprintf(os.Stderr, "Error rotating log file %s: %v\n", rotatingFile.Path, err)
+		}
+	}
+	if rotatingFile.file == nil {
+		if err := rotatingFile.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := rotatingFile.file.Write(p)
+	rotatingFile.size += int64(n)
+	rotatingFile.lastWrite = now
+	return n, err
+}
+
+func (rotatingFile *RotatingFile) shouldRotate(now time.Time, writeSize int) bool {
+	if rotatingFile.MaxSize > 0 && rotatingFile.size > 0 && rotatingFile.size+int64(writeSize) > rotatingFile.MaxSize {
+		return true
+	}
+	if rotatingFile.RotateEvery > 0 && rotatingFile.size > 0 && !rotatingFile.lastWrite.Truncate(rotatingFile.RotateEvery).Equal(now.Truncate(rotatingFile.RotateEvery)) {
+		return true
+	}
+	return false
+}
+
+func (rotatingFile *RotatingFile) rotate(now time.Time) error {
+	rotatingFile.file.Close()
+	rotatingFile.file = nil
+
+	if err := os.Rename(rotatingFile.Path, rotatingFile.Path+"."+now.Format("20060102-150405.000000000")); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	rotatingFile.prune(now)
+	return rotatingFile.open()
+}
+
+// prune removes the rotated files past the amount to keep or older than the age to keep them for
+func (rotatingFile *RotatingFile) prune(now time.Time) {
+	rotatedFiles, err := filepath.Glob(rotatingFile.Path + ".*")
+	if err != nil {
+		return
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(rotatedFiles))) //Newest first, as the rotation times sort in order
+
+	for i, rotatedFile := range rotatedFiles {
+		if rotatingFile.MaxBackups > 0 && i >= rotatingFile.MaxBackups {
+			os.Remove(rotatedFile)
+			continue
+		}
+		if rotatingFile.MaxAge > 0 {
+			if fileInfo, err := os.Stat(rotatedFile); err == nil && now.Sub(fileInfo.ModTime()) > rotatingFile.MaxAge {
+				os.Remove(rotatedFile)
+			}
+		}
+	}
+}
+
+// Configure applies the rotation settings of the given logging configuration
+func (rotatingFile *RotatingFile) Configure(config LoggingConfig) {
+	rotatingFile.Lock()
+	defer rotatingFile.Unlock()
+
+	rotatingFile.MaxSize = int64(config.MaxSize) * 1024 * 1024
+	rotatingFile.RotateEvery = time.Duration(config.RotateEvery) * time.Hour
+	rotatingFile.MaxBackups = config.MaxBackups
+	rotatingFile.MaxAge = time.Duration(config.MaxAge) * 24 * time.Hour
+}
+
+// Close closes the current log file
+func (rotatingFile *RotatingFile) Close() error {
+	rotatingFile.Lock()
+	defer rotatingFile.Unlock()
 
-	DebugAPI = log.New(ioutil.Discard, "[API] DEBUG: ", logFlags)
-	if debug == "true" {
-		DebugAPI = log.New(io.MultiWriter(logFile, os.Stdout), "[API] DEBUG: ", logFlags)
+	if rotatingFile.file == nil {
+		return nil
 	}
-	InfoAPI = log.New(io.MultiWriter(logFile, os.Stdout), "[API] INFO: ", logFlags)
-	WarningAPI = log.New(io.MultiWriter(logFile, os.Stdout), "[API] WARNING: ", logFlags)
-	ErrorAPI = log.New(io.MultiWriter(logFile, os.Stderr), "[API] ERROR: ", logFlags)
+	err := rotatingFile.file.Close()
+	rotatingFile.file = nil
+	return err
 }
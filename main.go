@@ -4,39 +4,27 @@ import (
 	"bufio"
 	"encoding/json"
 	"flag"
+	"fmt"
 	"io/ioutil"
 	"math/rand"
-	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
-	"github.com/mmcdole/gofeed"
-
-	duckduckgo "github.com/JoshuaDoes/duckduckgolang"
-	soundcloud "github.com/JoshuaDoes/go-soundcloud"
-	wolfram "github.com/JoshuaDoes/go-wolfram"
-	gassist "github.com/JoshuaDoes/google-assistant/v1alpha2"
-	"github.com/JoshuaDoes/spotigo"
 	"github.com/bwmarrin/discordgo"
-	"github.com/google/go-github/github"
-	klogger "github.com/koffeinsource/go-klogger"
-	xkcd "github.com/nishanths/go-xkcd"
-	lyrics "github.com/rhnvrm/lyric-api-go"
 	"github.com/robfig/cron"
-	ytdl "github.com/kkdai/youtube/v2"
-	"github.com/superwhiskers/fennel"
-	"google.golang.org/api/googleapi/transport"
-	"google.golang.org/api/youtube/v3"
 )
 
 var (
-	//Contains all bot configurations
-	botData *BotData = &BotData{}
+	//Contains all bot configurations, read with botData() as reloads swap it
+	botDataValue atomic.Value
 
 	//Contains guild-specific data in a string map, where key = guild ID
 	guildData = make(map[string]*GuildData)
@@ -47,62 +35,107 @@ var (
 	//Contains user-specific settings in a string map, where key = user ID
 	userSettings = make(map[string]*UserSettings)
 
+	//Guards userSettings, which commands, the API and shard syncing all use from their own goroutines
+	userSettingsLock sync.RWMutex
+
 	//Contains guild-specific starboard data in a string map, where key = guild ID
 	starboards = make(map[string]*Starboard)
 
 	//Contains all remind entries
 	remindEntries = make([]RemindEntry, 0)
 
+	//Guards remindEntries, which commands, the API and every reminder's timer change from their own goroutines
+	remindEntriesLock sync.RWMutex
+
 	//Contains guild-specific voice data in a string map, where key = guild ID
 	voiceData = make(map[string]*Voice)
 
-	//Contains a pointer to the current log file
-	logFile *os.File
-
 	//Contains the current uptime
 	uptime time.Time
 
 	//Whether or not discordReady() has been called
 	isReady bool
+
+	//Whether or not stateRestoreAll() has been called
+	stateLoaded bool
+
+	//Whether or not botData holds the loaded configuration, which the bot master never loads
+	configLoaded bool
+
+	//The state directories that were loaded on startup
+	stateSources []string
 )
 
 var (
 	configFile       string
 	gcpAuthTokenFile string
 
-	configIsBot string
+	configIsBot bool
 	masterPID   int
-	killOldBot  string
-	debug       string
+	killOldBot  bool
+	debug       bool
+	checkConfig bool
+	selfTest    bool
+	heartbeat   bool
+	restarts    int
+	shardID     int
+	shardCount  int
+
+	//Configuration layer flags, applied over the configuration file and environment when explicitly set
+	flagPrefix  string
+	flagAPI     bool
+	flagAPIHost string
 )
 
 func init() {
+	setBotData(&BotData{})
+
 	flag.StringVar(&configFile, "config", "config.json", "The path to the JSON-structured configuration file")
 	flag.StringVar(&gcpAuthTokenFile, "gcptoken", "client_secret_XXXXXXXXXXXX-XXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXX.apps.googleusercontent.com.json", "The path to the JSON-structured Google Cloud Platform authentication token")
-	flag.StringVar(&configIsBot, "bot", "false", "Whether or not to act as a bot")
+	flag.BoolVar(&configIsBot, "bot", false, "Whether or not to act as a bot")
 	flag.IntVar(&masterPID, "masterpid", -1, "The bot master's PID")
-	flag.StringVar(&killOldBot, "killold", "false", "Whether or not to kill an old bot process")
-	flag.StringVar(&debug, "debug", "false", "Whether or not to output debugging and trace messages")
+	flag.BoolVar(&killOldBot, "killold", false, "Whether or not to kill an old bot process")
+	flag.BoolVar(&debug, "debug", false, "Whether or not to output debugging and trace messages")
+	flag.BoolVar(&heartbeat, "heartbeat", false, "Whether or not to send heartbeats to the bot master over file descriptor 3")
+	flag.IntVar(&restarts, "restarts", 0, "How many times the bot master has restarted the bot")
+	flag.IntVar(&shardID, "shard", 0, "The ID of the shard to connect to Discord as")
+	flag.IntVar(&shardCount, "shards", 1, "The total number of shards")
+	flag.StringVar(&ipcHubAddr, "ipc", "", "The address of the bot master's IPC hub, when sharded")
+	flag.BoolVar(&checkConfig, "checkconfig", false, "Validates the configuration, prints a report and exits non-zero if it has errors")
+	flag.BoolVar(&selfTest, "selftest", false, "Checks that this build can start with the configuration and read the state, and exits non-zero if it can't")
+	flag.StringVar(&flagPrefix, "prefix", "", "Overrides the configured command prefix")
+	flag.BoolVar(&flagAPI, "api", false, "Overrides whether or not the API is enabled")
+	flag.StringVar(&flagAPIHost, "apihost", "", "Overrides the configured API host")
+}
+
+func main() {
+	//Flags are parsed here rather than in init so that go test can pass its own flags to the test binary
 	flag.Parse()
 
-	if configIsBot == "true" {
-		logFile, err := os.OpenFile("clinet.bot.log", os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
-		if err != nil {
-			panic("Error creating log file: " + err.Error())
-		}
-		initLogging(logFile, "BOT", debug)
-	} else {
-		logFile, err := os.OpenFile("clinet.main.log", os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
-		if err != nil {
-			panic("Error creating log file: " + err.Error())
+	//Boolean flags used to take a separate value (ex: -debug true), which would now silently stop flag parsing
+	if flag.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "Unexpected argument %q, boolean flags are now set as -flag or -flag=false\n", flag.Arg(0))
+		os.Exit(2)
+	}
+
+	logPath, processType := "clinet.main.log", "MAIN"
+	if configIsBot {
+		logPath, processType = "clinet.bot.log", "BOT"
+		if shardCount > 1 {
+			logPath = "clinet.bot." + strconv.Itoa(shardID) + ".log" //Each shard rotates its own log file
 		}
-		initLogging(logFile, "MAIN", debug)
 	}
-}
+	if checkConfig || selfTest {
+		logPath = "" //Checks are run by the master process while the bot is still running, so they mustn't write to or rotate its log file
+	}
+	if err := initLogging(logPath, processType, debug); err != nil {
+		panic("Error creating log file: " + err.Error())
+	}
 
-func main() {
 	defer recoverPanic()
-	defer logFile.Close()
+	if logFile != nil {
+		defer logFile.Close()
+	}
 
 	Info.Println("Clinet © JoshuaDoes: 2017-2021.")
 	Info.Println("Build ID: " + BuildID)
@@ -110,7 +143,14 @@ func main() {
 
 	uptime = time.Now()
 
-	if configIsBot == "true" {
+	if checkConfig {
+		os.Exit(runConfigCheck(configFile))
+	}
+	if selfTest {
+		os.Exit(runSelfTest(configFile))
+	}
+
+	if configIsBot {
 		numCPU := runtime.NumCPU()
 		runtime.GOMAXPROCS(numCPU * 2)
 
@@ -118,111 +158,57 @@ func main() {
 		Debug.Printf("Max Process Count: %d\n", numCPU*2)
 
 		Info.Println("Loading settings...")
-		configFileHandle, err := os.Open(configFile)
-		defer configFileHandle.Close()
-		if err != nil {
-			Error.Println(err)
-			os.Exit(1)
-		} else {
-			configParser := json.NewDecoder(configFileHandle)
-			if err = configParser.Decode(&botData); err != nil {
+		configData, errs := loadBotData(configFile) //Check the configuration for any errors or inconsistencies, then prepare it for usage
+		if len(errs) > 0 {
+			for _, err := range errs {
 				Error.Println(err)
-				os.Exit(1)
-			} else {
-				configErr := botData.PrepConfig() //Check the configuration for any errors or inconsistencies, then prepare it for usage
-				if configErr != nil {
-					Error.Println(configErr)
-					os.Exit(1)
-				}
 			}
+			os.Exit(1)
+		}
+		setBotData(configData)
+		configLoaded = true
+		configureLogging(botData().BotOptions.Logging)
+
+		if heartbeat {
+			Debug.Println("Sending heartbeats to the bot master...")
+			go sendHeartbeats(os.NewFile(3, "heartbeat"))
 		}
 
 		Info.Println("Initializing clients for external services...")
+		for _, err := range botData().initClients() {
+			Error.Println(err)
+		}
+
 		if gcpAuthTokenFile != "" {
-			tokenJSON, err := ioutil.ReadFile(gcpAuthTokenFile)
+			Info.Println("Initializing Google Assistant...")
+			googleAssistant, err := newGoogleAssistant(gcpAuthTokenFile, nil)
 			if err != nil {
 				Error.Printf("Error initializing Google Assistant: %v", err)
 			} else {
-				token := &gassist.Token{}
-				err = json.Unmarshal(tokenJSON, &token)
-				if err != nil {
-					Error.Printf("Error initializing Google Assistant: %v", err)
-				} else {
-					googleAssistant, err := gassist.NewAssistant(token, nil, "en-US", gassist.NewDevice("254636LIVE0001", "assistant-for-clinet"), gassist.NewAudioSettings(1, 1, 16000, 16000, 100))
-					if err != nil {
-						Error.Printf("Error initializing Google Assistant: %v", err)
-					} else {
-						botData.BotClients.GoogleAssistant = googleAssistant
-
-						if googleAssistant.GetAuthURL() != "" {
-							Warning.Println("Please open the following URL to authenticate with Google Cloud Platform:", googleAssistant.GetAuthURL())
-							Warning.Println("When you've authenticated successfully, press enter to continue.")
-							bufio.NewReader(os.Stdin).ReadLine()
-						}
-					}
+				botData().BotClients.GoogleAssistant = googleAssistant
+
+				if googleAssistant.GetAuthURL() != "" {
+					Warning.Println("Please open the following URL to authenticate with Google Cloud Platform:", googleAssistant.GetAuthURL())
+					Warning.Println("When you've authenticated successfully, press enter to continue.")
+					bufio.NewReader(os.Stdin).ReadLine()
 				}
 			}
 		}
-		if botData.BotOptions.UseDuckDuckGo {
-			botData.BotClients.DuckDuckGo = &duckduckgo.Client{AppName: botData.BotKeys.DuckDuckGoAppName}
-		}
-		if botData.BotOptions.UseImgur {
-			botData.BotClients.Imgur.HTTPClient = &http.Client{}
-			botData.BotClients.Imgur.Log = &klogger.CLILogger{}
-			botData.BotClients.Imgur.ImgurClientID = botData.BotKeys.ImgurClientID
-		}
-		if botData.BotOptions.UseSoundCloud {
-			botData.BotClients.SoundCloud = &soundcloud.Client{ClientID: botData.BotKeys.SoundCloudClientID}
-		}
-		if botData.BotOptions.UseSpotify {
-			botData.BotClients.Spotify = &spotigo.Client{Host: botData.BotKeys.SpotifyHost, Pass: botData.BotKeys.SpotifyPass}
-		}
-		if botData.BotOptions.UseWolframAlpha {
-			botData.BotClients.Wolfram = &wolfram.Client{AppID: botData.BotKeys.WolframAppID}
-		}
-		if botData.BotOptions.UseXKCD {
-			botData.BotClients.XKCD = xkcd.NewClient()
-		}
-		if botData.BotOptions.UseYouTube {
-			httpClient := &http.Client{
-				Transport: &transport.APIKey{Key: botData.BotKeys.YouTubeAPIKey},
-			}
-			youtubeClient, err := youtube.New(httpClient)
-			if err != nil {
-				Error.Printf("Error initializing YouTube: %v", err)
-			} else {
-				botData.BotClients.YouTube = youtubeClient
-			}
-			botData.BotClients.YTDL = &ytdl.Client{
-				HTTPClient: httpClient,
-			}
-		}
-		if botData.BotOptions.UseGitHub {
-			botData.BotClients.GitHub = github.NewClient(nil)
-		}
-		if botData.BotOptions.UseLyrics {
-			botData.BotClients.Lyrics = lyrics.New(lyrics.WithoutProviders(), lyrics.WithLyricsWikia(), lyrics.WithMusixMatch(), lyrics.WithSongLyrics(), lyrics.WithGeniusLyrics(botData.BotKeys.GeniusAccessToken))
-		}
-		if botData.BotOptions.UseNinty {
-			nintyClient, err := fennel.NewAccountServerClient("https://account.nintendo.net/v1/api", ctrCommonCert, ctrCommonKey, botData.BotKeys.Ninty)
-			if err != nil {
-				Error.Printf("Error initializing Ninty: %v", err)
-			} else {
-				botData.BotClients.Ninty = nintyClient
-			}
-		}
-		if botData.BotOptions.UseFeed {
-			botData.BotClients.FeedParser = gofeed.NewParser()
-		}
 
 		Info.Println("Creating a Discord session...")
-		discord, err := discordgo.New("Bot " + botData.BotToken)
+		discord, err := discordgo.New("Bot " + botData().BotToken)
 		if err != nil {
 			panic(err)
 		}
-		if debug == "true" {
+		instrumentClient(discord.Client)
+		if debug {
 			discord.LogLevel = discordgo.LogInformational
 		}
+		if shardCount > 1 {
+			Info.Printf("Connecting as shard %d of %d...", shardID, shardCount)
+			discord.ShardID = shardID
+			discord.ShardCount = shardCount
+		}
 
 		Info.Println("Registering Discord event handlers...")
 		discord.AddHandler(discordChannelCreate)
@@ -258,9 +244,9 @@ func main() {
 			panic(err)
 		}
 		Info.Println("Connected successfully!")
-		botData.DiscordSession = discord
+		botData().DiscordSession = discord
 
-		if botData.SendOwnerStackTraces {
+		if botData().SendOwnerStackTraces {
 			checkPanicRecovery()
 		}
 
@@ -274,62 +260,42 @@ func main() {
 
 		Debug.Println("Checking if bot was updated...")
 		checkUpdate()
+		go confirmUpdate()
 
-		if botData.BotOptions.API.Enabled {
-			Info.Printf("Starting API on [%s]...\n", botData.BotOptions.API.Host)
-			go StartAPI(botData.BotOptions.API.Host)
-		}
-
-		Debug.Println("Waiting for SIGINT syscall signal...")
-		sc := make(chan os.Signal, 1)
-		signal.Notify(sc, syscall.SIGINT)
-		<-sc
-
-		//Save the current state before shutting down
-		// Note: This is done before shutting down as the shutdown process may yield
-		//       some errors with goroutines like voice playback
-		stateSaveAll()
+		Debug.Println("Checking if bot was rolled back...")
+		checkRollback()
 
-		botData.BotClients.GoogleAssistant.Close()
+		if shardCount > 1 {
+			Debug.Println("Starting IPC server...")
+			if err := startIPCServer(); err != nil {
+				Error.Printf("Error starting the IPC server, statistics from the other shards will be unavailable: %v", err)
+			}
+		}
 
-		//Leave all voice channels
-		for _, voiceIDRow := range voiceData {
-			if voiceIDRow.IsConnected() {
-				if voiceIDRow.IsStreaming() {
-					//Notify users that an update is occuring
-					botData.DiscordSession.ChannelMessageSendEmbed(voiceIDRow.TextChannelID, NewEmbed().SetTitle("Update").SetDescription("Your audio playback has been interrupted for a "+botData.BotName+" update event. You may resume playback in a few seconds.").SetColor(0x1C1C1C).MessageEmbed)
+		if botData().BotOptions.API.Enabled && shardID == 0 { //The first shard serves the API for every shard
+			Info.Printf("Starting API on [%s]...\n", botData().BotOptions.API.Host)
+			go StartAPI(botData().BotOptions.API.Host)
+		}
 
-					debugLog("> Stopping stream in voice channel "+voiceIDRow.VoiceConnection.ChannelID+"...", false)
-					voiceIDRow.Stop()
-				}
-				debugLog("> Closing connection to voice channel "+voiceIDRow.VoiceConnection.ChannelID+"...", false)
-				voiceIDRow.VoiceConnection.Close()
-			}
+		if botData().BotOptions.ConfigWatchFrequency > 0 {
+			Debug.Println("Watching the configuration file for changes...")
+			scheduleConfigWatcher(botData().BotOptions.ConfigWatchFrequency)
 		}
 
-		Info.Println("Disconnecting from Discord...")
-		discord.Close()
-	} else {
-		botPid := spawnBot()
+		Debug.Println("Waiting for SIGINT, SIGTERM or SIGHUP syscall signals...")
 		sc := make(chan os.Signal, 1)
-		signal.Notify(sc, syscall.SIGINT)
-		watchdogTicker := time.Tick(1 * time.Second)
-
-		for {
-			select {
-			case _, ok := <-sc:
-				if ok {
-					botProcess, _ := os.FindProcess(botPid)
-					_ = botProcess.Signal(syscall.SIGINT)
-					waitProcess(botPid)
-					os.Exit(0)
-				}
-			case <-watchdogTicker:
-				if !isProcessRunning(botPid) {
-					botPid = spawnBot()
-				}
+		signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+		for sig := range sc {
+			if sig != syscall.SIGHUP {
+				break
 			}
+			Info.Println("Received SIGHUP, reloading the bot configuration...")
+			logReloadErrors(reloadBotData())
 		}
+
+		shutdownBot("shutdown")
+	} else {
+		superviseBot()
 	}
 }
 
@@ -340,19 +306,25 @@ func discordReady(session *discordgo.Session, event *discordgo.Ready) {
 	defer recoverPanic()
 
 	Debug.Println("Setting bot username from Discord state...")
-	botData.BotName = session.State.User.Username
+	botData().BotName = session.State.User.Username
 
 	Debug.Println("Initializing commands...")
-	initCommands()
+	botData().initCommands()
 
 	Debug.Println("Initializing natural language commands...")
 	initNLPCommands()
 
 	Debug.Println("Initializing query service handlers...")
-	initQueryServices()
+	botData().initQueryServices()
 
 	Debug.Println("Initializing voice service handlers...")
-	initVoiceServices()
+	botData().initVoiceServices()
+
+	Debug.Println("Resuming interrupted voice sessions...")
+	go resumeVoiceSessions()
+
+	Debug.Println("Backfilling starboard entry authors...")
+	go backfillStarboardAuthors("")
 
 	Debug.Println("Setting random presence...")
 	updateRandomStatus(session, 0)
@@ -369,9 +341,14 @@ func discordReady(session *discordgo.Session, event *discordgo.Ready) {
 	Debug.Println("Starting cronjobs...")
 	cronjob.Start()
 
+	Debug.Println("Scheduling the guild data sweeper...")
+	scheduleGuildDataSweeper(botData().BotOptions.GuildData.SweepFrequency)
+
 	Debug.Println("Loading active reminders...")
+	remindEntriesLock.Lock()
 	oldRemindEntries := remindEntries
 	remindEntries = make([]RemindEntry, 0)
+	remindEntriesLock.Unlock()
 	for i := range oldRemindEntries {
 		remindWhen(oldRemindEntries[i].UserID, oldRemindEntries[i].GuildID, oldRemindEntries[i].ChannelID, oldRemindEntries[i].Message, oldRemindEntries[i].Added, oldRemindEntries[i].When, time.Now())
 	}
@@ -383,18 +360,18 @@ func discordReady(session *discordgo.Session, event *discordgo.Ready) {
 		for _, feed := range oldFeeds {
 			addErr := addFeed(guildID, feed.ChannelID, feed.FeedURL, feed.Frequency)
 			if addErr != nil {
-				Error.Println("Error adding feed ["+feed.FeedLink+"]: %v", addErr)
+				ErrorFeed.With("guild", guildID, "channel", feed.ChannelID).Printf("Error adding feed [%s]: %v", feed.FeedURL, addErr)
 			}
 		}
 	}
 
-	if gcpAuthURL := botData.BotClients.GoogleAssistant.GetAuthURL(); gcpAuthURL != "" {
-		ownerPrivChannel, err := botData.DiscordSession.UserChannelCreate(botData.BotOwnerID)
+	if gcpAuthURL := botData().BotClients.GoogleAssistant.GetAuthURL(); gcpAuthURL != "" {
+		ownerPrivChannel, err := botData().DiscordSession.UserChannelCreate(botData().BotOwnerID)
 		if err != nil {
 			debugLog("An error occurred creating a private channel with the bot owner.", false)
 		} else {
 			ownerPrivChannelID := ownerPrivChannel.ID
-			botData.DiscordSession.ChannelMessageSend(ownerPrivChannelID, "Authenticate with the Google Assistant: "+gcpAuthURL)
+			botData().DiscordSession.ChannelMessageSend(ownerPrivChannelID, "Authenticate with the Google Assistant: "+gcpAuthURL)
 		}
 	}
 
@@ -403,13 +380,16 @@ func discordReady(session *discordgo.Session, event *discordgo.Ready) {
 }
 
 func updateRandomStatus(session *discordgo.Session, status int) {
+	if len(botData().CustomStatuses) == 0 {
+		return
+	}
 	if status == 0 {
-		status = rand.Intn(len(botData.CustomStatuses)) + 1
+		status = rand.Intn(len(botData().CustomStatuses)) + 1
 	}
 	status--
 
-	session.UpdateStatusComplex(discordgo.UpdateStatusData{Activities: []*discordgo.Activity{botData.CustomStatuses[status]}})
-	Debug.Printf("Presence: ", botData.CustomStatuses[status])
+	session.UpdateStatusComplex(discordgo.UpdateStatusData{Activities: []*discordgo.Activity{botData().CustomStatuses[status]}})
+	Debug.Printf("Presence: %v", botData().CustomStatuses[status])
 }
 
 func updateListeningStatus(session *discordgo.Session, artist, title string) {
@@ -417,14 +397,18 @@ func updateListeningStatus(session *discordgo.Session, artist, title string) {
 }
 
 func sendTipMessages() {
-	tipMessageN := -1
-	for {
-		tipMessageN = rand.Intn(len(botData.TipMessages))
-		if tipMessageN != botData.LastTipMessage {
+	if len(botData().TipMessages) == 0 {
+		return
+	}
+
+	tipMessageN := 0
+	for len(botData().TipMessages) > 1 {
+		tipMessageN = rand.Intn(len(botData().TipMessages))
+		if tipMessageN != botData().LastTipMessage {
 			break
 		}
 	}
-	tipMessage := botData.TipMessages[tipMessageN]
+	tipMessage := botData().TipMessages[tipMessageN]
 	tipMessageEmbed := NewEmbed().
 		AddField("Did You Know?", tipMessage.DidYouKnow).
 		AddField("How To Use", tipMessage.HowTo).
@@ -437,60 +421,73 @@ func sendTipMessages() {
 
 	for _, guild := range guildSettings {
 		if guild.TipsChannel != "" {
-			botData.DiscordSession.ChannelMessageSendEmbed(guild.TipsChannel, tipMessageEmbed.MessageEmbed)
+			botData().DiscordSession.ChannelMessageSendEmbed(guild.TipsChannel, tipMessageEmbed.MessageEmbed)
 		}
 	}
 
-	botData.LastTipMessage = tipMessageN
+	botData().LastTipMessage = tipMessageN
 }
 
 func typingEvent(session *discordgo.Session, channelID string, updatedMessageEvent bool) {
-	if botData.BotOptions.SendTypingEvent && updatedMessageEvent == false {
+	if botData().BotOptions.SendTypingEvent && updatedMessageEvent == false {
 		Debug.Printf("Typing in channel %s...\n", channelID)
 		session.ChannelTyping(channelID)
 	}
 }
 
 func debugLog(msg string, overrideConfig bool) {
-	if botData.DebugMode || overrideConfig {
+	if botData().DebugMode || overrideConfig {
 		Debug.Println(msg)
 	}
 }
 
 func stateSaveAll() {
-	if _, err := os.Stat("state"); os.IsNotExist(err) {
-		os.Mkdir("state", 0744)
+	defer metricStateSaveDuration.ObserveSince(time.Now())
+
+	dir := stateDir()
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		os.MkdirAll(dir, 0744)
 	}
 
-	err := stateSaveRaw(guildData, "state/guildData.json")
+	err := stateSaveRaw(guildData, filepath.Join(dir, "guildData.json"))
 	if err != nil {
 		Error.Printf("Error saving guildData state: %s\n", err)
 	}
 
-	err = stateSaveRaw(guildSettings, "state/guildSettings.json")
+	err = stateSaveRaw(guildSettings, filepath.Join(dir, "guildSettings.json"))
 	if err != nil {
 		Error.Printf("Error saving guildSettings state: %s\n", err)
 	}
 
-	err = stateSaveRaw(userSettings, "state/userSettings.json")
+	userSettingsLock.RLock()
+	err = stateSaveRaw(userSettings, filepath.Join(dir, "userSettings.json"))
+	userSettingsLock.RUnlock()
 	if err != nil {
 		Error.Printf("Error saving userSettings state: %s\n", err)
 	}
 
-	err = stateSaveRaw(starboards, "state/starboards.json")
+	err = stateSaveRaw(starboards, filepath.Join(dir, "starboards.json"))
 	if err != nil {
 		Error.Printf("Error saving starboards: %s\n", err)
 	}
 
-	err = stateSaveRaw(remindEntries, "state/reminds.json")
+	remindEntriesLock.RLock()
+	err = stateSaveRaw(remindEntries, filepath.Join(dir, "reminds.json"))
+	remindEntriesLock.RUnlock()
 	if err != nil {
 		Error.Printf("Error saving reminders: %s\n", err)
 	}
 
-	err = stateSaveRaw(voiceData, "state/voiceData.json")
+	err = stateSaveRaw(voiceData, filepath.Join(dir, "voiceData.json"))
 	if err != nil {
 		Error.Printf("Error saving voiceData state: %s\n", err)
 	}
+
+	if apiAuthLoaded {
+		apiAuthSave() //Keeps when each API token was last used
+	}
+
+	go syncUserSettings()
 }
 
 func stateSaveRaw(data interface{}, file string) error {
@@ -503,35 +500,60 @@ func stateSaveRaw(data interface{}, file string) error {
 }
 
 func stateRestoreAll() {
-	err := stateRestoreRaw("state/guildData.json", &guildData)
-	if err != nil {
-		Error.Printf("Error loading guildData state: %s\n", err)
-	}
+	//When migrating from a different shard count, the state of every old shard is merged and then narrowed down to the guilds this shard owns
+	stateSources = stateSourceDirs()
+	for _, dir := range stateSources {
+		err := stateRestoreRaw(filepath.Join(dir, "guildData.json"), &guildData)
+		if err != nil {
+			Error.Printf("Error loading guildData state: %s\n", err)
+		}
 
-	err = stateRestoreRaw("state/guildSettings.json", &guildSettings)
-	if err != nil {
-		Error.Printf("Error loading guildSettings state: %s\n", err)
-	}
+		err = stateRestoreRaw(filepath.Join(dir, "guildSettings.json"), &guildSettings)
+		if err != nil {
+			Error.Printf("Error loading guildSettings state: %s\n", err)
+		}
 
-	err = stateRestoreRaw("state/userSettings.json", &userSettings)
-	if err != nil {
-		Error.Printf("Error loading userSettings state: %s\n", err)
-	}
+		userSettingsLock.Lock()
+		err = stateRestoreRaw(filepath.Join(dir, "userSettings.json"), &userSettings)
+		userSettingsLock.Unlock()
+		if err != nil {
+			Error.Printf("Error loading userSettings state: %s\n", err)
+		}
 
-	err = stateRestoreRaw("state/starboards.json", &starboards)
-	if err != nil {
-		Error.Printf("Error loading starboards: %s\n", err)
-	}
+		err = stateRestoreRaw(filepath.Join(dir, "starboards.json"), &starboards)
+		if err != nil {
+			Error.Printf("Error loading starboards: %s\n", err)
+		}
 
-	err = stateRestoreRaw("state/reminds.json", &remindEntries)
-	if err != nil {
-		Error.Printf("Error loading reminders: %s\n", err)
+		dirRemindEntries := make([]RemindEntry, 0)
+		err = stateRestoreRaw(filepath.Join(dir, "reminds.json"), &dirRemindEntries)
+		if err != nil {
+			Error.Printf("Error loading reminders: %s\n", err)
+		}
+		remindEntriesLock.Lock()
+		remindEntries = append(remindEntries, dirRemindEntries...)
+		remindEntriesLock.Unlock()
+
+		err = stateRestoreRaw(filepath.Join(dir, "voiceData.json"), &voiceData)
+		if err != nil {
+			Error.Printf("Error loading voiceData state: %s\n", err)
+		}
 	}
+	dropUnownedState()
+	snapshotUserSettings()
 
-	err = stateRestoreRaw("state/voiceData.json", &voiceData)
-	if err != nil {
-		Error.Printf("Error loading voiceData state: %s\n", err)
+	//Voice connections and audio sessions don't survive a restart, interrupted playback is resumed from the resume manifest instead
+	for _, voice := range voiceData {
+		if voice == nil {
+			continue
+		}
+		voice.VoiceConnection = nil
+		voice.EncodingSession = nil
+		voice.StreamingSession = nil
+		voice.NowPlaying = nil
 	}
+
+	stateLoaded = true
 }
 
 func stateRestoreRaw(file string, data interface{}) error {
@@ -546,7 +568,7 @@ func stateRestoreRaw(file string, data interface{}) error {
 func firstRun() bool {
 	_, err := ioutil.ReadFile(".firstrun")
 	if err == nil {
-		DowntimeReason = "Restarted by host system or <@" + botData.BotOwnerID + ">"
+		DowntimeReason = "Restarted by host system or <@" + botData().BotOwnerID + ">"
 		return false
 	}
 
@@ -557,11 +579,11 @@ func firstRun() bool {
 func checkRestart() {
 	restartChannelID, err := ioutil.ReadFile(".restart")
 	if err == nil && len(restartChannelID) > 0 {
-		DowntimeReason = "Restarted by <@" + botData.BotOwnerID + ">"
+		DowntimeReason = "Restarted by <@" + botData().BotOwnerID + ">"
 
 		Info.Println("Restart succeeded!")
-		restartEmbed := NewGenericEmbed("Restart", "Successfully restarted "+botData.BotName+"!")
-		botData.DiscordSession.ChannelMessageSendEmbed(string(restartChannelID), restartEmbed)
+		restartEmbed := NewGenericEmbed("Restart", "Successfully restarted "+botData().BotName+"!")
+		botData().DiscordSession.ChannelMessageSendEmbed(string(restartChannelID), restartEmbed)
 
 		os.Remove(".restart")
 	}
@@ -573,8 +595,8 @@ func checkUpdate() {
 		DowntimeReason = "Updated to " + BuildID
 
 		Info.Println("Update succeeded!")
-		updateEmbed := NewGenericEmbed("Update", "Successfully updated "+botData.BotName+"!")
-		botData.DiscordSession.ChannelMessageSendEmbed(string(updateChannelID), updateEmbed)
+		updateEmbed := NewGenericEmbed("Update", "Successfully updated "+botData().BotName+"!")
+		botData().DiscordSession.ChannelMessageSendEmbed(string(updateChannelID), updateEmbed)
 
 		os.Remove(".update")
 	}
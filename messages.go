@@ -13,7 +13,8 @@ import (
 
 // Query holds data about a query's response message
 type Query struct {
-	ResponseMessageID string `json:"responseMessageID,omitempty"`
+	ResponseMessageID string    `json:"responseMessageID,omitempty"`
+	Created           time.Time `json:"created"` //When the query was first responded to, used to expire it
 }
 
 func debugMessage(session *discordgo.Session, message *discordgo.Message, channel *discordgo.Channel, guild *discordgo.Guild, updatedMessageEvent bool) {
@@ -39,10 +40,11 @@ func debugMessage(session *discordgo.Session, message *discordgo.Message, channe
 		userType = "*"
 	}
 
+	logger := Debug.With("guild", guild.ID, "channel", channel.ID, "user", message.Author.ID)
 	if strings.Contains(content, "\n") {
-		Debug.Printf("[%s][%s - #%s] %s%s#%s:\n%s", eventType, guild.Name, channel.Name, userType, message.Author.Username, message.Author.Discriminator, contentReplaced)
+		logger.Printf("[%s][%s - #%s] %s%s#%s:\n%s", eventType, guild.Name, channel.Name, userType, message.Author.Username, message.Author.Discriminator, contentReplaced)
 	} else {
-		Debug.Printf("[%s][%s - #%s] %s%s#%s: %s", eventType, guild.Name, channel.Name, userType, message.Author.Username, message.Author.Discriminator, contentReplaced)
+		logger.Printf("[%s][%s - #%s] %s%s#%s: %s", eventType, guild.Name, channel.Name, userType, message.Author.Username, message.Author.Discriminator, contentReplaced)
 	}
 }
 
@@ -60,11 +62,11 @@ func debugEmbed(embed *discordgo.MessageEmbed, author *discordgo.User, channel *
 		userType = "*"
 	}
 
-	Debug.Printf("[%s][%s - #%s] %s%s#%s:\n%s", eventType, guild.Name, channel.Name, userType, author.Username, author.Discriminator, string(embedJSON))
+	Debug.With("guild", guild.ID, "channel", channel.ID, "user", author.ID).Printf("[%s][%s - #%s] %s%s#%s:\n%s", eventType, guild.Name, channel.Name, userType, author.Username, author.Discriminator, string(embedJSON))
 }
 
 func handleMessage(session *discordgo.Session, message *discordgo.Message, updatedMessageEvent bool) {
-	defer recoverPanic()
+	defer recoverHandler("handleMessage", message.GuildID)
 
 	if message.Author.Bot {
 		return //We don't want bots to interact with our bot
@@ -82,7 +84,7 @@ func handleMessage(session *discordgo.Session, message *discordgo.Message, updat
 	if content == "" {
 		return //The message was empty
 	}
-	member, err := botData.DiscordSession.GuildMember(guild.ID, message.Author.ID)
+	member, err := botData().DiscordSession.GuildMember(guild.ID, message.Author.ID)
 	if err != nil {
 		return //Error finding the guild member
 	}
@@ -122,13 +124,13 @@ func handleMessage(session *discordgo.Session, message *discordgo.Message, updat
 			prefix = guildSettings[guild.ID].BotPrefix
 		}
 	} else {
-		if strings.HasPrefix(content, botData.CommandPrefix) {
-			prefix = botData.CommandPrefix
+		if strings.HasPrefix(content, botData().CommandPrefix) {
+			prefix = botData().CommandPrefix
 		}
 	}
 
 	if regexpBotName {
-		if botData.BotOptions.UseWolframAlpha || botData.BotOptions.UseDuckDuckGo || botData.BotOptions.UseCustomResponses {
+		if options := guildBotOptions(guild.ID); options.UseWolframAlpha || options.UseDuckDuckGo || options.UseCustomResponses {
 			debugMessage(session, message, channel, guild, updatedMessageEvent)
 			typingEvent(session, message.ChannelID, updatedMessageEvent)
 
@@ -165,11 +167,12 @@ func handleMessage(session *discordgo.Session, message *discordgo.Message, updat
 					guildData[guild.ID].WolframConversations = make(map[string]*wolfram.Conversation)
 					guildData[guild.ID].WolframConversations[message.Author.ID] = &wolfram.Conversation{}
 				}
+				guildData[guild.ID].TouchWolframConversation(message.Author.ID)
 
 				queryEnvironment := &QueryEnvironment{Channel: channel, Guild: guild, Message: message, User: message.Author, Member: member, WolframConversation: previousConversation}
 				responseEmbed, err = getQueryResult(query, queryEnvironment)
 				if err != nil {
-					responseEmbed = NewErrorEmbed("Query Error", "We couldn't find a service to handle your query.\nMake sure you're using proper grammar and query structure where applicable.")
+					responseEmbed = getErrorEmbed(wrapError(errCodeQueryNoService, err), guildLocale(guild.ID))
 				}
 			}
 		}
@@ -207,9 +210,9 @@ func handleMessage(session *discordgo.Session, message *discordgo.Message, updat
 			cmd = newCmd
 		}
 
-		member, _ := botData.DiscordSession.GuildMember(guild.ID, message.Author.ID)
+		member, _ := botData().DiscordSession.GuildMember(guild.ID, message.Author.ID)
 
-		commandEnvironment := &CommandEnvironment{Channel: channel, Guild: guild, Message: message, User: message.Author, Member: member, Command: cmd[0], BotPrefix: botData.CommandPrefix, UpdatedMessageEvent: updatedMessageEvent}
+		commandEnvironment := &CommandEnvironment{Channel: channel, Guild: guild, Message: message, User: message.Author, Member: member, Command: cmd[0], BotPrefix: botData().CommandPrefix, UpdatedMessageEvent: updatedMessageEvent}
 		responseEmbed = callCommand(cmd[0], cmd[1:], commandEnvironment)
 	}
 
@@ -218,7 +221,7 @@ func handleMessage(session *discordgo.Session, message *discordgo.Message, updat
 		swearFound, swears, err := guildSettings[guild.ID].SwearFilter.Check(content)
 		if err != nil {
 			//Report error to developer
-			ownerPrivChannel, chanErr := session.UserChannelCreate(botData.BotOwnerID)
+			ownerPrivChannel, chanErr := session.UserChannelCreate(botData().BotOwnerID)
 			if chanErr != nil {
 				debugLog("An error occurred creating a private channel with the bot owner.", false)
 			} else {
@@ -239,7 +242,7 @@ func handleMessage(session *discordgo.Session, message *discordgo.Message, updat
 					InlineAllFields().
 					SetColor(0x1C1C1C).MessageEmbed
 
-				session.ChannelMessageSendEmbed(settings.LogSettings.LoggingChannel, swearDetectEmbed)
+				sendLogEmbed(session, guild.ID, "swearDetect", swearDetectEmbed)
 			}
 
 			//Delete source message
@@ -276,16 +279,16 @@ func handleMessage(session *discordgo.Session, message *discordgo.Message, updat
 				canUpdateMessage = true
 				responseID = guildData[guild.ID].Queries[message.ID].ResponseMessageID
 			} else {
-				guildData[guild.ID].Queries[message.ID] = &Query{}
+				guildData[guild.ID].Queries[message.ID] = &Query{Created: time.Now()}
 			}
 		} else {
 			guildData[guild.ID].Queries = make(map[string]*Query)
-			guildData[guild.ID].Queries[message.ID] = &Query{}
+			guildData[guild.ID].Queries[message.ID] = &Query{Created: time.Now()}
 		}
 
 		if canUpdateMessage {
 			session.ChannelMessageEditEmbed(message.ChannelID, responseID, responseEmbed)
-			debugEmbed(responseEmbed, botData.DiscordSession.State.User, channel, guild, updatedMessageEvent)
+			debugEmbed(responseEmbed, botData().DiscordSession.State.User, channel, guild, updatedMessageEvent)
 		} else {
 			typingEvent(session, message.ChannelID, updatedMessageEvent)
 
@@ -296,7 +299,7 @@ func handleMessage(session *discordgo.Session, message *discordgo.Message, updat
 
 			responseMessage, err := session.ChannelMessageSendComplex(message.ChannelID, msgSend)
 			if err == nil {
-				debugEmbed(responseEmbed, botData.DiscordSession.State.User, channel, guild, updatedMessageEvent)
+				debugEmbed(responseEmbed, botData().DiscordSession.State.User, channel, guild, updatedMessageEvent)
 				guildData[guild.ID].Queries[message.ID].ResponseMessageID = responseMessage.ID
 			}
 		}
@@ -0,0 +1,416 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/render"
+)
+
+/*
+	Metrics are exposed at /metrics on the API in the Prometheus text format.
+	Counters and histograms are updated as things happen, while gauges of the current state (voice sessions, queues, reminders, etc.)
+	are collected whenever the metrics are scraped. When sharded, the first shard gathers the metrics of every shard over IPC and
+	labels each sample with the shard it came from.
+
+	The text format is written by hand rather than with the official Prometheus client so the shards can exchange their metrics as
+	plain JSON snapshots (MetricFamily) and have them relabelled and merged by the first shard, which the official client has no
+	way to do without parsing its own exposition format back into protobuf. It would also pull in protobuf, procfs and the rest of
+	the Prometheus libraries for a handful of counters, gauges and histograms. Only what's served here is implemented: no summaries,
+	exemplars or protobuf exposition.
+
+	Failed HTTP requests are only counted for the clients the bot owns (see instrumentClient). The DuckDuckGo, SoundCloud,
+	Spotify, Urban Dictionary and Wolfram|Alpha libraries send their requests with the default client and have no way to set
+	another one, so their failures aren't counted.
+
+	The endpoint is public, so no metric is labelled by guild, user or anything else that identifies who uses the bot.
+*/
+
+var (
+	//Every registered metric, in the order they were registered
+	metricsRegistry     = make([]*MetricVec, 0)
+	metricsRegistryLock sync.Mutex
+
+	//Buckets in seconds for how long commands take to run
+	commandDurationBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+	//Buckets in seconds for how long saving the state takes
+	stateSaveDurationBuckets = []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5}
+
+	//The HTTP client for requests the bot sends itself, counting the ones that fail
+	botHTTPClient = instrumentClient(&http.Client{Timeout: 30 * time.Second})
+
+	//The external services outbound HTTP requests are attributed to, where key = host or domain suffix
+	metricsServiceHosts = map[string]string{
+		"discord.com":     "discord",
+		"discordapp.com":  "discord",
+		"discordapp.net":  "discord",
+		"googleapis.com":  "google",
+		"youtube.com":     "youtube",
+		"googlevideo.com": "youtube",
+		"github.com":      "github",
+		"imgur.com":       "imgur",
+		"xkcd.com":        "xkcd",
+		"mojang.com":      "minecraft",
+		"bandcamp.com":    "bandcamp",
+	}
+
+	metricCommands          = newCounter("clinet_commands_total", "Commands run, by command.", "command")
+	metricCommandDuration   = newHistogram("clinet_command_duration_seconds", "How long commands took to run, by command.", commandDurationBuckets, "command")
+	metricHeartbeatLatency  = newGauge("clinet_gateway_heartbeat_latency_seconds", "The latency between the last gateway heartbeat and its acknowledgement.")
+	metricVoiceConnections  = newGauge("clinet_voice_connections", "Connected voice channels.")
+	metricVoiceStreaming    = newGauge("clinet_voice_streaming_sessions", "Voice connections that are currently playing audio.")
+	metricVoiceQueueLength  = newGauge("clinet_voice_queue_length", "Entries waiting in the queues of every connected voice session.")
+	metricFeedPolls         = newCounter("clinet_feed_polls_total", "Feed checks for new posts, by result.", "result")
+	metricReminders         = newGauge("clinet_reminders_pending", "Reminders waiting to be sent.")
+	metricStateSaveDuration = newHistogram("clinet_state_save_duration_seconds", "How long saving the state took.", stateSaveDurationBuckets)
+	metricGoroutines        = newGauge("clinet_goroutines", "Goroutines that currently exist.")
+	metricHandlerPanics     = newCounter("clinet_handler_panics_total", "Panics recovered from event handlers, commands and background jobs, by handler.", "handler")
+	metricHTTPErrors        = newCounter("clinet_http_errors_total", "Outbound HTTP requests that failed, by external service and reason (transport, 429 or a 5xx status code).", "service", "reason")
+)
+
+// MetricVec holds every series of a metric, one for each combination of label values
+type MetricVec struct {
+	sync.Mutex
+
+	Name       string
+	Help       string
+	Type       string    //The Prometheus metric type: counter, gauge or histogram
+	LabelNames []string  //The names of the labels that tell each series apart
+	Buckets    []float64 //The upper bounds of each histogram bucket, excluding +Inf
+
+	series map[string]*metricSeries //Where key = label values joined by a null byte
+}
+
+type metricSeries struct {
+	labelValues []string
+	value       float64  //The value of a counter or gauge
+	counts      []uint64 //The observations in each histogram bucket, not cumulative
+	sum         float64  //The sum of every histogram observation
+	count       uint64   //The number of histogram observations
+}
+
+// MetricFamily holds a snapshot of every sample of a metric, in a form that can be sent between shards
+type MetricFamily struct {
+	Name    string         `json:"name"`
+	Help    string         `json:"help"`
+	Type    string         `json:"type"`
+	Samples []MetricSample `json:"samples"`
+}
+
+// MetricSample holds a single sample of a metric, where the name may carry a histogram suffix such as _bucket
+type MetricSample struct {
+	Name   string        `json:"name"`
+	Labels []MetricLabel `json:"labels"`
+	Value  float64       `json:"value"`
+}
+
+// MetricLabel holds a single label of a sample
+type MetricLabel struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func registerMetric(vec *MetricVec) *MetricVec {
+	vec.series = make(map[string]*metricSeries)
+
+	metricsRegistryLock.Lock()
+	defer metricsRegistryLock.Unlock()
+	metricsRegistry = append(metricsRegistry, vec)
+	return vec
+}
+
+func newCounter(name, help string, labelNames ...string) *MetricVec {
+	return registerMetric(&MetricVec{Name: name, Help: help, Type: "counter", LabelNames: labelNames})
+}
+
+func newGauge(name, help string, labelNames ...string) *MetricVec {
+	return registerMetric(&MetricVec{Name: name, Help: help, Type: "gauge", LabelNames: labelNames})
+}
+
+func newHistogram(name, help string, buckets []float64, labelNames ...string) *MetricVec {
+	return registerMetric(&MetricVec{Name: name, Help: help, Type: "histogram", LabelNames: labelNames, Buckets: buckets})
+}
+
+// with returns the series for the given label values, creating it if it doesn't exist yet
+// The caller must hold the lock
+func (vec *MetricVec) with(labelValues []string) *metricSeries {
+	if len(labelValues) != len(vec.LabelNames) {
+		panic(fmt.Sprintf("metric %s takes %d label values, got %d", vec.Name, len(vec.LabelNames), len(labelValues)))
+	}
+
+	key := strings.Join(labelValues, "\x00")
+	series, exists := vec.series[key]
+	if !exists {
+		series = &metricSeries{labelValues: append([]string{}, labelValues...)}
+		if vec.Type == "histogram" {
+			series.counts = make([]uint64, len(vec.Buckets))
+		}
+		vec.series[key] = series
+	}
+	return series
+}
+
+// Add adds the given value to the series of a counter or gauge
+func (vec *MetricVec) Add(value float64, labelValues ...string) {
+	vec.Lock()
+	defer vec.Unlock()
+	vec.with(labelValues).value += value
+}
+
+// Inc adds one to the series of a counter or gauge
+func (vec *MetricVec) Inc(labelValues ...string) {
+	vec.Add(1, labelValues...)
+}
+
+// Set sets the series of a gauge to the given value
+func (vec *MetricVec) Set(value float64, labelValues ...string) {
+	vec.Lock()
+	defer vec.Unlock()
+	vec.with(labelValues).value = value
+}
+
+// Observe records an observation in the series of a histogram
+func (vec *MetricVec) Observe(value float64, labelValues ...string) {
+	vec.Lock()
+	defer vec.Unlock()
+
+	series := vec.with(labelValues)
+	for i, bound := range vec.Buckets {
+		if value <= bound {
+			series.counts[i]++
+			break
+		}
+	}
+	series.sum += value
+	series.count++
+}
+
+// ObserveSince records the time since the given start in seconds in the series of a histogram
+func (vec *MetricVec) ObserveSince(start time.Time, labelValues ...string) {
+	vec.Observe(time.Since(start).Seconds(), labelValues...)
+}
+
+// Reset removes every series, such as before collecting a gauge whose series come and go
+func (vec *MetricVec) Reset() {
+	vec.Lock()
+	defer vec.Unlock()
+	vec.series = make(map[string]*metricSeries)
+}
+
+// family returns a snapshot of every series of the metric, with the given extra labels added to each sample
+func (vec *MetricVec) family(extraLabels []MetricLabel) MetricFamily {
+	vec.Lock()
+	defer vec.Unlock()
+
+	family := MetricFamily{Name: vec.Name, Help: vec.Help, Type: vec.Type, Samples: make([]MetricSample, 0)}
+
+	keys := make([]string, 0, len(vec.series))
+	for key := range vec.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		series := vec.series[key]
+		labels := append([]MetricLabel{}, extraLabels...)
+		for i, labelName := range vec.LabelNames {
+			labels = append(labels, MetricLabel{Name: labelName, Value: series.labelValues[i]})
+		}
+
+		if vec.Type != "histogram" {
+			family.Samples = append(family.Samples, MetricSample{Name: vec.Name, Labels: labels, Value: series.value})
+			continue
+		}
+
+		cumulative := uint64(0)
+		for i, bound := range vec.Buckets {
+			cumulative += series.counts[i]
+			bucketLabels := append(append([]MetricLabel{}, labels...), MetricLabel{Name: "le", Value: strconv.FormatFloat(bound, 'g', -1, 64)})
+			family.Samples = append(family.Samples, MetricSample{Name: vec.Name + "_bucket", Labels: bucketLabels, Value: float64(cumulative)})
+		}
+		infLabels := append(append([]MetricLabel{}, labels...), MetricLabel{Name: "le", Value: "+Inf"})
+		family.Samples = append(family.Samples,
+			MetricSample{Name: vec.Name + "_bucket", Labels: infLabels, Value: float64(series.count)},
+			MetricSample{Name: vec.Name + "_sum", Labels: labels, Value: series.sum},
+			MetricSample{Name: vec.Name + "_count", Labels: labels, Value: float64(series.count)},
+		)
+	}
+
+	return family
+}
+
+// collectMetrics updates every gauge of the bot's current state
+func collectMetrics() {
+	metricGoroutines.Set(float64(runtime.NumGoroutine()))
+	remindEntriesLock.RLock()
+	metricReminders.Set(float64(len(remindEntries)))
+	remindEntriesLock.RUnlock()
+
+	if botData().DiscordSession != nil {
+		metricHeartbeatLatency.Set(botData().DiscordSession.HeartbeatLatency().Seconds())
+	}
+
+	connections, streaming, queued := 0, 0, 0
+	for _, voice := range voiceData {
+		if !voice.IsConnected() {
+			continue
+		}
+		connections++
+		if voice.IsStreaming() {
+			streaming++
+		}
+		voice.Lock()
+		queued += len(voice.Entries)
+		voice.Unlock()
+	}
+	metricVoiceQueueLength.Set(float64(queued))
+	metricVoiceConnections.Set(float64(connections))
+	metricVoiceStreaming.Set(float64(streaming))
+}
+
+// localMetricFamilies collects and returns a snapshot of every metric of this process
+func localMetricFamilies() []MetricFamily {
+	collectMetrics()
+
+	var extraLabels []MetricLabel
+	if shardCount > 1 {
+		extraLabels = []MetricLabel{{Name: "shard", Value: strconv.Itoa(shardID)}}
+	}
+
+	metricsRegistryLock.Lock()
+	defer metricsRegistryLock.Unlock()
+
+	families := make([]MetricFamily, 0, len(metricsRegistry))
+	for _, vec := range metricsRegistry {
+		families = append(families, vec.family(extraLabels))
+	}
+	return families
+}
+
+// allMetricFamilies returns a snapshot of every metric of every shard, merging the samples of each metric together
+func allMetricFamilies() []MetricFamily {
+	families := localMetricFamilies()
+	if shardCount <= 1 {
+		return families
+	}
+
+	shardAddrs, err := ipcShardAddrs()
+	if err != nil {
+		ErrorAPI.Printf("Error finding the other shards to gather metrics from: %v", err)
+		return families
+	}
+
+	familyIndex := make(map[string]int)
+	for i, family := range families {
+		familyIndex[family.Name] = i
+	}
+	for id, addr := range shardAddrs {
+		if id == shardID {
+			continue
+		}
+		shardFamilies := make([]MetricFamily, 0)
+		if err := ipcRequest("GET", "http://"+addr+"/metricfamilies", nil, &shardFamilies); err != nil {
+			WarningAPI.Printf("Error gathering metrics from shard %d: %v", id, err)
+			continue
+		}
+		for _, family := range shardFamilies {
+			if i, exists := familyIndex[family.Name]; exists {
+				families[i].Samples = append(families[i].Samples, family.Samples...)
+			}
+		}
+	}
+	return families
+}
+
+// writeMetrics writes the given metrics in the Prometheus text format
+func writeMetrics(w *strings.Builder, families []MetricFamily) {
+	for _, family := range families {
+		fmt.Fprintf(w, "# HELP %s %s\n", family.Name, strings.NewReplacer("\\", "\\\\", "\n", "\\n").Replace(family.Help))
+		fmt.Fprintf(w, "# TYPE %s %s\n", family.Name, family.Type)
+		for _, sample := range family.Samples {
+			w.WriteString(sample.Name)
+			if len(sample.Labels) > 0 {
+				labels := make([]string, 0, len(sample.Labels))
+				for _, label := range sample.Labels {
+					labels = append(labels, label.Name+"=\""+strings.NewReplacer("\\", "\\\\", "\"", "\\\"", "\n", "\\n").Replace(label.Value)+"\"")
+				}
+				w.WriteString("{" + strings.Join(labels, ",") + "}")
+			}
+			w.WriteString(" " + formatMetricValue(sample.Value) + "\n")
+		}
+	}
+}
+
+func formatMetricValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func apiGetMetrics(w http.ResponseWriter, r *http.Request) {
+	metrics := &strings.Builder{}
+	writeMetrics(metrics, allMetricFamilies())
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write([]byte(metrics.String()))
+}
+
+func ipcGetMetrics(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, localMetricFamilies())
+}
+
+// MetricsTransport counts the failed requests of an HTTP transport by the external service they were sent to
+type MetricsTransport struct {
+	Transport http.RoundTripper //The transport to send requests with, or nil to use the default transport
+}
+
+// RoundTrip sends the request with the wrapped transport, counting it if it fails
+func (metricsTransport *MetricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := metricsTransport.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		metricHTTPErrors.Inc(metricsService(req.URL), "transport")
+	} else if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		metricHTTPErrors.Inc(metricsService(req.URL), strconv.Itoa(resp.StatusCode))
+	}
+	return resp, err
+}
+
+// metricsService returns the name of the external service the given URL belongs to, or other if it's not a known one
+func metricsService(requestURL *url.URL) string {
+	host := requestURL.Hostname()
+	for domain, service := range metricsServiceHosts {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return service
+		}
+	}
+	return "other" //Feeds and other user-supplied URLs would give every host its own series
+}
+
+// instrumentClient counts the failed requests of the given HTTP client, which must be one the bot owns
+// Libraries that only send requests with the default client aren't counted, as replacing the default transport would change it for every
+// other package in the process too
+func instrumentClient(client *http.Client) *http.Client {
+	if _, ok := client.Transport.(*MetricsTransport); !ok {
+		client.Transport = &MetricsTransport{Transport: client.Transport}
+	}
+	return client
+}
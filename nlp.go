@@ -99,11 +99,11 @@ func nlpNew(command, argPrefix, argSuffix string, regex, regexBadMatch, regexArg
 }
 
 func addNLPCommand(nlp ...*NLP) {
-	botData.NLPCommands = append(botData.NLPCommands, &CommandNLP{Commands: nlp})
+	botData().NLPCommands = append(botData().NLPCommands, &CommandNLP{Commands: nlp})
 }
 
 func callNLP(message string, env *CommandEnvironment) *discordgo.MessageEmbed {
-	for i, command := range botData.NLPCommands {
+	for i, command := range botData().NLPCommands {
 		for j := 0; j < len(command.Commands); j++ {
 			Debug.Printf("Testing NLP %d, command %d...", i, j)
 
@@ -123,7 +123,7 @@ func callNLP(message string, env *CommandEnvironment) *discordgo.MessageEmbed {
 				}
 			}
 
-			if _, exists := botData.Commands[nlp.Command]; !exists {
+			if _, exists := botData().Commands[nlp.Command]; !exists {
 				break
 			}
 
@@ -0,0 +1,606 @@
+package main
+
+import (
+	"encoding"
+	"encoding/json"
+	"net/http"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+)
+
+/*
+	The OpenAPI document at /api/openapi.json is generated from the router itself, so it can't list a route that doesn't exist.
+	Each route is described in apiRouteDocs, keyed by its method and pattern, and the schemas of its bodies are built from the Go types it sends and receives.
+	A route without a description is logged when the API starts and fails the self-test, so add one to apiRouteDocs whenever a route is added.
+*/
+
+const (
+	apiAccessPublic  = "public"  //Anyone, without authenticating
+	apiAccessUser    = "user"    //Anyone who's authenticated
+	apiAccessSelf    = "self"    //Only the user in the path, or an admin
+	apiAccessMember  = "member"  //Anyone in the guild in the path
+	apiAccessManager = "manager" //Anyone who can manage the guild in the path
+)
+
+// APIRouteDoc describes a route for the OpenAPI document
+type APIRouteDoc struct {
+	Tag         string            //The group of routes it belongs to
+	Summary     string            //What it does, in one line
+	Description string            //Anything else worth knowing about it
+	Access      string            //Who can use it, one of the apiAccess constants
+	Query       map[string]string //The query parameters it reads, where key = name and value = description
+	Request     interface{}       //A value of the type of the request body, if it reads one
+	Response    interface{}       //A value of the type of the response body, if it sends one
+	ContentType string            //The content type of the response body, application/json by default
+	Status      int               //The status of a successful response, 200 by default
+	Errors      []int             //The statuses of the errors it responds with, besides those of its access
+}
+
+var (
+	//The description of every path parameter used by the routes
+	apiParamDocs = map[string]string{
+		"guildID":    "The ID of the guild",
+		"userID":     "The ID of the user",
+		"index":      "The position of the entry in the list, starting at 0",
+		"setting":    "The path to the setting, made of JSON names joined by dots",
+		"key":        "The guild's invite key",
+		"code":       "The error code",
+		"tokenID":    "The ID of the API token or session",
+		"keyID":      "The ID of the webhook key",
+		"alias":      "The alias of the webhook channel",
+		"feedID":     "The ID of the feed",
+		"reminderID": "The ID of the reminder",
+	}
+
+	//Routes that serve pages rather than the API, and so aren't described
+	apiHiddenRoutes = []string{"/", "/dashboard/*"}
+
+	//The description of every route the API serves, where key = method + " " + pattern without a trailing slash
+	apiRouteDocs = map[string]*APIRouteDoc{
+		"GET /healthz":          {Tag: "status", Summary: "Whether or not the process is alive and responsive", Access: apiAccessPublic, Response: &HealthReport{}, Errors: []int{http.StatusServiceUnavailable}},
+		"GET /readyz":           {Tag: "status", Summary: "Whether or not every shard is ready to serve commands and API requests", Access: apiAccessPublic, Response: &HealthReport{}, Errors: []int{http.StatusServiceUnavailable}},
+		"GET /metrics":          {Tag: "status", Summary: "Prometheus metrics for every shard", Access: apiAccessPublic, Response: "", ContentType: "text/plain; version=0.0.4"},
+		"GET /errors":           {Tag: "errors", Summary: "Every error code in the error catalog", Access: apiAccessPublic, Response: []*ErrorDefinition{}},
+		"GET /errors/{code}":    {Tag: "errors", Summary: "A single error code, for looking up codes users report", Access: apiAccessPublic, Response: &ErrorDefinition{}, Errors: []int{http.StatusNotFound}},
+		"GET /api/openapi.json": {Tag: "status", Summary: "This document", Access: apiAccessPublic, Response: &OpenAPI{}},
+		"GET /ws": {
+			Tag: "events", Summary: "Streams events from guilds as they happen over a WebSocket", Access: apiAccessUser,
+			Description: "Each message is an event. The first message has the type hello and lists the guilds and types the stream receives.",
+			Query: map[string]string{
+				"guild": "The guilds to receive events from, repeated or comma-separated. Required unless the token has the admin scope",
+				"type":  "The types of events to receive, repeated or comma-separated. Every type is sent if left out",
+			},
+			Response: &Event{}, Status: http.StatusSwitchingProtocols, Errors: []int{http.StatusBadRequest},
+		},
+
+		"GET /auth/login": {
+			Tag: "auth", Summary: "Redirects to Discord to log in", Access: apiAccessPublic,
+			Query:  map[string]string{"redirect": "A page on the API host to send the user back to after logging in, instead of showing the new session"},
+			Status: http.StatusFound, Errors: []int{http.StatusNotImplemented},
+		},
+		"GET /auth/callback": {
+			Tag: "auth", Summary: "Finishes logging in with Discord and issues a session", Access: apiAccessPublic,
+			Description: "Sets the session cookie. Redirects to the page given when logging in, if any.",
+			Response:    &APINewToken{}, Errors: []int{http.StatusBadRequest, http.StatusNotImplemented, http.StatusBadGateway},
+		},
+		"GET /auth/me":      {Tag: "auth", Summary: "Retrieves who the request was authenticated as", Access: apiAccessUser, Response: &APIPrincipal{}},
+		"POST /auth/logout": {Tag: "auth", Summary: "Revokes the session or token the request was authenticated with", Access: apiAccessUser, Status: http.StatusNoContent},
+		"GET /auth/tokens": {
+			Tag: "auth", Summary: "Retrieves every API token and session of the user", Access: apiAccessUser,
+			Query:    map[string]string{"user": "The user to retrieve the tokens of instead, only for admins"},
+			Response: []*APIToken{}, Errors: []int{http.StatusForbidden},
+		},
+		"POST /auth/tokens":             {Tag: "auth", Summary: "Creates a new API token for the user", Access: apiAccessUser, Request: &APITokenRequest{}, Response: &APINewToken{}, Status: http.StatusCreated, Errors: []int{http.StatusBadRequest}},
+		"DELETE /auth/tokens/{tokenID}": {Tag: "auth", Summary: "Revokes an API token or session of the user", Description: "Admins may revoke anyone's.", Access: apiAccessUser, Status: http.StatusNoContent, Errors: []int{http.StatusNotFound}},
+		"GET /auth/audit": {
+			Tag: "auth", Summary: "Retrieves the latest audit records of the user, or of every user for admins", Access: apiAccessUser,
+			Description: "Records every request that changes something, and every invite generated, newest first.",
+			Query: map[string]string{
+				"user":  "Only the records of this user. Anyone but admins may only give themselves",
+				"guild": "Only the records of requests for this guild",
+				"token": "Only the records of requests made with this API token or session",
+				"limit": "How many records to retrieve at most, 100 by default",
+			},
+			Response: []*APIAuditRecord{}, Errors: []int{http.StatusBadRequest, http.StatusForbidden},
+		},
+
+		"GET /api/v0/layout/main":            {Tag: "layouts", Summary: "Retrieves every layout", Access: apiAccessPublic, Response: []*SettingLayout{}},
+		"GET /api/v0/layout/guild":           {Tag: "layouts", Summary: "Retrieves the guild layout", Access: apiAccessPublic, Response: &SettingLayout{}},
+		"GET /api/v0/layout/guild/role":      {Tag: "layouts", Summary: "Retrieves the guild roles layout", Access: apiAccessPublic, Response: &SettingLayout{}},
+		"GET /api/v0/layout/guild/starboard": {Tag: "layouts", Summary: "Retrieves the guild starboard layout", Access: apiAccessPublic, Response: &SettingLayout{}},
+		"GET /api/v0/layout/user":            {Tag: "layouts", Summary: "Retrieves the user layout", Access: apiAccessPublic, Response: &SettingLayout{}},
+
+		"GET /api/v0/guild/{guildID}/invite/{key}": {
+			Tag: "guilds", Summary: "Retrieves a new one-user invite link for the specified guild", Access: apiAccessPublic,
+			Description: "Authenticated by the guild's invite key instead of a token. The invite lasts an hour, and only so many are generated for each guild per hour.",
+			Response:    &discordgo.Invite{}, Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusBadGateway},
+		},
+		"GET /api/v0/guild/{guildID}":                    {Tag: "guilds", Summary: "Retrieves info about a particular guild", Access: apiAccessManager, Response: &discordgo.Guild{}},
+		"GET /api/v0/guild/{guildID}/settings":           {Tag: "guilds", Summary: "Retrieves all settings and their values for a particular guild", Access: apiAccessManager, Response: &GuildSettings{}, Errors: []int{http.StatusNotFound}},
+		"PATCH /api/v0/guild/{guildID}/settings":         {Tag: "guilds", Summary: "Sets new values to any of the guild settings", Access: apiAccessManager, Request: &GuildSettings{}, Response: &GuildSettings{}, Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusUnprocessableEntity}},
+		"PUT /api/v0/guild/{guildID}/settings/{setting}": {Tag: "guilds", Summary: "Sets a new value to a particular guild setting", Access: apiAccessManager, Request: json.RawMessage{}, Response: &GuildSettings{}, Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusUnprocessableEntity}},
+		"GET /api/v0/guild/{guildID}/channels":           {Tag: "guilds", Summary: "Retrieves every channel in the guild, for picking channels in settings", Access: apiAccessManager, Response: []*APIGuildChannel{}, Errors: []int{http.StatusNotFound}},
+		"GET /api/v0/guild/{guildID}/starboard":          {Tag: "starboard", Summary: "Retrieves all starboard settings and entries", Access: apiAccessManager, Response: &Starboard{}},
+
+		"GET /api/v0/guild/{guildID}/feeds":            {Tag: "feeds", Summary: "Retrieves every feed", Access: apiAccessManager, Response: []*APIFeed{}},
+		"POST /api/v0/guild/{guildID}/feeds":           {Tag: "feeds", Summary: "Adds a feed", Access: apiAccessManager, Request: &APIFeedRequest{}, Response: &APIFeed{}, Status: http.StatusCreated, Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusBadGateway}},
+		"PATCH /api/v0/guild/{guildID}/feeds/{index}":  {Tag: "feeds", Summary: "Changes the channel or frequency of a feed", Access: apiAccessManager, Request: &APIFeedRequest{}, Response: &APIFeed{}, Errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+		"DELETE /api/v0/guild/{guildID}/feeds/{index}": {Tag: "feeds", Summary: "Removes a feed", Access: apiAccessManager, Status: http.StatusNoContent, Errors: []int{http.StatusNotFound}},
+
+		"POST /api/v0/guild/{guildID}/webhook": {
+			Tag: "webhooks", Summary: "Posts a message to one of the webhook channels", Access: apiAccessPublic,
+			Description: "Authenticated by one of the guild's webhook keys in the " + webhookKeyHeader + " header instead of a token. Each key may only post so many messages per minute.",
+			Request:     &APIWebhookPost{}, Response: &APIWebhookPosted{}, Status: http.StatusCreated,
+			Errors: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusTooManyRequests, http.StatusBadGateway},
+		},
+		"GET /api/v0/guild/{guildID}/webhooks":                     {Tag: "webhooks", Summary: "Retrieves every webhook channel and key", Access: apiAccessManager, Response: &APIWebhooks{}},
+		"POST /api/v0/guild/{guildID}/webhooks/keys":               {Tag: "webhooks", Summary: "Creates a webhook key", Access: apiAccessManager, Request: &APIWebhookKeyRequest{}, Response: &APINewWebhookKey{}, Status: http.StatusCreated, Errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+		"DELETE /api/v0/guild/{guildID}/webhooks/keys/{keyID}":     {Tag: "webhooks", Summary: "Revokes a webhook key", Access: apiAccessManager, Status: http.StatusNoContent, Errors: []int{http.StatusNotFound}},
+		"PUT /api/v0/guild/{guildID}/webhooks/channels/{alias}":    {Tag: "webhooks", Summary: "Points a channel alias at a channel", Access: apiAccessManager, Request: &APIWebhookChannelRequest{}, Response: &APIWebhooks{}, Errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+		"DELETE /api/v0/guild/{guildID}/webhooks/channels/{alias}": {Tag: "webhooks", Summary: "Removes a channel alias", Access: apiAccessManager, Status: http.StatusNoContent, Errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+
+		"GET /api/v0/guild/{guildID}/reminders":            {Tag: "reminders", Summary: "Retrieves the user's reminders in the guild", Access: apiAccessMember, Response: []RemindEntry{}},
+		"DELETE /api/v0/guild/{guildID}/reminders/{index}": {Tag: "reminders", Summary: "Deletes one of the user's reminders", Access: apiAccessMember, Status: http.StatusNoContent, Errors: []int{http.StatusNotFound}},
+
+		"GET /api/v0/guild/{guildID}/voice":                     {Tag: "voice", Summary: "Retrieves the state of the voice session", Access: apiAccessMember, Response: &APIVoiceStatus{}},
+		"GET /api/v0/guild/{guildID}/voice/nowplaying":          {Tag: "voice", Summary: "Retrieves what's playing and how far into it playback is", Access: apiAccessMember, Response: &APIVoiceNowPlaying{}, Errors: []int{http.StatusConflict}},
+		"GET /api/v0/guild/{guildID}/voice/queue":               {Tag: "voice", Summary: "Retrieves every entry in the queue", Access: apiAccessMember, Response: []*APIVoiceEntry{}},
+		"POST /api/v0/guild/{guildID}/voice/queue":              {Tag: "voice", Summary: "Plays a URL or adds it to the queue", Access: apiAccessMember, Request: &APIVoiceQueueRequest{}, Response: &APIVoiceEntry{}, Status: http.StatusAccepted, Errors: []int{http.StatusBadRequest, http.StatusConflict, http.StatusBadGateway}},
+		"DELETE /api/v0/guild/{guildID}/voice/queue/{index}":    {Tag: "voice", Summary: "Removes an entry from the queue", Access: apiAccessMember, Response: &APIVoiceStatus{}, Errors: []int{http.StatusNotFound}},
+		"POST /api/v0/guild/{guildID}/voice/queue/{index}/move": {Tag: "voice", Summary: "Moves an entry to another place in the queue", Access: apiAccessMember, Request: &APIVoiceMoveRequest{}, Response: &APIVoiceStatus{}, Errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+		"POST /api/v0/guild/{guildID}/voice/skip":               {Tag: "voice", Summary: "Skips to the next entry in the queue", Access: apiAccessMember, Response: &APIVoiceStatus{}, Errors: []int{http.StatusConflict}},
+		"POST /api/v0/guild/{guildID}/voice/pause":              {Tag: "voice", Summary: "Pauses playback", Access: apiAccessMember, Response: &APIVoiceStatus{}, Errors: []int{http.StatusConflict}},
+		"POST /api/v0/guild/{guildID}/voice/resume":             {Tag: "voice", Summary: "Resumes playback", Access: apiAccessMember, Response: &APIVoiceStatus{}, Errors: []int{http.StatusConflict}},
+		"POST /api/v0/guild/{guildID}/voice/stop":               {Tag: "voice", Summary: "Stops playback", Access: apiAccessMember, Response: &APIVoiceStatus{}, Errors: []int{http.StatusConflict}},
+		"PUT /api/v0/guild/{guildID}/voice/repeat":              {Tag: "voice", Summary: "Sets the repeat level", Access: apiAccessMember, Request: &APIVoiceRepeatRequest{}, Response: &APIVoiceStatus{}, Errors: []int{http.StatusBadRequest}},
+		"PUT /api/v0/guild/{guildID}/voice/shuffle":             {Tag: "voice", Summary: "Sets whether the queue is played in a random order", Access: apiAccessMember, Request: &APIVoiceShuffleRequest{}, Response: &APIVoiceStatus{}, Errors: []int{http.StatusBadRequest}},
+
+		"GET /api/v0/user/{userID}":                    {Tag: "users", Summary: "Retrieves info about a particular user", Access: apiAccessSelf, Response: &discordgo.User{}},
+		"GET /api/v0/user/{userID}/guilds":             {Tag: "users", Summary: "Retrieves every guild the user shares with the bot", Access: apiAccessSelf, Response: []*APIUserGuild{}},
+		"GET /api/v0/user/{userID}/settings":           {Tag: "users", Summary: "Retrieves all settings and their values for a particular user", Access: apiAccessSelf, Response: &UserSettings{}},
+		"PATCH /api/v0/user/{userID}/settings":         {Tag: "users", Summary: "Sets new values to any of the user settings", Access: apiAccessSelf, Request: &UserSettings{}, Response: &UserSettings{}, Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusUnprocessableEntity}},
+		"PUT /api/v0/user/{userID}/settings/{setting}": {Tag: "users", Summary: "Sets a new value to a particular user setting", Access: apiAccessSelf, Request: json.RawMessage{}, Response: &UserSettings{}, Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusUnprocessableEntity}},
+
+		"GET /api/v1/guilds/{guildID}/reminders": {
+			Tag: "v1", Summary: "Lists the user's reminders in the guild", Access: apiAccessMember,
+			Description: "Ordered by when they'll be sent.",
+			Query:       map[string]string{"channel": "Only the reminders sent to this channel"},
+			Response:    []*APIv1Reminder{},
+		},
+		"POST /api/v1/guilds/{guildID}/reminders":                {Tag: "v1", Summary: "Sets a reminder for the user", Access: apiAccessMember, Request: &APIv1ReminderRequest{}, Response: &APIv1Reminder{}, Status: http.StatusCreated, Errors: []int{http.StatusBadRequest, http.StatusForbidden}},
+		"DELETE /api/v1/guilds/{guildID}/reminders/{reminderID}": {Tag: "v1", Summary: "Deletes one of the user's reminders", Access: apiAccessMember, Status: http.StatusNoContent, Errors: []int{http.StatusNotFound}},
+		"GET /api/v1/guilds/{guildID}/balances": {
+			Tag: "v1", Summary: "Lists the balances of the guild's members, highest first", Access: apiAccessMember,
+			Query:    map[string]string{"minBalance": "Only the balances of at least this much, 1 by default"},
+			Response: []*APIv1Balance{},
+		},
+		"GET /api/v1/guilds/{guildID}/starboard": {Tag: "v1", Summary: "Retrieves the starboard settings", Access: apiAccessManager, Response: &APIv1Starboard{}, Errors: []int{http.StatusNotFound}},
+		"GET /api/v1/guilds/{guildID}/starboard/entries": {
+			Tag: "v1", Summary: "Lists the starboard entries, newest first", Access: apiAccessManager,
+			Query: map[string]string{
+				"author":   "Only the entries of messages by this user",
+				"channel":  "Only the entries of messages from this channel",
+				"minStars": "Only the entries with at least this many stars",
+			},
+			Response: []*APIv1StarboardEntry{},
+		},
+		"GET /api/v1/guilds/{guildID}/feeds": {
+			Tag: "v1", Summary: "Lists the feeds", Access: apiAccessManager,
+			Description: "Ordered by URL.",
+			Query:       map[string]string{"channel": "Only the feeds posting to this channel"},
+			Response:    []*APIFeed{},
+		},
+		"POST /api/v1/guilds/{guildID}/feeds":            {Tag: "v1", Summary: "Adds a feed", Access: apiAccessManager, Request: &APIFeedRequest{}, Response: &APIFeed{}, Status: http.StatusCreated, Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusBadGateway}},
+		"GET /api/v1/guilds/{guildID}/feeds/{feedID}":    {Tag: "v1", Summary: "Retrieves a feed", Access: apiAccessManager, Response: &APIFeed{}, Errors: []int{http.StatusNotFound}},
+		"PATCH /api/v1/guilds/{guildID}/feeds/{feedID}":  {Tag: "v1", Summary: "Changes the channel or frequency of a feed", Access: apiAccessManager, Request: &APIFeedRequest{}, Response: &APIFeed{}, Errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+		"DELETE /api/v1/guilds/{guildID}/feeds/{feedID}": {Tag: "v1", Summary: "Removes a feed", Access: apiAccessManager, Status: http.StatusNoContent, Errors: []int{http.StatusNotFound}},
+		"GET /api/v1/guilds/{guildID}/rolemes": {
+			Tag: "v1", Summary: "Lists the rolemes", Access: apiAccessManager,
+			Description: "Ordered by position. Rolemes are changed with the roleme command.",
+			Query: map[string]string{
+				"channel": "Only the rolemes that work in this channel",
+				"role":    "Only the rolemes that add or remove this role",
+			},
+			Response: []*APIv1RoleMe{},
+		},
+		"GET /api/v1/guilds/{guildID}/customresponses": {
+			Tag: "v1", Summary: "Lists the custom responses", Access: apiAccessManager,
+			Description: "Ordered by position.",
+			Query:       map[string]string{"command": "Only the custom responses that run this command"},
+			Response:    []*APIv1CustomResponse{},
+		},
+		"GET /api/v1/users/{userID}/balance": {Tag: "v1", Summary: "Retrieves the user's balance", Access: apiAccessSelf, Response: &APIv1Balance{}},
+	}
+)
+
+// OpenAPI holds an OpenAPI 3 document, with only the parts this API uses
+type OpenAPI struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       *OpenAPIInfo                            `json:"info"`
+	Servers    []*OpenAPIServer                        `json:"servers,omitempty"`
+	Paths      map[string]map[string]*OpenAPIOperation `json:"paths"` //Where key = path, then method in lowercase
+	Components *OpenAPIComponents                      `json:"components"`
+}
+
+// OpenAPIInfo holds what the document describes
+type OpenAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// OpenAPIServer holds a URL the API is reached at
+type OpenAPIServer struct {
+	URL string `json:"url"`
+}
+
+// OpenAPIOperation describes a method of a path
+type OpenAPIOperation struct {
+	Tags        []string                    `json:"tags,omitempty"`
+	Summary     string                      `json:"summary"`
+	Description string                      `json:"description,omitempty"`
+	Parameters  []*OpenAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *OpenAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*OpenAPIResponse `json:"responses"` //Where key = status code
+	Security    []map[string][]string       `json:"security"`  //Empty for public routes
+}
+
+// OpenAPIParameter describes a path or query parameter
+type OpenAPIParameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"` //Either path or query
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required"`
+	Schema      *OpenAPISchema `json:"schema"`
+}
+
+// OpenAPIRequestBody describes the body of a request
+type OpenAPIRequestBody struct {
+	Required bool                         `json:"required"`
+	Content  map[string]*OpenAPIMediaType `json:"content"`
+}
+
+// OpenAPIResponse describes a response
+type OpenAPIResponse struct {
+	Description string                       `json:"description"`
+	Content     map[string]*OpenAPIMediaType `json:"content,omitempty"`
+}
+
+// OpenAPIMediaType holds the schema of a body
+type OpenAPIMediaType struct {
+	Schema *OpenAPISchema `json:"schema"`
+}
+
+// OpenAPISchema describes a JSON value
+type OpenAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Description          string                    `json:"description,omitempty"`
+	Nullable             bool                      `json:"nullable,omitempty"`
+	Items                *OpenAPISchema            `json:"items,omitempty"`
+	Properties           map[string]*OpenAPISchema `json:"properties,omitempty"`
+	AdditionalProperties *OpenAPISchema            `json:"additionalProperties,omitempty"`
+}
+
+// OpenAPIComponents holds the schemas and security schemes referenced throughout the document
+type OpenAPIComponents struct {
+	Schemas         map[string]*OpenAPISchema         `json:"schemas"`
+	SecuritySchemes map[string]*OpenAPISecurityScheme `json:"securitySchemes"`
+}
+
+// OpenAPISecurityScheme describes a way of authenticating
+type OpenAPISecurityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme,omitempty"`
+	In     string `json:"in,omitempty"`
+	Name   string `json:"name,omitempty"`
+}
+
+// apiRouteKey returns the key of a route in apiRouteDocs
+func apiRouteKey(method, pattern string) string {
+	if pattern != "/" {
+		pattern = strings.TrimSuffix(pattern, "/")
+	}
+	return method + " " + pattern
+}
+
+// isHiddenAPIRoute returns whether or not a route serves pages rather than the API
+func isHiddenAPIRoute(pattern string) bool {
+	for _, hidden := range apiHiddenRoutes {
+		if pattern == hidden {
+			return true
+		}
+	}
+	return false
+}
+
+// undocumentedAPIRoutes returns the routes of the router missing from apiRouteDocs, and the routes in apiRouteDocs missing from the router
+func undocumentedAPIRoutes(router chi.Routes) (undocumented []string, missing []string, err error) {
+	found := make(map[string]bool)
+	err = chi.Walk(router, func(method, pattern string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		if isHiddenAPIRoute(pattern) {
+			return nil
+		}
+		key := apiRouteKey(method, pattern)
+		found[key] = true
+		if _, exists := apiRouteDocs[key]; !exists {
+			undocumented = append(undocumented, key)
+		}
+		return nil
+	})
+	for key := range apiRouteDocs {
+		if !found[key] {
+			missing = append(missing, key)
+		}
+	}
+	sort.Strings(undocumented)
+	sort.Strings(missing)
+	return undocumented, missing, err
+}
+
+// newOpenAPI generates the OpenAPI document of every documented route of the router
+func newOpenAPI(router chi.Routes) (*OpenAPI, error) {
+	document := &OpenAPI{
+		OpenAPI: "3.0.3",
+		Info:    &OpenAPIInfo{Title: "Clinet API", Version: BuildID},
+		Paths:   make(map[string]map[string]*OpenAPIOperation),
+		Components: &OpenAPIComponents{
+			Schemas: make(map[string]*OpenAPISchema),
+			SecuritySchemes: map[string]*OpenAPISecurityScheme{
+				"bearer":  {Type: "http", Scheme: "bearer"},
+				"session": {Type: "apiKey", In: "cookie", Name: apiSessionCookie},
+			},
+		},
+	}
+	if botData().BotOptions.API.PublicURL != "" {
+		document.Servers = []*OpenAPIServer{{URL: strings.TrimSuffix(botData().BotOptions.API.PublicURL, "/")}}
+	}
+	schemas := &openAPISchemas{components: document.Components.Schemas}
+	document.Components.Schemas["APIError"] = schemas.schema(reflect.TypeOf(APIError{}))
+
+	err := chi.Walk(router, func(method, pattern string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		if isHiddenAPIRoute(pattern) {
+			return nil
+		}
+		key := apiRouteKey(method, pattern)
+		doc, exists := apiRouteDocs[key]
+		if !exists {
+			return nil //Logged when the API starts instead
+		}
+		routePath := strings.TrimPrefix(key, method+" ")
+		if document.Paths[routePath] == nil {
+			document.Paths[routePath] = make(map[string]*OpenAPIOperation)
+		}
+		document.Paths[routePath][strings.ToLower(method)] = doc.operation(routePath, schemas)
+		return nil
+	})
+	return document, err
+}
+
+// operation describes the route at the given path
+func (doc *APIRouteDoc) operation(routePath string, schemas *openAPISchemas) *OpenAPIOperation {
+	operation := &OpenAPIOperation{
+		Tags:        []string{doc.Tag},
+		Summary:     doc.Summary,
+		Description: doc.Description,
+		Responses:   make(map[string]*OpenAPIResponse),
+		Security:    make([]map[string][]string, 0),
+	}
+
+	for _, segment := range strings.Split(routePath, "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			name := segment[1 : len(segment)-1]
+			operation.Parameters = append(operation.Parameters, &OpenAPIParameter{Name: name, In: "path", Description: apiParamDocs[name], Required: true, Schema: &OpenAPISchema{Type: "string"}})
+		}
+	}
+	//v1 wraps responses in an envelope, and pages every list
+	enveloped := strings.HasPrefix(routePath, apiV1Prefix+"/")
+	paged := enveloped && doc.Response != nil && reflect.TypeOf(doc.Response).Kind() == reflect.Slice
+	query := make(map[string]string)
+	for name, description := range doc.Query {
+		query[name] = description
+	}
+	if paged {
+		query["cursor"] = "The next cursor of the previous page, to retrieve the page after it"
+		query["limit"] = "How many items to retrieve at most, " + strconv.Itoa(v1DefaultLimit) + " by default and " + strconv.Itoa(v1MaxLimit) + " at most"
+	}
+	queryNames := make([]string, 0, len(query))
+	for name := range query {
+		queryNames = append(queryNames, name)
+	}
+	sort.Strings(queryNames)
+	for _, name := range queryNames {
+		operation.Parameters = append(operation.Parameters, &OpenAPIParameter{Name: name, In: "query", Description: query[name], Schema: &OpenAPISchema{Type: "string"}})
+	}
+
+	if doc.Request != nil {
+		operation.RequestBody = &OpenAPIRequestBody{Required: true, Content: map[string]*OpenAPIMediaType{
+			"application/json": {Schema: schemas.schema(reflect.TypeOf(doc.Request))},
+		}}
+	}
+
+	status := doc.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	response := &OpenAPIResponse{Description: http.StatusText(status)}
+	if doc.Response != nil {
+		contentType := doc.ContentType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		schema := schemas.schema(reflect.TypeOf(doc.Response))
+		if enveloped {
+			schema = &OpenAPISchema{Type: "object", Properties: map[string]*OpenAPISchema{"data": schema}}
+			if paged {
+				schema.Properties["next"] = &OpenAPISchema{Type: "string", Description: "The cursor of the next page, left out on the last page"}
+			}
+		}
+		response.Content = map[string]*OpenAPIMediaType{contentType: {Schema: schema}}
+	}
+	operation.Responses[strconv.Itoa(status)] = response
+
+	errorStatuses := append([]int{http.StatusTooManyRequests}, doc.Errors...) //Every route is rate limited
+	if paged {
+		errorStatuses = append(errorStatuses, http.StatusBadRequest) //For an invalid cursor or limit
+	}
+	if doc.Access != apiAccessPublic {
+		operation.Security = []map[string][]string{{"bearer": {}}, {"session": {}}}
+		errorStatuses = append(errorStatuses, http.StatusUnauthorized)
+		if doc.Access != apiAccessUser {
+			errorStatuses = append(errorStatuses, http.StatusForbidden)
+		}
+	}
+	for _, errorStatus := range errorStatuses {
+		operation.Responses[strconv.Itoa(errorStatus)] = &OpenAPIResponse{
+			Description: http.StatusText(errorStatus),
+			Content:     map[string]*OpenAPIMediaType{"application/json": {Schema: &OpenAPISchema{Ref: "#/components/schemas/APIError"}}},
+		}
+	}
+	return operation
+}
+
+// openAPISchemas builds schemas from Go types, adding each named struct to the components once and referencing it everywhere else
+type openAPISchemas struct {
+	components map[string]*OpenAPISchema
+	names      map[reflect.Type]string
+}
+
+var (
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// schema returns the schema of the JSON encoding of the given type
+func (schemas *openAPISchemas) schema(valueType reflect.Type) *OpenAPISchema {
+	nullable := false
+	for valueType.Kind() == reflect.Ptr {
+		nullable = true
+		valueType = valueType.Elem()
+	}
+
+	schema := schemas.typeSchema(valueType)
+	if nullable && schema.Ref == "" {
+		schema.Nullable = true
+	}
+	return schema
+}
+
+func (schemas *openAPISchemas) typeSchema(valueType reflect.Type) *OpenAPISchema {
+	switch {
+	case valueType == reflect.TypeOf(time.Time{}):
+		return &OpenAPISchema{Type: "string", Format: "date-time"}
+	case valueType == reflect.TypeOf(json.RawMessage{}):
+		return &OpenAPISchema{Description: "Any JSON value"}
+	case valueType.Implements(jsonMarshalerType) || reflect.PtrTo(valueType).Implements(jsonMarshalerType):
+		return &OpenAPISchema{}
+	case valueType.Implements(textMarshalerType) || reflect.PtrTo(valueType).Implements(textMarshalerType):
+		return &OpenAPISchema{Type: "string"}
+	}
+
+	switch valueType.Kind() {
+	case reflect.Bool:
+		return &OpenAPISchema{Type: "boolean"}
+	case reflect.String:
+		return &OpenAPISchema{Type: "string"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &OpenAPISchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &OpenAPISchema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		if valueType.Elem().Kind() == reflect.Uint8 {
+			return &OpenAPISchema{Type: "string", Format: "byte"} //Byte slices are encoded in base64
+		}
+		return &OpenAPISchema{Type: "array", Items: schemas.schema(valueType.Elem())}
+	case reflect.Map:
+		return &OpenAPISchema{Type: "object", AdditionalProperties: schemas.schema(valueType.Elem())}
+	case reflect.Struct:
+		return schemas.structSchema(valueType)
+	}
+	return &OpenAPISchema{} //Interfaces can hold anything
+}
+
+// structSchema returns a reference to the schema of a named struct, or the schema itself if the struct has no name
+func (schemas *openAPISchemas) structSchema(valueType reflect.Type) *OpenAPISchema {
+	if valueType.Name() == "" {
+		return schemas.objectSchema(valueType)
+	}
+
+	if schemas.names == nil {
+		schemas.names = make(map[reflect.Type]string)
+	}
+	name, exists := schemas.names[valueType]
+	if !exists {
+		name = valueType.Name()
+		if valueType.PkgPath() != reflect.TypeOf(OpenAPI{}).PkgPath() {
+			name = path.Base(valueType.PkgPath()) + "." + name //Types from other packages can share names with ours
+		}
+		schemas.names[valueType] = name
+		schemas.components[name] = &OpenAPISchema{} //Referenced by itself while it's being built, for types that contain themselves
+		schemas.components[name] = schemas.objectSchema(valueType)
+	}
+	return &OpenAPISchema{Ref: "#/components/schemas/" + name}
+}
+
+// objectSchema returns the schema of every field of a struct, including those of embedded structs
+func (schemas *openAPISchemas) objectSchema(valueType reflect.Type) *OpenAPISchema {
+	schema := &OpenAPISchema{Type: "object", Properties: make(map[string]*OpenAPISchema)}
+	if valueType.Name() != "" {
+		schema.Description = settingDescription(valueType.Name()).Description
+	}
+	for i := 0; i < valueType.NumField(); i++ {
+		field := valueType.Field(i)
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && field.Tag.Get("json") == "" && fieldType.Kind() == reflect.Struct {
+			for name, property := range schemas.objectSchema(fieldType).Properties {
+				if _, exists := schema.Properties[name]; !exists {
+					schema.Properties[name] = property //Fields of the outer struct take precedence
+				}
+			}
+			continue
+		}
+
+		jsonName := settingJSONName(field)
+		if jsonName == "" || fieldType.Kind() == reflect.Func || fieldType.Kind() == reflect.Chan {
+			continue
+		}
+		property := schemas.schema(field.Type)
+		if description := settingDescription(valueType.Name() + "." + field.Name).Description; description != "" && property.Ref == "" {
+			property.Description = description
+		}
+		schema.Properties[jsonName] = property
+	}
+	return schema
+}
+
+// apiGetOpenAPI returns the handler serving the OpenAPI document of the router, which is generated on the first request after every route is added
+func apiGetOpenAPI(router chi.Routes) http.HandlerFunc {
+	var (
+		once     sync.Once
+		document *OpenAPI
+		err      error
+	)
+	return func(w http.ResponseWriter, r *http.Request) {
+		once.Do(func() {
+			document, err = newOpenAPI(router)
+		})
+		if err != nil {
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, errAPI(wrapError(errCodeAPIOpenAPIFailed, err)))
+			return
+		}
+		render.JSON(w, r, document)
+	}
+}
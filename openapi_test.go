@@ -0,0 +1,24 @@
+package main
+
+import "testing"
+
+// TestAPIRoutesDocumented fails when a route is added to the API without documenting it in apiRouteDocs, or when a documented route no longer exists
+func TestAPIRoutesDocumented(t *testing.T) {
+	undocumented, missing, err := undocumentedAPIRoutes(APIRouter())
+	if err != nil {
+		t.Fatalf("Error walking the API routes: %v", err)
+	}
+	for _, route := range undocumented {
+		t.Errorf("Route %s isn't documented in apiRouteDocs", route)
+	}
+	for _, route := range missing {
+		t.Errorf("Route %s is documented in apiRouteDocs but doesn't exist", route)
+	}
+}
+
+// TestOpenAPIDocument fails when the OpenAPI document can't be generated from the documented routes
+func TestOpenAPIDocument(t *testing.T) {
+	if _, err := newOpenAPI(APIRouter()); err != nil {
+		t.Fatalf("Error generating the OpenAPI document: %v", err)
+	}
+}
@@ -78,6 +78,37 @@ func MemberHasPermission(s *discordgo.Session, guildID string, userID string, ch
 	return false, nil
 }
 
+// MemberManagesGuild returns whether or not a member owns a guild or has a role that can manage it
+func MemberManagesGuild(s *discordgo.Session, guildID string, userID string) (bool, error) {
+	guild, err := s.State.Guild(guildID)
+	if err != nil {
+		if guild, err = s.Guild(guildID); err != nil {
+			return false, err
+		}
+	}
+	if guild.OwnerID == userID {
+		return true, nil
+	}
+
+	member, err := s.State.Member(guildID, userID)
+	if err != nil {
+		if member, err = s.GuildMember(guildID, userID); err != nil {
+			return false, err
+		}
+	}
+
+	for _, roleID := range append([]string{guildID}, member.Roles...) { //The @everyone role shares the guild's ID
+		role, err := s.State.Role(guildID, roleID)
+		if err != nil {
+			continue
+		}
+		if role.Permissions&(discordgo.PermissionAdministrator|discordgo.PermissionManageServer) != 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // CreationTime returns the creation time of a Snowflake ID relative to the creation of Discord.
 // Taken from https://github.com/Moonlington/FloSelfbot/blob/master/commands/commandutils.go#L117
 func CreationTime(ID string) (t time.Time, err error) {
@@ -4,64 +4,70 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
-	"runtime"
 	"strconv"
 	"syscall"
 
 	"github.com/mitchellh/go-ps"
 )
 
-func spawnBot() int {
-	if killOldBot == "true" {
-		processList, err := ps.Processes()
-		if err == nil {
-			for _, process := range processList {
-				if process.Pid() != os.Getpid() && process.Pid() != masterPID && process.Executable() == filepath.Base(os.Args[0]) {
-					oldProcess, err := os.FindProcess(process.Pid())
-					if err == nil {
-						oldProcess.Signal(syscall.SIGKILL)
-					}
+// killOldBots kills every other process running this executable, such as the bot processes left behind by an update
+func killOldBots() {
+	processList, err := ps.Processes()
+	if err == nil {
+		for _, process := range processList {
+			if process.Pid() != os.Getpid() && process.Pid() != masterPID && process.Executable() == filepath.Base(os.Args[0]) {
+				oldProcess, err := os.FindProcess(process.Pid())
+				if err == nil {
+					oldProcess.Signal(syscall.SIGKILL)
 				}
 			}
 		}
 	}
-	os.Remove(os.Args[0] + ".old")
-
-	botProcess := exec.Command(os.Args[0], "-bot", "true", "-config", configFile, "-masterpid", strconv.Itoa(os.Getpid()), "-debug", debug, "-gcptoken", gcpAuthTokenFile)
-	botProcess.Stdout = os.Stdout
-	botProcess.Stderr = os.Stderr
-	err := botProcess.Start()
-	if err != nil {
-		panic(err)
-	}
-	return botProcess.Process.Pid
 }
 
-func isProcessRunning(pid int) bool {
-	process, err := os.FindProcess(pid)
-	if err != nil {
-		return false
+// spawnBot starts a new bot process for a shard, passing it the write end of a heartbeat pipe where supported
+func spawnBot(shard *ShardSupervisor, hub *IPCHub) (*BotProcess, error) {
+	botArgs := []string{"-bot", "-config", configFile, "-masterpid", strconv.Itoa(os.Getpid()), "-debug=" + strconv.FormatBool(debug), "-gcptoken", gcpAuthTokenFile, "-restarts", strconv.Itoa(shard.Restarts)}
+	if shard.ShardCount > 1 {
+		botArgs = append(botArgs, "-shard", strconv.Itoa(shard.ShardID), "-shards", strconv.Itoa(shard.ShardCount), "-ipc", hub.Addr)
 	}
+	bot := &BotProcess{}
 
-	if runtime.GOOS != "windows" {
-		return process.Signal(syscall.Signal(0)) == nil
+	var heartbeatReader, heartbeatWriter *os.File
+	if heartbeatsSupported() {
+		var err error
+		heartbeatReader, heartbeatWriter, err = os.Pipe()
+		if err != nil {
+			return nil, err
+		}
+		botArgs = append(botArgs, "-heartbeat") //The write end is always the first extra file, which is fd 3 in the bot process
 	}
 
-	processState, err := process.Wait()
-	if err != nil {
-		return false
+	bot.Cmd = exec.Command(os.Args[0], append(botArgs, configLayerArgs()...)...)
+	bot.Cmd.Stdout = os.Stdout
+	bot.Cmd.Stderr = os.Stderr
+	if hub != nil {
+		//The IPC token is passed through the environment so it doesn't show up in the process list
+		bot.Cmd.Env = append(os.Environ(), ipcTokenEnv+"="+hub.Token)
 	}
-	if processState.Exited() {
-		return false
+	if heartbeatWriter != nil {
+		bot.Cmd.ExtraFiles = []*os.File{heartbeatWriter}
 	}
 
-	return true
-}
-
-func waitProcess(pid int) {
-	process, err := os.FindProcess(pid)
+	err := bot.Cmd.Start()
+	if heartbeatWriter != nil {
+		heartbeatWriter.Close() //Only the bot should hold the write end, so the reader sees EOF when the bot exits
+	}
 	if err != nil {
-		return
+		if heartbeatReader != nil {
+			heartbeatReader.Close()
+		}
+		return nil, err
+	}
+
+	if heartbeatReader != nil {
+		bot.Heartbeats = make(chan bool, 1)
+		go readHeartbeats(heartbeatReader, bot.Heartbeats)
 	}
-	_, _ = process.Wait()
+	return bot, nil
 }
@@ -37,8 +37,8 @@ func (*QueryServiceCustomResponse) Query(query string, env *QueryEnvironment) (*
 		customResponses = append(customResponses, guildSettings[env.Guild.ID].CustomResponses...)
 	}
 	//Add global custom responses
-	if len(botData.CustomResponses) > 0 {
-		customResponses = append(customResponses, botData.CustomResponses...)
+	if len(botData().CustomResponses) > 0 {
+		customResponses = append(customResponses, botData().CustomResponses...)
 	}
 
 	for _, response := range customResponses {
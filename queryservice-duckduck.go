@@ -29,7 +29,7 @@ func (*QueryServiceDuckDuckGo) GetIconURL() string {
 //Query returns the response to a query
 func (*QueryServiceDuckDuckGo) Query(query string, env *QueryEnvironment) (*discordgo.MessageEmbed, error) {
 	Debug.Printf("[DuckDuckGo] Getting result for [%s]...", query)
-	queryResult, err := botData.BotClients.DuckDuckGo.GetQueryResult(query)
+	queryResult, err := botData().BotClients.DuckDuckGo.GetQueryResult(query)
 	if err != nil {
 		Debug.Printf("[DuckDuckGo] Error getting query result: %v", err)
 		return nil, errors.New("error getting response")
@@ -49,7 +49,7 @@ func (*QueryServiceDuckDuckGo) Query(query string, env *QueryEnvironment) (*disc
 		return nil, errors.New("error getting allowed result from response")
 	}
 
-	for old, new := range botData.BotOptions.QueryResponseReplacements {
+	for old, new := range botData().BotOptions.QueryResponseReplacements {
 		result = strings.ReplaceAll(result, old, new)
 	}
 
@@ -30,7 +30,7 @@ func (*QueryServiceGoogleAssistant) GetIconURL() string {
 //Query returns a Google Assistant response to a given query
 func (*QueryServiceGoogleAssistant) Query(query string, env *QueryEnvironment) (*discordgo.MessageEmbed, error) {
 	Debug.Println("[Google Assistant] Spawning a conversation...")
-	conversation, err := botData.BotClients.GoogleAssistant.NewConversation(time.Second * 240)
+	conversation, err := botData().BotClients.GoogleAssistant.NewConversation(time.Second * 240)
 	if err != nil {
 		return nil, err
 	}
@@ -50,7 +50,7 @@ func (*QueryServiceGoogleAssistant) Query(query string, env *QueryEnvironment) (
 		return nil, errors.New("error getting allowed result from response")
 	}
 
-	for old, new := range botData.BotOptions.QueryResponseReplacements {
+	for old, new := range botData().BotOptions.QueryResponseReplacements {
 		result = strings.ReplaceAll(result, old, new)
 	}
 
@@ -30,7 +30,7 @@ func (*QueryServiceWolframAlpha) GetIconURL() string {
 //Query returns the response to a query
 func (service *QueryServiceWolframAlpha) Query(query string, env *QueryEnvironment) (*discordgo.MessageEmbed, error) {
 	Debug.Printf("[Wolfram|Alpha] Getting result for query [%s]...", query)
-	conversationResult, err := botData.BotClients.Wolfram.GetConversationalQuery(query, wolfram.Metric, env.WolframConversation)
+	conversationResult, err := botData().BotClients.Wolfram.GetConversationalQuery(query, wolfram.Metric, env.WolframConversation)
 	if err != nil {
 		Debug.Printf("[Wolfram|Alpha] Error getting query result: %v", err)
 		wolframStoreConversation(nil, env)
@@ -55,7 +55,7 @@ func (service *QueryServiceWolframAlpha) Query(query string, env *QueryEnvironme
 
 	result := conversationResult.Result
 
-	for old, new := range botData.BotOptions.QueryResponseReplacements {
+	for old, new := range botData().BotOptions.QueryResponseReplacements {
 		result = strings.ReplaceAll(result, old, new)
 	}
 
@@ -66,6 +66,7 @@ func (service *QueryServiceWolframAlpha) Query(query string, env *QueryEnvironme
 }
 
 func wolframStoreConversation(conversation *wolfram.Conversation, env *QueryEnvironment) {
-	Debug.Printf("[Wolfram|Alpha] Storing conversation...")
+	Debug.With("guild", env.Guild.ID, "user", env.User.ID).Println("[Wolfram|Alpha] Storing conversation...")
 	guildData[env.Guild.ID].WolframConversations[env.User.ID] = conversation
+	guildData[env.Guild.ID].TouchWolframConversation(env.User.ID)
 }
@@ -31,25 +31,29 @@ type QueryEnvironment struct {
 	UpdatedMessageEvent bool
 }
 
-func initQueryServices() {
-	botData.QueryServices = make([]QueryService, 0)
+func (configData *BotData) initQueryServices() {
+	configData.QueryServices = make([]QueryService, 0)
 
-	if botData.BotOptions.UseCustomResponses {
-		botData.QueryServices = append(botData.QueryServices, &QueryServiceCustomResponse{})
+	if configData.BotOptions.UseCustomResponses {
+		configData.QueryServices = append(configData.QueryServices, &QueryServiceCustomResponse{})
 	}
 	if gcpAuthTokenFile != "" {
-		botData.QueryServices = append(botData.QueryServices, &QueryServiceGoogleAssistant{})
+		configData.QueryServices = append(configData.QueryServices, &QueryServiceGoogleAssistant{})
 	}
-	if botData.BotOptions.UseDuckDuckGo {
-		botData.QueryServices = append(botData.QueryServices, &QueryServiceDuckDuckGo{})
+	if configData.BotOptions.UseDuckDuckGo {
+		configData.QueryServices = append(configData.QueryServices, &QueryServiceDuckDuckGo{})
 	}
-	if botData.BotOptions.UseWolframAlpha {
-		botData.QueryServices = append(botData.QueryServices, &QueryServiceWolframAlpha{})
+	if configData.BotOptions.UseWolframAlpha {
+		configData.QueryServices = append(configData.QueryServices, &QueryServiceWolframAlpha{})
 	}
 }
 
 func getQueryResult(query string, env *QueryEnvironment) (*discordgo.MessageEmbed, error) {
-	for _, service := range botData.QueryServices {
+	for _, service := range botData().QueryServices {
+		if !serviceEnabled(env.Guild.ID, service.GetName()) {
+			continue
+		}
+
 		queryResult, err := service.Query(query, env)
 		if err != nil {
 			Error.Println(err)
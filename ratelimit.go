@@ -0,0 +1,61 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiter limits how often each key may do something within a sliding window, such as an IP address making API requests
+type RateLimiter struct {
+	sync.Mutex
+
+	Window time.Duration //How far back hits count against the limit
+
+	hits      map[string][]time.Time //When each key was allowed within the window, oldest first
+	lastSweep time.Time
+}
+
+func newRateLimiter(window time.Duration) *RateLimiter {
+	return &RateLimiter{Window: window, hits: make(map[string][]time.Time), lastSweep: time.Now()}
+}
+
+// Allow records a hit by the key if it's within the limit, returning how many hits it has left in the window
+// If it's over the limit, it instead returns how long until the key may hit again
+func (limiter *RateLimiter) Allow(key string, limit int) (remaining int, retryAfter time.Duration, allowed bool) {
+	limiter.Lock()
+	defer limiter.Unlock()
+
+	now := time.Now()
+	if now.Sub(limiter.lastSweep) >= limiter.Window {
+		limiter.sweep(now) //Keys that stop hitting, such as IPs that went away, would otherwise be kept forever
+	}
+
+	hits := limiter.hits[key]
+	for len(hits) > 0 && now.Sub(hits[0]) >= limiter.Window {
+		hits = hits[1:]
+	}
+	if len(hits) > 0 && len(hits) >= limit {
+		limiter.hits[key] = hits
+		return 0, hits[0].Add(limiter.Window).Sub(now), false
+	}
+	limiter.hits[key] = append(hits, now)
+	return limit - len(hits) - 1, 0, true
+}
+
+// Forget removes every hit by the key, such as when a key is revoked
+func (limiter *RateLimiter) Forget(key string) {
+	limiter.Lock()
+	defer limiter.Unlock()
+
+	delete(limiter.hits, key)
+}
+
+// sweep removes the keys without any hits left in the window
+func (limiter *RateLimiter) sweep(now time.Time) {
+	for key, hits := range limiter.hits {
+		if len(hits) == 0 || now.Sub(hits[len(hits)-1]) >= limiter.Window {
+			delete(limiter.hits, key)
+		}
+	}
+	limiter.lastSweep = now
+}
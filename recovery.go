@@ -1,35 +1,189 @@
 package main
 
 import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"runtime"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var (
+	//How many handler panics within the window are treated as a fault that only a restart may fix
+	handlerPanicThreshold = 10
+	handlerPanicWindow    = 10 * time.Minute
+
+	//How many guilds the same handler must panic in within the window for it to be treated as a global fault
+	globalFaultGuilds = 3
+
+	//How long to wait before reporting the same panic to the bot owner again
+	panicReportCooldown = 10 * time.Minute
+
+	//The recent handler panics, and when each panic was last reported to the bot owner, where key = handler and reason
+	handlerPanics     = make([]HandlerPanic, 0)
+	panicReports      = make(map[string]time.Time)
+	handlerPanicsLock sync.Mutex
+
+	//Makes sure only the first fault to escalate restarts the bot
+	escalateOnce sync.Once
 )
 
+// HandlerPanic records a panic recovered from a handler
+type HandlerPanic struct {
+	Time    time.Time
+	Handler string //The handler that panicked, ex: messageCreate or command:queue
+	GuildID string //The guild the handler was running for, if any
+}
+
+// recoverPanic is deferred at the top of the process and of anything the bot can't run without, and crashes the bot on a panic
+// The crash is saved so the bot owner can be told about it once the bot has been restarted
 func recoverPanic() {
 	if panicReason := recover(); panicReason != nil {
 		fmt.Println("Clinet has encountered an unrecoverable error and has crashed.")
-		fmt.Println("Some information describing this crash: " + panicReason.(error).Error())
-		if botData.SendOwnerStackTraces || configIsBot == "false" {
-			stack := make([]byte, 65536)
-			l := runtime.Stack(stack, true)
-			fmt.Println("Stack trace:\n" + string(stack[:l]))
-			err := ioutil.WriteFile("stacktrace.txt", stack[:l], 0644)
-			if err != nil {
-				fmt.Println("Failed to write stack trace.")
-			}
-			err = ioutil.WriteFile("crash.txt", []byte(panicReason.(error).Error()), 0644)
-			if err != nil {
-				fmt.Println("Failed to write crash error.")
-			}
-		}
+		fmt.Printf("Some information describing this crash: %v\n", panicReason)
+		stack := make([]byte, 65536)
+		l := runtime.Stack(stack, true)
+		fmt.Println("Stack trace:\n" + string(stack[:l]))
+		writeCrashReport(fmt.Sprint(panicReason), stack[:l])
 		os.Exit(1)
 	}
 }
 
+// writeCrashReport saves a crash for checkPanicRecovery to report to the bot owner on the next startup
+func writeCrashReport(reason string, stack []byte) {
+	if !botData().SendOwnerStackTraces && configIsBot {
+		return
+	}
+	err := ioutil.WriteFile("stacktrace.txt", stack, 0644)
+	if err != nil {
+		fmt.Println("Failed to write stack trace.")
+	}
+	err = ioutil.WriteFile("crash.txt", []byte(reason), 0644)
+	if err != nil {
+		fmt.Println("Failed to write crash error.")
+	}
+}
+
+// recoverHandler is deferred at the top of event handlers and background jobs, so a panic only affects the event or job that caused it
+func recoverHandler(handler, guildID string) {
+	if panicReason := recover(); panicReason != nil {
+		handlePanic(handler, guildID, panicReason)
+	}
+}
+
+// recoverCommand is deferred by callCommand, so a panic in a command responds with an error embed instead of taking the bot down
+func recoverCommand(commandName string, env *CommandEnvironment, responseEmbed **discordgo.MessageEmbed) {
+	if panicReason := recover(); panicReason != nil {
+		guildID := ""
+		if env.Guild != nil {
+			guildID = env.Guild.ID
+		}
+		ref := handlePanic("command:"+commandName, guildID, panicReason)
+		*responseEmbed = getErrorEmbed(newError(errCodeCommandInternal, ref), env.locale())
+	}
+}
+
+// handlePanic logs and reports a panic recovered from a handler, returning the reference ID it was logged with
+// If handlers keep panicking, or the same handler panics across several guilds, the bot is restarted
+func handlePanic(handler, guildID string, panicReason interface{}) string {
+	stack := make([]byte, 65536)
+	stack = stack[:runtime.Stack(stack, false)] //Only the panicking goroutine, the others carry on
+	ref := newPanicReference()
+
+	Error.With("handler", handler, "guild", guildID, "ref", ref).Printf("Recovered from panic: %v\n%s", panicReason, stack)
+	metricHandlerPanics.Inc(handler)
+	go reportPanic(handler, guildID, ref, panicReason, stack)
+
+	if fault := recordHandlerPanic(handler, guildID); fault != "" {
+		escalateOnce.Do(func() {
+			Error.Printf("Restarting after %s, last panic in %s: %v", fault, handler, panicReason)
+			writeCrashReport(fmt.Sprintf("%v (in %s, restarted after %s)", panicReason, handler, fault), stack)
+			shutdownBot("restart")
+			os.Exit(1)
+		})
+	}
+	return ref
+}
+
+// recordHandlerPanic records a handler panic, returning a description of the fault if a restart is needed
+func recordHandlerPanic(handler, guildID string) string {
+	handlerPanicsLock.Lock()
+	defer handlerPanicsLock.Unlock()
+
+	now := time.Now()
+	recentPanics := make([]HandlerPanic, 0, len(handlerPanics)+1)
+	for _, handlerPanic := range handlerPanics {
+		if now.Sub(handlerPanic.Time) < handlerPanicWindow {
+			recentPanics = append(recentPanics, handlerPanic)
+		}
+	}
+	recentPanics = append(recentPanics, HandlerPanic{Time: now, Handler: handler, GuildID: guildID})
+	handlerPanics = recentPanics
+
+	if len(handlerPanics) >= handlerPanicThreshold {
+		return strconv.Itoa(len(handlerPanics)) + " panics within " + handlerPanicWindow.String()
+	}
+
+	guilds := make(map[string]bool)
+	for _, handlerPanic := range handlerPanics {
+		if handlerPanic.Handler == handler && handlerPanic.GuildID != "" {
+			guilds[handlerPanic.GuildID] = true
+		}
+	}
+	if len(guilds) >= globalFaultGuilds {
+		return handler + " panicking in " + strconv.Itoa(len(guilds)) + " guilds within " + handlerPanicWindow.String()
+	}
+	return ""
+}
+
+// reportPanic sends a handler panic to the bot owner, unless the same panic was reported recently
+func reportPanic(handler, guildID, ref string, panicReason interface{}, stack []byte) {
+	if !botData().SendOwnerStackTraces || botData().BotOwnerID == "" || botData().DiscordSession == nil {
+		return
+	}
+
+	reportKey := handler + "\x00" + fmt.Sprint(panicReason)
+	handlerPanicsLock.Lock()
+	if lastReported, exists := panicReports[reportKey]; exists && time.Since(lastReported) < panicReportCooldown {
+		handlerPanicsLock.Unlock()
+		return
+	}
+	panicReports[reportKey] = time.Now()
+	handlerPanicsLock.Unlock()
+
+	ownerPrivChannel, err := botData().DiscordSession.UserChannelCreate(botData().BotOwnerID)
+	if err != nil {
+		debugLog("An error occurred creating a private channel with the bot owner.", false)
+		return
+	}
+
+	where := handler
+	if guildID != "" {
+		where += " in guild " + guildID
+	}
+	botData().DiscordSession.ChannelMessageSend(ownerPrivChannel.ID, "Clinet has just recovered from a panic in "+where+" (reference ID ``"+ref+"``).")
+	botData().DiscordSession.ChannelMessageSend(ownerPrivChannel.ID, fmt.Sprintf("Panic:\n```%v```", panicReason))
+	botData().DiscordSession.ChannelFileSendWithMessage(ownerPrivChannel.ID, "Stack trace:", "stacktrace-"+ref+".txt", bytes.NewReader(stack))
+}
+
+// newPanicReference returns a short random ID to find a panic in the logs by
+func newPanicReference() string {
+	ref := make([]byte, 4)
+	if _, err := rand.Read(ref); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(ref)
+}
+
 func checkPanicRecovery() {
-	ownerPrivChannel, err := botData.DiscordSession.UserChannelCreate(botData.BotOwnerID)
+	ownerPrivChannel, err := botData().DiscordSession.UserChannelCreate(botData().BotOwnerID)
 	if err != nil {
 		debugLog("An error occurred creating a private channel with the bot owner.", false)
 	} else {
@@ -41,9 +195,9 @@ func checkPanicRecovery() {
 		if crashErr == nil && stackErr == nil {
 			DowntimeReason = "Crash: " + string(crash)
 
-			botData.DiscordSession.ChannelMessageSend(ownerPrivChannelID, "Clinet has just recovered from an error that caused a crash.")
-			botData.DiscordSession.ChannelMessageSend(ownerPrivChannelID, "Crash:\n```"+string(crash)+"```")
-			botData.DiscordSession.ChannelFileSendWithMessage(ownerPrivChannelID, "Stack trace:", "stacktrace.txt", stack)
+			botData().DiscordSession.ChannelMessageSend(ownerPrivChannelID, "Clinet has just recovered from an error that caused a crash.")
+			botData().DiscordSession.ChannelMessageSend(ownerPrivChannelID, "Crash:\n```"+string(crash)+"```")
+			botData().DiscordSession.ChannelFileSendWithMessage(ownerPrivChannelID, "Stack trace:", "stacktrace.txt", stack)
 		}
 
 		stack.Close()
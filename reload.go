@@ -0,0 +1,282 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+
+	duckduckgo "github.com/JoshuaDoes/duckduckgolang"
+	soundcloud "github.com/JoshuaDoes/go-soundcloud"
+	wolfram "github.com/JoshuaDoes/go-wolfram"
+	gassist "github.com/JoshuaDoes/google-assistant/v1alpha2"
+	"github.com/JoshuaDoes/spotigo"
+	"github.com/google/go-github/github"
+	ytdl "github.com/kkdai/youtube/v2"
+	klogger "github.com/koffeinsource/go-klogger"
+	xkcd "github.com/nishanths/go-xkcd"
+	lyrics "github.com/rhnvrm/lyric-api-go"
+	"github.com/superwhiskers/fennel"
+	"google.golang.org/api/googleapi/transport"
+	"google.golang.org/api/youtube/v3"
+)
+
+var (
+	//Serializes configuration reloads so a signal and a command can't swap at the same time
+	reloadMutex sync.Mutex
+
+	//Stops the running configuration watcher, which checks the configuration file every configWatchFrequency seconds
+	configWatcherStop    chan bool
+	configWatchFrequency int
+	configWatcherLock    sync.Mutex
+)
+
+// botData returns the live configuration, which may be read from any goroutine
+// Reloads replace it with a new one through setBotData rather than changing it in place
+func botData() *BotData {
+	return botDataValue.Load().(*BotData)
+}
+
+// setBotData publishes a complete configuration, which every later call to botData() returns
+func setBotData(configData *BotData) {
+	botDataValue.Store(configData)
+}
+
+// loadBotData layers and prepares a fresh configuration from the given file, returning every error found along the way
+// Clients for external services are left for the caller to build with initClients
+func loadBotData(file string) (*BotData, []error) {
+	configData, err := loadConfigLayers(file)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	report := configData.PrepConfig()
+	for _, warning := range report.Warnings {
+		Warning.Printf("Configuration warning: %v", warning)
+	}
+	if len(report.Errors) > 0 {
+		return configData, report.Errs()
+	}
+
+	return configData, nil
+}
+
+// newGoogleAssistant builds a Google Assistant client from the token file
+// The library can only wait for one interactive login per process, so if the login of a running client is given, its OAuth token is reused instead
+func newGoogleAssistant(tokenFile string, login *gassist.GCPAuthWrapper) (gassist.Assistant, error) {
+	tokenJSON, err := ioutil.ReadFile(tokenFile)
+	if err != nil {
+		return gassist.Assistant{}, err
+	}
+	token := &gassist.Token{}
+	if err := json.Unmarshal(tokenJSON, token); err != nil {
+		return gassist.Assistant{}, err
+	}
+	device := gassist.NewDevice("254636LIVE0001", "assistant-for-clinet")
+	audioSettings := gassist.NewAudioSettings(1, 1, 16000, 16000, 100)
+
+	if login == nil {
+		return gassist.NewAssistant(token, nil, "en-US", device, audioSettings)
+	}
+
+	if login.Config == nil || login.Config.ClientID != token.Installed.ClientID || login.Config.ClientSecret != token.Installed.ClientSecret {
+		return gassist.Assistant{}, fmt.Errorf("%s is for a different client than the one logged in, restart to log in with it", tokenFile)
+	}
+	oauthToken, err := login.Config.TokenSource(context.Background(), login.OauthToken).Token() //Refreshed if expired, as an invalid token starts a new login
+	if err != nil {
+		return gassist.Assistant{}, err
+	}
+	assistant, err := gassist.NewAssistant(token, oauthToken, "en-US", device, audioSettings)
+	if err != nil {
+		return gassist.Assistant{}, err
+	}
+	assistant.GCPAuth.Config = login.Config
+	assistant.GCPAuth.OauthSrv = login.OauthSrv //Still shut down along with the client
+	return assistant, nil
+}
+
+// initClients builds every client for external services enabled by the configuration, returning every error found along the way
+// The Google Assistant isn't built here as it's authenticated interactively from a token file, see newGoogleAssistant
+func (configData *BotData) initClients() []error {
+	errs := make([]error, 0)
+
+	if configData.BotOptions.UseDuckDuckGo {
+		configData.BotClients.DuckDuckGo = &duckduckgo.Client{AppName: configData.BotKeys.DuckDuckGoAppName}
+	}
+	if configData.BotOptions.UseImgur {
+		configData.BotClients.Imgur.HTTPClient = botHTTPClient
+		configData.BotClients.Imgur.Log = &klogger.CLILogger{}
+		configData.BotClients.Imgur.ImgurClientID = configData.BotKeys.ImgurClientID
+	}
+	if configData.BotOptions.UseSoundCloud {
+		configData.BotClients.SoundCloud = &soundcloud.Client{ClientID: configData.BotKeys.SoundCloudClientID}
+	}
+	if configData.BotOptions.UseSpotify {
+		configData.BotClients.Spotify = &spotigo.Client{Host: configData.BotKeys.SpotifyHost, Pass: configData.BotKeys.SpotifyPass}
+	}
+	if configData.BotOptions.UseWolframAlpha {
+		configData.BotClients.Wolfram = &wolfram.Client{AppID: configData.BotKeys.WolframAppID}
+	}
+	if configData.BotOptions.UseXKCD {
+		configData.BotClients.XKCD = xkcd.NewClient()
+		configData.BotClients.XKCD.HTTPClient = botHTTPClient
+	}
+	if configData.BotOptions.UseYouTube {
+		httpClient := &http.Client{
+			Transport: &transport.APIKey{Key: configData.BotKeys.YouTubeAPIKey, Transport: &MetricsTransport{}},
+		}
+		youtubeClient, err := youtube.New(httpClient)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error initializing YouTube: %v", err))
+		} else {
+			configData.BotClients.YouTube = youtubeClient
+		}
+		configData.BotClients.YTDL = &ytdl.Client{
+			HTTPClient: httpClient,
+		}
+	}
+	if configData.BotOptions.UseGitHub {
+		configData.BotClients.GitHub = github.NewClient(botHTTPClient)
+	}
+	if configData.BotOptions.UseLyrics {
+		configData.BotClients.Lyrics = lyrics.New(lyrics.WithoutProviders(), lyrics.WithLyricsWikia(), lyrics.WithMusixMatch(), lyrics.WithSongLyrics(), lyrics.WithGeniusLyrics(configData.BotKeys.GeniusAccessToken))
+	}
+	if configData.BotOptions.UseNinty {
+		nintyClient, err := fennel.NewAccountServerClient("https://account.nintendo.net/v1/api", ctrCommonCert, ctrCommonKey, configData.BotKeys.Ninty)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error initializing Ninty: %v", err))
+		} else {
+			configData.BotClients.Ninty = nintyClient
+		}
+	}
+	if configData.BotOptions.UseFeed {
+		configData.BotClients.FeedParser = gofeed.NewParser()
+	}
+
+	return errs
+}
+
+// reloadBotData builds a complete new configuration and client set from the configuration file and swaps it in
+// If anything is wrong with the new configuration, the running one is left untouched and every error is returned
+func reloadBotData() []error {
+	reloadMutex.Lock()
+	defer reloadMutex.Unlock()
+
+	newBotData, errs := loadBotData(configFile)
+	if len(errs) > 0 {
+		return errs
+	}
+	if errs = newBotData.initClients(); len(errs) > 0 {
+		return errs
+	}
+
+	//Carry over everything that belongs to the running process rather than the configuration
+	oldBotData := botData()
+	newBotData.BotName = oldBotData.BotName
+	newBotData.DiscordSession = oldBotData.DiscordSession
+	newBotData.NLPCommands = oldBotData.NLPCommands
+	newBotData.LastTipMessage = oldBotData.LastTipMessage
+	newBotData.Updating = oldBotData.Updating
+
+	newBotData.BotClients.GoogleAssistant = oldBotData.BotClients.GoogleAssistant
+	replacedAssistant := false
+	if login := oldBotData.BotClients.GoogleAssistant.GCPAuth; gcpAuthTokenFile != "" && (login == nil || login.OauthToken != nil) {
+		//An assistant still waiting for its login is kept, as the login can only be started once per process
+		googleAssistant, err := newGoogleAssistant(gcpAuthTokenFile, login)
+		if err != nil {
+			return []error{fmt.Errorf("error initializing Google Assistant: %v", err)}
+		}
+		if gcpAuthURL := googleAssistant.GetAuthURL(); gcpAuthURL != "" {
+			Warning.Println("Please open the following URL to authenticate with Google Cloud Platform:", gcpAuthURL)
+		}
+		newBotData.BotClients.GoogleAssistant = googleAssistant
+		replacedAssistant = true
+	}
+
+	//Build everything that depends on the configuration before it goes live, so nothing ever sees it half built
+	newBotData.initCommands()
+	newBotData.initQueryServices()
+	newBotData.initVoiceServices()
+
+	setBotData(newBotData)
+	configureLogging(newBotData.BotOptions.Logging)
+	scheduleGuildDataSweeper(newBotData.BotOptions.GuildData.SweepFrequency)
+	scheduleConfigWatcher(newBotData.BotOptions.ConfigWatchFrequency)
+	if replacedAssistant {
+		closeReplacedGoogleAssistant(oldBotData.BotClients.GoogleAssistant)
+	}
+
+	Info.Println("Reloaded the bot configuration")
+	return nil
+}
+
+// closeReplacedGoogleAssistant closes a Google Assistant client that a reload replaced
+// The login server is left running, as the new client took it over along with the login
+func closeReplacedGoogleAssistant(assistant gassist.Assistant) {
+	if assistant.GCPAuth != nil {
+		login := *assistant.GCPAuth
+		login.OauthSrv = nil
+		assistant.GCPAuth = &login
+	}
+	assistant.Close()
+}
+
+// scheduleConfigWatcher starts watching the configuration file every frequency seconds, replacing the previous watcher if the frequency
+// changed, or stops watching it if the frequency is 0
+func scheduleConfigWatcher(frequency int) {
+	configWatcherLock.Lock()
+	defer configWatcherLock.Unlock()
+
+	if frequency == configWatchFrequency {
+		return
+	}
+	if configWatcherStop != nil {
+		close(configWatcherStop)
+		configWatcherStop = nil
+	}
+
+	configWatchFrequency = frequency
+	if frequency > 0 {
+		configWatcherStop = make(chan bool)
+		go watchConfig(time.Duration(frequency)*time.Second, configWatcherStop)
+	}
+}
+
+// watchConfig reloads the configuration whenever the configuration file is modified, until stop is closed
+func watchConfig(frequency time.Duration, stop chan bool) {
+	lastModified := time.Time{}
+	if configFileInfo, err := os.Stat(configFile); err == nil {
+		lastModified = configFileInfo.ModTime()
+	}
+
+	ticker := time.NewTicker(frequency)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		configFileInfo, err := os.Stat(configFile)
+		if err != nil || !configFileInfo.ModTime().After(lastModified) {
+			continue
+		}
+		lastModified = configFileInfo.ModTime()
+
+		Info.Println("Configuration file was modified, reloading...")
+		logReloadErrors(reloadBotData())
+	}
+}
+
+func logReloadErrors(errs []error) {
+	for _, err := range errs {
+		Error.Printf("Error reloading the bot configuration: %v", err)
+	}
+}
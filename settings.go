@@ -0,0 +1,339 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"4d63.com/tz"
+)
+
+/*
+	Guild and user settings can be changed through the API one field at a time, addressed by the path of JSON names leading to it (ex: logSettings.loggingChannel).
+	Only the settings listed in the rules below can be changed, and each new value is checked by the same validators the chat commands use.
+*/
+
+// SettingRule describes a setting that can be changed through the API
+type SettingRule struct {
+	Path     string                                   //The path of the setting, covering every setting within it if it holds more settings
+	Validate func(id string, value interface{}) error //Validates a new value for the setting given the ID of the guild or user it belongs to, nil if any value of the right type is allowed
+	Format   string                                   //What the value refers to for dashboards to render it with, such as channel or role
+}
+
+// SettingError holds why a change to a setting was rejected
+type SettingError struct {
+	Field string `json:"field"`
+	Code  string `json:"code"`
+	Error string `json:"error"`
+}
+
+var (
+	guildSettingRules = []*SettingRule{
+		{Path: "allowVoice"},
+		{Path: "adminRoles", Validate: validateSettingRoles, Format: "role"},
+		{Path: "adminUsers", Validate: validateSettingIDs, Format: "user"},
+		{Path: "botFeatures"},
+		{Path: "botPrefix", Validate: validateSettingPrefix, Format: "prefix"},
+		{Path: "logSettings.loggingEnabled"},
+		{Path: "logSettings.loggingChannel", Validate: validateSettingChannel, Format: "channel"},
+		{Path: "logSettings.loggingEvents"},
+		{Path: "swearFilter.Enabled"},
+		{Path: "swearFilter.DisableNormalize"},
+		{Path: "swearFilter.DisableSpacedTab"},
+		{Path: "swearFilter.DisableMultiWhitespaceStripping"},
+		{Path: "swearFilter.DisableZeroWidthStripping"},
+		{Path: "swearFilter.DisableSpacedBypass"},
+		{Path: "swearFilter.WarningDeleteTimeout", Validate: validateSettingNotNegative, Format: "seconds"},
+		{Path: "swearFilter.AllowAdminBypass"},
+		{Path: "swearFilter.AllowBotOwnerBypass"},
+		{Path: "swearFilter.BlacklistedWords", Validate: func(id string, value interface{}) error { return validateFilterWords(value.([]string)) }},
+		{Path: "tipsChannel", Validate: validateSettingChannel, Format: "channel"},
+		{Path: "userJoinMessage"},
+		{Path: "userJoinMessageChannel", Validate: validateSettingChannel, Format: "channel"},
+		{Path: "userLeaveMessage"},
+		{Path: "userLeaveMessageChannel", Validate: validateSettingChannel, Format: "channel"},
+		{Path: "disableNowPlaying"},
+		{Path: "apiInviteChannel", Validate: validateSettingChannel, Format: "channel"},
+		{Path: "apiInviteKey", Format: "secret"},
+	}
+
+	userSettingRules = []*SettingRule{
+		{Path: "description"},
+		{Path: "timezone", Validate: func(id string, value interface{}) error {
+			if value.(string) == "" {
+				return nil
+			}
+			_, err := loadTimezone(value.(string))
+			return err
+		}, Format: "timezone"},
+		{Path: "socials.switchFC", Validate: func(id string, value interface{}) error {
+			if value.(string) == "" {
+				return nil
+			}
+			return validateSwitchFC(value.(string))
+		}, Format: "switchFC"},
+		{Path: "socials.nintyID", Validate: func(id string, value interface{}) error {
+			if value.(string) == "" {
+				return nil
+			}
+			return validateNNID(value.(string))
+		}, Format: "nnid"},
+		{Path: "socials.psn"},
+		{Path: "socials.xbox"},
+	}
+)
+
+// loadTimezone loads a timezone by its name in the tz database, ex: America/New_York
+func loadTimezone(name string) (*time.Location, error) {
+	location, err := tz.LoadLocation(name)
+	if err != nil {
+		return nil, wrapError(errCodeSettingTimezone, err, name)
+	}
+	return location, nil
+}
+
+// validateSwitchFC checks that a Nintendo Switch friend code is valid
+func validateSwitchFC(code string) error {
+	if !regexpSwitchFC.MatchString(code) {
+		return newError(errCodeSettingSwitchFC)
+	}
+	return nil
+}
+
+// validateNNID checks that a Nintendo Network ID exists
+func validateNNID(nnid string) error {
+	if botData().BotClients.Ninty == nil {
+		return newError(errCodeSettingNNIDUnchecked)
+	}
+	exists, _, err := botData().BotClients.Ninty.DoesUserExist(nnid)
+	if err != nil {
+		return wrapError(errCodeSettingNNIDUnchecked, err)
+	}
+	if !exists {
+		return newError(errCodeSettingNNIDUnknown)
+	}
+	return nil
+}
+
+// validateFilterWords checks that every word in a swear filter is usable and only listed once
+func validateFilterWords(words []string) error {
+	seen := make(map[string]bool)
+	for _, word := range words {
+		if strings.TrimSpace(word) == "" {
+			return newError(errCodeSettingFilterWordEmpty)
+		}
+		if seen[strings.ToLower(word)] {
+			return newError(errCodeSettingFilterWordDuplicate, word)
+		}
+		seen[strings.ToLower(word)] = true
+	}
+	return nil
+}
+
+func validateSettingPrefix(id string, value interface{}) error {
+	if strings.ContainsAny(value.(string), " \t\n") {
+		return newError(errCodeSettingPrefix)
+	}
+	return nil
+}
+
+func validateSettingNotNegative(id string, value interface{}) error {
+	if reflect.ValueOf(value).Int() < 0 {
+		return newError(errCodeSettingNegative)
+	}
+	return nil
+}
+
+func validateSettingIDs(id string, value interface{}) error {
+	for _, snowflake := range value.([]string) {
+		if !isSnowflake(snowflake) {
+			return newError(errCodeSettingID, snowflake)
+		}
+	}
+	return nil
+}
+
+// validateSettingChannel checks that a channel belongs to the guild, allowing no channel at all
+func validateSettingChannel(guildID string, value interface{}) error {
+	channelID := value.(string)
+	if channelID == "" {
+		return nil
+	}
+	if !isSnowflake(channelID) {
+		return newError(errCodeSettingID, channelID)
+	}
+	if channel, err := botData().DiscordSession.State.Channel(channelID); err != nil || channel.GuildID != guildID {
+		return newError(errCodeSettingChannel, channelID)
+	}
+	return nil
+}
+
+// validateSettingRoles checks that every role belongs to the guild
+func validateSettingRoles(guildID string, value interface{}) error {
+	for _, roleID := range value.([]string) {
+		if !isSnowflake(roleID) {
+			return newError(errCodeSettingID, roleID)
+		}
+		if _, err := botData().DiscordSession.State.Role(guildID, roleID); err != nil {
+			return newError(errCodeSettingRole, roleID)
+		}
+	}
+	return nil
+}
+
+// isSnowflake returns whether or not a string looks like a Discord ID
+func isSnowflake(id string) bool {
+	if id == "" || len(id) > 20 {
+		return false
+	}
+	for _, char := range id {
+		if char < '0' || char > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// settingRule returns the rule covering the setting at the given path, or nil if it can't be changed
+func settingRule(rules []*SettingRule, path string) *SettingRule {
+	for _, rule := range rules {
+		if strings.EqualFold(rule.Path, path) || strings.HasPrefix(strings.ToLower(path), strings.ToLower(rule.Path)+".") {
+			return rule
+		}
+	}
+	return nil
+}
+
+// settingField finds the field of a struct by its JSON name, or by its field name if it has none
+func settingField(value reflect.Value, name string) (reflect.Value, string, bool) {
+	for i := 0; i < value.NumField(); i++ {
+		jsonName := settingJSONName(value.Type().Field(i))
+		if jsonName != "" && strings.EqualFold(jsonName, name) {
+			return value.Field(i), jsonName, true
+		}
+	}
+	return reflect.Value{}, "", false
+}
+
+// settingJSONName returns the name of a field in JSON, or nothing if it's left out of JSON
+func settingJSONName(field reflect.StructField) string {
+	if field.PkgPath != "" {
+		return "" //Unexported
+	}
+	jsonName := strings.Split(field.Tag.Get("json"), ",")[0]
+	if jsonName == "-" {
+		return ""
+	}
+	if jsonName == "" {
+		return field.Name
+	}
+	return jsonName
+}
+
+// isSettingGroup returns whether or not a setting holds more settings rather than a value
+func isSettingGroup(value reflect.Value) bool {
+	return value.Kind() == reflect.Struct && value.Type() != reflect.TypeOf(time.Time{})
+}
+
+// applySetting sets the setting at the given path within settings to a JSON value, validated by the given rules
+// A setting holding more settings is given a JSON object with new values for any of them, and each is applied in turn
+// Every rejected change is returned, and settings should be discarded if any were
+func applySetting(settings interface{}, rules []*SettingRule, id, path string, rawValue json.RawMessage) []*SettingError {
+	value := reflect.ValueOf(settings).Elem()
+	resolvedPath := make([]string, 0)
+	for _, name := range strings.Split(path, ".") {
+		if !isSettingGroup(value) {
+			return []*SettingError{settingError(path, newError(errCodeSettingUnknown))}
+		}
+		field, jsonName, exists := settingField(value, name)
+		if !exists {
+			return []*SettingError{settingError(path, newError(errCodeSettingUnknown))}
+		}
+		value = field
+		resolvedPath = append(resolvedPath, jsonName)
+	}
+	path = strings.Join(resolvedPath, ".")
+
+	if isSettingGroup(value) {
+		changes := make(map[string]json.RawMessage)
+		if err := json.Unmarshal(rawValue, &changes); err != nil {
+			return []*SettingError{settingError(path, newError(errCodeSettingType, "an object"))}
+		}
+		errs := make([]*SettingError, 0)
+		for _, name := range sortedSettingNames(changes) {
+			errs = append(errs, applySetting(settings, rules, id, path+"."+name, changes[name])...)
+		}
+		return errs
+	}
+
+	rule := settingRule(rules, path)
+	if rule == nil {
+		return []*SettingError{settingError(path, newError(errCodeSettingReadOnly))}
+	}
+
+	newValue := reflect.New(value.Type())
+	decoder := json.NewDecoder(bytes.NewReader(rawValue))
+	if err := decoder.Decode(newValue.Interface()); err != nil {
+		return []*SettingError{settingError(path, newError(errCodeSettingType, settingTypeName(value.Type())))}
+	}
+	if rule.Validate != nil && strings.EqualFold(rule.Path, path) {
+		if err := rule.Validate(id, newValue.Elem().Interface()); err != nil {
+			return []*SettingError{settingError(path, err)}
+		}
+	}
+	value.Set(newValue.Elem())
+	return nil
+}
+
+// applySettings applies every change in a JSON object of settings, as with a setting that holds more settings
+func applySettings(settings interface{}, rules []*SettingRule, id string, rawChanges json.RawMessage) []*SettingError {
+	changes := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(rawChanges, &changes); err != nil {
+		return []*SettingError{settingError("", newError(errCodeSettingType, "an object"))}
+	}
+	errs := make([]*SettingError, 0)
+	for _, name := range sortedSettingNames(changes) {
+		errs = append(errs, applySetting(settings, rules, id, name, changes[name])...)
+	}
+	return errs
+}
+
+// sortedSettingNames returns the names of the changed settings in order, so rejected changes are always listed in the same order
+func sortedSettingNames(changes map[string]json.RawMessage) []string {
+	names := make([]string, 0, len(changes))
+	for name := range changes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func settingError(path string, err error) *SettingError {
+	clinetErr := asClinetError(err)
+	return &SettingError{Field: path, Code: clinetErr.Code, Error: clinetErr.Message("en")}
+}
+
+// settingTypeName describes the type of a setting in the words of JSON
+func settingTypeName(valueType reflect.Type) string {
+	switch valueType.Kind() {
+	case reflect.Ptr:
+		return settingTypeName(valueType.Elem()) + " or null"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.String:
+		return "a string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "a list of " + strings.TrimPrefix(strings.TrimPrefix(settingTypeName(valueType.Elem()), "a "), "an ") + "s"
+	}
+	if valueType == reflect.TypeOf(time.Time{}) {
+		return "a timestamp"
+	}
+	return "an object"
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var (
+	//How long to wait between spawning each shard, as Discord only allows one shard to identify every 5 seconds
+	shardIdentifyDelay = 6 * time.Second
+)
+
+// ShardStats holds the statistics of a single shard, shared with the other shards over IPC
+type ShardStats struct {
+	ShardID       int           `json:"shardID"`
+	Guilds        int           `json:"guilds"`
+	VoiceSessions int           `json:"voiceSessions"`
+	Latency       time.Duration `json:"latency"`
+	Started       time.Time     `json:"started"`
+	Restarts      int           `json:"restarts"`
+	Unreachable   bool          `json:"unreachable,omitempty"` //Whether or not the shard failed to respond
+}
+
+// resolveShardCount returns the configured shard count, or the shard count recommended by Discord if it's set to 0
+func resolveShardCount() int {
+	configData, err := loadConfigLayers(configFile)
+	if err != nil {
+		Error.Printf("Error loading the configuration to resolve the shard count: %v", err)
+		return 1
+	}
+	if configData.BotOptions.Sharding.ShardCount > 0 {
+		return configData.BotOptions.Sharding.ShardCount
+	}
+
+	session, err := discordgo.New("Bot " + configData.BotToken)
+	if err != nil {
+		Error.Printf("Error fetching the recommended shard count: %v", err)
+		return 1
+	}
+	gateway, err := session.GatewayBot()
+	if err != nil || gateway.Shards <= 0 {
+		Error.Printf("Error fetching the recommended shard count, defaulting to 1: %v", err)
+		return 1
+	}
+	return gateway.Shards
+}
+
+// shardForGuild returns the ID of the shard that Discord sends a guild's events to
+func shardForGuild(guildID string) int {
+	if shardCount <= 1 {
+		return 0
+	}
+	id, err := strconv.ParseUint(guildID, 10, 64)
+	if err != nil {
+		return 0 //Direct messages and anything without a guild belong to the first shard
+	}
+	return int((id >> 22) % uint64(shardCount))
+}
+
+// ownsGuild returns whether or not this shard is responsible for a guild
+func ownsGuild(guildID string) bool {
+	return shardForGuild(guildID) == shardID
+}
+
+// stateDir returns the directory this shard saves its state to
+func stateDir() string {
+	if shardCount <= 1 {
+		return "state"
+	}
+	return filepath.Join("state", fmt.Sprintf("shard-%d-of-%d", shardID, shardCount))
+}
+
+// stateSourceDirs returns the directories this shard should load its state from
+// A shard loads its own directory, unless it doesn't exist yet or the bot was since run with a different shard count,
+// in which case it migrates the guilds it owns from whichever layout was saved most recently
+func stateSourceDirs() []string {
+	layouts := make(map[int][]string) //Where key = shard count and value = the state directories saved with that shard count
+	if stateModified("state").After(time.Time{}) {
+		layouts[1] = []string{"state"}
+	}
+	shardDirs, _ := filepath.Glob(filepath.Join("state", "shard-*-of-*"))
+	for _, dir := range shardDirs {
+		var dirShardID, dirShardCount int
+		if _, err := fmt.Sscanf(filepath.Base(dir), "shard-%d-of-%d", &dirShardID, &dirShardCount); err != nil || dirShardCount <= 1 {
+			continue
+		}
+		layouts[dirShardCount] = append(layouts[dirShardCount], dir)
+	}
+
+	ownModified := stateModified(stateDir())
+	newestCount := 0
+	newestModified := time.Time{}
+	for count, dirs := range layouts {
+		if count == shardCount {
+			continue
+		}
+		for _, dir := range dirs {
+			if modified := stateModified(dir); modified.After(newestModified) {
+				newestCount = count
+				newestModified = modified
+			}
+		}
+	}
+
+	if !ownModified.IsZero() && !newestModified.After(ownModified) {
+		return []string{stateDir()}
+	}
+	if newestCount == 0 {
+		return nil //Nothing has been saved yet
+	}
+	Info.Printf("Migrating state from the layout for %d shard(s)", newestCount)
+	return layouts[newestCount]
+}
+
+// stateModified returns when the state files in a directory were last saved, or the zero time if there are none
+func stateModified(dir string) time.Time {
+	modified := time.Time{}
+	stateFiles, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	for _, stateFile := range stateFiles {
+		if stateFileInfo, err := os.Stat(stateFile); err == nil && stateFileInfo.ModTime().After(modified) {
+			modified = stateFileInfo.ModTime()
+		}
+	}
+	return modified
+}
+
+// dropUnownedState removes the state of every guild that this shard isn't responsible for, such as after migrating from another layout
+func dropUnownedState() {
+	if shardCount <= 1 {
+		return
+	}
+
+	for guildID := range guildData {
+		if !ownsGuild(guildID) {
+			delete(guildData, guildID)
+		}
+	}
+	for guildID := range guildSettings {
+		if !ownsGuild(guildID) {
+			delete(guildSettings, guildID)
+		}
+	}
+	for guildID := range starboards {
+		if !ownsGuild(guildID) {
+			delete(starboards, guildID)
+		}
+	}
+	for guildID := range voiceData {
+		if !ownsGuild(guildID) {
+			delete(voiceData, guildID)
+		}
+	}
+
+	remindEntriesLock.Lock()
+	defer remindEntriesLock.Unlock()
+
+	ownedRemindEntries := make([]RemindEntry, 0)
+	for _, remindEntry := range remindEntries {
+		if ownsGuild(remindEntry.GuildID) {
+			ownedRemindEntries = append(ownedRemindEntries, remindEntry)
+		}
+	}
+	remindEntries = ownedRemindEntries
+}
+
+// localShardStats returns the statistics of this shard
+func localShardStats() *ShardStats {
+	stats := &ShardStats{
+		ShardID:  shardID,
+		Started:  uptime,
+		Restarts: restarts,
+	}
+	if botData().DiscordSession != nil {
+		stats.Guilds = len(botData().DiscordSession.State.Guilds)
+		stats.Latency = botData().DiscordSession.HeartbeatLatency()
+	}
+	for _, voice := range voiceData {
+		if voice.IsConnected() {
+			stats.VoiceSessions++
+		}
+	}
+	return stats
+}
+
+// allShardStats returns the statistics of every shard, in order of shard ID
+func allShardStats() []*ShardStats {
+	stats := make([]*ShardStats, shardCount)
+	stats[shardID] = localShardStats()
+	if shardCount <= 1 {
+		return stats
+	}
+
+	shardAddrs, err := ipcShardAddrs()
+	if err != nil {
+		Error.Printf("Error finding the other shards: %v", err)
+	}
+	for i := range stats {
+		if i == shardID {
+			continue
+		}
+		stats[i] = &ShardStats{ShardID: i, Unreachable: true}
+		if addr, exists := shardAddrs[i]; exists {
+			shardStats := &ShardStats{}
+			if err := ipcRequest("GET", "http://"+addr+"/stats", nil, shardStats); err != nil {
+				Error.Printf("Error fetching the statistics of shard %d: %v", i, err)
+				continue
+			}
+			stats[i] = shardStats
+		}
+	}
+	return stats
+}
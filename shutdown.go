@@ -0,0 +1,211 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+var (
+	//How old a resume manifest can be before its voice sessions are considered abandoned
+	resumeManifestMaxAge = 10 * time.Minute
+)
+
+// ResumeManifest holds the voice sessions that were interrupted by a shutdown, so they can be resumed on the next startup
+type ResumeManifest struct {
+	Created  time.Time                 `json:"created"`  //When the manifest was written
+	Reason   string                    `json:"reason"`   //Why the bot was shut down
+	Sessions map[string]*ResumeSession `json:"sessions"` //The interrupted voice sessions, where key = guild ID
+}
+
+// ResumeSession holds everything needed to resume a guild's voice session
+type ResumeSession struct {
+	VoiceChannelID string        `json:"voiceChannelID"` //The voice channel to rejoin
+	TextChannelID  string        `json:"textChannelID"`  //The text channel that was last used to interact with the voice session
+	NowPlaying     *QueueEntry   `json:"nowPlaying"`     //The queue entry that was playing
+	Position       time.Duration `json:"position"`       //How far into the now playing entry playback was
+	Queue          []*QueueEntry `json:"queue"`          //The queue entries that were waiting to be played
+	RepeatLevel    RepeatLevel   `json:"repeatLevel"`
+	Shuffle        bool          `json:"shuffle"`
+}
+
+// restartsRightAway returns whether or not the bot is respawned as soon as it shuts down for the given reason
+// Other shutdowns, such as a signal from systemd or Docker, may still be followed by a restart, which resumes playback if it's soon enough
+func restartsRightAway(reason string) bool {
+	switch reason {
+	case "restart", "update", "rollback":
+		return true
+	}
+	return false
+}
+
+// newResumeManifest records every voice session that is currently playing
+func newResumeManifest(reason string) *ResumeManifest {
+	manifest := &ResumeManifest{
+		Created:  time.Now(),
+		Reason:   reason,
+		Sessions: make(map[string]*ResumeSession),
+	}
+
+	for guildID, voice := range voiceData {
+		if !voice.IsStreaming() {
+			continue
+		}
+
+		voice.Lock()
+		if voice.NowPlaying != nil {
+			manifest.Sessions[guildID] = &ResumeSession{
+				VoiceChannelID: voice.VoiceConnection.ChannelID,
+				TextChannelID:  voice.TextChannelID,
+				NowPlaying:     voice.NowPlaying.Entry,
+				Position:       voice.NowPlaying.Position,
+				Queue:          append([]*QueueEntry{}, voice.Entries...),
+				RepeatLevel:    voice.RepeatLevel,
+				Shuffle:        voice.Shuffle,
+			}
+		}
+		voice.Unlock()
+	}
+
+	return manifest
+}
+
+// shutdownBot saves the state and resume manifest, leaves all voice channels and disconnects from Discord
+// If this takes longer than the configured shutdown timeout, it gives up so the process can exit anyway
+func shutdownBot(reason string) {
+	timeout := time.Duration(botData().BotOptions.ShutdownTimeout) * time.Second
+	Info.Printf("Shutting down for %s, waiting up to %s...", reason, timeout)
+
+	done := make(chan bool, 1)
+	go func() {
+		defer recoverPanic()
+
+		//Record the voice sessions first, as leaving the voice channels clears them
+		manifest := newResumeManifest(reason)
+
+		//Save the current state before shutting down
+		// Note: This is done before shutting down as the shutdown process may yield
+		//       some errors with goroutines like voice playback
+		stateSaveAll()
+		if len(manifest.Sessions) > 0 {
+			if err := stateSaveRaw(manifest, filepath.Join(stateDir(), "resume.json")); err != nil {
+				Error.Printf("Error saving resume manifest: %s\n", err)
+			}
+		}
+
+		botData().BotClients.GoogleAssistant.Close()
+
+		leaveVoiceChannels(reason)
+
+		if botData().DiscordSession != nil {
+			Info.Println("Disconnecting from Discord...")
+			botData().DiscordSession.Close()
+		}
+
+		done <- true
+	}()
+
+	select {
+	case <-done:
+		Info.Println("Shut down cleanly")
+	case <-time.After(timeout):
+		Error.Printf("Shutdown took longer than %s, exiting anyway", timeout)
+	}
+}
+
+// leaveVoiceChannels stops playback and leaves every voice channel, telling listeners their playback will resume
+func leaveVoiceChannels(reason string) {
+	for _, voiceIDRow := range voiceData {
+		if voiceIDRow.IsConnected() {
+			if voiceIDRow.IsStreaming() {
+				//Notify users that playback is being interrupted
+				interrupted := "Your audio playback has been interrupted for a " + botData().BotName + " " + reason + "."
+				if restartsRightAway(reason) {
+					interrupted += " It will resume automatically in a few seconds."
+				} else {
+					interrupted += " It will resume automatically if " + botData().BotName + " is back within " + strconv.Itoa(int(resumeManifestMaxAge.Minutes())) + " minutes."
+				}
+				botData().DiscordSession.ChannelMessageSendEmbed(voiceIDRow.TextChannelID, NewEmbed().SetTitle("Voice").SetDescription(interrupted).SetColor(0x1C1C1C).MessageEmbed)
+
+				debugLog("> Stopping stream in voice channel "+voiceIDRow.VoiceConnection.ChannelID+"...", false)
+				voiceIDRow.Stop()
+			}
+			debugLog("> Closing connection to voice channel "+voiceIDRow.VoiceConnection.ChannelID+"...", false)
+			voiceIDRow.VoiceConnection.Close()
+		}
+	}
+}
+
+// resumeVoiceSessions rejoins and resumes playback for every voice session in the resume manifests that were loaded with the state
+func resumeVoiceSessions() {
+	defer recoverHandler("resumeVoiceSessions", "")
+
+	for _, dir := range stateSources {
+		resumeManifestFile := filepath.Join(dir, "resume.json")
+		manifest := &ResumeManifest{}
+		if err := stateRestoreRaw(resumeManifestFile, manifest); err != nil {
+			if !os.IsNotExist(err) {
+				Error.Printf("Error loading resume manifest: %s\n", err)
+			}
+			continue
+		}
+		if dir == stateDir() {
+			os.Remove(resumeManifestFile) //Manifests migrated from another shard layout are left for the other shards to read
+		}
+
+		if time.Since(manifest.Created) > resumeManifestMaxAge {
+			WarningVoice.Printf("Resume manifest from %s is too old, not resuming %d voice sessions", manifest.Created, len(manifest.Sessions))
+			continue
+		}
+		resumeManifest(manifest)
+	}
+}
+
+func resumeManifest(manifest *ResumeManifest) {
+	for guildID, session := range manifest.Sessions {
+		if !ownsGuild(guildID) {
+			continue
+		}
+		if err := resumeVoiceSession(guildID, session); err != nil {
+			ErrorVoice.With("guild", guildID).Printf("Error resuming voice session: %v", err)
+			if session.TextChannelID != "" {
+				botData().DiscordSession.ChannelMessageSendEmbed(session.TextChannelID, getErrorEmbed(wrapError(errCodeVoiceResumeFailed, err), guildLocale(guildID)))
+			}
+		}
+	}
+}
+
+func resumeVoiceSession(guildID string, session *ResumeSession) error {
+	if session.NowPlaying == nil || session.NowPlaying.Metadata == nil {
+		return errors.New("no now playing entry")
+	}
+
+	VoiceInit(guildID)
+	voice := voiceData[guildID]
+	voice.TextChannelID = session.TextChannelID
+	voice.Entries = session.Queue
+	voice.RepeatLevel = session.RepeatLevel
+	voice.Shuffle = session.Shuffle
+
+	//Stream URLs are short-lived, so fetch a fresh one for the now playing entry
+	queueEntry, err := createQueueEntry(session.NowPlaying.Metadata.DisplayURL)
+	if err != nil {
+		return err
+	}
+	queueEntry.Requester = session.NowPlaying.Requester
+
+	if err := voice.Connect(guildID, session.VoiceChannelID); err != nil {
+		return err
+	}
+
+	InfoVoice.With("guild", guildID, "channel", session.VoiceChannelID).Printf("Resuming voice session at %s", session.Position)
+	voice.resumePosition = session.Position
+	go func() {
+		if err := voice.Play(queueEntry, false); err != nil {
+			ErrorVoice.With("guild", guildID).Printf("Error resuming playback: %v", err)
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,346 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var (
+	//How long to wait before respawning a crashed bot, doubling after each crash up to the maximum
+	supervisorMinBackoff = 1 * time.Second
+	supervisorMaxBackoff = 5 * time.Minute
+
+	//How long the bot must run before a crash is no longer considered part of a crash loop
+	supervisorStableAfter = 5 * time.Minute
+
+	//How many crashes within the window are considered a crash loop, and how long to stop respawning for once one is detected
+	crashLoopThreshold = 5
+	crashLoopWindow    = 10 * time.Minute
+	crashLoopCooldown  = 30 * time.Minute
+
+	//How often the bot sends a heartbeat, and how long the supervisor waits for one before considering the bot wedged
+	heartbeatInterval = 15 * time.Second
+	heartbeatTimeout  = 90 * time.Second
+
+	//How long since the last Discord heartbeat acknowledgement before the bot stops sending heartbeats of its own
+	heartbeatStaleAfter = 3 * time.Minute
+)
+
+// BotProcess holds a bot process spawned by the supervisor
+type BotProcess struct {
+	Cmd        *exec.Cmd
+	Heartbeats chan bool //Receives each heartbeat from the bot, nil if heartbeats aren't supported on this platform
+}
+
+// heartbeatsSupported returns whether or not heartbeats can be passed between processes on this platform
+func heartbeatsSupported() bool {
+	return runtime.GOOS != "windows" //Extra file descriptors can't be inherited on Windows
+}
+
+// ShardSupervisor keeps the bot process for a single shard running
+type ShardSupervisor struct {
+	sync.Mutex
+
+	ShardID    int
+	ShardCount int
+	Restarts   int //How many times the bot process for this shard has been respawned
+
+	bot    *BotProcess   //The current bot process, nil while waiting to respawn
+	exited chan bool     //Closed when the current bot process exits
+	hub    *IPCHub       //The IPC hub for the bot processes to find each other, nil if not sharded
+	stop   chan struct{} //Closed to stop respawning the bot process
+}
+
+// superviseBot spawns a bot process for every shard and keeps them running, forwarding signals to all of them
+func superviseBot() {
+	sc := make(chan os.Signal, 1)
+	signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+
+	if killOldBot {
+		killOldBots()
+	}
+
+	//The bot process reports configuration problems itself, so the defaults are kept until they're fixed
+	if configData, err := loadConfigLayers(configFile); err == nil && len(configData.PrepConfig().Errors) == 0 {
+		configureLogging(configData.BotOptions.Logging)
+	}
+
+	shardCount := resolveShardCount()
+	Info.Printf("Running %d shard(s)", shardCount)
+
+	var hub *IPCHub
+	if shardCount > 1 {
+		var err error
+		hub, err = startIPCHub()
+		if err != nil {
+			Error.Printf("Error starting the IPC hub: %v", err)
+			os.Exit(1)
+		}
+	}
+
+	stop := make(chan struct{})
+	shards := make([]*ShardSupervisor, shardCount)
+	for i := range shards {
+		shards[i] = &ShardSupervisor{ShardID: i, ShardCount: shardCount, hub: hub, stop: stop}
+		go shards[i].supervise()
+
+		//Discord only allows one shard to identify every 5 seconds
+		if i < shardCount-1 {
+			time.Sleep(shardIdentifyDelay)
+		}
+	}
+
+	for sig := range sc {
+		if sig == syscall.SIGHUP {
+			for _, shard := range shards {
+				shard.signal(sig) //Each bot process reloads its configuration and keeps running
+			}
+			continue
+		}
+		break
+	}
+
+	//Stop respawning and give every bot process a little longer than its own shutdown timeout before killing it
+	close(stop)
+	for _, shard := range shards {
+		shard.signal(syscall.SIGTERM)
+	}
+	deadline := time.Now().Add(botShutdownTimeout() + 5*time.Second)
+	for _, shard := range shards {
+		shard.Lock()
+		exited := shard.exited
+		shard.Unlock()
+		if exited == nil {
+			continue
+		}
+
+		select {
+		case <-exited:
+		case <-time.After(time.Until(deadline)):
+			Error.Printf("Bot for shard %d didn't shut down in time and was killed", shard.ShardID)
+			shard.kill()
+		}
+	}
+	os.Exit(0)
+}
+
+// supervise spawns the bot process for this shard and keeps it running, backing off between crashes and killing it if it stops sending heartbeats
+func (shard *ShardSupervisor) supervise() {
+	backoff := supervisorMinBackoff
+	crashes := make([]time.Time, 0)
+
+	for {
+		if shard.stopped() {
+			return
+		}
+
+		bot, err := spawnBot(shard, shard.hub)
+		if err != nil {
+			Error.Printf("Error spawning the bot for shard %d: %v", shard.ShardID, err)
+		} else {
+			shard.Lock()
+			shard.bot = bot
+			shard.exited = make(chan bool)
+			shard.Unlock()
+
+			started := time.Now()
+			exitErr, wedged := superviseBotProcess(bot)
+
+			shard.Lock()
+			shard.bot = nil
+			close(shard.exited)
+			shard.Unlock()
+
+			if shard.stopped() {
+				return
+			}
+
+			if exitErr == nil && !wedged {
+				//The bot exited on purpose, such as for a restart or update
+				Info.Printf("Bot for shard %d exited cleanly, respawning...", shard.ShardID)
+				shard.Restarts++
+				backoff = supervisorMinBackoff
+				continue
+			}
+
+			if wedged {
+				Error.Printf("Bot for shard %d stopped sending heartbeats and was killed", shard.ShardID)
+			} else {
+				Error.Printf("Bot for shard %d crashed: %v", shard.ShardID, exitErr)
+			}
+			if time.Since(started) > supervisorStableAfter {
+				backoff = supervisorMinBackoff
+			}
+		}
+
+		now := time.Now()
+		recentCrashes := make([]time.Time, 0)
+		for _, crash := range append(crashes, now) {
+			if now.Sub(crash) < crashLoopWindow {
+				recentCrashes = append(recentCrashes, crash)
+			}
+		}
+		crashes = recentCrashes
+
+		if checkAutoRollback(crashes) {
+			Info.Println("Restarting with the rolled back build...")
+			if err := spawnMaster(); err != nil {
+				Error.Printf("Error spawning the rolled back master process: %v", err)
+			} else {
+				os.Exit(0)
+			}
+		}
+
+		if len(crashes) >= crashLoopThreshold {
+			Error.Printf("Bot for shard %d crashed %d times within %s, waiting %s before trying again", shard.ShardID, len(crashes), crashLoopWindow, crashLoopCooldown)
+			notifyOwnerFromSupervisor("Clinet (shard " + strconv.Itoa(shard.ShardID) + ") crashed " + strconv.Itoa(len(crashes)) + " times within " + crashLoopWindow.String() + " and won't be respawned for " + crashLoopCooldown.String() + ". Check clinet.bot.log for details.")
+			shard.wait(crashLoopCooldown)
+			crashes = make([]time.Time, 0)
+			backoff = supervisorMinBackoff
+		} else {
+			Warning.Printf("Respawning the bot for shard %d in %s...", shard.ShardID, backoff)
+			shard.wait(backoff)
+			backoff *= 2
+			if backoff > supervisorMaxBackoff {
+				backoff = supervisorMaxBackoff
+			}
+		}
+		shard.Restarts++
+	}
+}
+
+// signal sends a signal to the current bot process for this shard, if there is one
+func (shard *ShardSupervisor) signal(sig os.Signal) {
+	shard.Lock()
+	defer shard.Unlock()
+
+	if shard.bot != nil {
+		_ = shard.bot.Cmd.Process.Signal(sig)
+	}
+}
+
+// kill kills the current bot process for this shard, if there is one
+func (shard *ShardSupervisor) kill() {
+	shard.Lock()
+	defer shard.Unlock()
+
+	if shard.bot != nil {
+		_ = shard.bot.Cmd.Process.Kill()
+	}
+}
+
+func (shard *ShardSupervisor) stopped() bool {
+	select {
+	case <-shard.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// wait waits for the given duration, returning early if respawning is stopped
+func (shard *ShardSupervisor) wait(duration time.Duration) {
+	select {
+	case <-shard.stop:
+	case <-time.After(duration):
+	}
+}
+
+// superviseBotProcess waits for a bot process to exit, killing it if it stops sending heartbeats
+func superviseBotProcess(bot *BotProcess) (exitErr error, wedged bool) {
+	exited := make(chan error, 1)
+	go func() { exited <- bot.Cmd.Wait() }()
+
+	var watchdog <-chan time.Time
+	var watchdogTimer *time.Timer
+	if bot.Heartbeats != nil {
+		watchdogTimer = time.NewTimer(heartbeatTimeout)
+		defer watchdogTimer.Stop()
+		watchdog = watchdogTimer.C
+	}
+
+	for {
+		select {
+		case <-bot.Heartbeats:
+			if !watchdogTimer.Stop() {
+				<-watchdogTimer.C
+			}
+			watchdogTimer.Reset(heartbeatTimeout)
+		case <-watchdog:
+			wedged = true
+			_ = bot.Cmd.Process.Kill()
+		case exitErr = <-exited:
+			return exitErr, wedged
+		}
+	}
+}
+
+// botShutdownTimeout returns how long the bot is configured to take to shut down cleanly
+func botShutdownTimeout() time.Duration {
+	configData, err := loadConfigLayers(configFile)
+	if err != nil || configData.BotOptions.ShutdownTimeout <= 0 {
+		return time.Duration(defaultBotData().BotOptions.ShutdownTimeout) * time.Second
+	}
+	return time.Duration(configData.BotOptions.ShutdownTimeout) * time.Second
+}
+
+// notifyOwnerFromSupervisor sends a direct message to the bot owner using a REST-only Discord session, as the bot itself isn't running
+func notifyOwnerFromSupervisor(message string) {
+	configData, err := loadConfigLayers(configFile)
+	if err != nil || configData.BotToken == "" || configData.BotOwnerID == "" {
+		Warning.Println("Unable to notify the bot owner, the configuration doesn't have a usable bot token and owner")
+		return
+	}
+
+	session, err := discordgo.New("Bot " + configData.BotToken)
+	if err != nil {
+		Error.Printf("Error notifying the bot owner: %v", err)
+		return
+	}
+	ownerPrivChannel, err := session.UserChannelCreate(configData.BotOwnerID)
+	if err != nil {
+		Error.Printf("Error notifying the bot owner: %v", err)
+		return
+	}
+	if _, err = session.ChannelMessageSend(ownerPrivChannel.ID, message); err != nil {
+		Error.Printf("Error notifying the bot owner: %v", err)
+	}
+}
+
+// readHeartbeats forwards each heartbeat byte read from the bot to the given channel until the bot closes its end
+func readHeartbeats(heartbeatReader *os.File, heartbeats chan bool) {
+	defer heartbeatReader.Close()
+
+	beat := make([]byte, 1)
+	for {
+		if _, err := heartbeatReader.Read(beat); err != nil {
+			return
+		}
+		select {
+		case heartbeats <- true:
+		default:
+		}
+	}
+}
+
+// sendHeartbeats writes a heartbeat to the supervisor on every interval for as long as the bot is healthy
+func sendHeartbeats(heartbeatWriter *os.File) {
+	for range time.Tick(heartbeatInterval) {
+		if report := livenessReport(); !report.OK() { //The same checks as /healthz on the API
+			Warning.Printf("Bot is unhealthy, withholding heartbeat from the supervisor: %s", strings.Join(report.Failing(), "; "))
+			continue
+		}
+		if _, err := heartbeatWriter.Write([]byte{1}); err != nil {
+			return
+		}
+	}
+}
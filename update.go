@@ -0,0 +1,305 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	updatePendingFile = ".update-pending" //Records an update that hasn't yet survived the rollback window
+	rollbackFile      = ".rollback"       //Holds the channel ID to report a rollback to after restarting
+)
+
+var (
+	//How long each smoke test of a new build may run for
+	verifyTimeout = 30 * time.Second
+
+	//How many crashes of a new build within the rollback window trigger an automatic rollback
+	rollbackCrashThreshold = 3
+
+	//Serializes automatic rollbacks so multiple shards crashing at once only roll back once
+	rollbackMutex sync.Mutex
+)
+
+// UpdateRecord holds an update that hasn't yet survived the rollback window
+type UpdateRecord struct {
+	Started   time.Time `json:"started"`   //When the updated build was swapped in
+	FromBuild string    `json:"fromBuild"` //The build ID that was updated from
+	ToCommit  string    `json:"toCommit"`  //The commit that was updated to
+	ChannelID string    `json:"channelID"` //The channel the update was requested from
+}
+
+// oldBinary returns the path of the nth previous binary, where 1 is the most recent
+func oldBinary(n int) string {
+	return os.Args[0] + ".old." + strconv.Itoa(n)
+}
+
+// verifyBinary smoke tests a new build against the current configuration and state before it's swapped in
+func verifyBinary(binary string) error {
+	for _, check := range [][]string{{"-checkconfig"}, {"-selftest"}} {
+		ctx, cancel := context.WithTimeout(context.Background(), verifyTimeout)
+		smokeTest := exec.CommandContext(ctx, binary, append(check, "-config", configFile)...)
+		output, err := smokeTest.CombinedOutput()
+		cancel()
+		if err != nil {
+			return fmt.Errorf("%s %s failed: %v\n%s", binary, check[0], err, output)
+		}
+	}
+	return nil
+}
+
+// swapBinary moves the current binary to the first previous binary slot, shifting the older ones down and dropping
+// any past the configured amount to keep, then moves the new build into place
+func swapBinary(newBinary string) error {
+	keep := botData().BotOptions.Updates.KeepBinaries
+
+	//Drop the binaries that fall off the end, including any left from keeping more before
+	for n := keep; ; n++ {
+		if _, err := os.Stat(oldBinary(n)); os.IsNotExist(err) {
+			break
+		}
+		os.Remove(oldBinary(n))
+	}
+	os.Remove(os.Args[0] + ".old") //Left behind by updates before binaries were kept
+
+	for n := keep - 1; n >= 1; n-- {
+		if _, err := os.Stat(oldBinary(n)); err == nil {
+			if err := os.Rename(oldBinary(n), oldBinary(n+1)); err != nil {
+				return err
+			}
+		}
+	}
+	if err := os.Rename(os.Args[0], oldBinary(1)); err != nil {
+		return err
+	}
+	if err := os.Rename(newBinary, os.Args[0]); err != nil {
+		os.Rename(oldBinary(1), os.Args[0]) //Put the current binary back so there's still something to run
+		return err
+	}
+	return nil
+}
+
+// rollbackBinary replaces the current binary with the most recent previous binary, shifting the older ones up
+// The replaced binary is kept with a .failed suffix for inspection
+func rollbackBinary() error {
+	if _, err := os.Stat(oldBinary(1)); os.IsNotExist(err) {
+		return errors.New("no previous binary to roll back to")
+	}
+
+	if err := os.Rename(os.Args[0], os.Args[0]+".failed"); err != nil {
+		return err
+	}
+	if err := os.Rename(oldBinary(1), os.Args[0]); err != nil {
+		os.Rename(os.Args[0]+".failed", os.Args[0])
+		return err
+	}
+	for n := 2; ; n++ {
+		if _, err := os.Stat(oldBinary(n)); os.IsNotExist(err) {
+			break
+		}
+		if err := os.Rename(oldBinary(n), oldBinary(n-1)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// masterArgs returns the arguments to start a new master process with the same configuration as this one
+func masterArgs() []string {
+	args := []string{"-config", configFile, "-debug=" + strconv.FormatBool(debug), "-gcptoken", gcpAuthTokenFile}
+	return append(args, configLayerArgs()...)
+}
+
+// spawnMaster starts a new master process that kills this one and every bot process along with it
+func spawnMaster() error {
+	masterProcess := exec.Command(os.Args[0], append(masterArgs(), "-killold")...)
+	masterProcess.Stdout = os.Stdout
+	masterProcess.Stderr = os.Stderr
+	return masterProcess.Start()
+}
+
+// readUpdateRecord returns the pending update, or nil if there isn't one
+func readUpdateRecord() *UpdateRecord {
+	updateRecordJSON, err := ioutil.ReadFile(updatePendingFile)
+	if err != nil {
+		return nil
+	}
+	updateRecord := &UpdateRecord{}
+	if err := json.Unmarshal(updateRecordJSON, updateRecord); err != nil {
+		Error.Printf("Error reading the pending update: %v", err)
+		return nil
+	}
+	return updateRecord
+}
+
+func writeUpdateRecord(updateRecord *UpdateRecord) error {
+	updateRecordJSON, err := json.Marshal(updateRecord)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(updatePendingFile, updateRecordJSON, 0644)
+}
+
+// confirmUpdate clears the pending update once the updated build has run for the rollback window without the master rolling it back
+func confirmUpdate() {
+	updateRecord := readUpdateRecord()
+	if updateRecord == nil {
+		return
+	}
+
+	time.Sleep(time.Until(updateRecord.Started.Add(rollbackWindow())))
+	if readUpdateRecord() != nil {
+		Info.Println("Update survived the rollback window, keeping it")
+		os.Remove(updatePendingFile)
+	}
+}
+
+// checkAutoRollback rolls back a pending update if the given crashes show the updated build crash looping within the rollback window
+// It returns whether or not a rollback happened, in which case the master process should be replaced
+func checkAutoRollback(crashes []time.Time) bool {
+	rollbackMutex.Lock()
+	defer rollbackMutex.Unlock()
+
+	updateRecord := readUpdateRecord()
+	if updateRecord == nil {
+		return false
+	}
+	if time.Since(updateRecord.Started) > rollbackWindow() {
+		os.Remove(updatePendingFile)
+		return false
+	}
+
+	updateCrashes := 0
+	for _, crash := range crashes {
+		if crash.After(updateRecord.Started) {
+			updateCrashes++
+		}
+	}
+	if updateCrashes < rollbackCrashThreshold {
+		return false
+	}
+
+	Error.Printf("Updated build crashed %d times within %s of the update, rolling back to %s", updateCrashes, rollbackWindow(), updateRecord.FromBuild)
+	if err := rollbackBinary(); err != nil {
+		Error.Printf("Error rolling back the update: %v", err)
+		notifyOwnerFromSupervisor("Clinet's update to commit " + updateRecord.ToCommit + " is crash looping, and rolling it back failed: " + err.Error())
+		os.Remove(updatePendingFile)
+		return false
+	}
+
+	os.Remove(updatePendingFile)
+	os.Remove(".update")
+	if updateRecord.ChannelID != "" {
+		ioutil.WriteFile(rollbackFile, []byte(updateRecord.ChannelID), 0644)
+	}
+	notifyOwnerFromSupervisor("Clinet's update to commit " + updateRecord.ToCommit + " crashed " + strconv.Itoa(updateCrashes) + " times and was rolled back to " + updateRecord.FromBuild + ".")
+	return true
+}
+
+// rollbackWindow returns how long after an update its crashes trigger an automatic rollback
+// The bot master never loads the configuration into botData, so it reads the configuration file instead
+func rollbackWindow() time.Duration {
+	if configLoaded {
+		return time.Duration(botData().BotOptions.Updates.RollbackWindow) * time.Second
+	}
+	configData, err := loadConfigLayers(configFile)
+	if err != nil {
+		return time.Duration(defaultBotData().BotOptions.Updates.RollbackWindow) * time.Second
+	}
+	return time.Duration(configData.BotOptions.Updates.RollbackWindow) * time.Second
+}
+
+func checkRollback() {
+	rollbackChannelID, err := ioutil.ReadFile(rollbackFile)
+	if err == nil && len(rollbackChannelID) > 0 {
+		DowntimeReason = "Rolled back to " + BuildID
+
+		Info.Println("Rollback succeeded!")
+		rollbackEmbed := getErrorEmbed(newError(errCodeUpdateRolledBack, botData().BotName, BuildID), channelLocale(string(rollbackChannelID)))
+		botData().DiscordSession.ChannelMessageSendEmbed(string(rollbackChannelID), rollbackEmbed)
+
+		os.Remove(rollbackFile)
+	}
+}
+
+// runSelfTest checks that this build can start with the current configuration and read the current state, returning the exit code to use
+func runSelfTest(file string) int {
+	configData, errs := loadBotData(file)
+	if len(errs) > 0 {
+		for _, err := range errs {
+			fmt.Printf("ERROR   %v\n", err)
+		}
+		fmt.Println("Self-test failed: the configuration has errors")
+		return 1
+	}
+	setBotData(configData)
+	configLoaded = true
+
+	failures := 0
+	botData().initCommands()
+	initNLPCommands()
+	botData().initQueryServices()
+	botData().initVoiceServices()
+	for name, command := range botData().Commands {
+		if command.IsAlternateOf != "" {
+			if _, exists := botData().Commands[command.IsAlternateOf]; !exists {
+				fmt.Printf("ERROR   command %s is an alternate of missing command %s\n", name, command.IsAlternateOf)
+				failures++
+			}
+			continue
+		}
+		if command.Function == nil && command.AdvancedFunction == nil {
+			fmt.Printf("ERROR   command %s has no function\n", name)
+			failures++
+		}
+	}
+
+	//Every route must be described, or integrators relying on the OpenAPI document won't know it exists
+	undocumented, missing, err := undocumentedAPIRoutes(APIRouter())
+	if err != nil {
+		fmt.Printf("ERROR   walking API routes: %v\n", err)
+		failures++
+	}
+	for _, route := range undocumented {
+		fmt.Printf("ERROR   API route %s is missing from apiRouteDocs\n", route)
+		failures++
+	}
+	for _, route := range missing {
+		fmt.Printf("ERROR   apiRouteDocs describes %s, which isn't a route\n", route)
+		failures++
+	}
+
+	//The state must be readable by this build, or everything saved so far would be lost on startup
+	stateFiles := map[string]interface{}{
+		"guildData.json":     &map[string]*GuildData{},
+		"guildSettings.json": &map[string]*GuildSettings{},
+		"userSettings.json":  &map[string]*UserSettings{},
+		"starboards.json":    &map[string]*Starboard{},
+		"reminds.json":       &[]RemindEntry{},
+		"voiceData.json":     &map[string]*Voice{},
+	}
+	for _, dir := range stateSourceDirs() {
+		for stateFile, data := range stateFiles {
+			if err := stateRestoreRaw(filepath.Join(dir, stateFile), data); err != nil && !os.IsNotExist(err) {
+				fmt.Printf("ERROR   %s/%s: %v\n", dir, stateFile, err)
+				failures++
+			}
+		}
+	}
+
+	if failures > 0 {
+		fmt.Printf("Self-test failed: %d errors\n", failures)
+		return 1
+	}
+	fmt.Printf("Self-test passed: %d commands\n", len(botData().Commands))
+	return 0
+}
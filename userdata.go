@@ -0,0 +1,303 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UserDataExport holds everything the bot stores that is keyed to a single user
+type UserDataExport struct {
+	UserID   string    `json:"userID"`   //The user the export belongs to
+	Exported time.Time `json:"exported"` //When the export was generated
+
+	Settings         *UserSettings               `json:"settings,omitempty"`         //The user's settings (balance, about me, timezone, socials)
+	Reminders        []RemindEntry               `json:"reminders,omitempty"`        //The user's active remind entries
+	StarboardEntries map[string][]StarboardEntry `json:"starboardEntries,omitempty"` //Starboard entries of the user's messages, where key = guild ID
+	Sessions         map[string][]string         `json:"sessions,omitempty"`         //Active search and conversation sessions, where key = guild ID
+	GuildReferences  map[string][]string         `json:"guildReferences,omitempty"`  //Server-managed lists that reference the user, where key = guild ID
+
+	UnresolvedStarboardEntries int   `json:"unresolvedStarboardEntries,omitempty"` //Starboard entries that couldn't be checked, as their source message no longer exists to tell who wrote it
+	UnreachableShards          []int `json:"unreachableShards,omitempty"`          //The shards that couldn't be reached, so the data of their guilds is missing
+}
+
+// UserDataPurgeResult holds a summary of what was removed when purging a user's data
+type UserDataPurgeResult struct {
+	Settings         bool //Whether or not user settings were removed
+	Reminders        int  //The amount of remind entries removed
+	StarboardEntries int  //The amount of starboard entries removed
+	Sessions         int  //The amount of search and conversation sessions removed
+
+	UnresolvedStarboardEntries int   //The amount of starboard entries left alone, as their source message no longer exists to tell who wrote it
+	UnreachableShards          []int //The shards that couldn't be reached, so the data of their guilds wasn't removed
+}
+
+// Summary returns a summary of what was removed, for the user data commands to respond with
+func (result *UserDataPurgeResult) Summary() string {
+	summary := "Reminders removed: " + strconv.Itoa(result.Reminders) + "\n" +
+		"Starboard entries removed: " + strconv.Itoa(result.StarboardEntries) + "\n" +
+		"Sessions removed: " + strconv.Itoa(result.Sessions)
+	if result.UnresolvedStarboardEntries > 0 {
+		summary += "\n\n" + strconv.Itoa(result.UnresolvedStarboardEntries) + " starboard entries weren't removed, as their original messages were deleted and their authors are unknown. " +
+			"A server administrator can remove them from the starboard channel by hand."
+	}
+	if len(result.UnreachableShards) > 0 {
+		summary += "\n\nThe data kept for some servers couldn't be reached and wasn't removed (shards " + joinShardIDs(result.UnreachableShards) + "). Please try again later."
+	}
+	return summary
+}
+
+// exportUserData collects everything keyed to the given user ID on every shard, as each shard only keeps the data of the guilds it owns
+// - lockedGuildID: The guild whose data is already locked by the caller, if any
+func exportUserData(userID, lockedGuildID string) *UserDataExport {
+	export := localExportUserData(userID, lockedGuildID)
+	export.UnreachableShards = requestOtherShards("/userdata/"+userID, func(url string) error {
+		shardExport := &UserDataExport{}
+		if err := ipcRequestWith(ipcSlowClient, "GET", url, nil, shardExport); err != nil {
+			return err
+		}
+
+		//User settings are synced to every shard, so only the rest is merged in
+		if export.Settings == nil {
+			export.Settings = shardExport.Settings
+		}
+		export.Reminders = append(export.Reminders, shardExport.Reminders...)
+		for guildID, entries := range shardExport.StarboardEntries {
+			export.StarboardEntries[guildID] = append(export.StarboardEntries[guildID], entries...)
+		}
+		for guildID, sessions := range shardExport.Sessions {
+			export.Sessions[guildID] = append(export.Sessions[guildID], sessions...)
+		}
+		for guildID, references := range shardExport.GuildReferences {
+			export.GuildReferences[guildID] = append(export.GuildReferences[guildID], references...)
+		}
+		export.UnresolvedStarboardEntries += shardExport.UnresolvedStarboardEntries
+		return nil
+	})
+	return export
+}
+
+// purgeUserData removes everything keyed to the given user ID on every shard, as each shard only keeps the data of the guilds it owns
+// - lockedGuildID: The guild whose data is already locked by the caller, if any
+func purgeUserData(userID, lockedGuildID string) *UserDataPurgeResult {
+	result := localPurgeUserData(userID, lockedGuildID)
+	result.UnreachableShards = requestOtherShards("/userdata/"+userID, func(url string) error {
+		shardResult := &UserDataPurgeResult{}
+		if err := ipcRequestWith(ipcSlowClient, "DELETE", url, nil, shardResult); err != nil {
+			return err
+		}
+
+		result.Settings = result.Settings || shardResult.Settings
+		result.Reminders += shardResult.Reminders
+		result.StarboardEntries += shardResult.StarboardEntries
+		result.Sessions += shardResult.Sessions
+		result.UnresolvedStarboardEntries += shardResult.UnresolvedStarboardEntries
+		return nil
+	})
+	return result
+}
+
+// requestOtherShards calls request with the URL of the given IPC path on every other shard, returning the IDs of the shards that couldn't be reached
+func requestOtherShards(path string, request func(url string) error) []int {
+	unreachable := make([]int, 0)
+	if shardCount <= 1 {
+		return unreachable
+	}
+
+	shardAddrs, err := ipcShardAddrs()
+	if err != nil {
+		Error.Printf("Error finding the other shards: %v", err)
+	}
+	for id := 0; id < shardCount; id++ {
+		if id == shardID {
+			continue
+		}
+		addr, exists := shardAddrs[id]
+		if !exists {
+			unreachable = append(unreachable, id)
+			continue
+		}
+		if err := request("http://" + addr + path); err != nil {
+			Error.Printf("Error requesting %s from shard %d: %v", path, id, err)
+			unreachable = append(unreachable, id)
+		}
+	}
+	return unreachable
+}
+
+// joinShardIDs returns the given shard IDs as a comma separated list
+func joinShardIDs(ids []int) string {
+	idStrings := make([]string, len(ids))
+	for i, id := range ids {
+		idStrings[i] = strconv.Itoa(id)
+	}
+	return strings.Join(idStrings, ", ")
+}
+
+// localExportUserData collects everything keyed to the given user ID on this shard
+// Starboard entries without a known author are resolved first, and those that still can't be attributed to anyone are only counted
+// - lockedGuildID: The guild whose data is already locked by the caller, if any
+func localExportUserData(userID, lockedGuildID string) *UserDataExport {
+	export := &UserDataExport{
+		UserID:           userID,
+		Exported:         time.Now(),
+		StarboardEntries: make(map[string][]StarboardEntry),
+		Sessions:         make(map[string][]string),
+		GuildReferences:  make(map[string][]string),
+	}
+
+	if settings, exists := copyUserSettings(userID); exists {
+		export.Settings = &settings
+	}
+
+	remindEntriesLock.RLock()
+	for _, entry := range remindEntries {
+		if entry.UserID == userID {
+			export.Reminders = append(export.Reminders, entry)
+		}
+	}
+	remindEntriesLock.RUnlock()
+
+	backfillStarboardAuthors(lockedGuildID)
+	for guildID, starboard := range starboards {
+		unlock := lockUserDataGuild(guildID, lockedGuildID)
+		for _, entry := range starboard.StarboardEntries {
+			if entry.SourceAuthorID == "" {
+				export.UnresolvedStarboardEntries++
+			} else if entry.SourceAuthorID == userID {
+				export.StarboardEntries[guildID] = append(export.StarboardEntries[guildID], entry)
+			}
+		}
+		for _, blacklistedUserID := range starboard.BlacklistUsers {
+			if blacklistedUserID == userID {
+				export.GuildReferences[guildID] = append(export.GuildReferences[guildID], "starboardBlacklist")
+			}
+		}
+		unlock()
+	}
+
+	for guildID, settings := range guildSettings {
+		for _, adminUserID := range settings.BotAdminUsers {
+			if adminUserID == userID {
+				export.GuildReferences[guildID] = append(export.GuildReferences[guildID], "adminUsers")
+			}
+		}
+	}
+
+	for guildID, data := range guildData {
+		unlock := lockUserDataGuild(guildID, lockedGuildID)
+		if _, exists := data.YouTubeResults[userID]; exists {
+			export.Sessions[guildID] = append(export.Sessions[guildID], "youtubeSearch")
+		}
+		if _, exists := data.SpotifyResults[userID]; exists {
+			export.Sessions[guildID] = append(export.Sessions[guildID], "spotifySearch")
+		}
+		if _, exists := data.WolframConversations[userID]; exists {
+			export.Sessions[guildID] = append(export.Sessions[guildID], "wolframConversation")
+		}
+		unlock()
+	}
+
+	return export
+}
+
+// localPurgeUserData removes everything keyed to the given user ID across all stores of this shard
+// Server-managed lists (bot admins, starboard blacklists) are left alone as they're controlled by server administrators
+// Like exports, starboard entries whose author still can't be resolved are left alone and counted
+// - lockedGuildID: The guild whose data is already locked by the caller, if any
+func localPurgeUserData(userID, lockedGuildID string) *UserDataPurgeResult {
+	result := &UserDataPurgeResult{}
+
+	userSettingsLock.Lock()
+	if _, exists := userSettings[userID]; exists {
+		delete(userSettings, userID)
+		result.Settings = true
+	}
+	userSettingsLock.Unlock()
+
+	remindEntriesLock.Lock()
+	newRemindEntries := make([]RemindEntry, 0)
+	for _, entry := range remindEntries {
+		if entry.UserID == userID {
+			result.Reminders++
+			continue
+		}
+		newRemindEntries = append(newRemindEntries, entry)
+	}
+	remindEntries = newRemindEntries
+	remindEntriesLock.Unlock()
+
+	backfillStarboardAuthors(lockedGuildID)
+	removedStarboardEntries := make([]StarboardEntry, 0)
+	for guildID, starboard := range starboards {
+		unlock := lockUserDataGuild(guildID, lockedGuildID)
+		newStarboardEntries := make([]StarboardEntry, 0)
+		for _, entry := range starboard.StarboardEntries {
+			if entry.SourceAuthorID == "" {
+				result.UnresolvedStarboardEntries++
+			} else if entry.SourceAuthorID == userID {
+				removedStarboardEntries = append(removedStarboardEntries, entry)
+				continue
+			}
+			newStarboardEntries = append(newStarboardEntries, entry)
+		}
+		starboard.StarboardEntries = newStarboardEntries
+		unlock()
+	}
+	for _, entry := range removedStarboardEntries {
+		//The starboard message reproduces the user's message, so it goes too
+		botData().DiscordSession.ChannelMessageDelete(entry.StarboardChannelID, entry.StarboardMessageID)
+	}
+	result.StarboardEntries = len(removedStarboardEntries)
+
+	for guildID, data := range guildData {
+		unlock := lockUserDataGuild(guildID, lockedGuildID)
+		if _, exists := data.YouTubeResults[userID]; exists {
+			delete(data.YouTubeResults, userID)
+			result.Sessions++
+		}
+		if _, exists := data.SpotifyResults[userID]; exists {
+			delete(data.SpotifyResults, userID)
+			result.Sessions++
+		}
+		if _, exists := data.WolframConversations[userID]; exists {
+			delete(data.WolframConversations, userID)
+			result.Sessions++
+		}
+		unlock()
+	}
+
+	return result
+}
+
+// lockUserDataGuild locks a guild's data unless the caller already holds its lock, returning the function to unlock it with
+func lockUserDataGuild(guildID, lockedGuildID string) func() {
+	data, exists := guildData[guildID]
+	if !exists || guildID == lockedGuildID {
+		return func() {}
+	}
+	data.Lock()
+	return data.Unlock
+}
+
+// sendUserDataExport sends a user data export as a JSON file to the given recipient's DMs
+func sendUserDataExport(recipientID string, export *UserDataExport) error {
+	exportJSON, err := json.MarshalIndent(export, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	privChannel, err := botData().DiscordSession.UserChannelCreate(recipientID)
+	if err != nil {
+		return err
+	}
+
+	message := "Here is everything " + botData().BotName + " stores about <@!" + export.UserID + ">."
+	if len(export.UnreachableShards) > 0 {
+		message += " The data kept for some servers couldn't be reached and is missing (shards " + joinShardIDs(export.UnreachableShards) + "). Please try again later."
+	}
+	_, err = botData().DiscordSession.ChannelFileSendWithMessage(privChannel.ID, message, "userdata-"+export.UserID+".json", bytes.NewReader(exportJSON))
+	return err
+}
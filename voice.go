@@ -4,6 +4,7 @@ import (
 	"io"
 	"net/url"
 	"sync"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/jonas747/dca"
@@ -17,6 +18,28 @@ const (
 	RepeatNowPlaying
 )
 
+// repeatLevelNames holds the name of each repeat level, as used by the API
+var repeatLevelNames = map[RepeatLevel]string{
+	RepeatNone:       "none",
+	RepeatPlaylist:   "queue",
+	RepeatNowPlaying: "nowplaying",
+}
+
+// String returns the name of the repeat level
+func (level RepeatLevel) String() string {
+	return repeatLevelNames[level]
+}
+
+// parseRepeatLevel returns the repeat level with the given name
+func parseRepeatLevel(name string) (RepeatLevel, bool) {
+	for level, levelName := range repeatLevelNames {
+		if levelName == name {
+			return level, true
+		}
+	}
+	return RepeatNone, false
+}
+
 //Voice contains data about the current voice session
 type Voice struct {
 	sync.Mutex `json:"-"` //This struct gets accessed very repeatedly throughout various goroutines so we need a mutex to prevent race conditions
@@ -44,9 +67,12 @@ type Voice struct {
 	NowPlaying *VoiceNowPlaying `json:"nowPlaying"`   //Holds the queue entry currently in the now playing slot
 
 	//Miscellaneous
+	GuildID       string     `json:"guildID"`       //The guild the voice session belongs to
 	TextChannelID string     `json:"textChannelID"` //The channel that was last used to interact with the voice session
 	done          chan error `json:"-"`             //Used to signal when streaming is done or other actions are performed
 	Started       bool       `json:"-"`             //If the playback session has started
+
+	resumePosition time.Duration //The position to start the next played entry from, used when resuming after a restart
 }
 
 // Connect connects to a given voice channel
@@ -60,19 +86,23 @@ func (voice *Voice) Connect(guildID, vChannelID string) error {
 		err := voice.VoiceConnection.ChangeChannel(vChannelID, voice.Muted, voice.Deafened)
 		if err != nil {
 			//There was an error changing the voice channel
-			return errVoiceJoinChangeChannel
+			ErrorVoice.With("guild", guildID, "channel", vChannelID).Printf("Error changing voice channel: %v", err)
+			return errVoiceJoinChangeChannel.Wrap(err)
 		}
 
 		//Changing the voice channel worked out fine
+		InfoVoice.With("guild", guildID, "channel", vChannelID).Println("Changed voice channel")
 		return nil
 	}
 
 	//Join the voice channel
-	voiceConnection, err := botData.DiscordSession.ChannelVoiceJoin(guildID, vChannelID, voice.Muted, voice.Deafened)
+	voiceConnection, err := botData().DiscordSession.ChannelVoiceJoin(guildID, vChannelID, voice.Muted, voice.Deafened)
 	if err != nil {
 		//There was an error joining the voice channel
-		return errVoiceJoinChannel
+		ErrorVoice.With("guild", guildID, "channel", vChannelID).Printf("Error joining voice channel: %v", err)
+		return errVoiceJoinChannel.Wrap(err)
 	}
+	InfoVoice.With("guild", guildID, "channel", vChannelID).Println("Joined voice channel")
 
 	//Store the new voice connection in memory
 	voice.VoiceConnection = voiceConnection
@@ -91,6 +121,8 @@ func (voice *Voice) Disconnect() error {
 
 	//If a voice connection is already established...
 	if voice.IsConnected() {
+		voice.logger(InfoVoice).Println("Leaving voice channel")
+
 		//Stop the Google Assistant
 		voice.AssistantStop()
 
@@ -125,7 +157,7 @@ func (voice *Voice) Play(queueEntry *QueueEntry, announceQueueAdded bool) error
 		//If we are streaming, add to the queue instead
 		voice.QueueAdd(queueEntry)
 		if announceQueueAdded {
-			botData.DiscordSession.ChannelMessageSendEmbed(voice.TextChannelID, voice.GetAddedEmbed(queueEntry))
+			botData().DiscordSession.ChannelMessageSendEmbed(voice.TextChannelID, voice.GetAddedEmbed(queueEntry))
 		}
 		return nil
 	}
@@ -135,27 +167,12 @@ func (voice *Voice) Play(queueEntry *QueueEntry, announceQueueAdded bool) error
 		return errVoicePlayMuted
 	}
 
-	voice.Lock()
-
 	//Let others know we're beginning to play something
-	voice.Started = true
-
-	//Set the requested entry as now playing
-	voice.NowPlaying = &VoiceNowPlaying{Entry: queueEntry}
-
-	//Tell the server we're now playing this entry
-	botData.DiscordSession.ChannelMessageSendEmbed(voice.TextChannelID, voice.GetNowPlayingEmbed(queueEntry))
-
-	//Tell the world we're now playing this entry
-	updateListeningStatus(botData.DiscordSession, voice.NowPlaying.Entry.Metadata.Artists[0].Name, voice.NowPlaying.Entry.Metadata.Title)
-
-	//Create a channel to signal when the voice stream is finished or stopped
-	voice.done = make(chan error)
-
-	voice.Unlock()
+	voice.startNowPlaying(queueEntry)
 
 	//Start playing this entry
-	msg, err := voice.playRaw(voice.NowPlaying.Entry.Metadata.StreamURL)
+	msg, err := voice.playRaw(queueEntry.Metadata.StreamURL)
+	publishEvent(voice.GuildID, EventTrackEnded, &EventTrack{Entry: newAPIVoiceEntry(queueEntry), Reason: trackEndedReason(msg, err)})
 
 	if msg != nil {
 		if msg == errVoiceStoppedManually {
@@ -184,7 +201,7 @@ func (voice *Voice) Play(queueEntry *QueueEntry, announceQueueAdded bool) error
 		voice.NowPlaying = nil
 		if len(voice.Entries) <= 0 {
 			voice.Disconnect()
-			botData.DiscordSession.ChannelMessageSendEmbed(voice.TextChannelID, NewGenericEmbed("Voice", "Finished playing the queue."))
+			botData().DiscordSession.ChannelMessageSendEmbed(voice.TextChannelID, NewGenericEmbed("Voice", "Finished playing the queue."))
 			return nil
 		}
 		nextQueueEntry, index = voice.QueueGetNext()
@@ -201,6 +218,46 @@ func (voice *Voice) Play(queueEntry *QueueEntry, announceQueueAdded bool) error
 	return voice.Play(nextQueueEntry, announceQueueAdded)
 }
 
+// startNowPlaying sets the given queue entry as now playing and announces it
+// The voice session is unlocked on return even if announcing panics, as handlers recover from panics without exiting
+func (voice *Voice) startNowPlaying(queueEntry *QueueEntry) {
+	voice.Lock()
+	defer voice.Unlock()
+
+	voice.Started = true
+
+	//Set the requested entry as now playing
+	voice.NowPlaying = &VoiceNowPlaying{Entry: queueEntry, StartPosition: voice.resumePosition}
+	voice.resumePosition = 0
+
+	voice.logger(InfoVoice).Printf("Playing [%s] from %s", queueEntry.Metadata.Title, queueEntry.Metadata.DisplayURL)
+
+	//Create a channel to signal when the voice stream is finished or stopped
+	voice.done = make(chan error)
+
+	//Tell the server we're now playing this entry
+	botData().DiscordSession.ChannelMessageSendEmbed(voice.TextChannelID, voice.GetNowPlayingEmbed(queueEntry))
+
+	//Tell the world we're now playing this entry
+	updateListeningStatus(botData().DiscordSession, queueEntry.Metadata.Artists[0].Name, queueEntry.Metadata.Title)
+
+	//Tell API clients we're now playing this entry
+	publishEvent(voice.GuildID, EventTrackStarted, &EventTrack{Entry: newAPIVoiceEntry(queueEntry)})
+}
+
+// trackEndedReason describes why an entry stopped playing, given what playRaw returned
+func trackEndedReason(msg, err error) string {
+	switch {
+	case msg == errVoiceStoppedManually:
+		return "stopped"
+	case msg == errVoiceSkippedManually:
+		return "skipped"
+	case err != nil:
+		return "error"
+	}
+	return "finished"
+}
+
 // playRaw plays a given media URL in a connected voice channel
 func (voice *Voice) playRaw(mediaURL string) (error, error) {
 	/*
@@ -216,23 +273,50 @@ func (voice *Voice) playRaw(mediaURL string) (error, error) {
 		return nil, errVoicePlayAlreadyStreaming
 	}
 
+	if err := voice.startStream(mediaURL); err != nil {
+		return nil, err
+	}
+
+	//Start a goroutine to update the current streaming position
+	go voice.updatePosition()
+
+	//Wait for the streaming session to finish
+	msg := <-voice.done
+
+	//Return any streaming errors, if any
+	return msg, voice.finishStream()
+}
+
+// startStream starts encoding the given media URL and streaming it to Discord
+// Like the other helpers of playRaw, it holds the voice session's lock only until it returns, on every path
+func (voice *Voice) startStream(mediaURL string) error {
 	voice.Lock()
+	defer voice.Unlock()
 
 	//Make sure we're allowed to speak
 	if voice.Muted {
-		return nil, errVoicePlayMuted
+		return errVoicePlayMuted
 	}
 
 	//Ensure that the media URL is valid
 	_, err := url.ParseRequestURI(mediaURL)
 	if err != nil {
-		return nil, errVoicePlayInvalidURL
+		return errVoicePlayInvalidURL
 	}
 
 	//Create the encoding session to encode the audio stream as DCA
-	voice.EncodingSession, err = dca.EncodeFile(mediaURL, voice.EncodingOptions)
+	encodingOptions := voice.EncodingOptions
+	if voice.NowPlaying != nil && voice.NowPlaying.StartPosition > 0 {
+		//Seek into the stream without changing the encoding options for later entries
+		seekOptions := *voice.EncodingOptions
+		seekOptions.StartTime = int(voice.NowPlaying.StartPosition.Seconds())
+		encodingOptions = &seekOptions
+	}
+	voice.EncodingSession, err = dca.EncodeFile(mediaURL, encodingOptions)
 	if err != nil {
-		return nil, err
+		voice.logger(ErrorVoice).Printf("Error starting the encoding session: %v", err)
+		voice.EncodingSession = nil
+		return err
 	}
 
 	//Mark our voice presence as speaking
@@ -240,22 +324,22 @@ func (voice *Voice) playRaw(mediaURL string) (error, error) {
 
 	//Create the streaming session to send the encoded DCA audio to Discord
 	voice.StreamingSession = dca.NewStream(voice.EncodingSession, voice.VoiceConnection, voice.done)
+	return nil
+}
 
-	voice.Unlock()
-
-	//Start a goroutine to update the current streaming position
-	go voice.updatePosition()
-
-	//Wait for the streaming session to finish
-	msg := <-voice.done
-
+// finishStream cleans up after the streaming session stopped, returning why it stopped
+func (voice *Voice) finishStream() error {
 	voice.Lock()
+	defer voice.Unlock()
 
 	//Mark our voice presence as not speaking
 	voice.Silent()
 
 	//Figure out why the streaming session stopped
-	_, err = voice.StreamingSession.Finished()
+	_, err := voice.StreamingSession.Finished()
+	if err != nil && err != io.EOF {
+		voice.logger(WarningVoice).Printf("Streaming session stopped: %v", err)
+	}
 
 	//Clean up the streaming session
 	voice.StreamingSession = nil
@@ -265,25 +349,25 @@ func (voice *Voice) playRaw(mediaURL string) (error, error) {
 	voice.EncodingSession.Cleanup()
 	voice.EncodingSession = nil
 
-	voice.Unlock()
-
-	//Return any streaming errors, if any
-	return msg, err
+	return err
 }
 
 // updatePosition updates the current position of a playing media
 func (voice *Voice) updatePosition() {
-	for {
-		voice.Lock()
+	for voice.refreshPosition() {
+	}
+}
 
-		if voice.StreamingSession == nil || voice.NowPlaying == nil {
-			voice.Unlock()
-			return
-		}
-		voice.NowPlaying.Position = voice.StreamingSession.PlaybackPosition()
+// refreshPosition updates the current position of a playing media once, returning false when nothing is playing anymore
+func (voice *Voice) refreshPosition() bool {
+	voice.Lock()
+	defer voice.Unlock()
 
-		voice.Unlock()
+	if voice.StreamingSession == nil || voice.NowPlaying == nil {
+		return false
 	}
+	voice.NowPlaying.Position = voice.NowPlaying.StartPosition + voice.StreamingSession.PlaybackPosition()
+	return true
 }
 
 // Stop stops the playback of a media
@@ -434,6 +518,14 @@ func (voice *Voice) AssistantStop() {
 	//Placeholder for now
 }
 
+// logger returns the given logger with the guild and voice channel of the voice connection, if any
+func (voice *Voice) logger(logger *Logger) *Logger {
+	if voice.VoiceConnection == nil {
+		return logger
+	}
+	return logger.With("guild", voice.VoiceConnection.GuildID, "channel", voice.VoiceConnection.ChannelID)
+}
+
 // VoiceInit initializes a voice object for the given guild
 func VoiceInit(guildID string) {
 	if voiceData[guildID] != nil {
@@ -441,6 +533,33 @@ func VoiceInit(guildID string) {
 	}
 
 	voiceData[guildID] = &Voice{
-		EncodingOptions: botData.BotOptions.AudioEncoding,
+		GuildID:         guildID,
+		EncodingOptions: botData().BotOptions.AudioEncoding,
 	}
 }
+
+// userVoiceChannel returns the voice channel a user is in within a guild, or nothing if they aren't in one
+func userVoiceChannel(guildID, userID string) string {
+	guild, err := botData().DiscordSession.State.Guild(guildID)
+	if err != nil {
+		return ""
+	}
+	for _, voiceState := range guild.VoiceStates {
+		if voiceState.UserID == userID {
+			return voiceState.ChannelID
+		}
+	}
+	return ""
+}
+
+// CanControl returns why a user can't control the voice session, if they can't, as the voice commands require
+// The bot must be in a voice channel, and the user must be in the same one
+func (voice *Voice) CanControl(guildID, userID, action string) error {
+	if !voice.IsConnected() {
+		return newError(errCodeVoiceBotNotInChannel, botData().BotName)
+	}
+	if userVoiceChannel(guildID, userID) != voice.VoiceConnection.ChannelID {
+		return newError(errCodeVoiceUserWrongChannel, botData().BotName, action)
+	}
+	return nil
+}
@@ -11,10 +11,12 @@ import (
 func (voice *Voice) QueueAdd(entry *QueueEntry) {
 	//Add the new queue entry
 	voice.Entries = append(voice.Entries, entry)
+	voice.queueChanged()
 }
 func (voice *Voice) QueueRemove(entry int) {
 	//Remove the queue entry
 	voice.Entries = append(voice.Entries[:entry], voice.Entries[entry+1:]...)
+	voice.queueChanged()
 }
 func (voice *Voice) QueueRemoveRange(start, end int) {
 	if len(voice.Entries) == 0 {
@@ -34,6 +36,12 @@ func (voice *Voice) QueueRemoveRange(start, end int) {
 }
 func (voice *Voice) QueueClear() {
 	voice.Entries = nil
+	voice.queueChanged()
+}
+
+// queueChanged tells API clients the queue changed, which must be called after changing voice.Entries directly
+func (voice *Voice) queueChanged() {
+	publishEvent(voice.GuildID, EventQueueChanged, &EventQueue{Length: len(voice.Entries)})
 }
 func (voice *Voice) QueueGet(entry int) *QueueEntry {
 	if len(voice.Entries) < entry {
@@ -114,8 +122,9 @@ func (voice *Voice) getQueueEmbed(entry *QueueEntry, embedType int) *discordgo.M
 
 //VoiceNowPlaying contains data about the now playing queue entry
 type VoiceNowPlaying struct {
-	Entry    *QueueEntry   //The underlying queue entry
-	Position time.Duration //The current position in the audio stream
+	Entry         *QueueEntry   //The underlying queue entry
+	Position      time.Duration //The current position in the audio stream
+	StartPosition time.Duration //The position the audio stream was started from, when resuming playback
 }
 
 // Metadata stores the metadata of a queue entry
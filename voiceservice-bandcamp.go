@@ -9,7 +9,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"net/http"
 	"regexp"
 	"strconv"
 	"strings"
@@ -97,7 +96,7 @@ type VoiceServiceBandcampTrack struct {
 }
 
 func bandcampGetAlbum(url string) (*VoiceServiceBandcampAlbum, error) {
-	_, err := http.Get(url)
+	_, err := botHTTPClient.Get(url)
 	if err != nil {
 		return nil, err
 	}
@@ -30,7 +30,7 @@ func (*VoiceServiceSoundCloud) TestURL(url string) (bool, error) {
 
 // GetMetadata returns the metadata for a given SoundCloud track URL
 func (*VoiceServiceSoundCloud) GetMetadata(url string) (*Metadata, error) {
-	trackInfo, err := botData.BotClients.SoundCloud.GetTrackInfo(url)
+	trackInfo, err := botData().BotClients.SoundCloud.GetTrackInfo(url)
 	if err != nil {
 		return nil, err
 	}
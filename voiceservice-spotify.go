@@ -36,7 +36,7 @@ func (*VoiceServiceSpotify) GetMetadata(url string) (*Metadata, error) {
 		url = newURL
 	}
 
-	trackInfo, err := botData.BotClients.Spotify.GetTrackInfo(url)
+	trackInfo, err := botData().BotClients.Spotify.GetTrackInfo(url)
 	if err != nil {
 		return nil, err
 	}
@@ -102,7 +102,7 @@ func (page *VoiceServiceSpotifyResultNav) Search(query string) error {
 	}
 
 	if page.MaxResults == 0 {
-		page.MaxResults = botData.BotOptions.SpotifyMaxResults
+		page.MaxResults = botData().BotOptions.SpotifyMaxResults
 	}
 
 	page.Query = ""
@@ -115,7 +115,7 @@ func (page *VoiceServiceSpotifyResultNav) Search(query string) error {
 	page.PlaylistUserID = ""
 	page.TotalPages = 0
 
-	searchResults, err := botData.BotClients.Spotify.Search(query)
+	searchResults, err := botData().BotClients.Spotify.Search(query)
 	if err != nil {
 		return err
 	}
@@ -155,7 +155,7 @@ func (page *VoiceServiceSpotifyResultNav) Playlist(url string) error {
 	}
 
 	if page.MaxResults == 0 {
-		page.MaxResults = botData.BotOptions.SpotifyMaxResults
+		page.MaxResults = botData().BotOptions.SpotifyMaxResults
 	}
 
 	page.Query = ""
@@ -168,7 +168,7 @@ func (page *VoiceServiceSpotifyResultNav) Playlist(url string) error {
 	page.PlaylistUserID = ""
 	page.TotalPages = 0
 
-	playlist, err := botData.BotClients.Spotify.GetPlaylist(url)
+	playlist, err := botData().BotClients.Spotify.GetPlaylist(url)
 	if err != nil {
 		return err
 	}
@@ -191,7 +191,7 @@ func (page *VoiceServiceSpotifyResultNav) Playlist(url string) error {
 		hit := spotigo.SpotigoSearchHit{}
 
 		if i < page.MaxResults {
-			trackInfo, err := botData.BotClients.Spotify.GetTrackInfo(item.TrackURI)
+			trackInfo, err := botData().BotClients.Spotify.GetTrackInfo(item.TrackURI)
 			if err != nil {
 				continue
 			}
@@ -256,7 +256,7 @@ func (page *VoiceServiceSpotifyResultNav) Prev() error {
 
 	for i := 0; i < len(page.Results); i++ {
 		if strings.HasPrefix(page.Results[i].URI, "spotify:track:") {
-			trackInfo, err := botData.BotClients.Spotify.GetTrackInfo(page.Results[i].URI)
+			trackInfo, err := botData().BotClients.Spotify.GetTrackInfo(page.Results[i].URI)
 			if err != nil {
 				continue
 			}
@@ -298,7 +298,7 @@ func (page *VoiceServiceSpotifyResultNav) Next() error {
 
 	for i := 0; i < len(page.Results); i++ {
 		if strings.HasPrefix(page.Results[i].URI, "spotify:track:") {
-			trackInfo, err := botData.BotClients.Spotify.GetTrackInfo(page.Results[i].URI)
+			trackInfo, err := botData().BotClients.Spotify.GetTrackInfo(page.Results[i].URI)
 			if err != nil {
 				continue
 			}
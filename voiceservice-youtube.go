@@ -36,7 +36,7 @@ func (*VoiceServiceYouTube) TestURL(url string) (bool, error) {
 
 // GetMetadata returns the metadata for a given YouTube video URL
 func (*VoiceServiceYouTube) GetMetadata(url string) (*Metadata, error) {
-	videoInfo, err := botData.BotClients.YTDL.GetVideo(url)
+	videoInfo, err := botData().BotClients.YTDL.GetVideo(url)
 	if err != nil {
 		return nil, err
 	}
@@ -52,12 +52,12 @@ func (*VoiceServiceYouTube) GetMetadata(url string) (*Metadata, error) {
 
 	format := formats[0]
 
-	videoURL, err := botData.BotClients.YTDL.GetStreamURL(videoInfo, &format)
+	videoURL, err := botData().BotClients.YTDL.GetStreamURL(videoInfo, &format)
 	if err != nil {
 		return nil, err
 	}
 
-	ytCall := youtube.NewVideosService(botData.BotClients.YouTube).
+	ytCall := youtube.NewVideosService(botData().BotClients.YouTube).
 		List([]string{"snippet","contentDetails"}).
 		Id(videoInfo.ID)
 
@@ -89,7 +89,7 @@ func (*VoiceServiceYouTube) GetMetadata(url string) (*Metadata, error) {
 
 //YouTubeGetQuery returns YouTube search results
 func YouTubeGetQuery(query string) (string, error) {
-	call := botData.BotClients.YouTube.Search.List([]string{"id"}).
+	call := botData().BotClients.YouTube.Search.List([]string{"id"}).
 		Q(query).
 		MaxResults(50)
 
@@ -132,7 +132,7 @@ func (page *VoiceServiceYouTubeResultNav) Prev() error {
 		return errors.New("No pages exist before current page")
 	}
 
-	searchCall := botData.BotClients.YouTube.Search.
+	searchCall := botData().BotClients.YouTube.Search.
 		List([]string{"id"}).
 		Q(page.Query).
 		MaxResults(page.MaxResults).
@@ -160,7 +160,7 @@ func (page *VoiceServiceYouTubeResultNav) Next() error {
 		return errors.New("No pages exist after current page")
 	}
 
-	searchCall := botData.BotClients.YouTube.Search.
+	searchCall := botData().BotClients.YouTube.Search.
 		List([]string{"id"}).
 		Q(page.Query).
 		MaxResults(page.MaxResults).
@@ -190,7 +190,7 @@ func (page *VoiceServiceYouTubeResultNav) GetResults() ([]*youtube.SearchResult,
 //Search starts a search and stores the results
 func (page *VoiceServiceYouTubeResultNav) Search(query string) error {
 	if page.MaxResults == 0 {
-		page.MaxResults = int64(botData.BotOptions.YouTubeMaxResults)
+		page.MaxResults = int64(botData().BotOptions.YouTubeMaxResults)
 	}
 
 	page.Query = ""
@@ -200,7 +200,7 @@ func (page *VoiceServiceYouTubeResultNav) Search(query string) error {
 	page.PrevPageToken = ""
 	page.NextPageToken = ""
 
-	searchCall := botData.BotClients.YouTube.Search.
+	searchCall := botData().BotClients.YouTube.Search.
 		List([]string{"id"}).
 		Q(query).
 		MaxResults(page.MaxResults).
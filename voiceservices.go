@@ -21,18 +21,18 @@ type VoiceService interface {
 	//Search(query string) (results *SearchResults, err error)
 }
 
-func initVoiceServices() {
-	botData.VoiceServices = make([]VoiceService, 0)
+func (configData *BotData) initVoiceServices() {
+	configData.VoiceServices = make([]VoiceService, 0)
 
-	botData.VoiceServices = append(botData.VoiceServices, &VoiceServiceYouTube{})
-	botData.VoiceServices = append(botData.VoiceServices, &VoiceServiceSoundCloud{})
-	botData.VoiceServices = append(botData.VoiceServices, &VoiceServiceSpotify{})
-	botData.VoiceServices = append(botData.VoiceServices, &VoiceServiceBandcamp{})
-	botData.VoiceServices = append(botData.VoiceServices, &VoiceServiceDirect{})
+	configData.VoiceServices = append(configData.VoiceServices, &VoiceServiceYouTube{})
+	configData.VoiceServices = append(configData.VoiceServices, &VoiceServiceSoundCloud{})
+	configData.VoiceServices = append(configData.VoiceServices, &VoiceServiceSpotify{})
+	configData.VoiceServices = append(configData.VoiceServices, &VoiceServiceBandcamp{})
+	configData.VoiceServices = append(configData.VoiceServices, &VoiceServiceDirect{})
 }
 
 func createQueueEntry(url string) (*QueueEntry, error) {
-	for _, service := range botData.VoiceServices {
+	for _, service := range botData().VoiceServices {
 		test, err := service.TestURL(url)
 		if err != nil {
 			return nil, err
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"crypto/subtle"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+/*
+	Webhooks let other systems, such as CI or monitoring, post messages into a guild through the bot.
+	Guild managers give channels an alias and create keys, and each post names the alias of the channel it goes to, so keys never need to know channel IDs.
+	Keys are stored as SHA-256 hashes in the guild's settings, just like API tokens, and each one may only post so many messages per minute.
+*/
+
+const (
+	webhookKeyPrefix = "clinethook_"
+)
+
+var (
+	//Channel aliases are short names safe to put in URLs and scripts
+	webhookAliasRegexp = regexp.MustCompile(`^[a-z0-9_-]{1,32}$`)
+
+	//How often each webhook key posts, where key = webhook key ID
+	webhookLimiter = newRateLimiter(time.Minute)
+)
+
+// WebhookKey holds a key that can post messages to a guild's webhook channels
+type WebhookKey struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Hash      string    `json:"hash,omitempty"` //The SHA-256 hash of the key, never included in responses
+	RateLimit int       `json:"rateLimit"`      //How many messages the key may post per minute, 0 uses botOptions.api.webhookRateLimit
+	Created   time.Time `json:"created"`
+	LastUsed  time.Time `json:"lastUsed"`
+}
+
+// redacted returns a copy of the key info without its hash, for responses
+func (key *WebhookKey) redacted() *WebhookKey {
+	redacted := *key
+	redacted.Hash = ""
+	return &redacted
+}
+
+// Limit returns how many messages the key may post per minute
+func (key *WebhookKey) Limit() int {
+	if key.RateLimit > 0 {
+		return key.RateLimit
+	}
+	return botData().BotOptions.API.WebhookRateLimit
+}
+
+// issueWebhookKey creates a new webhook key for a guild, returning the key itself along with its info
+// The caller must hold the guild's lock
+func issueWebhookKey(guildID, name string, rateLimit int) (string, *WebhookKey, error) {
+	id, err := randomHex(8)
+	if err != nil {
+		return "", nil, err
+	}
+	secret, err := randomHex(32)
+	if err != nil {
+		return "", nil, err
+	}
+	key := webhookKeyPrefix + id + "_" + secret
+
+	info := &WebhookKey{
+		ID:        id,
+		Name:      name,
+		Hash:      hashAPIToken(key),
+		RateLimit: rateLimit,
+		Created:   time.Now(),
+	}
+	guildSettings[guildID].WebhookKeys = append(guildSettings[guildID].WebhookKeys, info)
+	return key, info, nil
+}
+
+// lookupWebhookKey returns the info of the given key if it belongs to the guild, or nil if it doesn't
+// The caller must hold the guild's lock
+func lookupWebhookKey(guildID, key string) *WebhookKey {
+	parts := strings.SplitN(strings.TrimPrefix(key, webhookKeyPrefix), "_", 2)
+	if !strings.HasPrefix(key, webhookKeyPrefix) || len(parts) != 2 {
+		return nil
+	}
+
+	settings, exists := guildSettings[guildID]
+	if !exists {
+		return nil
+	}
+	for _, info := range settings.WebhookKeys {
+		if info.ID == parts[0] && subtle.ConstantTimeCompare([]byte(info.Hash), []byte(hashAPIToken(key))) == 1 {
+			return info
+		}
+	}
+	return nil
+}
+
+// revokeWebhookKey removes the key with the given ID from a guild, returning false if the guild has no such key
+// The caller must hold the guild's lock
+func revokeWebhookKey(guildID, keyID string) bool {
+	keys := guildSettings[guildID].WebhookKeys
+	for i, key := range keys {
+		if key.ID == keyID {
+			guildSettings[guildID].WebhookKeys = append(keys[:i], keys[i+1:]...)
+			webhookLimiter.Forget(keyID)
+			return true
+		}
+	}
+	return false
+}
+
+// allowWebhookPost records a post by the key if it's within its rate limit, otherwise returning how long until it may post again
+func allowWebhookPost(key *WebhookKey) (time.Duration, bool) {
+	_, retryAfter, allowed := webhookLimiter.Allow(key.ID, key.Limit())
+	return retryAfter, allowed
+}
+
+// logWebhookPost sends a message posted by a webhook key to the guild's logging channel
+func logWebhookPost(guildID string, key *WebhookKey, message *discordgo.Message) {
+	settings, exists := guildSettings[guildID]
+	if !exists || !settings.LogSettings.LoggingEnabled || !settings.LogSettings.LoggingEvents.WebhookPost {
+		return
+	}
+
+	webhookPostEmbed := NewEmbed().
+		SetTitle("Logging Event - Webhook Post").
+		SetDescription("A webhook key posted a message.").
+		AddField("Key", key.Name+" ("+key.ID+")").
+		AddField("Channel", "<#"+message.ChannelID+">").
+		AddField("Message", "https://discord.com/channels/"+guildID+"/"+message.ChannelID+"/"+message.ID).
+		SetColor(0x1C1C1C)
+	if message.Content != "" {
+		webhookPostEmbed.AddField("Content", message.Content)
+	}
+	if len(message.Embeds) > 0 && message.Embeds[0].Title != "" {
+		webhookPostEmbed.AddField("Embed Title", message.Embeds[0].Title)
+	}
+
+	sendLogEmbed(botData().DiscordSession, guildID, "webhookPost", webhookPostEmbed.Truncate().MessageEmbed)
+}